@@ -0,0 +1,17 @@
+# Aurora, slow waves of the players' colors drifting along the field
+#
+# Read again whenever it's saved. Every line is name = expression, worked out for each led of every frame from
+# x, y, width, height, t, left_r, left_g, left_b, right_r, right_g, right_b, playing, left_score, right_score,
+# match_point, ball_x, ball_speed and anything assigned above it. Set r, g, b and a from 0 to 1
+
+u = x / width
+wave = 0.5 + 0.5 * sin(u * 2 * pi * 1.5 + t * 0.7) * cos(u * pi * 3 - t * 0.3)
+shimmer = 0.75 + 0.25 * sin(x * 1.7 + t * 5)
+
+# throbs at match point so everyone watching knows the next point decides it
+tension = match_point * (0.5 + 0.5 * sin(t * 10))
+
+r = mix(left_r, right_r, wave) * shimmer
+g = mix(left_g, right_g, wave) * shimmer
+b = mix(left_b, right_b, wave) * shimmer
+a = clamp(0.3 + 0.7 * wave * (1 - tension) + tension * 0.2, 0, 1)
@@ -0,0 +1,12 @@
+# Victory sweep, the winner's color filling the field from their end and pulsing
+#
+# As well as the inputs of a background, victories have left_won which is 1 if the left player won and progress from
+# 0 to 1 over the time the winner is shown
+
+from = if(left_won, x / width, 1 - x / width)
+lit = step(from, progress * 1.5)
+pulse = 0.6 + 0.4 * sin(t * 12)
+r = if(left_won, left_r, right_r) * pulse
+g = if(left_won, left_g, right_g) * pulse
+b = if(left_won, left_b, right_b) * pulse
+a = lit
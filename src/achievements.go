@@ -0,0 +1,76 @@
+package main
+
+import (
+	"log"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+	"github.com/brandonagr/pongpi/src/pong/stats"
+)
+
+// Seconds each achievement unlocked in a game is celebrated for
+var celebrationSeconds float64 = 3
+
+// An achievement unlocked in the last game, waiting to be celebrated
+type unlockedAchievement struct {
+	stats.Achievement
+
+	// name of the player who unlocked it, and the side they played on
+	player string
+	isLeft bool
+
+	// color the player is drawn in
+	color RGBA
+}
+
+// Unlock achievements earned in match by players with profiles, queueing a celebration for each
+func (this *game) updateAchievements(match stats.Match) {
+
+	sides := []struct {
+		profile PlayerProfile
+		isLeft  bool
+	}{
+		{this.current.config.LeftProfile, true},
+		{this.current.config.RightProfile, false},
+	}
+
+	for _, side := range sides {
+		if side.profile.IsGuest() {
+			continue
+		}
+		color := CurrentTheme().PlayerColor(side.profile, side.isLeft)
+		for _, achievement := range this.achievements.Evaluate(side.profile.Name, match, side.isLeft) {
+			log.Print(side.profile.Name, " unlocked ", achievement.Name, ": ", achievement.Description)
+			this.celebrations = append(this.celebrations, unlockedAchievement{achievement, side.profile.Name, side.isLeft, color})
+		}
+	}
+
+	if len(this.celebrations) > 0 {
+		if err := this.achievements.Save(); err != nil {
+			log.Print(err)
+		}
+	}
+}
+
+// Celebrate the next unlocked achievement once the last one is done, returns false once there are none left
+func (this *game) celebrate() bool {
+
+	if this.celebration != nil && this.celebration.TimeRemaining() > 0 {
+		return true
+	}
+	this.celebration = nil
+
+	if len(this.celebrations) == 0 {
+		return false
+	}
+	unlocked := this.celebrations[0]
+	this.celebrations = this.celebrations[1:]
+
+	scene := NewMatrixScene("achievement", Settings.LedCount, Settings.MatrixRows)
+	this.celebration = NewCelebration(scene.Field(), unlocked.isLeft, unlocked.color, celebrationSeconds)
+	scene.Add(this.celebration)
+	this.show(scene, Settings.SceneFadeSeconds)
+	go PlayTTS(unlocked.player + " unlocked " + unlocked.Name)
+
+	return true
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"log"
+	"runtime/debug"
+	"time"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+// Lines logged most recently, saved with crash reports
+var recentEvents = NewEventLog(100)
+
+// Seconds the crash pattern is shown before the game starts over
+var crashPatternSeconds float64 = 3
+
+// Called deferred by each frame, reports a panic, shows the crash on the strip, and starts over from the intro
+func (this *game) catchPanic() {
+
+	cause := recover()
+	if cause == nil {
+		return
+	}
+	stack := debug.Stack()
+	if rendering, ok := cause.(*RenderPanic); ok {
+		// the stack of the render worker shows where it went wrong, the game loop only shows it waiting for the frame
+		stack = append(append(rendering.Stack, '\n'), stack...)
+	}
+	log.Print("Game crashed: ", cause)
+
+	activity := "booting"
+	if this.boot == nil {
+		activity = this.states.Phase().String()
+	}
+	report := NewCrashReport(cause, stack, activity, recentEvents.Lines(), this.capture.LastFrame())
+	report.Frames = this.capture.Frames()
+	if path, err := report.Save(Settings.CrashReportDir); err != nil {
+		log.Print(err)
+	} else {
+		log.Print("Crash report saved to ", path)
+	}
+
+	this.showCrash()
+
+	this.boot = nil
+	this.restart()
+}
+
+// Flash the crash pattern on the display for crashPatternSeconds
+func (this *game) showCrash() {
+
+	field := NewMatrixField(Settings.LedCount, Settings.MatrixRows)
+	field.Add(NewCrashPattern())
+
+	startTime := time.Now()
+	timer := NewFrameTimer(startTime)
+	for now := range this.ticks.C {
+		if now.Sub(startTime).Seconds() >= crashPatternSeconds {
+			break
+		}
+		this.beat("showing crash")
+		field.Animate(timer.Elapsed(now))
+		field.RenderTo(this.display)
+	}
+	field.Release()
+}
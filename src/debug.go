@@ -0,0 +1,49 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+// Show the debug overlay over every scene from now on, or stop showing it
+func (this *game) setDebug(enabled bool) {
+
+	this.debug = enabled
+	if this.debugOverlay != nil {
+		this.debugOverlay.Stop()
+		this.debugOverlay = nil
+	}
+	if enabled {
+		this.addDebugOverlay(this.scenes.Current())
+	}
+	log.Print("Debug overlay enabled: ", enabled)
+}
+
+// Draw the debug overlay over scene while it is enabled
+func (this *game) addDebugOverlay(scene *Scene) {
+
+	if !this.debug || scene == nil {
+		return
+	}
+	this.debugOverlay = NewDebugOverlay(scene.Field(), this.states)
+	scene.Add(this.debugOverlay)
+}
+
+// Turn the debug overlay on or off with a POST to /api/debug with enabled set to true or false
+func (this *game) debugHandler(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != "POST" {
+		http.Error(w, "POST enabled=true or enabled=false to show or hide the debug overlay", http.StatusMethodNotAllowed)
+		return
+	}
+	enabled, err := strconv.ParseBool(r.FormValue("enabled"))
+	if err != nil {
+		http.Error(w, "enabled must be true or false", http.StatusBadRequest)
+		return
+	}
+	this.debugRequests <- enabled
+}
@@ -0,0 +1,1048 @@
+package main
+
+import (
+	"log"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+	"github.com/brandonagr/pongpi/src/pong/stats"
+)
+
+// How a single game is played
+type gameOptions struct {
+
+	// name of the registered game mode
+	mode string
+
+	// options passed to the mode
+	config GameConfig
+
+	// name of the background drawn behind the game
+	background string
+
+	// if the game is the next match of the tournament
+	tournament bool
+}
+
+// State shared by every phase of the game loop
+type game struct {
+
+	// real buttons and display, and the last errors reading the buttons and rendering
+	buttons      ButtonInput
+	display      Display
+	buttonError  string
+	displayError string
+
+	// options used for games started by players
+	options gameOptions
+
+	ratings  *Ratings
+	stats    *stats.Store
+	history  *stats.History
+	profiles *Profiles
+	states   *StateMachine
+
+	// achievements earned by players, the ones unlocked by the last game that haven't been celebrated yet, and the
+	// celebration being shown
+	achievements *stats.AchievementStore
+	celebrations []unlockedAchievement
+	celebration  *Celebration
+
+	// best scores of modes played for a high score
+	highScores *stats.HighScoreStore
+
+	// game time, stopped while paused, and the wall clock it follows, simulated to run faster than real time
+	clock     *GameClock
+	wallClock Clock
+
+	// frame rate, lowered when frames take too long, and the ticker that paces frames at it
+	governor *FrameRateGovernor
+	ticks    *time.Ticker
+
+	// lowers the frame rate as the board or strip heats up, nil when temperatures aren't watched
+	thermal *ThermalMonitor
+
+	// battery of a UPS HAT, nil without one
+	ups *UpsMonitor
+
+	// knob that turns the brightness and moves through the menus, nil if there isn't one
+	encoder    *RotaryEncoder
+	brightness *AutoBrightness
+
+	// animation shown on startup, nil once it has finished
+	boot *Boot
+
+	// signals that stop the game
+	shutdown chan os.Signal
+
+	// watchdog fed every frame, nil when disabled, and the channel it uses to ask for the game to restart
+	watchdog *Watchdog
+	stalls   chan bool
+
+	// quiet hours state
+	quietHours   QuietHours
+	awakeUntil   time.Time
+	blanked      bool
+	quietDisplay *DimmedDisplay
+
+	// if the clock has replaced the attract backgrounds while idle
+	clockShown bool
+
+	// notification shown over the idle scene, nil if there isn't one, and notifications pushed from the web
+	notification   *NotificationLight
+	notifyRequests chan notifyRequest
+
+	// pause state, requests come from the web server
+	pauseChord    *ChordDetector
+	pauseRequests chan bool
+	resumeTime    float64
+	pausedDisplay *DimmedDisplay
+
+	// scenes shown in each phase, the field of the current scene, and the display it is rendered to
+	scenes *SceneManager
+	field  *GameField
+	output Display
+
+	// keeps the last frames rendered for crash reports and the web
+	capture *FrameCapture
+
+	// options of the game currently being played
+	current gameOptions
+
+	// rules of the game currently being played and how it ended
+	mode          GameMode
+	timestep      *FixedTimestep
+	outcome       GameOutcome
+	leftPlayerWon bool
+
+	// game time the current game started at
+	gameStart float64
+
+	// score of the current game when the last point was won, to tell who won the next one
+	leftScore, rightScore int
+
+	// game seconds since a real button was last pushed
+	idleTime float64
+
+	// animations that end their phase
+	countdown *Countdown
+	winner    victory
+
+	// final rally being replayed in slow motion before the winner is shown, nil when there isn't one
+	matchPoint *matchPointReplay
+
+	// where the ball was returned and missed in the last match, and the heatmap of them being shown
+	heatmapReturns, heatmapMisses []float64
+	heatmap                       *HitHeatmap
+
+	// leaderboard of the week, shown after the one of the day, nil once it has been shown
+	weeklyLeaderboard *Scene
+
+	// menus for choosing the options of the next game, shown while waiting for players
+	menus     []*Menu
+	menuIndex int
+	menuOpen  bool
+	menuPress *PressDetector
+
+	// profiles chosen for the next game from the web
+	playerChoices chan playerChoice
+
+	// bracket the next games are played from while it is running
+	tournament *Tournament
+
+	// players queued from the web for king of the hill
+	hill *HillQueue
+
+	// winners spectators predicted from the web for the next match
+	predictions *Predictions
+
+	// players who signed up from their phones to play next
+	signups *SignupQueue
+
+	// phones pushing the buttons in the crowd mode
+	crowd *CrowdInput
+
+	// if the debug overlay is drawn over every scene, the overlay of the current scene, and requests from the web
+	// to show or hide it
+	debug         bool
+	debugOverlay  *DebugOverlay
+	debugRequests chan bool
+
+	// requests from the web for the state of the game
+	stateRequests chan chan gameState
+}
+
+// Construct a game and hook up every phase
+func newGame(buttons ButtonInput, display Display, options gameOptions, ratings *Ratings, store *stats.Store, profiles *Profiles) *game {
+
+	this := &game{
+		buttons:  buttons,
+		display:  display,
+		options:  options,
+		ratings:  ratings,
+		stats:    store,
+		history:  stats.NewHistory(Settings.HistoryPath),
+		profiles: profiles,
+		states:   NewStateMachine(),
+		scenes:   NewSceneManager(Settings.Leds()),
+		menus:    newMenus(profiles),
+
+		achievements: stats.LoadAchievements(Settings.AchievementsPath),
+		tournament:   LoadTournament(Settings.TournamentPath),
+		highScores:   stats.LoadHighScores(Settings.HighScoresPath),
+
+		clock:          NewGameClock(),
+		wallClock:      SystemClock{},
+		governor:       NewFrameRateGovernor(Settings.MinFPS, Settings.MaxFPS),
+		pauseChord:     NewChordDetector(0.15, pauseHoldSeconds),
+		pauseRequests:  make(chan bool, 1),
+		playerChoices:  make(chan playerChoice, 1),
+		debugRequests:  make(chan bool, 1),
+		stateRequests:  make(chan chan gameState),
+		notifyRequests: make(chan notifyRequest),
+		shutdown:       make(chan os.Signal, 1),
+		stalls:         make(chan bool, 1),
+		capture:        NewFrameCapture(display, Settings.FrameCaptureCount),
+		predictions:    NewPredictions(),
+		signups:        &SignupQueue{},
+		crowd:          NewCrowdInput(time.Now),
+	}
+
+	this.hill = NewHillQueue(this.tournamentProfile)
+
+	this.clock.SetScale(Settings.TimeScale)
+	if this.clock.Scale() != 1 {
+		log.Print("Game time runs at ", this.clock.Scale(), "x")
+	}
+
+	this.states.OnEnter(PhaseIdle, this.enterIdle)
+	this.states.OnUpdate(PhaseIdle, this.updateIdle)
+	this.states.OnUpdate(PhaseWaitingForPlayers, this.updateWaitingForPlayers)
+	this.states.OnEnter(PhaseCountdown, this.enterCountdown)
+	this.states.OnUpdate(PhaseCountdown, this.updateCountdown)
+	this.states.OnUpdate(PhaseRally, this.updateRally)
+	this.states.OnEnter(PhasePointScored, this.enterPointScored)
+	this.states.OnEnter(PhaseGameOver, this.enterGameOver)
+	this.states.OnUpdate(PhaseGameOver, this.updateGameOver)
+	this.states.OnEnter(PhaseLeaderboard, this.enterLeaderboard)
+	this.states.OnUpdate(PhaseLeaderboard, this.updateLeaderboard)
+
+	return this
+}
+
+// Run the game loop until a signal is received on shutdown
+func (this *game) run() {
+
+	scene := NewMatrixScene("boot", Settings.LedCount, Settings.MatrixRows)
+	this.boot = NewBoot(scene.Field(), 2)
+	scene.Add(this.boot)
+	this.show(scene, 0)
+	this.output = this.display
+
+	curTime := this.wallClock.Now()
+	timer := NewFrameTimer(curTime)
+
+	this.ticks = time.NewTicker(this.governor.FrameTime())
+	defer this.ticks.Stop()
+
+	for {
+		select {
+		case sig := <-this.shutdown:
+			log.Print("Captured ", sig, ", shutting down")
+			this.fadeOut(this.ticks)
+			return
+		case <-this.stalls:
+			this.restart()
+			continue
+		case choice := <-this.playerChoices:
+			this.choosePlayers(choice)
+			continue
+		case enabled := <-this.debugRequests:
+			this.setDebug(enabled)
+			continue
+		case reply := <-this.stateRequests:
+			reply <- this.state()
+			continue
+		case request := <-this.notifyRequests:
+			request.shown <- this.notify(request.notification)
+			continue
+		case <-this.nextTick():
+		}
+
+		curTime = this.wallClock.Now()
+		this.frame(timer.Elapsed(curTime), curTime)
+	}
+}
+
+// Channel the next frame is started from, a simulated clock is moved forward a frame and doesn't wait
+func (this *game) nextTick() <-chan time.Time {
+
+	simulated, ok := this.wallClock.(*SimulatedClock)
+	if !ok {
+		return this.ticks.C
+	}
+
+	simulated.Advance(this.governor.FrameTime())
+	tick := make(chan time.Time, 1)
+	tick <- simulated.Now()
+	return tick
+}
+
+// Move the game forward by wallDt seconds and render it, a panic is reported and the game restarted instead of exiting
+func (this *game) frame(wallDt float64, curTime time.Time) {
+
+	defer this.catchPanic()
+
+	if wallDt > 0 {
+		GameMetrics.Observe("fps", 1/wallDt)
+	}
+
+	// a long frame slows the game down rather than moving the ball past the paddle
+	if maxDt := this.governor.MaxDt(); wallDt > maxDt {
+		wallDt = maxDt
+	}
+
+	if this.boot != nil {
+		this.beat("booting")
+	} else {
+		this.beat(this.states.Phase().String())
+	}
+
+	if this.boot != nil {
+		this.scenes.Animate(wallDt)
+		if this.boot.TimeRemaining() <= 0 {
+			this.boot = nil
+			this.states.Start()
+		}
+		this.render(this.output, curTime)
+		return
+	}
+
+	output := this.output
+	if this.isQuiet(curTime) {
+		if Settings.QuietBrightness <= 0 {
+			this.suspend()
+			return
+		}
+		output = this.quietOutput()
+	}
+	this.blanked = false
+
+	this.updateEncoder()
+	this.updatePause(wallDt)
+	if this.clock.Paused() {
+		this.render(this.pausedOutput(), curTime)
+		return
+	}
+
+	dt := this.clock.Advance(wallDt)
+	this.states.Update(dt)
+	this.checkButtons()
+
+	this.render(output, curTime)
+}
+
+// Log when rendering starts or stops failing, frames that fail are dropped and the watchdog resets a display that
+// stops responding, so the game carries on rather than stopping
+func (this *game) checkDisplay() {
+
+	fallible, ok := this.display.(FallibleDisplay)
+	if !ok {
+		return
+	}
+	message := ""
+	if err := fallible.RenderError(); err != nil {
+		message = err.Error()
+	}
+	if message == this.displayError {
+		return
+	}
+	if message == "" {
+		log.Print("Frames are shown again")
+	} else {
+		log.Print("Rendering: ", message)
+	}
+	this.displayError = message
+}
+
+// Log when reading the buttons starts or stops failing, a button that can't be read stays released rather than
+// stopping the game
+func (this *game) checkButtons() {
+
+	fallible, ok := this.buttons.(FallibleButtonInput)
+	if !ok {
+		return
+	}
+	message := ""
+	if err := fallible.ReadError(); err != nil {
+		message = err.Error()
+	}
+	if message == this.buttonError {
+		return
+	}
+	if message == "" {
+		log.Print("Buttons can be read again")
+	} else {
+		log.Print("Reading buttons: ", message)
+	}
+	this.buttonError = message
+}
+
+// Render the current scene to output, lowering the frame rate if the frame took too long
+func (this *game) render(output Display, frameStart time.Time) {
+
+	this.capture.Display = output
+	this.scenes.RenderTo(this.capture)
+	this.checkDisplay()
+
+	work := this.wallClock.Now().Sub(frameStart)
+	GameMetrics.Observe("frame_latency_ms", work.Seconds()*1000)
+
+	changed := this.governor.Update(work.Seconds())
+	if this.thermal != nil || this.ups != nil {
+		limit := Settings.MaxFPS
+		if this.thermal != nil {
+			limit = this.thermal.FPSLimit(Settings.MinFPS, Settings.MaxFPS)
+		}
+		if this.ups != nil {
+			limit = this.ups.FPSLimit(limit)
+		}
+		changed = this.governor.LimitFPS(limit) || changed
+	}
+	if changed {
+		log.Print("Frame rate changed to ", int(this.governor.FPS()))
+		this.ticks.Reset(this.governor.FrameTime())
+	}
+	GameMetrics.Observe("target_fps", this.governor.FPS())
+}
+
+// Fade the last frame to black over ShutdownFadeSeconds
+func (this *game) fadeOut(ticks *time.Ticker) {
+
+	faded := NewDimmedDisplay(this.display, 1)
+
+	startTime := time.Now()
+	for _ = range ticks.C {
+		elapsed := time.Since(startTime).Seconds()
+		if elapsed >= Settings.ShutdownFadeSeconds {
+			break
+		}
+		this.beat("shutting down")
+		faded.SetBrightness(1 - elapsed/Settings.ShutdownFadeSeconds)
+		this.scenes.RenderTo(faded)
+	}
+
+	this.display.Render(make([]RGBA, Settings.Leds()))
+}
+
+// Show scene, fading from the previous scene over fadeDuration seconds
+func (this *game) show(scene *Scene, fadeDuration float64) {
+	if this.governor.Overloaded() {
+		// a crossfade renders two scenes every frame
+		fadeDuration = 0
+	}
+	if tint := CurrentTheme().Tint; tint.A > 0 {
+		scene.Add(NewTint(tint))
+	}
+	this.addDebugOverlay(scene)
+	this.scenes.Show(scene, fadeDuration)
+	this.field = scene.Field()
+}
+
+// Animate the scenes by dt seconds, letting drawables react to how the game stands
+func (this *game) animate(dt float64) {
+
+	context := AnimateContext{Phase: this.states.Phase()}
+	playing := context.Phase == PhaseRally || context.Phase == PhasePointScored || context.Phase == PhaseGameOver
+	if playing && this.mode != nil {
+		if mode, ok := this.mode.(StatsGameMode); ok {
+			stats := mode.Stats()
+			context.LeftScore, context.RightScore = stats.LeftScore, stats.RightScore
+		}
+		if mode, ok := this.mode.(ContextGameMode); ok {
+			mode.UpdateContext(&context)
+		}
+	}
+
+	this.scenes.AnimateInContext(dt, context)
+}
+
+// Show the intro animation
+func (this *game) enterIdle(phase Phase) {
+
+	this.stopMatchPointReplay()
+	this.showIntro()
+	this.output = this.display
+	this.clockShown = false
+	this.notification = nil
+}
+
+// Show the attract backgrounds, and who is up next while a tournament is running
+func (this *game) showIntro() {
+
+	scene := NewMatrixScene("intro", Settings.LedCount, Settings.MatrixRows)
+	backgrounds := CurrentTheme().Backgrounds
+	if len(backgrounds) == 0 {
+		backgrounds = strings.Fields(Settings.AttractBackgrounds)
+	}
+	scene.Add(NewBackgroundRotation(scene.Field(), backgrounds, Settings.AttractDwellSeconds, Settings.AttractFadeSeconds, 1))
+	this.showUpNext(scene)
+	this.showCharge(scene)
+
+	this.show(scene, Settings.SceneFadeSeconds)
+}
+
+// Show the time of day along the strip, for when the game is left alone as a hallway light
+func (this *game) showClock() {
+
+	log.Print("Showing the clock")
+	scene := NewMatrixScene("clock", Settings.LedCount, Settings.MatrixRows)
+	scene.Add(NewAmbientClock(scene.Field(), this.wallClock.Now))
+	this.showCharge(scene)
+	this.show(scene, Settings.AttractFadeSeconds)
+	this.clockShown = true
+}
+
+// Add the charge of the battery to an idle scene, if there's a UPS HAT
+func (this *game) showCharge(scene *Scene) {
+	if this.ups != nil {
+		scene.Add(NewChargeIndicator(scene.Field(), this.ups.Battery))
+	}
+}
+
+// Wait for a button press, or show a demo game if nobody plays for a while
+func (this *game) updateIdle(dt float64) {
+
+	if runtime.GOOS == "windows" || this.buttons.LeftButton() || this.buttons.RightButton() {
+		this.states.Transition(PhaseWaitingForPlayers)
+		return
+	}
+
+	if this.notification != nil {
+		this.animate(dt)
+		if this.notification.TimeRemaining() <= 0 {
+			this.endNotification()
+		}
+		return
+	}
+
+	// quiet hours keep the dimmed intro up, the clock would light the field and a demo game would leave idle and with it
+	// the quiet output
+	if this.quietHours.Contains(this.wallClock.Now()) {
+		if this.clockShown {
+			this.clockShown = false
+			this.showIntro()
+		}
+		this.animate(dt)
+		return
+	}
+
+	// the clock stays up until a button is pressed, instead of the demo
+	if Settings.ClockIdleMinutes > 0 && this.states.TimeInPhase() > Settings.ClockIdleMinutes*60 {
+		if !this.clockShown {
+			this.showClock()
+		}
+		this.animate(dt)
+		return
+	}
+
+	if Settings.DemoIdleMinutes > 0 && this.states.TimeInPhase() > Settings.DemoIdleMinutes*60 {
+		this.startGame(gameOptions{mode: "classic", config: GameConfig{Demo: true}}, Settings.SceneFadeSeconds)
+		this.output = NewDimmedDisplay(this.display, Settings.DemoBrightness)
+		this.states.Transition(PhaseRally)
+		return
+	}
+
+	this.animate(dt)
+}
+
+// Keep showing the intro until the button that woke the game is released, or open the menus if it is held
+func (this *game) updateWaitingForPlayers(dt float64) {
+
+	buttonDown := this.buttons.LeftButton() || this.buttons.RightButton()
+
+	if !this.menuOpen {
+		if !buttonDown {
+			this.states.Transition(PhaseCountdown)
+			return
+		}
+		if this.states.TimeInPhase() >= Settings.LongPressSeconds {
+			this.openMenus()
+		}
+	} else {
+		shortPress, longPress := this.menuPress.Update(buttonDown, dt)
+		if shortPress {
+			this.menus[this.menuIndex].Next()
+		} else if longPress && this.chooseMenuOption() {
+			return
+		}
+	}
+
+	this.animate(dt)
+}
+
+// Choose the highlighted option and show the next menu, returns true if that closed the menus and left the phase
+func (this *game) chooseMenuOption() bool {
+
+	if this.menuIndex == modeMenu && this.menus[modeMenu].Selected().Name == leaderboardOption {
+		this.menuOpen = false
+		this.states.Transition(PhaseLeaderboard)
+		return true
+	}
+	this.menuIndex++
+	if this.menuIndex >= len(this.menus) {
+		this.menuOpen = false
+		this.applyMenus()
+		this.states.Transition(PhaseCountdown)
+		return true
+	}
+	this.showMenu()
+	return false
+}
+
+// Move through the open menus with the knob and choose with its button, or open the menus with the button while idle.
+// The rest of the time turning the knob turns the brightness
+func (this *game) updateEncoder() {
+
+	if this.encoder == nil {
+		return
+	}
+	clicks, presses := this.encoder.Take()
+	phase := this.states.Phase()
+
+	if this.menuOpen && phase == PhaseWaitingForPlayers {
+		menu := this.menus[this.menuIndex]
+		for ; clicks > 0; clicks-- {
+			menu.Next()
+		}
+		for ; clicks < 0; clicks++ {
+			menu.Previous()
+		}
+		if presses > 0 {
+			this.chooseMenuOption()
+		}
+		return
+	}
+
+	if presses > 0 && phase == PhaseIdle {
+		this.states.Transition(PhaseWaitingForPlayers)
+		this.openMenus()
+		return
+	}
+
+	if clicks != 0 && this.brightness != nil {
+		log.Print("Brightness turned to ", this.brightness.Adjust(float64(clicks)*Settings.EncoderBrightnessStep))
+	}
+}
+
+// Position of each menu in the order they are shown
+const (
+	modeMenu = iota
+	difficultyMenu
+	backgroundMenu
+	leftPlayerMenu
+	rightPlayerMenu
+	themeMenu
+	menuCount
+)
+
+// Build the menus for choosing the mode, AI difficulty, background, players of a game, and the theme of every scene
+func newMenus(profiles *Profiles) []*Menu {
+
+	modes := NewMenu("mode", append(GameModeOptions(), MenuOption{leaderboardOption, RGBA{255, 200, 0, 255}}))
+
+	difficultyOptions := []MenuOption{}
+	for index, personality := range AIPersonalities {
+		red := uint8(255 * (index + 1) / len(AIPersonalities))
+		difficultyOptions = append(difficultyOptions, MenuOption{personality.Name, RGBA{red, 255 - red, 0, 255}})
+	}
+	difficulty := NewMenu("difficulty", difficultyOptions)
+
+	backgroundOptions := []MenuOption{}
+	backgroundNames := BackgroundNames()
+	for index, name := range backgroundNames {
+		blue := uint8(255 * (index + 1) / len(backgroundNames))
+		backgroundOptions = append(backgroundOptions, MenuOption{name, RGBA{0, 255 - blue, blue, 255}})
+	}
+	backgrounds := NewMenu("background", backgroundOptions)
+
+	leftPlayer := NewMenu("left player", profiles.MenuOptions())
+	rightPlayer := NewMenu("right player", profiles.MenuOptions())
+
+	themes := NewMenu("theme", ThemeOptions())
+
+	menus := make([]*Menu, menuCount)
+	menus[modeMenu], menus[difficultyMenu], menus[backgroundMenu] = modes, difficulty, backgrounds
+	menus[leftPlayerMenu], menus[rightPlayerMenu], menus[themeMenu] = leftPlayer, rightPlayer, themes
+	return menus
+}
+
+// Start choosing options with the first menu
+func (this *game) openMenus() {
+
+	this.menuOpen = true
+	this.menuIndex = modeMenu
+
+	// profiles may have been created from the web, and themes installed by an update, since the menus were last shown
+	this.menus[leftPlayerMenu].SetOptions(this.profiles.MenuOptions())
+	this.menus[rightPlayerMenu].SetOptions(this.profiles.MenuOptions())
+	this.menus[themeMenu].SetOptions(ThemeOptions())
+	this.menuPress = NewPressDetector(Settings.LongPressSeconds)
+
+	// the press that opened the menus shouldn't also choose an option
+	this.menuPress.Update(true, 0)
+	this.menuPress.Consume()
+
+	this.showMenu()
+}
+
+// Show the menu at menuIndex
+func (this *game) showMenu() {
+
+	menu := this.menus[this.menuIndex]
+	log.Print("Showing ", menu.Name, " menu")
+
+	scene := NewMatrixScene(menu.Name, Settings.LedCount, Settings.MatrixRows)
+	scene.Add(NewMenuDisplay(scene.Field(), menu))
+	this.show(scene, Settings.SceneFadeSeconds)
+}
+
+// Set the options of the next games from the menus
+func (this *game) applyMenus() {
+
+	options := gameOptions{
+		mode:       this.menus[modeMenu].Selected().Name,
+		config:     GameConfig{Difficulty: this.menus[difficultyMenu].Selected().Name},
+		background: this.menus[backgroundMenu].Selected().Name,
+	}
+	options.config.LeftProfile, _ = this.profiles.Find(this.menus[leftPlayerMenu].Selected().Name)
+	options.config.RightProfile, _ = this.profiles.Find(this.menus[rightPlayerMenu].Selected().Name)
+	if options.config.LeftProfile.SamePlayer(options.config.RightProfile) {
+		log.Print(options.config.RightProfile.Name, " can't play both sides, the right player is a guest")
+		options.config.RightProfile = GuestProfile
+		this.menus[rightPlayerMenu].SelectName(GuestProfileName)
+	}
+	UseTheme(this.menus[themeMenu].Selected().Name)
+
+	if options.mode == "ghost" || options.mode == "replay" {
+		lastGame, err := LoadGameRecording(Settings.RecordingPath)
+		if err != nil {
+			log.Print(err)
+			options.mode = "classic"
+		} else if options.mode == "ghost" {
+			options.config.Ghost = lastGame
+		} else {
+			options.config.Replay = lastGame
+		}
+	}
+
+	log.Print("Chose ", options.mode, " against ", options.config.Difficulty, " on ", options.background,
+		" for ", options.config.LeftProfile.Name, " and ", options.config.RightProfile.Name, " in the ",
+		CurrentTheme().Name, " theme")
+	this.options = options
+}
+
+// Show an animation to start the game
+func (this *game) enterCountdown(phase Phase) {
+
+	scene := NewMatrixScene("countdown", Settings.LedCount, Settings.MatrixRows)
+	this.countdown = NewCountdown(scene.Field(), 2)
+	scene.Add(this.countdown)
+	this.showUpNext(scene)
+	this.showPredictions(scene)
+	this.show(scene, Settings.SceneFadeSeconds)
+	this.announceLeague()
+
+	go PlaySound(GAMESTART)
+}
+
+// Start the game once the countdown finishes, the next tournament match while a tournament is running
+func (this *game) updateCountdown(dt float64) {
+
+	this.animate(dt)
+
+	if this.countdown.TimeRemaining() <= 0 {
+		options, ok := this.tournamentOptions()
+		if !ok {
+			options = this.options
+		}
+		this.predictions.Close()
+		this.startGame(options, 0)
+		this.states.Transition(PhaseRally)
+	}
+}
+
+// Set up the scene and the mode for a new game, fading in over fadeDuration seconds
+func (this *game) startGame(options gameOptions, fadeDuration float64) {
+
+	options.config.Hill = this.hill
+	options.config.Crowd = this.crowd
+	this.current = options
+	this.gameStart = this.clock.Time()
+	this.leftScore, this.rightScore = 0, 0
+	this.idleTime = 0
+
+	mode, ok := NewGameMode(options.mode)
+	if !ok {
+		log.Print("Unknown game mode ", options.mode, ", playing classic")
+		mode, _ = NewGameMode("classic")
+	}
+	this.mode = mode
+
+	// only the modes played around a loop get one, the ball of every other mode has to go past a player to score
+	this.show(NewMatrixScene("game", Settings.LedCount, Settings.MatrixRows), fadeDuration)
+	this.field.SetTopology(FieldTopologyFor(mode, Settings))
+
+	if background := NewBackground(options.background, this.field, 1); background != nil {
+		this.field.Add(background)
+	}
+	this.mode.Setup(this.field, options.config)
+	this.timestep = NewFixedTimestep(Settings.PhysicsHz)
+	this.timestep.ScaleMaxSteps(this.clock.Scale())
+}
+
+// Let the mode move the game forward, following the outcome of each tick
+func (this *game) updateRally(dt float64) {
+
+	if this.abandoned(dt) {
+		this.abandon()
+		return
+	}
+
+	if this.current.config.Demo && (this.buttons.LeftButton() || this.buttons.RightButton()) {
+		// a real player showed up, stop the demo and start a game
+		this.states.Transition(PhaseIdle)
+		this.states.Transition(PhaseWaitingForPlayers)
+		return
+	}
+
+	// play in fixed steps so hits don't depend on the frame rate, drawing the ball between the last two
+	steps := this.timestep.Advance(dt)
+	for step := 0; step < steps; step++ {
+		if !this.stepRally(this.timestep.Step()) {
+			break
+		}
+	}
+	this.scenes.Interpolate(this.timestep.Alpha())
+}
+
+// Move the game forward by a single step of dt seconds, returns false once the rally is over
+func (this *game) stepRally(dt float64) bool {
+
+	outcome := StepRally(this.mode, this.buttons, dt, this.animate)
+	next, over := PhaseAfter(outcome)
+	if !over {
+		return true
+	}
+
+	if outcome == GamePointScored {
+		this.showGoalEffects()
+	} else {
+		this.outcome = outcome
+	}
+	this.states.Transition(next)
+	return false
+}
+
+// Returns true if nobody has pushed a button for AbandonSeconds during a real game
+func (this *game) abandoned(dt float64) bool {
+
+	if this.current.config.Demo || this.current.config.Replay != nil || Settings.AbandonSeconds <= 0 {
+		return false
+	}
+
+	if this.buttons.LeftButton() || this.buttons.RightButton() || this.crowd.LeftButton() || this.crowd.RightButton() {
+		this.idleTime = 0
+		return false
+	}
+
+	this.idleTime += dt
+	return this.idleTime >= Settings.AbandonSeconds
+}
+
+// Save what was played of a game that was walked away from and go back to the intro
+func (this *game) abandon() {
+
+	log.Print("Game abandoned")
+
+	if summarized, ok := this.mode.(SummarizedGameMode); ok {
+		log.Print(summarized.Summary())
+	}
+
+	if recorded, ok := this.mode.(RecordedGameMode); ok {
+		recording := recorded.Recording()
+		recording.Abandoned = true
+		recording.Duration = recording.Time()
+		if err := recording.Save(Settings.AbandonedRecordingPath); err != nil {
+			log.Print(err)
+		}
+	}
+	if !this.current.config.Demo {
+		this.predictions.Discard()
+
+		// what was played counts toward the records, but not as a game won or lost
+		match := this.playedMatch()
+		match.Abandoned = true
+		this.recordMatch(match)
+	}
+
+	this.states.Transition(PhaseIdle)
+}
+
+// The mode has already served again, carry on with the rally
+func (this *game) enterPointScored(phase Phase) {
+	this.states.Transition(PhaseRally)
+}
+
+// Record the result and show the winner
+func (this *game) enterGameOver(phase Phase) {
+
+	if this.current.config.Demo {
+		this.states.Transition(PhaseIdle)
+		return
+	}
+
+	summary := ""
+	if summarized, ok := this.mode.(SummarizedGameMode); ok {
+		summary = summarized.Summary()
+	}
+	if this.updateHighScores() {
+		summary = strings.TrimSpace(summary + " New high score!")
+	}
+	if crowd := this.resolvePredictions(); crowd != "" {
+		summary = strings.TrimSpace(summary + " " + crowd)
+	}
+	if summary != "" {
+		log.Print(summary)
+		go PlayTTS(summary)
+	}
+
+	if this.outcome == GameFinished {
+		this.states.Transition(PhaseIdle)
+		return
+	}
+	this.leftPlayerWon = this.outcome == GameLeftWon
+
+	if recorded, ok := this.mode.(RecordedGameMode); ok {
+		recording := recorded.Recording()
+		recording.LeftWon = this.leftPlayerWon
+		recording.Duration = recording.Time()
+		if err := recording.Save(Settings.RecordingPath); err != nil {
+			log.Print(err)
+		}
+	}
+	this.updateRatings()
+	this.updateStats()
+	this.updateTournament()
+	if calledUp := this.callUpNext(); calledUp != "" {
+		go PlayTTS(calledUp)
+		summary = strings.TrimSpace(summary + " " + calledUp)
+	}
+
+	scene := NewMatrixScene("winner", Settings.LedCount, Settings.MatrixRows)
+	winner := this.current.config.RightProfile
+	if this.leftPlayerWon {
+		winner = this.current.config.LeftProfile
+	}
+	this.winner = newVictory(scene.Field(), this.leftPlayerWon, winner)
+	scene.Add(this.winner)
+	if summary != "" && scene.Field().Height() >= FontHeight {
+		scene.Add(NewTextBanner(scene.Field(), summary, RGBA{255, 255, 255, 255}, TextBannerSpeed))
+	}
+	this.replayMatchPoint(scene)
+	//go PlaySound(GAMEOVER)
+}
+
+// Replay match point, celebrate achievements and show where the ball was returned and missed, then show the
+// leaderboards once the winner has been shown, or return to idle after demo games
+func (this *game) updateGameOver(dt float64) {
+
+	this.animate(dt)
+	if this.updateMatchPointReplay(dt) {
+		return
+	}
+
+	if this.winner.TimeRemaining() <= 0 && !this.celebrate() && !this.showHeatmap() {
+		if Settings.LeaderboardSeconds > 0 && !this.current.config.Demo {
+			this.states.Transition(PhaseLeaderboard)
+		} else {
+			this.states.Transition(PhaseIdle)
+		}
+	}
+}
+
+// Update the Elo ratings after a game between two rated players
+func (this *game) updateRatings() {
+
+	rated, ok := this.mode.(RatedGameMode)
+	if !ok {
+		return
+	}
+
+	leftName, rightName, counts := rated.PlayerNames()
+	if !counts {
+		return
+	}
+
+	if this.leftPlayerWon {
+		this.ratings.RecordMatch(leftName, rightName)
+	} else {
+		this.ratings.RecordMatch(rightName, leftName)
+	}
+
+	if err := this.ratings.Save(); err != nil {
+		log.Print(err)
+	}
+}
+
+// Add a finished game to the match history and the stats, demo games don't count
+func (this *game) updateStats() {
+
+	if this.current.config.Demo {
+		return
+	}
+
+	match := this.playedMatch()
+	this.updateAchievements(match)
+	this.queueHeatmap(match)
+	this.updateLeague(match)
+	this.recordMatch(match)
+}
+
+// What was played of the current game, as it is kept in the match history
+func (this *game) playedMatch() stats.Match {
+
+	match := stats.Match{
+		Time:     this.wallClock.Now(),
+		Mode:     this.current.mode,
+		LeftWon:  this.leftPlayerWon,
+		Duration: this.clock.Time() - this.gameStart,
+	}
+	if rated, ok := this.mode.(RatedGameMode); ok {
+		match.Left, match.Right, _ = rated.PlayerNames()
+	}
+	if recorded, ok := this.mode.(StatsGameMode); ok {
+		gameStats := recorded.Stats()
+		match.LeftScore, match.RightScore = gameStats.LeftScore, gameStats.RightScore
+		match.Rallies = gameStats.Rallies
+		match.FastestReturn = gameStats.FastestReturn
+		match.Hits = gameStats.Hits
+	}
+	return match
+}
+
+// Append match to the history and add it to the stats
+func (this *game) recordMatch(match stats.Match) {
+
+	if err := this.history.Append(match); err != nil {
+		log.Print(err)
+	}
+	this.stats.Record(match.Game())
+	if err := this.stats.Save(); err != nil {
+		log.Print(err)
+	}
+}
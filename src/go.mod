@@ -0,0 +1,7 @@
+module pongpi
+
+go 1.21
+
+require github.com/brandonagr/pongpi/src/pong v0.0.0-00010101000000-000000000000
+
+replace github.com/brandonagr/pongpi/src/pong => ./pong
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"log"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+// Play the effects the players chose for the point just won at their ends, the scorer's score effect at their end and
+// the other player's concede effect at theirs, worked out from the score of modes that keep one
+func (this *game) showGoalEffects() {
+
+	recorded, ok := this.mode.(StatsGameMode)
+	if !ok || Settings.GoalEffectSeconds <= 0 {
+		return
+	}
+	gameStats := recorded.Stats()
+	leftScored := gameStats.LeftScore > this.leftScore
+	rightScored := gameStats.RightScore > this.rightScore
+	this.leftScore, this.rightScore = gameStats.LeftScore, gameStats.RightScore
+	if leftScored == rightScored {
+		return
+	}
+
+	this.addGoalEffect(this.current.config.LeftProfile, true, leftScored)
+	this.addGoalEffect(this.current.config.RightProfile, false, rightScored)
+}
+
+// Add the effect of the player with profile at the left or right end for scoring or conceding, in their color
+func (this *game) addGoalEffect(profile PlayerProfile, isLeft, scored bool) {
+
+	theme := CurrentTheme()
+	spec := theme.GoalEffect(profile, scored)
+	if spec == "" {
+		return
+	}
+
+	bound := EffectParams{"color": HexColor(theme.PlayerColor(profile, isLeft))}
+	effect, err := NewBoundEffect(spec, this.field, 15, bound)
+	if err != nil {
+		log.Print("Goal effect ", spec, ": ", err)
+		return
+	}
+	this.field.Add(NewGoalEffect(this.field, effect, isLeft, Settings.GoalEffectLeds, Settings.GoalEffectSeconds))
+}
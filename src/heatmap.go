@@ -0,0 +1,44 @@
+package main
+
+import (
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+	"github.com/brandonagr/pongpi/src/pong/stats"
+)
+
+// Remember where the ball was returned and missed over match, to show once the winner has been shown
+func (this *game) queueHeatmap(match stats.Match) {
+
+	this.heatmapReturns, this.heatmapMisses = nil, nil
+	if Settings.HeatmapSeconds <= 0 {
+		return
+	}
+	for _, hit := range match.Hits {
+		if hit.Returned {
+			this.heatmapReturns = append(this.heatmapReturns, hit.Position)
+		} else {
+			this.heatmapMisses = append(this.heatmapMisses, hit.Position)
+		}
+	}
+}
+
+// Show the heatmap of the last match once, returns false once it is done or if there was nothing to show
+func (this *game) showHeatmap() bool {
+
+	if this.heatmap != nil && this.heatmap.TimeRemaining() > 0 {
+		return true
+	}
+	this.heatmap = nil
+
+	if len(this.heatmapReturns) == 0 && len(this.heatmapMisses) == 0 {
+		return false
+	}
+
+	scene := NewMatrixScene("heatmap", Settings.LedCount, Settings.MatrixRows)
+	this.heatmap = NewHitHeatmap(scene.Field(), this.heatmapReturns, this.heatmapMisses, Settings.HeatmapSeconds)
+	this.heatmapReturns, this.heatmapMisses = nil, nil
+	scene.Add(this.heatmap)
+	this.show(scene, Settings.SceneFadeSeconds)
+
+	return true
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	"github.com/brandonagr/pongpi/src/pong/stats"
+)
+
+// Record the score of a mode played for a high score under the names of the players, games that didn't score don't
+// count, returns true if it's the best the mode has seen
+func (this *game) updateHighScores() bool {
+
+	scored, ok := this.mode.(ScoredGameMode)
+	if !ok || scored.Score() <= 0 {
+		return false
+	}
+
+	names := []string{}
+	for _, profile := range []PlayerProfile{this.current.config.LeftProfile, this.current.config.RightProfile} {
+		if !profile.IsGuest() {
+			names = append(names, profile.Name)
+		}
+	}
+	if len(names) == 0 {
+		names = append(names, "Guest")
+	}
+
+	score := stats.HighScore{Name: strings.Join(names, " and "), Score: scored.Score(), Time: this.wallClock.Now()}
+	rank, ok := this.highScores.Record(this.current.mode, score)
+	if !ok {
+		return false
+	}
+	log.Print(score.Name, " scored ", score.Score, " at ", this.current.mode, ", number ", rank+1, " of all time")
+
+	if err := this.highScores.Save(); err != nil {
+		log.Print(err)
+	}
+	return rank == 0
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Time the web server waits for the game loop to report its state before answering without it
+var stateTimeout = time.Second
+
+// A drawable on the field of the current scene
+type drawableState struct {
+	Type   string
+	ZIndex ZIndex
+}
+
+// Live state of the game, served for `pong inspect`
+type gameState struct {
+
+	// false if the game loop didn't answer in time, only the fields after Drawables are filled in then
+	Responding bool
+
+	Phase       string
+	TimeInPhase float64
+	Paused      bool
+
+	// mode of the game being played and its score, if it keeps one
+	Mode                  string
+	LeftScore, RightScore int
+
+	// real buttons and the type reading them
+	LeftButton, RightButton bool
+	Input                   string
+
+	Drawables []drawableState
+
+	// how frames are keeping up
+	FPS, TargetFPS, FrameLatencyMs float64
+
+	// last time the game loop fed the watchdog and what it was doing, empty without a watchdog
+	LastBeat time.Time
+	Activity string
+}
+
+// State of the game, called on the game loop
+func (this *game) state() gameState {
+
+	state := gameState{
+		Responding:  true,
+		Phase:       this.states.Phase().String(),
+		TimeInPhase: this.states.TimeInPhase(),
+		Paused:      this.clock.Paused(),
+		LeftButton:  this.buttons.LeftButton(),
+		RightButton: this.buttons.RightButton(),
+		Input:       fmt.Sprintf("%T", this.buttons),
+	}
+	if this.boot != nil {
+		state.Phase = "booting"
+	}
+
+	if phase := this.states.Phase(); this.mode != nil && (phase == PhaseRally || phase == PhaseGameOver) {
+		state.Mode = this.current.mode
+		if recorded, ok := this.mode.(StatsGameMode); ok {
+			gameStats := recorded.Stats()
+			state.LeftScore, state.RightScore = gameStats.LeftScore, gameStats.RightScore
+		}
+	}
+
+	if scene := this.scenes.Current(); scene != nil {
+		for _, drawable := range scene.Field().Drawables() {
+			state.Drawables = append(state.Drawables, drawableState{fmt.Sprintf("%T", drawable), drawable.ZIndex()})
+		}
+	}
+
+	return state
+}
+
+// Serve the state of the game as json from /api/state
+func (this *game) stateHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(this.requestState())
+}
+
+// Ask the game loop for the state of the game, reporting it isn't responding if it takes too long, with how frames
+// are keeping up filled in from the metrics
+func (this *game) requestState() gameState {
+
+	state := gameState{}
+	reply := make(chan gameState, 1)
+	select {
+	case this.stateRequests <- reply:
+		select {
+		case state = <-reply:
+		case <-time.After(stateTimeout):
+		}
+	case <-time.After(stateTimeout):
+	}
+
+	if metric, ok := GameMetrics.Get("fps"); ok {
+		state.FPS = metric.Average
+	}
+	if metric, ok := GameMetrics.Get("target_fps"); ok {
+		state.TargetFPS = metric.Last
+	}
+	if metric, ok := GameMetrics.Get("frame_latency_ms"); ok {
+		state.FrameLatencyMs = metric.Average
+	}
+	if this.watchdog != nil {
+		state.LastBeat, state.Activity = this.watchdog.LastBeat()
+	}
+	return state
+}
+
+// Print the state of the game running on this machine, for `pong inspect [-address host:port] [-watch interval]`,
+// returns the exit code
+func inspect(args []string) int {
+
+	flags := flag.NewFlagSet("inspect", flag.ExitOnError)
+	localAddress := Settings.WebAddress
+	if strings.HasPrefix(localAddress, ":") {
+		localAddress = "localhost" + localAddress
+	}
+	address := flags.String("address", localAddress, "address of the web server of the running game")
+	watch := flags.Duration("watch", 0, "print the state again every interval until interrupted")
+	flags.Parse(args)
+	if !strings.Contains(*address, "://") {
+		*address = "http://" + *address
+	}
+
+	for {
+		state := gameState{}
+		response, err := http.Get(*address + "/api/state")
+		if err == nil {
+			err = json.NewDecoder(response.Body).Decode(&state)
+			response.Body.Close()
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Couldn't read the state of the game:", err)
+			return 1
+		}
+
+		printState(state)
+		if *watch <= 0 {
+			return 0
+		}
+		time.Sleep(*watch)
+		fmt.Println()
+	}
+}
+
+// Print state as aligned columns
+func printState(state gameState) {
+
+	buttonNames := map[bool]string{false: "up", true: "down"}
+	out := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+
+	if !state.Responding {
+		fmt.Fprintln(out, "Loop\tNOT RESPONDING")
+	} else {
+		fmt.Fprintf(out, "Phase\t%v for %.1fs\n", state.Phase, state.TimeInPhase)
+		if state.Paused {
+			fmt.Fprintln(out, "Paused\tyes")
+		}
+		if state.Mode != "" {
+			fmt.Fprintf(out, "Game\t%v, left %v - right %v\n", state.Mode, state.LeftScore, state.RightScore)
+		}
+		fmt.Fprintf(out, "Buttons\tleft %v, right %v from %v\n", buttonNames[state.LeftButton], buttonNames[state.RightButton], state.Input)
+	}
+	fmt.Fprintf(out, "Frames\t%.1f fps of %.0f, %.2f ms each\n", state.FPS, state.TargetFPS, state.FrameLatencyMs)
+	if !state.LastBeat.IsZero() {
+		fmt.Fprintf(out, "Watchdog\tfed %v ago while %v\n", time.Since(state.LastBeat).Round(time.Millisecond), state.Activity)
+	}
+
+	if len(state.Drawables) > 0 {
+		fmt.Fprintln(out, "Drawables\tzindex\ttype")
+		for _, drawable := range state.Drawables {
+			fmt.Fprintf(out, "\t%v\t%v\n", drawable.ZIndex, drawable.Type)
+		}
+	}
+	out.Flush()
+}
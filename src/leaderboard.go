@@ -0,0 +1,74 @@
+package main
+
+import (
+	"time"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+// Option added to the mode menu that shows the leaderboards instead of starting a game
+const leaderboardOption = "leaderboard"
+
+// Most players shown on a leaderboard
+var leaderboardSize int = 5
+
+// Colors of players whose profile doesn't have one, by place on the leaderboard
+var leaderboardColors = []RGBA{{255, 200, 0, 255}, {192, 192, 192, 255}, {205, 127, 50, 255}, {128, 0, 255, 255}, {0, 128, 255, 255}}
+
+// Show the leaders of the day, followed by the leaders of the week
+func (this *game) enterLeaderboard(phase Phase) {
+
+	now := this.wallClock.Now()
+	daily := this.leaderboardScene("daily leaderboard", now, now)
+	this.weeklyLeaderboard = this.leaderboardScene("weekly leaderboard", now.AddDate(0, 0, -6), now)
+	this.show(daily, Settings.SceneFadeSeconds)
+}
+
+// Switch to the weekly leaderboard halfway through, and return to idle once both have been shown
+func (this *game) updateLeaderboard(dt float64) {
+
+	duration := leaderboardSeconds()
+	if this.weeklyLeaderboard != nil && this.states.TimeInPhase() >= duration/2 {
+		this.show(this.weeklyLeaderboard, Settings.SceneFadeSeconds)
+		this.weeklyLeaderboard = nil
+	}
+	if this.states.TimeInPhase() >= duration {
+		this.states.Transition(PhaseIdle)
+		return
+	}
+
+	this.scenes.Animate(dt)
+}
+
+// Scene showing the players with the most wins over the days from first to last
+func (this *game) leaderboardScene(name string, first, last time.Time) *Scene {
+
+	bars := []LeaderboardBar{}
+	for _, leader := range this.stats.Leaderboard(first, last, leaderboardSize+2) {
+
+		// guests are recorded under the name of their side, which isn't anybody in particular
+		if leader.Name == Settings.LeftPlayerName || leader.Name == Settings.RightPlayerName || len(bars) == leaderboardSize {
+			continue
+		}
+
+		profile, _ := this.profiles.Find(leader.Name)
+		color, ok := profile.RGBA()
+		if !ok {
+			color = leaderboardColors[len(bars)%len(leaderboardColors)]
+		}
+		bars = append(bars, LeaderboardBar{color, leader.Wins})
+	}
+
+	scene := NewMatrixScene(name, Settings.LedCount, Settings.MatrixRows)
+	scene.Add(NewLeaderboard(scene.Field(), bars, leaderboardSeconds()/2))
+	return scene
+}
+
+// Seconds both leaderboards are shown for, shown from the menu even when they aren't shown after games
+func leaderboardSeconds() float64 {
+	if Settings.LeaderboardSeconds <= 0 {
+		return 6
+	}
+	return Settings.LeaderboardSeconds
+}
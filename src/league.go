@@ -0,0 +1,56 @@
+package main
+
+import (
+	"log"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	"github.com/brandonagr/pongpi/src/pong/stats"
+)
+
+// Announce if the players about to play have a league match scheduled against each other, or else who they are
+// scheduled to play next
+func (this *game) announceLeague() {
+
+	if _, _, ok := this.tournament.Next(); ok {
+		return
+	}
+	left, right := this.options.config.LeftProfile, this.options.config.RightProfile
+	if left.IsGuest() || right.IsGuest() {
+		return
+	}
+
+	announcement := ""
+	if _, ok := this.stats.LeagueFixture(left.Name, right.Name); ok {
+		announcement = "League match. " + left.Name + " against " + right.Name
+	} else {
+		for _, player := range []string{left.Name, right.Name} {
+			if next, ok := this.stats.NextLeagueFixture(player); ok {
+				opponent := next.Right
+				if opponent == player {
+					opponent = next.Left
+				}
+				announcement = player + "'s next league match is against " + opponent
+				break
+			}
+		}
+	}
+	if announcement != "" {
+		log.Print(announcement)
+		go PlayTTS(announcement)
+	}
+}
+
+// Count match in the league if it was rated and scheduled
+func (this *game) updateLeague(match stats.Match) {
+
+	rated, ok := this.mode.(RatedGameMode)
+	if !ok {
+		return
+	}
+	if _, _, counts := rated.PlayerNames(); !counts {
+		return
+	}
+	if this.stats.RecordLeague(match) {
+		log.Print("Recorded the league match between ", match.Left, " and ", match.Right)
+	}
+}
@@ -3,209 +3,409 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	_ "log"
 	_ "math"
+	"math/rand"
+	"net/http"
 	"os"
 	"os/signal"
-	. "pong"
-	. "pong/draw"
 	"runtime"
 	"runtime/pprof"
+	"syscall"
 	"time"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+	_ "github.com/brandonagr/pongpi/src/pong/effects/twinkle"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/breakout"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/circular"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/classic"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/coop"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/crowd"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/drill"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/fourplayer"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/hill"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/lanes"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/matrix"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/race"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/reaction"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/relay"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/replay"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/simon"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/snake"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/tugofwar"
+	"github.com/brandonagr/pongpi/src/pong/stats"
+	"github.com/brandonagr/pongpi/src/pong/status"
+	"github.com/brandonagr/pongpi/src/pong/tables"
 )
 
 var cpuProfile = flag.String("cpuprofile", "", "write cpu profile to file")
 var webDisplay = flag.Bool("webdisplay", false, "use webhost on localhost:8080 for the display")
+var ghostFile = flag.String("ghost", "", "play against the winner of a recorded game instead of a second player")
+var replayFile = flag.String("replay", "", "play back a recorded game on the strip, -mode replay plays back the last game")
+var drillMode = flag.Bool("drill", false, "run timing drills for the left player instead of games")
+var aiOpponent = flag.String("ai", "", "play against the named AI personality on the right side")
+var aiTeammate = flag.String("doubles", "", "add the named AI personality as a teammate on both sides")
+var gameMode = flag.String("mode", "classic", "name of the game mode to play")
+var exportFormat = flag.String("export", "", "write the match history to stdout as csv or json and exit")
+var seed = flag.Int64("seed", 0, "seed random numbers with this instead of the time, so a run can be repeated")
+var debugOverlay = flag.Bool("debug", false, "draw hit windows, ball speed and the phase of the game over the field")
+var chaos = flag.Bool("chaos", false, "simulate the display and buttons failing at the Chaos rates in the settings")
+var simulate = flag.Bool("simulate", false, "run on a simulated clock as fast as frames can be rendered instead of in real time")
+
+// Time between reads of the rotary encoder pins, short enough to catch every step of a quick turn
+var encoderPollTime = 2 * time.Millisecond
+
+// Check an AI personality name given on the command line
+func checkPersonality(name string) string {
+	if _, ok := FindAIPersonality(name); !ok {
+		log.Fatal("Unknown AI personality ", name)
+	}
+	return name
+}
 
 // Application entry point
 func main() {
 
+	log.SetOutput(io.MultiWriter(os.Stderr, recentEvents))
 	Settings.Read()
 
+	if len(os.Args) > 1 && os.Args[1] == "inspect" {
+		os.Exit(inspect(os.Args[2:]))
+	}
+
 	flag.Parse()
+	if *exportFormat != "" {
+		if err := stats.NewHistory(Settings.HistoryPath).Export(os.Stdout, *exportFormat); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	if *cpuProfile != "" {
 		f, err := os.Create(*cpuProfile)
 		if err != nil {
 			panic(err)
 		}
 		pprof.StartCPUProfile(f)
-		//defer pprof.StopCPUProfile() // line not executed because of how exiting from http.ListenAnServe is a process kill
-
-		// capture ctrl+c and stop CPU profiler
-		c := make(chan os.Signal, 1)
-		signal.Notify(c, os.Interrupt)
-		go func() {
-			for sig := range c {
-				fmt.Printf("captured %v, stopping profiler and exiting..", sig)
-				pprof.StopCPUProfile()
-				os.Exit(1)
-			}
-		}()
+		defer pprof.StopCPUProfile() // the game loop returns on ctrl+c so this gets run
 
 		fmt.Println("Start profiling")
 	}
 
 	log.Print("MinFrameTime is ", Settings.MinFrameTime)
 
-	var display Display
-	if *webDisplay || runtime.GOOS == "windows" {
-		display = NewWebDisplay(Settings)
-	} else {
-		display = NewLedDisplay(Settings)
+	if *seed != 0 {
+		UseRandSource(rand.NewSource(*seed))
 	}
 
-	buttons := NewGpioReader(Settings)
-
-	// should intro and game(play / dead / win) be different states in statemachine?
-
-	// loop forever
-	for {
-		runIntro(buttons, display)
-		runOpening(display)
-		winner, bounces := runGame(buttons, display)
-		go PlayTTS(fmt.Sprint("Game over. Score ", bounces))
-		runClosing(buttons, display, winner)
+	if err := tables.SetResolution(Settings.TableResolution); err != nil {
+		log.Fatal(err)
 	}
-}
-
-// Run an intro animation
-func runIntro(buttons *GpioReader, display Display) {
 
-	if runtime.GOOS == "windows" {
-		return
+	if Settings.RenderWorkers > 1 {
+		pool := NewRenderPool(Settings.RenderWorkers)
+		defer pool.Close()
+		UseRenderPool(pool)
 	}
 
-	field := NewGameField(Settings.LedCount)
-	field.Add(NewSinusoid(field, 1))
-
-	curTime := time.Now()
-	prevTime := curTime
-
-	ticks := time.NewTicker(time.Duration(Settings.MinFrameTime*1000.0) * time.Millisecond)
-	defer ticks.Stop()
-
-	for _ = range ticks.C {
-
-		prevTime, curTime = curTime, time.Now()
-		dt := curTime.Sub(prevTime).Seconds()
-
-		if buttons.LeftButton() || buttons.RightButton() {
-			return
+	// handlers are added as each part starts, the web server is up first so a standby can be asked about the strip
+	go StartWebServer(Settings.WebAddress)
+
+	// one of a redundant pair only drives the strip while the other isn't, waiting on standby until it stops answering
+	useLeds := !*webDisplay && runtime.GOOS != "windows"
+	if Settings.FailoverPeer != "" && useLeds {
+		failover := NewFailover(Settings)
+		http.Handle("/api/failover", failover)
+		if Settings.FailoverStandby || failover.PeerDriving() {
+			failover.Standby(time.Duration(Settings.FailoverCheckSeconds * float64(time.Second)))
+		} else {
+			failover.Drive()
 		}
-
-		field.Animate(dt)
-		field.RenderTo(display)
 	}
-}
-
-// Run an animation to start the game
-func runOpening(display Display) {
-
-	field := NewGameField(Settings.LedCount)
-	countDown := NewCountdown(field, 2)
-	field.Add(countDown)
-
-	go PlaySound(GAMESTART)
-
-	curTime := time.Now()
-	prevTime := curTime
-
-	ticks := time.NewTicker(time.Duration(Settings.MinFrameTime*1000.0) * time.Millisecond)
-	defer ticks.Stop()
-
-	for _ = range ticks.C {
 
-		prevTime, curTime = curTime, time.Now()
-		dt := curTime.Sub(prevTime).Seconds()
-
-		field.Animate(dt)
-
-		if countDown.TimeRemaining() <= 0 {
-			return
+	// report everything wrong with the settings and hardware at once rather than stopping at the first problem, blinking
+	// the count of them on the strip for an installation nobody is watching the log of
+	if err := SelfTest(Settings, useLeds, runtime.GOOS != "windows"); err != nil {
+		log.Print(err)
+		if useLeds {
+			BlinkStartupProblems(Settings, err)
 		}
-
-		field.RenderTo(display)
+		os.Exit(1)
 	}
-}
-
-// Run the actual game
-func runGame(buttons *GpioReader, display Display) (leftPlayerWon bool, totalBounces int) {
-
-	field := NewGameField(64)
 
-	ball := NewBall(field)
-	field.Add(ball)
-
-	leftPlayer := NewPlayer(true, Settings.LifeInSeconds, field)
-	field.Add(leftPlayer)
-	rightPlayer := NewPlayer(false, Settings.LifeInSeconds, field)
-	field.Add(rightPlayer)
-
-	curTime := time.Now()
-	prevTime := curTime
-	totalBounces = 0
-
-	ticks := time.NewTicker(time.Duration(Settings.MinFrameTime*1000.0) * time.Millisecond)
-	defer ticks.Stop()
-
-	for _ = range ticks.C {
-
-		prevTime, curTime = curTime, time.Now()
-		dt := curTime.Sub(prevTime).Seconds()
-
-		leftPlayer.UpdatePaddleActive(buttons.LeftButton())
-		rightPlayer.UpdatePaddleActive(buttons.RightButton())
-		//leftPlayer.UpdatePaddleActive(true)
-		//rightPlayer.UpdatePaddleActive(true)
+	// the web display shows the frame as it is rendered, leds are laid out by the pixel map or the rows of the matrix
+	var pixels PixelMap
+	if Settings.PixelMapPath != "" && useLeds {
+		var err error
+		if pixels, err = LoadPixelMap(Settings.PixelMapPath); err != nil {
+			log.Fatal(err)
+		}
+	}
 
-		field.Animate(dt)
+	// the game can be played on a window of a longer strip
+	windowed := Settings.StripLedCount > 0 && Settings.MatrixRows == 1 && pixels == nil && useLeds
 
-		ball.UpdateOffensiveHide(leftPlayer, rightPlayer)
+	zones, err := ParseBrightnessZones(Settings.BrightnessZones)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-		playerMissed, bounce := ball.MissedByPlayer(leftPlayer, rightPlayer, Settings.BounceVelocityIncrease)
-		if playerMissed != nil {
-			ball.ResetPosition(field)
-			if playerMissed.DecreaseLife(0.75) {
-				return playerMissed == leftPlayer, totalBounces
-			}
+	var display Display
+	if !useLeds {
+		display = NewWebDisplay(Settings)
+	} else {
+		ledCount, brightness := Settings.Leds(), []float64(nil)
+		if pixels != nil {
+			ledCount, brightness = len(pixels), pixels.Brightness()
+		} else if windowed {
+			ledCount = Settings.StripLedCount
 		}
-		if bounce {
-			totalBounces++
+		leds, err := NewLedDisplay(Settings, ledCount)
+		if err != nil {
+			log.Fatal(err)
 		}
-
-		field.RenderTo(display)
+		display = leds
+		if Settings.PowerBudgetMilliamps > 0 {
+			// limits the colors sent to the leds, after zones have brightened or dimmed them
+			display = NewPowerLimitDisplay(leds, Settings.PowerBudgetMilliamps, Settings.LedChannelMilliamps, Settings.LedIdleMilliamps)
+		}
+		display = zonedDisplay(display, ledCount, brightness, zones)
+	}
+	if windowed {
+		ambient := NewGameField(Settings.StripLedCount)
+		ambient.Add(NewBackground(Settings.AmbientBackground, ambient, 0))
+		window, err := NewWindowDisplay(display, Settings.GameWindowOffset, ambient, time.Now)
+		if err != nil {
+			log.Fatal(err)
+		}
+		display = window
+	}
+	if pixels != nil {
+		mapped, err := NewPixelMapDisplay(display, Settings.LedCount, Settings.MatrixRows, pixels)
+		if err != nil {
+			log.Fatal(err)
+		}
+		display = mapped
+	} else if Settings.MatrixRows > 1 && !*webDisplay {
+		matrix, err := NewMatrixDisplay(display, Settings.LedCount, Settings.MatrixRows, Settings.MatrixLayout)
+		if err != nil {
+			log.Fatal(err)
+		}
+		display = matrix
+	}
+	if Settings.DisplayTransform != "" && !*webDisplay {
+		transformed, err := NewTransformDisplay(display, Settings.LedCount, Settings.MatrixRows, Settings.DisplayTransform)
+		if err != nil {
+			log.Fatal(err)
+		}
+		display = transformed
 	}
 
-	panic("Shouldn't get here")
-}
-
-// Run an animation showing the winner
-func runClosing(buttons *GpioReader, display Display, winner bool) {
+	// the brightness follows the light in the room if there's a sensor, and can be chosen on the brightness page
+	var sensor LightSensor
+	if Settings.LightSensor != "" && useLeds {
+		if sensor, err = NewLightSensor(Settings.LightSensor, Settings.LightSensorBus, Settings.LightSensorAddress); err != nil {
+			log.Fatal(err)
+		}
+	}
+	brightness := NewAutoBrightness(display, sensor, Settings)
+	go brightness.Watch(time.Duration(Settings.LightSensorSeconds * float64(time.Second)))
+	http.Handle("/api/brightness", brightness)
+	http.HandleFunc("/brightness", brightness.ServePage)
+	display = brightness
+
+	// leds are dimmed and the frame rate lowered as the board or strip heats up
+	var thermal *ThermalMonitor
+	if useLeds && Settings.ThermalPollSeconds > 0 {
+		thermal = NewThermalMonitor(display, Settings)
+		go thermal.Watch(time.Duration(Settings.ThermalPollSeconds * float64(time.Second)))
+		display = thermal
+	}
 
-	field := NewGameField(Settings.LedCount)
-	winnerDisplay := NewWinner(field, winner, 4)
-	field.Add(winnerDisplay)
+	// leds are dimmed and the frame rate lowered while running on the battery of a UPS HAT
+	var ups *UpsMonitor
+	if useLeds && (Settings.BatteryGauge != "" || Settings.UpsPowerLossPath != "") {
+		var gauge BatteryGauge
+		if Settings.BatteryGauge != "" {
+			if gauge, err = NewBatteryGauge(Settings.BatteryGauge, Settings.BatteryGaugeBus, Settings.BatteryGaugeAddress, Settings.BatteryEmptyVolts, Settings.BatteryFullVolts); err != nil {
+				log.Fatal(err)
+			}
+		}
+		ups = NewUpsMonitor(display, gauge, Settings)
+		go ups.Watch(time.Duration(Settings.UpsPollSeconds * float64(time.Second)))
+		display = ups
+	}
 
-	//go PlaySound(GAMEOVER)
+	buttons, err := NewGpioReader(Settings)
+	if err != nil {
+		log.Fatal(err)
+	}
+	encoder, err := NewGpioEncoder(Settings)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if encoder != nil {
+		go func() {
+			log.Print("Stopped reading the rotary encoder: ", encoder.Watch(encoderPollTime))
+		}()
+	}
+	var input ButtonInput = buttons
+	if *chaos {
+		faults := NewFaultInjector(FaultRates{
+			SpiError:         Settings.ChaosSpiErrorChance,
+			BounceStorm:      Settings.ChaosBounceStormChance,
+			SlowFrame:        Settings.ChaosSlowFrameChance,
+			SlowFrameSeconds: Settings.ChaosSlowFrameSeconds,
+		}, time.Now().UnixNano())
+		display = faults.Display(display)
+		input = faults.Buttons(buttons)
+		log.Print("Injecting hardware faults")
+	}
+	input = NewDebouncedButtons(input)
 
-	curTime := time.Now()
-	prevTime := curTime
+	ratings := LoadRatings(Settings.RatingsPath)
+	for _, personality := range AIPersonalities {
+		ratings.SetFixed(personality.Name, personality.Rating)
+	}
+	http.Handle("/api/ratings", ratings)
+	store := stats.Load(Settings.StatsPath)
+	http.Handle("/api/stats", store)
+	http.HandleFunc("/api/league", store.ServeLeague)
+	profiles := LoadProfiles(Settings.ProfilesPath)
+	http.Handle("/api/profiles", profiles)
+	tweaks := LoadEffectTweaks(Settings.EffectTweaksPath)
+	UseEffectTweaks(tweaks)
+	http.Handle("/api/effects", AdminMethodsOnly(tweaks, Settings.AdminToken, "POST", "DELETE"))
+	http.HandleFunc("/effects", tweaks.ServePage)
+	http.Handle("/api/metrics", GameMetrics)
+
+	options := gameOptions{mode: *gameMode}
+	switch {
+	case *drillMode:
+		options.mode = "drill"
+	case *ghostFile != "":
+		var err error
+		if options.config.Ghost, err = LoadGameRecording(*ghostFile); err != nil {
+			log.Fatal(err)
+		}
+		options.mode = "ghost"
+	case *replayFile != "" || *gameMode == "replay":
+		if *replayFile == "" {
+			*replayFile = Settings.RecordingPath
+		}
+		var err error
+		if options.config.Replay, err = LoadGameRecording(*replayFile); err != nil {
+			log.Fatal(err)
+		}
+		options.mode = "replay"
+	case *aiOpponent != "":
+		options.mode = "ai"
+		options.config.Difficulty = checkPersonality(*aiOpponent)
+	case *aiTeammate != "":
+		options.mode = "doubles"
+		options.config.Difficulty = checkPersonality(*aiTeammate)
+	}
+	if _, ok := NewGameMode(options.mode); !ok {
+		log.Fatal("Unknown game mode ", options.mode)
+	}
 
-	ticks := time.NewTicker(time.Duration(Settings.MinFrameTime*1000.0) * time.Millisecond)
-	defer ticks.Stop()
+	if _, ok := FindTopology(Settings.Topology); !ok {
+		log.Print("Unknown topology ", Settings.Topology, ", playing on a line")
+	}
 
-	for _ = range ticks.C {
+	if err := LoadThemePacks(Settings.ThemesDir); err != nil {
+		log.Print(err)
+	}
+	if !UseTheme(Settings.Theme) {
+		log.Print("Unknown theme ", Settings.Theme, ", using ", CurrentTheme().Name)
+	}
 
-		prevTime, curTime = curTime, time.Now()
-		dt := curTime.Sub(prevTime).Seconds()
+	loop := newGame(input, display, options, ratings, store, profiles)
+	quietHours, err := ParseQuietHours(Settings.QuietHoursStart, Settings.QuietHoursEnd)
+	if err != nil {
+		log.Fatal(err)
+	}
+	loop.quietHours = quietHours
+	loop.debug = *debugOverlay
+	loop.thermal = thermal
+	loop.ups = ups
+	loop.brightness = brightness
+	loop.encoder = encoder
+	if Settings.StatusPanel != "" {
+		panel, err := status.Open(Settings.StatusPanel, Settings.StatusPanelBus, Settings.StatusPanelAddress, Settings.StatusPanelColumns, Settings.StatusPanelRows)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer panel.Close()
+		go loop.showStatus(panel, time.Duration(Settings.StatusPanelSeconds*float64(time.Second)))
+	}
+	if *simulate {
+		loop.wallClock = NewSimulatedClock(time.Now())
+	}
+	loop.menus[modeMenu].SelectName(options.mode)
+	loop.menus[difficultyMenu].SelectName(options.config.Difficulty)
+	loop.menus[themeMenu].SelectName(CurrentTheme().Name)
+	http.HandleFunc("/api/pause", loop.pauseHandler)
+	http.HandleFunc("/api/resume", loop.resumeHandler)
+	http.HandleFunc("/api/players", loop.playersHandler)
+	http.HandleFunc("/api/debug", loop.debugHandler)
+	http.Handle("/api/frames", loop.capture)
+	http.HandleFunc("/api/state", loop.stateHandler)
+	http.HandleFunc("/api/notify", loop.notifyHandler)
+	http.Handle("/api/matches", loop.history)
+	http.HandleFunc("/api/stats/telemetry", loop.history.ServeTelemetry)
+	http.Handle("/api/achievements", loop.achievements)
+	http.Handle("/api/tournament", AdminMethodsOnly(loop.tournament, Settings.AdminToken, "POST", "DELETE"))
+	http.Handle("/api/hill", AdminMethodsOnly(loop.hill, Settings.AdminToken, "DELETE"))
+	http.Handle("/api/predictions", loop.predictions)
+	http.Handle("/api/queue", AdminMethodsOnly(loop.signups, Settings.AdminToken, "DELETE"))
+	http.HandleFunc("/queue", loop.signups.ServePage)
+	http.Handle("/api/crowd", loop.crowd)
+	http.HandleFunc("/crowd", loop.crowd.ServePage)
+	http.Handle("/api/highscores", loop.highScores)
+	if Settings.UploadURL != "" {
+		uploader := stats.NewUploader(loop.history, store, Settings.UploadURL, Settings.UploadToken, Settings.UploadInstallation, Settings.UploadStatePath)
+		go uploader.Run(time.Duration(Settings.UploadIntervalSeconds * float64(time.Second)))
+	}
+	if Settings.UpdateURL != "" {
+		updater, err := NewAssetUpdater(Settings)
+		if err != nil {
+			log.Fatal(err)
+		}
+		http.Handle("/api/updates", AdminMethodsOnly(updater, Settings.AdminToken, "POST"))
+		if Settings.UpdateIntervalMinutes > 0 {
+			go updater.Run(time.Duration(Settings.UpdateIntervalMinutes * float64(time.Minute)))
+		}
+	}
+	if Settings.WatchdogSeconds > 0 {
+		loop.watch(time.Duration(Settings.WatchdogSeconds * float64(time.Second)))
+	}
 
-		field.Animate(dt)
+	signal.Notify(loop.shutdown, os.Interrupt, syscall.SIGTERM)
+	loop.run()
 
-		if winnerDisplay.TimeRemaining() <= 0 {
-			return
+	// flush everything to disk and release the hardware
+	if err := ratings.Save(); err != nil {
+		log.Print(err)
+	}
+	if closer, ok := display.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Print(err)
 		}
+	}
+	if err := buttons.Close(); err != nil {
+		log.Print(err)
+	}
+	log.Print("Shut down")
+}
 
-		field.RenderTo(display)
+// Wrap display of ledCount leds in a ZonedDisplay if brightness or zones change the brightness of any of them
+func zonedDisplay(display Display, ledCount int, brightness []float64, zones []BrightnessZone) Display {
+	if zoned := NewZonedDisplay(display, ledCount, brightness, zones); zoned.Zoned() {
+		return zoned
 	}
+	return display
 }
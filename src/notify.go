@@ -0,0 +1,78 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+// Longest the web server waits for the game to take a notification
+var notifyTimeout = time.Second
+
+// A notification pushed from the web, shown is sent false if the game is busy being played
+type notifyRequest struct {
+	notification Notification
+	shown        chan bool
+}
+
+// Show notification over the idle scene, returns false if a game is being played
+func (this *game) notify(notification Notification) bool {
+
+	if this.states.Phase() != PhaseIdle {
+		log.Print("Ignored the ", notification.Name, " notification during a game")
+		return false
+	}
+
+	log.Print("Showing the ", notification.Name, " notification")
+	scene := NewMatrixScene("notification", Settings.LedCount, Settings.MatrixRows)
+	this.notification = NewNotificationLight(scene.Field(), notification)
+	scene.Add(this.notification)
+	this.show(scene, Settings.SceneFadeSeconds)
+	return true
+}
+
+// Go back to the idle scene that was shown before the notification
+func (this *game) endNotification() {
+
+	this.notification = nil
+	if this.clockShown {
+		this.showClock()
+	} else {
+		this.showIntro()
+	}
+}
+
+// Show a notification while the game is idle with a POST to /api/notify, name is a preset or any name along with a
+// color written as #rrggbb, effect and seconds are optional
+func (this *game) notifyHandler(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != "POST" {
+		http.Error(w, "POST name, and color, effect and seconds for anything but a preset, to show a notification",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	notification, err := ParseNotification(r.FormValue("name"), r.FormValue("color"), r.FormValue("effect"),
+		r.FormValue("seconds"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	shown := false
+	request := notifyRequest{notification, make(chan bool, 1)}
+	select {
+	case this.notifyRequests <- request:
+		select {
+		case shown = <-request.shown:
+		case <-time.After(notifyTimeout):
+		}
+	case <-time.After(notifyTimeout):
+	}
+
+	if !shown {
+		http.Error(w, "The game is being played or isn't responding, try again later", http.StatusConflict)
+	}
+}
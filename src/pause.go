@@ -0,0 +1,125 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+// Brightness of the field while the game is paused
+var pausedBrightness float64 = 0.25
+
+// Seconds both buttons must be held to pause or resume, much longer than any press returning the ball
+var pauseHoldSeconds float64 = 1.2
+
+// Longest the web server waits for the game to take a pause or resume request
+var pauseTimeout = time.Second
+
+// Pause or resume the game when both buttons are pushed together and held or a request comes from the api
+func (this *game) updatePause(wallDt float64) {
+
+	// holding both buttons while the ball can be returned is playing, not asking to pause
+	left, right := this.buttons.LeftButton(), this.buttons.RightButton()
+	if !this.clock.Paused() && this.ballInHitZone() {
+		left, right = false, false
+	}
+	chord := this.pauseChord.Update(left, right, wallDt)
+
+	pause, resume := false, false
+	select {
+	case request := <-this.pauseRequests:
+		pause, resume = request, !request
+	default:
+	}
+
+	if this.clock.Paused() {
+		if this.resumeTime > 0 {
+			// counting down to resume
+			this.resumeTime -= wallDt
+			if this.resumeTime <= 0 {
+				this.clock.Resume()
+				log.Print("Resumed")
+			}
+		} else if chord || resume {
+			this.resumeTime = Settings.ResumeCountdownSeconds
+			if this.resumeTime <= 0 {
+				this.clock.Resume()
+			}
+		}
+	} else if (chord || pause) && this.states.Phase() == PhaseRally && !this.current.config.Demo {
+		this.clock.Pause()
+		this.resumeTime = 0
+		log.Print("Paused")
+	}
+}
+
+// If a ball on the field is inside either player's hit zone
+func (this *game) ballInHitZone() bool {
+
+	var zones []Extent
+	var balls []*Ball
+	for _, drawable := range this.field.Drawables() {
+		switch drawable := drawable.(type) {
+		case *Player:
+			zones = append(zones, drawable.HitZone())
+		case *Ball:
+			balls = append(balls, drawable)
+		}
+	}
+
+	for _, ball := range balls {
+		for _, zone := range zones {
+			if zone.Contains(Position(ball.Position())) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Display the paused field is rendered to, brightening back up during the countdown to resume
+func (this *game) pausedOutput() Display {
+
+	brightness := pausedBrightness
+	if this.resumeTime > 0 && Settings.ResumeCountdownSeconds > 0 {
+		brightness += (1.0 - pausedBrightness) * (1.0 - this.resumeTime/Settings.ResumeCountdownSeconds)
+	}
+
+	if this.pausedDisplay == nil {
+		this.pausedDisplay = NewDimmedDisplay(this.display, brightness)
+	}
+	this.pausedDisplay.SetBrightness(brightness)
+
+	return this.pausedDisplay
+}
+
+// Pause the game with a POST to /api/pause
+func (this *game) pauseHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "POST to pause the game", http.StatusMethodNotAllowed)
+		return
+	}
+	this.requestPause(w, true)
+}
+
+// Resume the game with a POST to /api/resume
+func (this *game) resumeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "POST to resume the game", http.StatusMethodNotAllowed)
+		return
+	}
+	this.requestPause(w, false)
+}
+
+// Hand a pause or resume to the game loop, which doesn't take them while starting up, suspended for quiet hours or
+// stalled, so the request gives up rather than holding the handler forever
+func (this *game) requestPause(w http.ResponseWriter, pause bool) {
+	select {
+	case this.pauseRequests <- pause:
+	case <-time.After(pauseTimeout):
+		http.Error(w, "The game isn't taking requests right now, try again later", http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Names of the profiles chosen from the web for the next game
+type playerChoice struct {
+	left, right string
+}
+
+// Use the chosen profiles for the following games, as if they were picked from the menus
+func (this *game) choosePlayers(choice playerChoice) {
+
+	this.options.config.LeftProfile, _ = this.profiles.Find(choice.left)
+	this.options.config.RightProfile, _ = this.profiles.Find(choice.right)
+
+	this.menus[leftPlayerMenu].SetOptions(this.profiles.MenuOptions())
+	this.menus[leftPlayerMenu].SelectName(this.options.config.LeftProfile.Name)
+	this.menus[rightPlayerMenu].SetOptions(this.profiles.MenuOptions())
+	this.menus[rightPlayerMenu].SelectName(this.options.config.RightProfile.Name)
+
+	log.Print("Chose ", this.options.config.LeftProfile.Name, " and ", this.options.config.RightProfile.Name, " from the web")
+}
+
+// Choose who plays the next game with a POST to /api/players of the left and right profile names
+func (this *game) playersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "POST left and right profile names to choose the players", http.StatusMethodNotAllowed)
+		return
+	}
+
+	choice := playerChoice{left: r.FormValue("left"), right: r.FormValue("right")}
+	var profiles []PlayerProfile
+	for _, name := range []string{choice.left, choice.right} {
+		profile, ok := this.profiles.Find(name)
+		if !ok {
+			http.Error(w, "No profile named "+name, http.StatusBadRequest)
+			return
+		}
+		profiles = append(profiles, profile)
+	}
+	if profiles[0].SamePlayer(profiles[1]) {
+		http.Error(w, choice.left+" can't play both sides", http.StatusBadRequest)
+		return
+	}
+	this.playerChoices <- choice
+}
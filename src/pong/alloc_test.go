@@ -0,0 +1,49 @@
+package pong
+
+import (
+	"testing"
+)
+
+// Rendering a frame, crossfading scenes, and serializing for the strip shouldn't allocate
+func Test_RenderPath_NoAllocations(t *testing.T) {
+	pool := NewRenderPool(4)
+	defer pool.Close()
+	UseRenderPool(pool)
+	defer UseRenderPool(nil)
+
+	red := NewScene("red", 300)
+	red.Add(&SolidDrawable{RGBA{255, 0, 0, 255}})
+	gradient := NewScene("gradient", 300)
+	gradient.Add(&GradientDrawable{})
+	gradient.Add(&DotDrawable{position: 10})
+
+	scenes := NewSceneManager(300)
+	scenes.Show(red, 0)
+	scenes.Show(gradient, 1000)
+
+	leds := &LedDisplay{expectedColors: 300, byteData: make([]byte, ledFrameSize(300))}
+	capture := &CaptureDisplay{}
+	dimmed := NewDimmedDisplay(capture, 0.5)
+
+	// first frame sizes the buffers
+	scenes.RenderTo(dimmed)
+
+	allocations := testing.AllocsPerRun(100, func() {
+		scenes.Animate(0.01)
+		scenes.RenderTo(dimmed)
+		leds.encode(capture.frame)
+	})
+	Assert(int(allocations), 0, "Allocations per frame", t)
+}
+
+// Fields made after another is released should reuse its frame
+func Test_GameField_ReleaseReusesFrame(t *testing.T) {
+	field := NewGameField(37)
+	frame := field.Render()
+	field.Release()
+
+	reused := NewGameField(37)
+	if &reused.Render()[0] != &frame[0] {
+		t.Fatal("Released frame wasn't reused")
+	}
+}
@@ -0,0 +1,280 @@
+// Package anim builds animations out of tweens played one after the other or at the same time, so effects made of
+// several steps such as a victory can be written as a timeline instead of a state machine in Animate
+package anim
+
+import (
+	"math"
+)
+
+// Shapes how a tween moves from its start to its end, taking and returning the fraction of the way from 0 to 1
+type Easing func(fraction float64) float64
+
+// Common easings
+var (
+	Linear    Easing = func(fraction float64) float64 { return fraction }
+	EaseIn    Easing = func(fraction float64) float64 { return fraction * fraction }
+	EaseOut   Easing = func(fraction float64) float64 { return fraction * (2 - fraction) }
+	EaseInOut Easing = func(fraction float64) float64 { return fraction * fraction * (3 - 2*fraction) }
+)
+
+// Part of a timeline, moved forward as the drawable playing it is animated
+type Animation interface {
+
+	// Move forward by dt seconds, returns true once finished along with the seconds of dt that weren't needed, which
+	// go to whatever plays next
+	Advance(dt float64) (leftover float64, done bool)
+
+	// Seconds from the start to the end, infinite if it never finishes
+	Duration() float64
+
+	// Go back to the start
+	Reset()
+}
+
+// A value moving from one number to another over a number of seconds
+type TweenAnimation struct {
+	from, to float64
+	duration float64
+	easing   Easing
+
+	// set to the value as the tween moves, nil if it is only read with Value
+	target *float64
+
+	elapsed float64
+}
+
+var _ Animation = &TweenAnimation{}
+
+// Construct a TweenAnimation moving from from to to over duration seconds shaped by easing, Linear if nil
+func Tween(from, to, duration float64, easing Easing) *TweenAnimation {
+	if easing == nil {
+		easing = Linear
+	}
+	return &TweenAnimation{
+		from:     from,
+		to:       to,
+		duration: duration,
+		easing:   easing,
+	}
+}
+
+// Set target to the value of the tween whenever it moves, so several tweens played in turn can move the same value
+func (this *TweenAnimation) Drive(target *float64) *TweenAnimation {
+	this.target = target
+	return this
+}
+
+// Value the tween is at
+func (this *TweenAnimation) Value() float64 {
+	if this.elapsed >= this.duration {
+		return this.to
+	}
+	return this.from + (this.to-this.from)*this.easing(this.elapsed/this.duration)
+}
+
+// Move the value on by dt seconds
+func (this *TweenAnimation) Advance(dt float64) (float64, bool) {
+	this.elapsed += dt
+	leftover, done := 0.0, this.elapsed >= this.duration
+	if done {
+		leftover = this.elapsed - this.duration
+		this.elapsed = this.duration
+	}
+	if this.target != nil {
+		*this.target = this.Value()
+	}
+	return leftover, done
+}
+
+// Seconds the tween takes
+func (this *TweenAnimation) Duration() float64 {
+	return this.duration
+}
+
+// Go back to the from value
+func (this *TweenAnimation) Reset() {
+	this.elapsed = 0
+}
+
+// Construct an animation doing nothing for duration seconds
+func Wait(duration float64) *TweenAnimation {
+	return Tween(0, 0, duration, nil)
+}
+
+// Calls a function when it is reached, taking no time
+type CallAnimation struct {
+	call   func()
+	called bool
+}
+
+var _ Animation = &CallAnimation{}
+
+// Construct a CallAnimation calling call once when it is reached, such as to play a sound
+func Call(call func()) *CallAnimation {
+	return &CallAnimation{call: call}
+}
+
+// Call the function the first time
+func (this *CallAnimation) Advance(dt float64) (float64, bool) {
+	if !this.called {
+		this.called = true
+		this.call()
+	}
+	return dt, true
+}
+
+// No time at all
+func (this *CallAnimation) Duration() float64 {
+	return 0
+}
+
+// Call the function again when it is next reached
+func (this *CallAnimation) Reset() {
+	this.called = false
+}
+
+// Animations played one after the other
+type SequenceAnimation struct {
+	steps   []Animation
+	current int
+}
+
+var _ Animation = &SequenceAnimation{}
+
+// Construct a SequenceAnimation playing steps in order
+func Sequence(steps ...Animation) *SequenceAnimation {
+	return &SequenceAnimation{steps: steps}
+}
+
+// Move the current step on by dt, starting the next with whatever time it didn't need
+func (this *SequenceAnimation) Advance(dt float64) (float64, bool) {
+	for this.current < len(this.steps) {
+		leftover, done := this.steps[this.current].Advance(dt)
+		if !done {
+			return 0, false
+		}
+		this.current++
+		dt = leftover
+	}
+	return dt, true
+}
+
+// Seconds every step takes together
+func (this *SequenceAnimation) Duration() (duration float64) {
+	for _, step := range this.steps {
+		duration += step.Duration()
+	}
+	return
+}
+
+// Go back to the start of the first step
+func (this *SequenceAnimation) Reset() {
+	for _, step := range this.steps {
+		step.Reset()
+	}
+	this.current = 0
+}
+
+// Animations played at the same time, finished once the longest one is
+type ParallelAnimation struct {
+	tracks []Animation
+	done   []bool
+}
+
+var _ Animation = &ParallelAnimation{}
+
+// Construct a ParallelAnimation playing tracks at the same time
+func Parallel(tracks ...Animation) *ParallelAnimation {
+	return &ParallelAnimation{
+		tracks: tracks,
+		done:   make([]bool, len(tracks)),
+	}
+}
+
+// Move every unfinished track on by dt
+func (this *ParallelAnimation) Advance(dt float64) (float64, bool) {
+	leftover, finished := dt, true
+	for index, track := range this.tracks {
+		if this.done[index] {
+			continue
+		}
+		var trackLeftover float64
+		trackLeftover, this.done[index] = track.Advance(dt)
+		if !this.done[index] {
+			finished = false
+		}
+		leftover = math.Min(leftover, trackLeftover)
+	}
+	if !finished {
+		return 0, false
+	}
+	return leftover, true
+}
+
+// Seconds the longest track takes
+func (this *ParallelAnimation) Duration() (duration float64) {
+	for _, track := range this.tracks {
+		duration = math.Max(duration, track.Duration())
+	}
+	return
+}
+
+// Go back to the start of every track
+func (this *ParallelAnimation) Reset() {
+	for index, track := range this.tracks {
+		track.Reset()
+		this.done[index] = false
+	}
+}
+
+// An animation played a number of times over
+type RepeatAnimation struct {
+	animation Animation
+
+	// times to play it, 0 for forever, and times it has been played
+	times, played int
+}
+
+var _ Animation = &RepeatAnimation{}
+
+// Construct a RepeatAnimation playing animation times times, or forever if times is 0
+func Repeat(times int, animation Animation) *RepeatAnimation {
+	return &RepeatAnimation{
+		animation: animation,
+		times:     times,
+	}
+}
+
+// Move the animation on by dt, starting it again each time it finishes until it has played enough times
+func (this *RepeatAnimation) Advance(dt float64) (float64, bool) {
+	for {
+		leftover, done := this.animation.Advance(dt)
+		if !done {
+			return 0, false
+		}
+		this.played++
+		if this.times > 0 && this.played >= this.times {
+			return leftover, true
+		}
+		if this.times <= 0 && this.animation.Duration() <= 0 {
+			// an animation taking no time would repeat forever within one frame
+			return 0, false
+		}
+		this.animation.Reset()
+		dt = leftover
+	}
+}
+
+// Seconds every repeat takes together
+func (this *RepeatAnimation) Duration() float64 {
+	if this.times <= 0 {
+		return math.Inf(1)
+	}
+	return float64(this.times) * this.animation.Duration()
+}
+
+// Go back to the start of the first repeat
+func (this *RepeatAnimation) Reset() {
+	this.animation.Reset()
+	this.played = 0
+}
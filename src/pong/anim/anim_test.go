@@ -0,0 +1,73 @@
+package anim
+
+import (
+	"math"
+	"testing"
+)
+
+// Tweens should ease between their ends, carrying time they don't need on to the next step of a sequence
+func Test_Anim_Sequence(t *testing.T) {
+
+	var value float64
+	calls := 0
+	sequence := Sequence(
+		Tween(0, 10, 1, nil).Drive(&value),
+		Call(func() { calls++ }),
+		Tween(10, 0, 2, EaseIn).Drive(&value),
+	)
+	if duration := sequence.Duration(); duration != 3 {
+		t.Fatal("Sequence lasts", duration)
+	}
+
+	sequence.Advance(0.5)
+	if value != 5 || calls != 0 {
+		t.Fatal("Halfway through the first tween the value is", value, "and it called", calls, "times")
+	}
+
+	// 0.5 finishes the first tween and the other 1 seconds is half of the second
+	sequence.Advance(1.5)
+	if value != 7.5 || calls != 1 {
+		t.Fatal("Halfway through the second tween the value is", value, "and it called", calls, "times")
+	}
+
+	leftover, done := sequence.Advance(1.25)
+	if !done || leftover != 0.25 || value != 0 {
+		t.Fatal("Finished sequence is done", done, "with", leftover, "left over at", value)
+	}
+
+	sequence.Reset()
+	sequence.Advance(1)
+	if calls != 2 {
+		t.Fatal("Reset sequence called", calls, "times")
+	}
+}
+
+// Parallel tracks should all play, finishing with the longest, and repeats should play the number of times asked
+func Test_Anim_ParallelRepeat(t *testing.T) {
+
+	short, long := Tween(0, 1, 1, nil), Tween(0, 1, 2, EaseOut)
+	parallel := Parallel(short, long)
+	if _, done := parallel.Advance(1.5); done || short.Value() != 1 || long.Value() != EaseOut(0.75) {
+		t.Fatal("Parallel tracks are at", short.Value(), long.Value())
+	}
+	if leftover, done := parallel.Advance(1); !done || leftover != 0.5 {
+		t.Fatal("Parallel is done", done, "with", leftover, "left over")
+	}
+
+	calls := 0
+	repeat := Repeat(3, Sequence(Wait(1), Call(func() { calls++ })))
+	if duration := repeat.Duration(); duration != 3 {
+		t.Fatal("Repeat lasts", duration)
+	}
+	if _, done := repeat.Advance(2.5); done || calls != 2 {
+		t.Fatal("Repeat called", calls, "times after 2.5 seconds")
+	}
+	if _, done := repeat.Advance(1); !done || calls != 3 {
+		t.Fatal("Repeat called", calls, "times once done")
+	}
+
+	forever := Repeat(0, Call(func() { calls++ }))
+	if _, done := forever.Advance(1); done || !math.IsInf(forever.Duration(), 1) {
+		t.Fatal("Repeating forever finished")
+	}
+}
@@ -0,0 +1,49 @@
+// Package atomicfile writes the files the game keeps its state in so that a power cut or crash part way through a
+// save leaves either the old file or the new one, never a truncated mix of the two
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Suffix of the file data is written to before it replaces the real one
+const temporarySuffix = ".tmp"
+
+// Write data to path with perm like ioutil.WriteFile, through a temporary file in the same directory that is synced
+// to disk and then renamed over path
+func Write(path string, data []byte, perm os.FileMode) (err error) {
+
+	temporary := path + temporarySuffix
+	file, err := os.OpenFile(temporary, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(temporary)
+		}
+	}()
+
+	if _, err = file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(temporary, path); err != nil {
+		return err
+	}
+
+	// the rename itself is only on disk once the directory is synced, not every platform can sync a directory
+	if dir, err := os.Open(filepath.Dir(path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
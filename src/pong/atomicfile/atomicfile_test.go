@@ -0,0 +1,63 @@
+package atomicfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Writing should replace the whole file and leave nothing else behind
+func Test_Write(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "atomicfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "profiles.xml")
+	if err := Write(path, []byte("a much longer first version"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := Write(path, []byte("second"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil || string(data) != "second" {
+		t.Fatal("Read back", string(data), err)
+	}
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 {
+		t.Fatal("Expected only the written file, found", len(files))
+	}
+}
+
+// A write that can't finish should leave the old file as it was
+func Test_Write_Failed(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "atomicfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "stats.xml")
+	if err := Write(path, []byte("old"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	// a directory where the temporary file goes stops it being created
+	if err := os.Mkdir(path+temporarySuffix, 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := Write(path, []byte("new"), 0666); err == nil {
+		t.Fatal("Expected the write to fail")
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil || string(data) != "old" {
+		t.Fatal("Read back", string(data), err)
+	}
+}
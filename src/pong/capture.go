@@ -0,0 +1,153 @@
+package pong
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Display that keeps copies of the last frames before passing them on to the Display it wraps, so glitches can be
+// looked at after the fact
+type FrameCapture struct {
+	Display
+
+	lock sync.Mutex
+
+	// ring of frames, next is the index the next frame is copied to
+	frames [][]RGBA
+	next   int
+	full   bool
+}
+
+// Construct a FrameCapture keeping the last count frames rendered to display
+func NewFrameCapture(display Display, count int) *FrameCapture {
+	if count < 1 {
+		count = 1
+	}
+	return &FrameCapture{Display: display, frames: make([][]RGBA, count)}
+}
+
+// Copy frame then render it to the wrapped Display
+func (this *FrameCapture) Render(frame []RGBA) {
+
+	this.lock.Lock()
+	if len(this.frames) == 0 {
+		this.frames = make([][]RGBA, 1)
+	}
+	this.frames[this.next] = append(this.frames[this.next][:0], frame...)
+	this.next = (this.next + 1) % len(this.frames)
+	if this.next == 0 {
+		this.full = true
+	}
+	this.lock.Unlock()
+
+	this.Display.Render(frame)
+}
+
+// Copies of the frames kept, oldest first
+func (this *FrameCapture) Frames() [][]RGBA {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	kept := this.frames[:this.next]
+	if this.full {
+		kept = append(append([][]RGBA{}, this.frames[this.next:]...), kept...)
+	}
+
+	frames := make([][]RGBA, len(kept))
+	for index, frame := range kept {
+		frames[index] = append([]RGBA{}, frame...)
+	}
+	return frames
+}
+
+// Copy of the last frame rendered
+func (this *FrameCapture) LastFrame() []RGBA {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if len(this.frames) == 0 || (this.next == 0 && !this.full) {
+		return []RGBA{}
+	}
+	last := (this.next + len(this.frames) - 1) % len(this.frames)
+	return append([]RGBA{}, this.frames[last]...)
+}
+
+// Serve the frames kept as a png strip with a row per frame, or as json with each led as rrggbb when format=json
+func (this *FrameCapture) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	frames := this.Frames()
+
+	if r.FormValue("format") == "json" {
+		leds := make([][]string, len(frames))
+		for index, frame := range frames {
+			leds[index] = hexColors(frame)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(leds)
+		return
+	}
+
+	// encoded before anything is sent, so a failure can still be answered with an error
+	var encoded bytes.Buffer
+	if err := writeFramesPng(&encoded, frames); err != nil {
+		log.Print("Encoding the captured frames: ", err)
+		http.Error(w, "Frames couldn't be encoded", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-control", "max-age=0, must-revalidate, no-store")
+	w.Write(encoded.Bytes())
+}
+
+// Each color as rrggbb
+func hexColors(colors []RGBA) []string {
+	leds := make([]string, len(colors))
+	for index, color := range colors {
+		leds[index] = fmt.Sprintf("%02x%02x%02x", color.R, color.G, color.B)
+	}
+	return leds
+}
+
+// Encode frames as a png with a row per frame, oldest at the top
+func writeFramesPng(w io.Writer, frames [][]RGBA) error {
+
+	width := 0
+	for _, frame := range frames {
+		if len(frame) > width {
+			width = len(frame)
+		}
+	}
+
+	strip := image.NewRGBA(image.Rect(0, 0, width, len(frames)))
+	for y, frame := range frames {
+		for x, led := range frame {
+			strip.SetRGBA(x, y, color.RGBA{led.R, led.G, led.B, 255})
+		}
+	}
+	return png.Encode(w, strip)
+}
+
+// Write frames to a png at path, see writeFramesPng
+func saveFramesPng(path string, frames [][]RGBA) error {
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := writeFramesPng(file, frames); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,52 @@
+package pong
+
+import (
+	"encoding/json"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// The capture should keep the newest frames, oldest first, and serve them as json or a png strip
+func Test_FrameCapture(t *testing.T) {
+
+	capture := NewFrameCapture(&countingDisplay{}, 3)
+	if len(capture.LastFrame()) != 0 || len(capture.Frames()) != 0 {
+		t.Fatal("Frames kept before any were rendered")
+	}
+
+	// no frames make an empty image, which can't be encoded
+	response := httptest.NewRecorder()
+	capture.ServeHTTP(response, httptest.NewRequest("GET", "/api/frames", nil))
+	Assert(response.Code, http.StatusInternalServerError, "Status serving no frames", t)
+
+	for frame := 0; frame < 5; frame++ {
+		capture.Render([]RGBA{{uint8(frame), 0, 0, 255}, {0, 0, 255, 255}})
+	}
+	Assert(capture.Display.(*countingDisplay).frames, 5, "Frames passed on", t)
+
+	frames := capture.Frames()
+	Assert(len(frames), 3, "Frames kept", t)
+	Assert(int(frames[0][0].R), 2, "Oldest frame", t)
+	Assert(int(capture.LastFrame()[0].R), 4, "Last frame", t)
+
+	response = httptest.NewRecorder()
+	capture.ServeHTTP(response, httptest.NewRequest("GET", "/api/frames?format=json", nil))
+	leds := [][]string{}
+	if err := json.NewDecoder(response.Body).Decode(&leds); err != nil {
+		t.Fatal(err)
+	}
+	if len(leds) != 3 || leds[2][0] != "040000" || leds[2][1] != "0000ff" {
+		t.Fatal("Served", leds)
+	}
+
+	response = httptest.NewRecorder()
+	capture.ServeHTTP(response, httptest.NewRequest("GET", "/api/frames", nil))
+	strip, err := png.Decode(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Assert(strip.Bounds().Dx(), 2, "Strip width", t)
+	Assert(strip.Bounds().Dy(), 3, "Strip height", t)
+}
@@ -0,0 +1,156 @@
+package pong
+
+import (
+	"log"
+	"math/rand"
+	"time"
+)
+
+// Reads a bouncing button keeps returning noise for
+var bounceStormReads int = 30
+
+// Chance of each simulated hardware fault, 0 never causes it
+type FaultRates struct {
+
+	// chance each frame is dropped as if the write to the SPI bus failed
+	SpiError float64
+
+	// chance each button read starts a storm of contact bounce on that button
+	BounceStorm float64
+
+	// chance each frame takes SlowFrameSeconds longer to render, long enough ones stall the game loop
+	SlowFrame        float64
+	SlowFrameSeconds float64
+}
+
+// Wraps the display and buttons to simulate hardware failing, so the watchdog and frame rate governor can be tested
+// without broken hardware
+type FaultInjector struct {
+	rates FaultRates
+
+	// faults are chosen from their own source, so injecting them doesn't change how a seeded game plays out
+	random *rand.Rand
+
+	// waits out a slow frame, replaced in tests to advance a SimulatedClock
+	Sleep func(time.Duration)
+
+	// number of each fault injected so far
+	SpiErrors, BounceStorms, SlowFrames int
+}
+
+// Construct a FaultInjector causing faults at rates, chosen from random numbers seeded with seed
+func NewFaultInjector(rates FaultRates, seed int64) *FaultInjector {
+	return &FaultInjector{
+		rates:  rates,
+		random: rand.New(rand.NewSource(seed)),
+		Sleep:  time.Sleep,
+	}
+}
+
+// If a fault with chance happens
+func (this *FaultInjector) happens(chance float64) bool {
+	return chance > 0 && this.random.Float64() < chance
+}
+
+// Wrap display so frames rendered to it fail or run slow
+func (this *FaultInjector) Display(display Display) Display {
+	return &faultyDisplay{wrappedDisplay{display}, this}
+}
+
+// Wrap buttons so reads of them bounce
+func (this *FaultInjector) Buttons(buttons ButtonInput) ButtonInput {
+	return &faultyButtons{buttons: buttons, faults: this}
+}
+
+// Display that frames are rendered to through a FaultInjector
+type faultyDisplay struct {
+	wrappedDisplay
+	faults *FaultInjector
+}
+
+var _ ResettableDisplay = &faultyDisplay{}
+
+// Render colorData to the wrapped display unless the write fails
+func (this *faultyDisplay) Render(colorData []RGBA) {
+
+	if this.faults.happens(this.faults.rates.SlowFrame) {
+		this.faults.SlowFrames++
+		this.faults.Sleep(time.Duration(this.faults.rates.SlowFrameSeconds * float64(time.Second)))
+	}
+
+	if this.faults.happens(this.faults.rates.SpiError) {
+		this.faults.SpiErrors++
+		log.Print("Injected SPI write error")
+		return
+	}
+
+	this.display.Render(colorData)
+}
+
+// Buttons read through a FaultInjector
+type faultyButtons struct {
+	buttons ButtonInput
+	faults  *FaultInjector
+
+	// reads left in the bounce storm of each button
+	leftStorm, rightStorm         int
+	leftDownStorm, rightDownStorm int
+}
+
+var _ DownButtonInput = &faultyButtons{}
+var _ FallibleButtonInput = &faultyButtons{}
+
+// State of the left button, noise while it is bouncing
+func (this *faultyButtons) LeftButton() bool {
+	return this.read(this.buttons.LeftButton(), &this.leftStorm)
+}
+
+// State of the right button, noise while it is bouncing
+func (this *faultyButtons) RightButton() bool {
+	return this.read(this.buttons.RightButton(), &this.rightStorm)
+}
+
+// If the wrapped buttons have second buttons wired up
+func (this *faultyButtons) HasDownButtons() bool {
+	buttons, ok := this.buttons.(DownButtonInput)
+	return ok && buttons.HasDownButtons()
+}
+
+// State of the left second button, noise while it is bouncing
+func (this *faultyButtons) LeftDownButton() bool {
+	if !this.HasDownButtons() {
+		return false
+	}
+	return this.read(this.buttons.(DownButtonInput).LeftDownButton(), &this.leftDownStorm)
+}
+
+// State of the right second button, noise while it is bouncing
+func (this *faultyButtons) RightDownButton() bool {
+	if !this.HasDownButtons() {
+		return false
+	}
+	return this.read(this.buttons.(DownButtonInput).RightDownButton(), &this.rightDownStorm)
+}
+
+// Error reading the wrapped buttons, if they can fail
+func (this *faultyButtons) ReadError() error {
+	if buttons, ok := this.buttons.(FallibleButtonInput); ok {
+		return buttons.ReadError()
+	}
+	return nil
+}
+
+// Pass down through, or noise while storm has reads left, possibly starting a new storm
+func (this *faultyButtons) read(down bool, storm *int) bool {
+
+	if *storm == 0 && this.faults.happens(this.faults.rates.BounceStorm) {
+		this.faults.BounceStorms++
+		*storm = bounceStormReads
+	}
+	if *storm == 0 {
+		return down
+	}
+
+	*storm--
+	return this.faults.random.Intn(2) == 0
+}
@@ -0,0 +1,132 @@
+package pong
+
+import (
+	"testing"
+	"time"
+)
+
+// Display counting the frames that reach it
+type countingDisplay struct {
+	frames int
+}
+
+func (this *countingDisplay) Render(colorData []RGBA) { this.frames++ }
+
+// Failed writes should never reach the display, and slow frames should lower the frame rate
+func Test_FaultInjector_Display(t *testing.T) {
+
+	clock := NewSimulatedClock(time.Time{})
+	faults := NewFaultInjector(FaultRates{SpiError: 0.5, SlowFrame: 0.5, SlowFrameSeconds: 0.05}, 1)
+	faults.Sleep = clock.Advance
+
+	counted := &countingDisplay{}
+	display := faults.Display(counted)
+	governor := NewFrameRateGovernor(15, 60)
+
+	frames := 200
+	for frame := 0; frame < frames; frame++ {
+		start := clock.Now()
+		display.Render(nil)
+		governor.Update(clock.Now().Sub(start).Seconds())
+	}
+
+	Assert(counted.frames+faults.SpiErrors, frames, "Frames rendered or dropped", t)
+	if faults.SpiErrors == 0 || faults.SlowFrames == 0 {
+		t.Fatal("No faults injected", faults.SpiErrors, faults.SlowFrames)
+	}
+	if governor.FPS() >= 60 {
+		t.Fatal("Frame rate not lowered by slow frames")
+	}
+}
+
+// A bounce storm should flip a held button for a while, then pass it through again
+func Test_FaultInjector_Buttons(t *testing.T) {
+
+	faults := NewFaultInjector(FaultRates{BounceStorm: 1}, 1)
+	buttons := faults.Buttons(&ButtonState{Left: true})
+
+	released := 0
+	for read := 0; read < bounceStormReads; read++ {
+		if !buttons.LeftButton() {
+			released++
+		}
+	}
+	Assert(faults.BounceStorms, 1, "Storms started", t)
+	if released == 0 || released == bounceStormReads {
+		t.Fatal("Bouncing button read released", released, "times")
+	}
+
+	faults.rates.BounceStorm = 0
+	if !buttons.LeftButton() {
+		t.Fatal("Button still bouncing after the storm")
+	}
+}
+
+// A bounce storm read through the debouncer the game uses should be seen as fewer presses than the raw reads, and the
+// held button should be held again once it settles
+func Test_FaultInjector_DebouncedButtons(t *testing.T) {
+
+	presses := func(buttons ButtonInput, reads int) (count int) {
+		previous := false
+		for read := 0; read < reads; read++ {
+			down := buttons.LeftButton()
+			if down && !previous {
+				count++
+			}
+			previous = down
+		}
+		return
+	}
+
+	// the same storm from the same seed, read raw and debounced
+	raw := NewFaultInjector(FaultRates{BounceStorm: 1}, 1)
+	rawPresses := presses(raw.Buttons(&ButtonState{Left: true}), bounceStormReads)
+	faults := NewFaultInjector(FaultRates{BounceStorm: 1}, 1)
+	buttons := NewDebouncedButtons(faults.Buttons(&ButtonState{Left: true}))
+	debouncedPresses := presses(buttons, bounceStormReads)
+
+	Assert(faults.BounceStorms, 1, "Storms started", t)
+	if debouncedPresses >= rawPresses {
+		t.Fatal("Debounced storm read as", debouncedPresses, "presses vs", rawPresses, "raw")
+	}
+
+	faults.rates.BounceStorm = 0
+	for read := 0; read < buttonDebounceReads; read++ {
+		buttons.LeftButton()
+	}
+	if !buttons.LeftButton() {
+		t.Fatal("Button not held once the storm settled")
+	}
+}
+
+// Buttons with second buttons wired up, for checking they reach the game through a wrapper
+type downButtonState struct {
+	ButtonState
+	leftDown, rightDown bool
+}
+
+func (this *downButtonState) HasDownButtons() bool {
+	return true
+}
+
+func (this *downButtonState) LeftDownButton() bool {
+	return this.leftDown
+}
+
+func (this *downButtonState) RightDownButton() bool {
+	return this.rightDown
+}
+
+// Second buttons should be passed through the fault injector, and only when the wrapped buttons have them
+func Test_FaultInjector_DownButtons(t *testing.T) {
+
+	faults := NewFaultInjector(FaultRates{}, 1)
+	buttons := faults.Buttons(&downButtonState{rightDown: true}).(DownButtonInput)
+	if !buttons.HasDownButtons() || buttons.LeftDownButton() || !buttons.RightDownButton() {
+		t.Fatal("Second buttons not passed through")
+	}
+
+	if faults.Buttons(&ButtonState{}).(DownButtonInput).HasDownButtons() {
+		t.Fatal("Second buttons found on buttons without them")
+	}
+}
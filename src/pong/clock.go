@@ -0,0 +1,146 @@
+package pong
+
+import (
+	"log"
+	"math"
+	"time"
+)
+
+// Slowest and fastest game time can run compared to wall clock time
+const minTimeScale, maxTimeScale float64 = 0.1, 10
+
+// Game time, which stops while the game is paused, as opposed to wall clock time
+type GameClock struct {
+
+	// if game time is stopped
+	paused bool
+
+	// total game time in seconds
+	time float64
+
+	// seconds of game time that pass each second of wall clock time
+	scale float64
+}
+
+// Construct a running GameClock
+func NewGameClock() *GameClock {
+	return &GameClock{scale: 1}
+}
+
+// Move wall clock time forward by wallDt, returns how much game time passed
+func (this *GameClock) Advance(wallDt float64) (dt float64) {
+
+	if this.paused {
+		return 0
+	}
+
+	dt = wallDt * this.scale
+	this.time += dt
+	return dt
+}
+
+// Run game time scale times as fast as wall clock time, from 0.1 for slow motion up to 10 to fast forward
+func (this *GameClock) SetScale(scale float64) {
+	this.scale = math.Max(minTimeScale, math.Min(scale, maxTimeScale))
+}
+
+// Seconds of game time that pass each second of wall clock time
+func (this *GameClock) Scale() float64 {
+	return this.scale
+}
+
+// Stop game time
+func (this *GameClock) Pause() {
+	this.paused = true
+}
+
+// Start game time again
+func (this *GameClock) Resume() {
+	this.paused = false
+}
+
+// If game time is stopped
+func (this *GameClock) Paused() bool {
+	return this.paused
+}
+
+// Total game time in seconds
+func (this *GameClock) Time() float64 {
+	return this.time
+}
+
+// Source of wall clock time for the game loop, simulated to run games deterministically and faster than real time
+type Clock interface {
+
+	// Current wall clock time
+	Now() time.Time
+}
+
+// Clock that reads the system time
+type SystemClock struct{}
+
+var _ Clock = SystemClock{}
+
+// Current system time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Clock that only moves when it is advanced
+type SimulatedClock struct {
+	now time.Time
+}
+
+var _ Clock = &SimulatedClock{}
+
+// Construct a SimulatedClock starting at start
+func NewSimulatedClock(start time.Time) *SimulatedClock {
+	return &SimulatedClock{now: start}
+}
+
+// Current simulated time
+func (this *SimulatedClock) Now() time.Time {
+	return this.now
+}
+
+// Move simulated time forward by duration
+func (this *SimulatedClock) Advance(duration time.Duration) {
+	this.now = this.now.Add(duration)
+}
+
+// Longest time in seconds counted between two frames, a stall or jump of the clock longer than this counts as this
+var maxFrameSeconds = 1.0
+
+// Gap opening up between the wall clock and the monotonic clock over one frame that is taken as the wall clock being
+// stepped, by NTP or by waking from a suspend
+var clockStepThreshold = time.Second
+
+// Measures the time between frames by the monotonic clock, so a step of the wall clock never moves the game backwards
+// or flings the ball across the field, and notices when the wall clock is stepped
+type FrameTimer struct {
+	previous time.Time
+}
+
+// Construct a FrameTimer timing frames from start
+func NewFrameTimer(start time.Time) *FrameTimer {
+	return &FrameTimer{previous: start}
+}
+
+// Seconds from the last frame to now, from 0 up to maxFrameSeconds, 0 for the first frame
+func (this *FrameTimer) Elapsed(now time.Time) float64 {
+
+	previous := this.previous
+	this.previous = now
+	if previous.IsZero() {
+		return 0
+	}
+
+	// times from time.Now carry a monotonic reading that Sub uses, Round(0) strips it to compare the wall clock
+	elapsed := now.Sub(previous)
+	if step := now.Round(0).Sub(previous.Round(0)) - elapsed; step > clockStepThreshold || step < -clockStepThreshold {
+		log.Print("Wall clock stepped by ", step.Round(time.Millisecond), ", frames are timed by the monotonic clock")
+		GameMetrics.Observe("clock_step_seconds", step.Seconds())
+	}
+
+	return math.Max(0, math.Min(elapsed.Seconds(), maxFrameSeconds))
+}
@@ -0,0 +1,31 @@
+package pong
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// Frames should be timed by the monotonic clock, never going backwards or jumping further than maxFrameSeconds
+func Test_FrameTimer(t *testing.T) {
+
+	start := time.Now()
+	timer := NewFrameTimer(start)
+
+	// the wall clock stepped back an hour while 20ms passed
+	stepped := start.Add(20 * time.Millisecond).Add(-time.Hour)
+	if dt := timer.Elapsed(start.Add(20 * time.Millisecond)); math.Abs(dt-0.02) > 1e-9 {
+		t.Fatal("Frame took", dt)
+	}
+	if dt := timer.Elapsed(stepped.Round(0)); dt != 0 {
+		t.Fatal("Frame after the wall clock went backwards took", dt)
+	}
+	if dt := timer.Elapsed(start.Add(10 * time.Minute)); dt != maxFrameSeconds {
+		t.Fatal("Frame after a jump of minutes took", dt)
+	}
+
+	var first FrameTimer
+	if dt := first.Elapsed(start); dt != 0 {
+		t.Fatal("First frame took", dt)
+	}
+}
@@ -0,0 +1,21 @@
+// +build !fixedpoint
+
+package pong
+
+// Color math used by ColorAt in the render hot path, build with -tags fixedpoint for the integer versions in colormath_fixed.go
+
+// Alpha fading from 255 at distance 0 to 0 at distance length
+func Falloff(distance, length float64) uint8 {
+	if distance <= 0 {
+		return 255
+	}
+	if distance >= length {
+		return 0
+	}
+	return uint8((length - distance) / length * 255.0)
+}
+
+// Scale a color channel by amount from 0 to 1
+func ScaleChannel(value uint8, amount float64) uint8 {
+	return uint8(float64(value) * amount)
+}
@@ -0,0 +1,31 @@
+// +build fixedpoint
+
+package pong
+
+// Integer versions of the color math in colormath.go, for ARMv6 where float to int conversions are slow
+
+// number of fractional bits in a fixed point value
+const fixedShift = 16
+
+// Convert to fixed point with fixedShift fractional bits
+func toFixed(value float64) int32 {
+	return int32(value * (1 << fixedShift))
+}
+
+// Alpha fading from 255 at distance 0 to 0 at distance length
+func Falloff(distance, length float64) uint8 {
+	d, l := toFixed(distance), toFixed(length)
+	if d <= 0 {
+		return 255
+	}
+	if d >= l || l < 1<<8 {
+		return 0
+	}
+	// drop to 8 fractional bits so the product fits in 32 bits, ARMv6 has no 64 bit divide
+	return uint8(((l - d) >> 8) * 255 / (l >> 8))
+}
+
+// Scale a color channel by amount from 0 to 1
+func ScaleChannel(value uint8, amount float64) uint8 {
+	return uint8((int32(value) * (toFixed(amount) >> 8)) >> (fixedShift - 8))
+}
@@ -0,0 +1,100 @@
+package pong
+
+import (
+	"math"
+	"testing"
+	"testing/quick"
+)
+
+// Helper assert that allows for rounding differences between the float and fixed point math
+func AssertClose(actual, expected int, message string, t *testing.T) {
+	if actual < expected-1 || actual > expected+1 {
+		t.Fatal(message, actual, "vs expected", expected)
+	}
+}
+
+// Color math should give the same results with or without the fixedpoint build tag
+func Test_ColorMath(t *testing.T) {
+	AssertClose(int(Falloff(0, 7)), 255, "Falloff at 0", t)
+	AssertClose(int(Falloff(3.5, 7)), 127, "Falloff at half", t)
+	AssertClose(int(Falloff(7, 7)), 0, "Falloff at length", t)
+	AssertClose(int(Falloff(0.25, 1)), 191, "Falloff of ball", t)
+
+	AssertClose(int(ScaleChannel(200, 0.5)), 100, "Scale by half", t)
+	AssertClose(int(ScaleChannel(255, 1)), 255, "Scale by one", t)
+	AssertClose(int(ScaleChannel(255, 0)), 0, "Scale by zero", t)
+
+	lighter := RGBA{0, 100, 255, 255}.Lighter()
+	AssertClose(int(lighter.R), 128, "Lighter red", t)
+	AssertClose(int(lighter.G), 178, "Lighter green", t)
+	AssertClose(int(lighter.B), 255, "Lighter blue", t)
+	AssertClose(int(lighter.A), 255, "Lighter alpha", t)
+}
+
+// Blending should never push a channel outside the two colors blended, scaling should never brighten, and falloff
+// should only ever fade with distance
+func Test_ColorMath_Properties(t *testing.T) {
+
+	blend := func(foreground, background RGBA) bool {
+		color := foreground.BlendWith(background)
+		channels := [][3]uint8{
+			{color.R, foreground.R, background.R},
+			{color.G, foreground.G, background.G},
+			{color.B, foreground.B, background.B},
+		}
+		for _, channel := range channels {
+			low, high := channel[1], channel[2]
+			if low > high {
+				low, high = high, low
+			}
+			if channel[0] < low || channel[0] > high {
+				t.Log(foreground, "over", background, "blended to", color)
+				return false
+			}
+		}
+		if foreground.A == 0 && (color.R != background.R || color.G != background.G || color.B != background.B) {
+			t.Log("Transparent", foreground, "changed", background, "to", color)
+			return false
+		}
+		return color.A == foreground.A
+	}
+
+	scale := func(value uint8, amount float64) bool {
+		amount = math.Mod(math.Abs(amount), 1)
+		if math.IsNaN(amount) {
+			amount = 0
+		}
+		return ScaleChannel(value, amount) <= value
+	}
+
+	falloff := func(near, far, length float64) bool {
+		near, far, length = math.Mod(math.Abs(near), 100), math.Mod(math.Abs(far), 100), math.Mod(math.Abs(length), 100)
+		if math.IsNaN(near) || math.IsNaN(far) || math.IsNaN(length) {
+			return true
+		}
+		if near > far {
+			near, far = far, near
+		}
+		return Falloff(near, length) >= Falloff(far, length)
+	}
+
+	for _, property := range []interface{}{blend, scale, falloff} {
+		if err := quick.Check(property, &quick.Config{MaxCount: 2000}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+var benchmarkChannel uint8
+
+func BenchmarkFalloff(b *testing.B) {
+	for iteration := 0; iteration < b.N; iteration++ {
+		benchmarkChannel += Falloff(float64(iteration%70)*0.1, 7)
+	}
+}
+
+func BenchmarkScaleChannel(b *testing.B) {
+	for iteration := 0; iteration < b.N; iteration++ {
+		benchmarkChannel += ScaleChannel(uint8(iteration), 0.3)
+	}
+}
@@ -0,0 +1,148 @@
+package pong
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Most crash reports kept on disk, older ones are removed as new ones are written
+var crashReportLimit int = 10
+
+// Keeps the last lines written to it, hook it up to the log to know what led up to a crash
+type EventLog struct {
+	lock sync.Mutex
+
+	// ring of lines, next is the index the next line is written to
+	lines []string
+	next  int
+	full  bool
+}
+
+// Construct an EventLog keeping the last count lines
+func NewEventLog(count int) *EventLog {
+	return &EventLog{lines: make([]string, count)}
+}
+
+// Remember each line in data, the log writes a single line at a time
+func (this *EventLog) Write(data []byte) (int, error) {
+
+	this.lock.Lock()
+	this.lines[this.next] = strings.TrimRight(string(data), "\n")
+	this.next = (this.next + 1) % len(this.lines)
+	if this.next == 0 {
+		this.full = true
+	}
+	this.lock.Unlock()
+
+	return len(data), nil
+}
+
+// Lines remembered, oldest first
+func (this *EventLog) Lines() []string {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if !this.full {
+		return append([]string{}, this.lines[:this.next]...)
+	}
+	return append(append([]string{}, this.lines[this.next:]...), this.lines[:this.next]...)
+}
+
+// What was going on when the game panicked
+type CrashReport struct {
+	XMLName xml.Name `xml:"CrashReport"`
+
+	Time time.Time
+
+	// value the game panicked with, and the stack of the goroutine that panicked
+	Panic string
+	Stack string
+
+	// what the game loop was doing
+	Activity string
+
+	// last lines logged before the crash, oldest first
+	Events []string `xml:"Event"`
+
+	// settings the game ran with, without the tokens as reports can be read from other machines
+	Settings SettingsData
+
+	// last frame sent to the display, each led as rrggbb
+	LastFrame string
+
+	// frames leading up to the crash, oldest first, saved next to the report as a png strip
+	Frames [][]RGBA `xml:"-"`
+}
+
+// Construct a CrashReport of a panic with value cause, while the game loop was doing activity
+func NewCrashReport(cause interface{}, stack []byte, activity string, events []string, lastFrame []RGBA) *CrashReport {
+
+	return &CrashReport{
+		Time:      time.Now(),
+		Panic:     fmt.Sprint(cause),
+		Stack:     string(stack),
+		Activity:  activity,
+		Events:    events,
+		Settings:  redactSettings(Settings),
+		LastFrame: strings.Join(hexColors(lastFrame), " "),
+	}
+}
+
+// Copy of settings with the tokens that let anyone who reads them upload stats or profile the game blanked out
+func redactSettings(settings SettingsData) SettingsData {
+	for _, token := range []*string{&settings.UploadToken, &settings.AdminToken} {
+		if *token != "" {
+			*token = "redacted"
+		}
+	}
+	return settings
+}
+
+// Write the report to a new file in dir, and its frames to a png of the same name, removing the oldest reports past
+// crashReportLimit, returns the file written
+func (this *CrashReport) Save(dir string) (string, error) {
+
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return "", err
+	}
+
+	fileData, err := xml.MarshalIndent(this, "", "\t")
+	if err != nil {
+		return "", err
+	}
+	name := filepath.Join(dir, "crash-"+this.Time.Format("20060102-150405.000"))
+	path := name + ".xml"
+	if err := ioutil.WriteFile(path, fileData, 0600); err != nil {
+		return "", err
+	}
+	if len(this.Frames) > 0 {
+		if err := saveFramesPng(name+".png", this.Frames); err != nil {
+			return path, err
+		}
+	}
+
+	// the time in the names sorts oldest first
+	for _, pattern := range []string{"crash-*.xml", "crash-*.png"} {
+		files, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return path, err
+		}
+		sort.Strings(files)
+		for len(files) > crashReportLimit {
+			if err := os.Remove(files[0]); err != nil {
+				return path, err
+			}
+			files = files[1:]
+		}
+	}
+
+	return path, nil
+}
@@ -0,0 +1,87 @@
+package pong
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The event log should keep only the newest lines, oldest first
+func Test_EventLog(t *testing.T) {
+	events := NewEventLog(3)
+
+	for line := 0; line < 5; line++ {
+		fmt.Fprintln(events, "event", line)
+	}
+
+	lines := events.Lines()
+	if len(lines) != 3 || lines[0] != "event 2" || lines[2] != "event 4" {
+		t.Fatal("Kept", lines)
+	}
+}
+
+// Saving reports should keep only the newest crashReportLimit of them
+func Test_CrashReport_Save(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "crashes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer func(limit int) { crashReportLimit = limit }(crashReportLimit)
+	crashReportLimit = 2
+
+	start := time.Date(2020, 6, 1, 12, 0, 0, 0, time.Local)
+	for crash := 0; crash < 4; crash++ {
+		report := NewCrashReport("boom", nil, "Rally", []string{"served"}, []RGBA{{255, 0, 16, 255}})
+		report.Frames = [][]RGBA{{{255, 0, 16, 255}}}
+		report.Time = start.Add(time.Duration(crash) * time.Second)
+		if _, err := report.Save(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reports, _ := filepath.Glob(filepath.Join(dir, "crash-*.xml"))
+	Assert(len(reports), 2, "Reports kept", t)
+	if filepath.Base(reports[0]) != "crash-20200601-120002.000.xml" {
+		t.Fatal("Kept", reports)
+	}
+	strips, _ := filepath.Glob(filepath.Join(dir, "crash-*.png"))
+	Assert(len(strips), 2, "Frame strips kept", t)
+}
+
+// Reports should leave out the tokens in the settings and only be readable by the user the game runs as
+func Test_CrashReport_Redacted(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "crashes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer func(settings SettingsData) { Settings = settings }(Settings)
+	Settings.UploadToken, Settings.AdminToken = "upload-secret", "admin-secret"
+
+	path, err := NewCrashReport("boom", nil, "Rally", nil, nil).Save(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileData, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(fileData), "secret") {
+		t.Fatal("Report has the tokens in it")
+	}
+	if Settings.UploadToken != "upload-secret" {
+		t.Fatal("Redacting changed the settings")
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatal("Report written with", info.Mode(), err)
+	}
+}
@@ -0,0 +1,200 @@
+package pong
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Seconds a phone counts as connected after it was last heard from
+var crowdTimeout = 10 * time.Second
+
+// Buttons pushed by a crowd of phones on each side, a side's button is down while enough of its crowd tapped within
+// the last CrowdWindowSeconds, the number needed growing with the number of phones connected to that side
+type CrowdInput struct {
+
+	// phones on either side by their address, a phone can't make up more of those to stand in for a crowd
+	members map[string]*crowdMember
+
+	// source of the time taps are counted at
+	now func() time.Time
+
+	// phones tap from the web server while the game reads the buttons
+	lock sync.Mutex
+}
+
+// A phone in the crowd
+type crowdMember struct {
+	left bool
+
+	// when the phone was last heard from, and when it last tapped
+	seen, tapped time.Time
+}
+
+var _ ButtonInput = &CrowdInput{}
+
+// Construct a CrowdInput nobody has joined yet, counting taps at the time given by now
+func NewCrowdInput(now func() time.Time) *CrowdInput {
+	return &CrowdInput{members: map[string]*crowdMember{}, now: now}
+}
+
+// Put the phone at address in the crowd of the left or right side
+func (this *CrowdInput) Join(address string, left bool) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	member, ok := this.members[address]
+	if !ok {
+		member = &crowdMember{}
+		this.members[address] = member
+	}
+	member.left = left
+	member.seen = this.now()
+}
+
+// Count a tap from the phone at address toward its side's button, taps closer together than CrowdTapSeconds are
+// refused so one phone can't stand in for a crowd
+func (this *CrowdInput) Tap(address string) error {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	member, ok := this.members[address]
+	if !ok {
+		return errors.New("Pick a side before tapping")
+	}
+
+	now := this.now()
+	member.seen = now
+	if !member.tapped.IsZero() && now.Sub(member.tapped).Seconds() < Settings.CrowdTapSeconds {
+		return errors.New("Tapping too fast")
+	}
+	member.tapped = now
+	return nil
+}
+
+// Phones connected to each side
+func (this *CrowdInput) Connected() (left, right int) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	now := this.now()
+	for address, member := range this.members {
+		switch {
+		case now.Sub(member.seen) > crowdTimeout:
+			delete(this.members, address)
+		case member.left:
+			left++
+		default:
+			right++
+		}
+	}
+	return
+}
+
+// Taps needed within the window to push the button of a side with connected phones, at least one
+func crowdQuorum(connected int) int {
+	return int(math.Max(1, math.Ceil(float64(connected)*Settings.CrowdQuorum)))
+}
+
+// If enough of the crowd on the left or right side tapped recently to hold its button down
+func (this *CrowdInput) pushed(left bool) bool {
+
+	connected, tapped := 0, 0
+	this.lock.Lock()
+	now := this.now()
+	for _, member := range this.members {
+		if member.left != left || now.Sub(member.seen) > crowdTimeout {
+			continue
+		}
+		connected++
+		if !member.tapped.IsZero() && now.Sub(member.tapped).Seconds() <= Settings.CrowdWindowSeconds {
+			tapped++
+		}
+	}
+	this.lock.Unlock()
+
+	return connected > 0 && tapped >= crowdQuorum(connected)
+}
+
+// true while enough of the left crowd is tapping
+func (this *CrowdInput) LeftButton() bool {
+	return this.pushed(true)
+}
+
+// true while enough of the right crowd is tapping
+func (this *CrowdInput) RightButton() bool {
+	return this.pushed(false)
+}
+
+// Serve the phones connected to each side and the taps each needs as json, POST side as left or right to join a
+// crowd, or POST without a side to tap. Phones are told apart by their address, as predictions are
+func (this *CrowdInput) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method == "POST" {
+		address := spectatorAddress(r)
+		switch side := r.FormValue("side"); side {
+		case "left", "right":
+			this.Join(address, side == "left")
+			log.Print("Phone joined the ", side, " crowd")
+		case "":
+			if err := this.Tap(address); err != nil {
+				http.Error(w, err.Error(), http.StatusTooManyRequests)
+				return
+			}
+		default:
+			http.Error(w, "Side has to be left or right", http.StatusBadRequest)
+			return
+		}
+	}
+
+	left, right := this.Connected()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Left, Right             int
+		LeftQuorum, RightQuorum int
+	}{left, right, crowdQuorum(left), crowdQuorum(right)})
+}
+
+// Serve a page for a phone to pick a side and tap along with its crowd
+func (this *CrowdInput) ServePage(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `
+<html>
+	<head>
+		<meta name="viewport" content="width=device-width, initial-scale=1"/>
+		<script type="text/javascript"><!--
+		function send(form)
+		{
+			fetch("api/crowd", {method: "POST", body: new URLSearchParams(form)}).then(function(response) {
+				if (response.ok) {
+					response.json().then(function(crowd) {
+						document.getElementById("crowd").textContent =
+							crowd.Left + " on the left, " + crowd.Right + " on the right";
+					});
+				}
+			});
+		}
+		function join(side)
+		{
+			send({side: side});
+			document.getElementById("tap").disabled = false;
+		}
+		--></script>
+	</head>
+	<body>
+		<p>
+			<button onclick="join('left')">Left</button>
+			<button onclick="join('right')">Right</button>
+		</p>
+		<button id="tap" disabled ontouchstart="send({})" onmousedown="send({})" style="width: 100%; height: 60%">Hit!</button>
+		<p id="crowd"></p>
+	</body>
+</html>`)
+}
@@ -0,0 +1,92 @@
+package pong
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// A side's button should only be down while enough of its crowd tapped within the window, and one phone shouldn't be
+// able to tap faster than allowed
+func Test_CrowdInput(t *testing.T) {
+
+	Settings.CrowdWindowSeconds = 0.5
+	Settings.CrowdQuorum = 0.5
+	Settings.CrowdTapSeconds = 0.2
+
+	now := time.Unix(0, 0)
+	crowd := NewCrowdInput(func() time.Time { return now })
+
+	if err := crowd.Tap("ann"); err == nil {
+		t.Fatal("Tapped without picking a side")
+	}
+	for _, session := range []string{"ann", "bob", "cat", "dan"} {
+		crowd.Join(session, true)
+	}
+	crowd.Join("eve", false)
+
+	left, right := crowd.Connected()
+	Assert(left, 4, "Left crowd", t)
+	Assert(right, 1, "Right crowd", t)
+
+	crowd.Tap("ann")
+	if crowd.LeftButton() {
+		t.Fatal("One of four phones pushed the button")
+	}
+	if err := crowd.Tap("ann"); err == nil {
+		t.Fatal("Tapped again straight away")
+	}
+	now = now.Add(300 * time.Millisecond)
+	crowd.Tap("bob")
+	if !crowd.LeftButton() || crowd.RightButton() {
+		t.Fatal("Half the left crowd tapping didn't push only the left button")
+	}
+
+	now = now.Add(300 * time.Millisecond)
+	if crowd.LeftButton() {
+		t.Fatal("Button stayed down after the first tap left the window")
+	}
+
+	// phones that stop checking in no longer count toward the quorum
+	now = now.Add(crowdTimeout)
+	crowd.Join("eve", false)
+	crowd.Tap("eve")
+	if left, _ := crowd.Connected(); left != 0 || !crowd.RightButton() {
+		t.Fatal("Left crowd is still", left, "phones")
+	}
+}
+
+// A phone should count once however many cookies it sends, so one client can't make up a crowd of its own
+func Test_CrowdInput_Address(t *testing.T) {
+
+	Settings.CrowdQuorum = 0.5
+	Settings.CrowdTapSeconds = 0.2
+	crowd := NewCrowdInput(time.Now)
+
+	post := func(address, cookie, side string) int {
+		form := url.Values{"side": {side}}
+		if side == "" {
+			form = url.Values{}
+		}
+		request := httptest.NewRequest("POST", "/api/crowd", strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		request.AddCookie(&http.Cookie{Name: "pongSpectator", Value: cookie})
+		request.RemoteAddr = address
+		recorder := httptest.NewRecorder()
+		crowd.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	for _, cookie := range []string{"a", "b", "c", "d"} {
+		Assert(post("10.0.0.2:5000", cookie, "left"), 200, "Join status", t)
+	}
+	Assert(post("10.0.0.3:5000", "e", "left"), 200, "Join status", t)
+	left, _ := crowd.Connected()
+	Assert(left, 2, "Phones in the left crowd", t)
+
+	Assert(post("10.0.0.2:5001", "f", ""), 200, "Tap status", t)
+	Assert(post("10.0.0.2:5002", "g", ""), 429, "Status of a second tap from the same phone", t)
+}
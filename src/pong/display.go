@@ -5,9 +5,13 @@ import (
 	"image"
 	"image/color"
 	"image/png"
+	"io"
 	"log"
 	"net/http"
+	"sync"
 	"time"
+
+	"github.com/brandonagr/pongpi/src/pong/tables"
 )
 
 // A display that can render the field
@@ -15,9 +19,58 @@ type Display interface {
 	Render([]RGBA)
 }
 
+// A display whose driver can be reset when it stops responding
+type ResettableDisplay interface {
+	Display
+
+	// Reopen the underlying device
+	Reset() error
+}
+
+// A display that can fail to show a frame, such as leds on a bus, a frame that fails is dropped
+type FallibleDisplay interface {
+	Display
+
+	// First error rendering since the last call, nil if every frame was shown
+	RenderError() error
+}
+
+// Embedded by displays that pass frames on to another display, so resetting or closing them reaches the device at the
+// end of the chain
+type wrappedDisplay struct {
+	display Display
+}
+
+// Reset the wrapped display if it can be
+func (this wrappedDisplay) Reset() error {
+	if resettable, ok := this.display.(ResettableDisplay); ok {
+		return resettable.Reset()
+	}
+	return nil
+}
+
+// Error rendering to the wrapped display, if it can fail
+func (this wrappedDisplay) RenderError() error {
+	if fallible, ok := this.display.(FallibleDisplay); ok {
+		return fallible.RenderError()
+	}
+	return nil
+}
+
+// Close the wrapped display if it can be
+func (this wrappedDisplay) Close() error {
+	if closer, ok := this.display.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
 // Web display
 type WebDisplay struct {
 	previousRender []RGBA
+
+	// leds in each row of the image
+	width int
 }
 
 var testWebDisplay Display = &WebDisplay{}
@@ -25,10 +78,11 @@ var testWebDisplay Display = &WebDisplay{}
 // Create a new WebDisplay
 func NewWebDisplay(settings SettingsData) *WebDisplay {
 	display := &WebDisplay{
-		previousRender: make([]RGBA, settings.LedCount),
+		previousRender: make([]RGBA, settings.Leds()),
+		width:          settings.LedCount,
 	}
 
-	go display.LaunchWebServer()
+	display.RegisterHandlers()
 	return display
 }
 
@@ -38,14 +92,11 @@ func (this *WebDisplay) Render(data []RGBA) {
 	this.previousRender = data
 }
 
-// Launches the webserver
-func (this *WebDisplay) LaunchWebServer() {
+// Adds the display pages to the webserver started by StartWebServer
+func (this *WebDisplay) RegisterHandlers() {
 
 	http.HandleFunc("/", htmlPageHandler)
 	http.HandleFunc("/image/", func(w http.ResponseWriter, r *http.Request) { this.imageHandler(w, r) })
-
-	log.Print("Server listening on 8080")
-	http.ListenAndServe(":8080", nil)
 }
 
 // Serve static html page
@@ -59,8 +110,29 @@ func htmlPageHandler(w http.ResponseWriter, r *http.Request) {
 			setTimeout(reloadpic, 100);
         }
         setTimeout(reloadpic, 100)
+
+		function predict(side)
+		{
+			fetch("api/predictions", {method: "POST", body: new URLSearchParams({side: side})})
+				.then(function(response) { return response.ok ? response.json() : null; })
+				.then(function(crowd) {
+					if (crowd) {
+						document.getElementById("crowd").textContent = crowd.Left + " say left, " + crowd.Right + " say right";
+					}
+				});
+		}
 	--></script></head>
-	<body><img id="gameBoard" src="image/test.png" height="24" width="1024"/></body>
+	<body>
+		<img id="gameBoard" src="image/test.png" height="24" width="1024"/>
+		<p>Who wins the next match?
+			<button onclick="predict('left')">Left</button>
+			<button onclick="predict('right')">Right</button>
+			<span id="crowd"></span>
+		</p>
+		<p><a href="queue">Sign up to play next</a></p>
+		<p><a href="effects">Tune the effects</a></p>
+		<p><a href="brightness">Brightness</a></p>
+	</body>
 </html>`)
 
 }
@@ -75,17 +147,22 @@ func (this *WebDisplay) imageHandler(w http.ResponseWriter, r *http.Request) {
 
 	data := this.previousRender
 
+	// a matrix is drawn a row at a time from the top
 	spacing := 1
-	width, height := len(data), 1
+	width := this.width
+	if width <= 0 || len(data)%width != 0 {
+		width = len(data)
+	}
+	height := 1
+	if width > 0 {
+		height = len(data) / width
+	}
 	image := image.NewRGBA(image.Rect(0, 0, width*spacing, height))
 
-	for dataIndex := 0; dataIndex < width; dataIndex++ {
-
-		for y := 0; y < height; y++ {
-			displayedColor := color.RGBA(data[dataIndex])
-			displayedColor.A = 255
-			image.Set(dataIndex*spacing, y, displayedColor)
-		}
+	for dataIndex := range data {
+		displayedColor := color.RGBA(data[dataIndex])
+		displayedColor.A = 255
+		image.Set(dataIndex%width*spacing, dataIndex/width, displayedColor)
 	}
 
 	png.Encode(w, image)
@@ -96,56 +173,227 @@ func (this *WebDisplay) imageHandler(w http.ResponseWriter, r *http.Request) {
 type LedDisplay struct {
 	bus *SpiBus
 
+	// settings the bus was opened with, used to reopen it
+	busFilePath string
+	busSpeedHz  uint
+	busLock     sync.Mutex
+
 	expectedColors int
 	byteData       []byte
+
+	// if the strip has to be sent every led next frame, such as after the bus is reset
+	fullWrite bool
+
+	// first error rendering since RenderError was last called
+	renderErr error
 }
 
-var testLedDisplay Display = &LedDisplay{}
+var testLedDisplay ResettableDisplay = &LedDisplay{}
+var _ FallibleDisplay = &LedDisplay{}
 
-// Construct an LedDisplay
-func NewLedDisplay(settings SettingsData) *LedDisplay {
-	return &LedDisplay{
-		bus:            NewSpiBus(settings.SpiFilePath, settings.SpiBusSpeedHz),
-		expectedColors: settings.LedCount,
-		byteData:       make([]byte, 4+settings.LedCount*3+4), // +8 is for the null bytes on front and end of data
+// Construct an LedDisplay sending ledCount leds a frame
+func NewLedDisplay(settings SettingsData, ledCount int) (*LedDisplay, error) {
+
+	if ledCount <= 0 {
+		return nil, fmt.Errorf("Led display on %v needs at least one led, not %v", settings.SpiFilePath, ledCount)
+	}
+	bus, err := NewSpiBus(settings.SpiFilePath, settings.SpiBusSpeedHz)
+	if err != nil {
+		return nil, err
+	}
+
+	frameSize := ledFrameSize(ledCount)
+	if bufferSize := spidevBufferSize(); bufferSize > 0 && frameSize > bufferSize {
+		log.Print("Frame of ", frameSize, " bytes is larger than the spidev buffer of ", bufferSize, ", raise spidev.bufsiz")
 	}
+	GameMetrics.Observe("spi_speed_hz", float64(settings.SpiBusSpeedHz))
+
+	return &LedDisplay{
+		bus:            bus,
+		busFilePath:    settings.SpiFilePath,
+		busSpeedHz:     settings.SpiBusSpeedHz,
+		expectedColors: ledCount,
+		byteData:       make([]byte, frameSize),
+		fullWrite:      true,
+	}, nil
 }
 
-// Render the colorData to the SPI bus
+// Bytes needed to send ledCount leds in a single write, 4 null bytes on the front and at least 4 on the end,
+// padded to whole 32 bit words so the driver can hand the buffer straight to DMA
+func ledFrameSize(ledCount int) int {
+	return (4 + ledCount*3 + 4 + 3) &^ 3
+}
+
+// Round up to a whole number of 32 bit words
+func wordAligned(size int) int {
+	return (size + 3) &^ 3
+}
+
+// Render the colorData to the SPI bus, a frame that isn't the expected length or can't be written is dropped and the
+// error kept for RenderError
 func (this *LedDisplay) Render(colorData []RGBA) {
 	if len(colorData) != this.expectedColors {
-		log.Fatal("colorData was not the expected length of ", this.expectedColors, " saw ", len(colorData))
+		this.fail(fmt.Errorf("Frame of %v leds isn't the expected %v", len(colorData), this.expectedColors))
+		return
 	}
 
+	writeEnd := this.encode(colorData)
+
+	this.busLock.Lock()
+	bus := this.bus
+	fullWrite := this.fullWrite
+	this.fullWrite = false
+	this.busLock.Unlock()
+
+	if bus == nil {
+		// closed
+		return
+	}
+	if fullWrite {
+		this.write(bus, this.byteData)
+		return
+	}
+	if writeEnd == 0 {
+		return
+	}
+
+	// temporarily zero the bytes after the last changed led so they latch the data
+	latchEnd := wordAligned(writeEnd + 4)
+	var saved, latch [8]byte
+	copy(saved[:], this.byteData[writeEnd:latchEnd])
+	copy(this.byteData[writeEnd:latchEnd], latch[:])
+	this.write(bus, this.byteData[:latchEnd])
+	copy(this.byteData[writeEnd:latchEnd], saved[:])
+}
+
+// Send a whole frame in one write, measuring how long it takes
+func (this *LedDisplay) write(bus *SpiBus, data []byte) {
+
+	startTime := time.Now()
+	if _, err := bus.Write(data); err != nil {
+		this.fail(err)
+		// the next frame is sent whole so no led is left showing a torn frame
+		this.busLock.Lock()
+		this.fullWrite = true
+		this.busLock.Unlock()
+	}
+
+	GameMetrics.ObserveSince("spi_write_ms", startTime)
+	GameMetrics.Observe("spi_write_bytes", float64(len(data)))
+}
+
+// Keep err for RenderError unless an earlier error hasn't been collected yet
+func (this *LedDisplay) fail(err error) {
+	if this.renderErr == nil {
+		this.renderErr = err
+	}
+}
+
+// First error rendering since the last call, nil if every frame was written
+func (this *LedDisplay) RenderError() error {
+	err := this.renderErr
+	this.renderErr = nil
+	return err
+}
+
+// Serialize colorData into byteData, returns the end of the last led that changed or 0 if none did
+func (this *LedDisplay) encode(colorData []RGBA) (writeEnd int) {
+
+	// only the leds up to the last one that changed have to be sent, the rest keep their color
 	for colorIndex := 0; colorIndex < len(colorData); colorIndex++ {
 		color := colorData[colorIndex]
 		byteIndex := colorIndex*3 + 4
 
-		this.byteData[byteIndex+0] = gammaCorrectionLookup[color.G] | 0x80
-		this.byteData[byteIndex+1] = gammaCorrectionLookup[color.R] | 0x80
-		this.byteData[byteIndex+2] = gammaCorrectionLookup[color.B] | 0x80
-	}
-
-	this.bus.Write(this.byteData)
-}
-
-// based on a pull request found at http://forums.adafruit.com/viewtopic.php?f=47&t=26591
-// is basically precomputing x = pow(i / 255, 3.0) * 127
-var gammaCorrectionLookup [256]uint8 = [256]uint8{
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 1,
-	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 2, 2, 2, 2,
-	2, 2, 2, 2, 2, 3, 3, 3, 3, 3, 3, 3, 3, 4, 4, 4,
-	4, 4, 4, 4, 5, 5, 5, 5, 5, 6, 6, 6, 6, 6, 7, 7,
-	7, 7, 7, 8, 8, 8, 8, 9, 9, 9, 9, 10, 10, 10, 10, 11,
-	11, 11, 12, 12, 12, 13, 13, 13, 13, 14, 14, 14, 15, 15, 16, 16,
-	16, 17, 17, 17, 18, 18, 18, 19, 19, 20, 20, 21, 21, 21, 22, 22,
-	23, 23, 24, 24, 24, 25, 25, 26, 26, 27, 27, 28, 28, 29, 29, 30,
-	30, 31, 32, 32, 33, 33, 34, 34, 35, 35, 36, 37, 37, 38, 38, 39,
-	40, 40, 41, 41, 42, 43, 43, 44, 45, 45, 46, 47, 47, 48, 49, 50,
-	50, 51, 52, 52, 53, 54, 55, 55, 56, 57, 58, 58, 59, 60, 61, 62,
-	62, 63, 64, 65, 66, 67, 67, 68, 69, 70, 71, 72, 73, 74, 74, 75,
-	76, 77, 78, 79, 80, 81, 82, 83, 84, 85, 86, 87, 88, 89, 90, 91,
-	92, 93, 94, 95, 96, 97, 98, 99, 100, 101, 102, 104, 105, 106, 107, 108,
-	109, 110, 111, 113, 114, 115, 116, 117, 118, 120, 121, 122, 123, 125, 126, 127,
+		g := tables.Gamma(color.G) | 0x80
+		r := tables.Gamma(color.R) | 0x80
+		b := tables.Gamma(color.B) | 0x80
+		if this.byteData[byteIndex+0] != g || this.byteData[byteIndex+1] != r || this.byteData[byteIndex+2] != b {
+			this.byteData[byteIndex+0] = g
+			this.byteData[byteIndex+1] = r
+			this.byteData[byteIndex+2] = b
+			writeEnd = byteIndex + 3
+		}
+	}
+
+	return
+}
+
+// Close the SPI bus, frames rendered afterwards are dropped
+func (this *LedDisplay) Close() error {
+
+	this.busLock.Lock()
+	bus := this.bus
+	this.bus = nil
+	this.busLock.Unlock()
+
+	if bus == nil {
+		return nil
+	}
+	return bus.Close()
+}
+
+// Reopen the SPI bus, closing the old one unblocks any write stuck on it
+func (this *LedDisplay) Reset() error {
+
+	bus, err := NewSpiBus(this.busFilePath, this.busSpeedHz)
+	if err != nil {
+		return err
+	}
+
+	this.busLock.Lock()
+	oldBus := this.bus
+	if oldBus == nil {
+		// closed while the new bus was opening
+		this.busLock.Unlock()
+		return bus.Close()
+	}
+	this.bus = bus
+	this.fullWrite = true
+	this.busLock.Unlock()
+
+	return oldBus.Close()
+}
+
+// Display that scales the brightness of everything rendered before passing it on
+type DimmedDisplay struct {
+	display Display
+
+	// 0 to 1, amount each color channel is scaled by
+	brightness float64
+
+	dimmedData []RGBA
+}
+
+var testDimmedDisplay Display = &DimmedDisplay{}
+
+// Construct a DimmedDisplay wrapping display
+func NewDimmedDisplay(display Display, brightness float64) *DimmedDisplay {
+	return &DimmedDisplay{
+		display:    display,
+		brightness: brightness,
+	}
+}
+
+// Change the amount each color channel is scaled by
+func (this *DimmedDisplay) SetBrightness(brightness float64) {
+	this.brightness = brightness
+}
+
+// Scale the colorData and render it to the wrapped display
+func (this *DimmedDisplay) Render(colorData []RGBA) {
+
+	if len(this.dimmedData) != len(colorData) {
+		this.dimmedData = make([]RGBA, len(colorData))
+	}
+
+	for colorIndex, color := range colorData {
+		this.dimmedData[colorIndex] = RGBA{
+			ScaleChannel(color.R, this.brightness),
+			ScaleChannel(color.G, this.brightness),
+			ScaleChannel(color.B, this.brightness),
+			color.A,
+		}
+	}
+
+	this.display.Render(this.dimmedData)
 }
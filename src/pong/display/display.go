@@ -0,0 +1,74 @@
+// Package display gathers what is needed for showing rendered frames, wrapping a display to lay leds out as a matrix,
+// map them from a file, transform or dim them. The names are part of the stable API described in the pong package
+package display
+
+import (
+	"github.com/brandonagr/pongpi/src/pong"
+)
+
+// Displays and the wrappers that lay out or adjust the leds of another display
+type (
+	Display           = pong.Display
+	ResettableDisplay = pong.ResettableDisplay
+	MatrixDisplay     = pong.MatrixDisplay
+	TransformDisplay  = pong.TransformDisplay
+	DimmedDisplay     = pong.DimmedDisplay
+	PixelCoordinate   = pong.PixelCoordinate
+	PixelMap          = pong.PixelMap
+	PixelMapDisplay   = pong.PixelMapDisplay
+	AutoBrightness    = pong.AutoBrightness
+	PowerLimitDisplay = pong.PowerLimitDisplay
+	ThermalMonitor    = pong.ThermalMonitor
+	LightSensor       = pong.LightSensor
+)
+
+// Wiring layouts of a matrix
+const (
+	MatrixProgressive = pong.MatrixProgressive
+	MatrixSerpentine  = pong.MatrixSerpentine
+)
+
+// Construct a MatrixDisplay showing frames of rows of width leds on display, wired in layout such as MatrixSerpentine
+func NewMatrixDisplay(display Display, width, rows int, layout string) (*MatrixDisplay, error) {
+	return pong.NewMatrixDisplay(display, width, rows, layout)
+}
+
+// Construct a TransformDisplay flipping or rotating frames of width by height leds, such as "rotate180" or "mirrorx"
+func NewTransformDisplay(display Display, width, height int, transform string) (*TransformDisplay, error) {
+	return pong.NewTransformDisplay(display, width, height, transform)
+}
+
+// Construct a DimmedDisplay showing display at brightness from 0 to 1
+func NewDimmedDisplay(display Display, brightness float64) *DimmedDisplay {
+	return pong.NewDimmedDisplay(display, brightness)
+}
+
+// Read the pixel map at path, a .csv of x,y lines or a JSON array of {"x": 0, "y": 0} objects
+func LoadPixelMap(path string) (PixelMap, error) {
+	return pong.LoadPixelMap(path)
+}
+
+// Construct a PixelMapDisplay showing frames of width by height leds on the leds of display laid out by pixels
+func NewPixelMapDisplay(display Display, width, height int, pixels PixelMap) (*PixelMapDisplay, error) {
+	return pong.NewPixelMapDisplay(display, width, height, pixels)
+}
+
+// Open the light sensor of kind, such as "bh1750", at address on the I2C bus at busFilePath, 0 for its usual address
+func NewLightSensor(kind, busFilePath string, address int) (LightSensor, error) {
+	return pong.NewLightSensor(kind, busFilePath, address)
+}
+
+// Construct an AutoBrightness showing display at the brightness the light measured by sensor calls for
+func NewAutoBrightness(display Display, sensor LightSensor, settings pong.SettingsData) *AutoBrightness {
+	return pong.NewAutoBrightness(display, sensor, settings)
+}
+
+// Construct a PowerLimitDisplay dimming frames of display that would draw more than budgetMilliamps
+func NewPowerLimitDisplay(display Display, budgetMilliamps, channelMilliamps, idleMilliamps float64) *PowerLimitDisplay {
+	return pong.NewPowerLimitDisplay(display, budgetMilliamps, channelMilliamps, idleMilliamps)
+}
+
+// Construct a ThermalMonitor dimming display as the temperatures in settings rise
+func NewThermalMonitor(display Display, settings pong.SettingsData) *ThermalMonitor {
+	return pong.NewThermalMonitor(display, settings)
+}
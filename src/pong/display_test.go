@@ -0,0 +1,66 @@
+//go:build !windows
+// +build !windows
+
+package pong
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// Frames rendered after the display is closed should be dropped rather than written to the closed bus
+func Test_LedDisplay_Close(t *testing.T) {
+
+	file, err := ioutil.TempFile("", "spidev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+
+	leds := &LedDisplay{
+		bus:            &SpiBus{fileDescriptor: file},
+		expectedColors: 3,
+		byteData:       make([]byte, ledFrameSize(3)),
+		fullWrite:      true,
+	}
+	leds.Render(make([]RGBA, 3))
+	if err := leds.Close(); err != nil {
+		t.Fatal(err)
+	}
+	leds.Render([]RGBA{{255, 0, 0, 255}, {}, {}})
+	if err := leds.Close(); err != nil {
+		t.Fatal("Closing twice:", err)
+	}
+
+	written, _ := ioutil.ReadFile(file.Name())
+	Assert(len(written), ledFrameSize(3), "Bytes written", t)
+}
+
+// A frame of the wrong length should be dropped and reported through the displays wrapping it, not stop the game
+func Test_LedDisplay_RenderError(t *testing.T) {
+
+	file, err := ioutil.TempFile("", "spidev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	leds := &LedDisplay{
+		bus:            &SpiBus{fileDescriptor: file},
+		expectedColors: 3,
+		byteData:       make([]byte, ledFrameSize(3)),
+	}
+	// read through a wrapper, as the game does
+	display := NewFaultInjector(FaultRates{}, 1).Display(leds)
+
+	display.Render(make([]RGBA, 2))
+	if err := display.(FallibleDisplay).RenderError(); err == nil {
+		t.Fatal("Frame of the wrong length wasn't reported")
+	}
+	display.Render(make([]RGBA, 3))
+	if err := display.(FallibleDisplay).RenderError(); err != nil {
+		t.Fatal("Error reported after a good frame:", err)
+	}
+}
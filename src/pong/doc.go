@@ -0,0 +1,10 @@
+// Package pong holds everything the game is built from, drawables and the fields they are drawn on, displays, buttons,
+// game modes and settings.
+//
+// Projects embedding the renderer or the rules require the module github.com/brandonagr/pongpi/src/pong and import
+// pong/render, pong/display, pong/input and pong/game. The names exported from those four packages are the stable API
+// of the module, within a major version they keep their meaning and signatures, new names may be added and a name is
+// only removed after it has been marked Deprecated for a release. The names they forward to here are held to the same
+// promise. Everything else in this package and the packages under it, draw, modes, stats and the rest, is how the game
+// itself is put together and may change between commits
+package pong
@@ -0,0 +1,239 @@
+package draw
+
+import (
+	"math"
+	"math/rand"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Parameters that describe how an AIPlayer plays
+type AIPersonality struct {
+
+	// name the AI is known by, such as in ratings
+	Name string
+
+	// fixed Elo rating of this AI
+	Rating float64
+
+	// chance from 0 to 1 of not returning the ball
+	MissChance float64
+
+	// random variation in leds of how close the ball gets before the AI reacts
+	Jitter float64
+
+	// multiplier applied to MissChance and Jitter when a miss would lose the game
+	Nervousness float64
+
+	// seconds before committing to a return that the paddle flickers, 0 disables the tell
+	TellTime float64
+}
+
+// Built in personalities
+var (
+	SteadyAI  = AIPersonality{Name: "steady", Rating: 1400, MissChance: 0.1, Jitter: 2.0, Nervousness: 1.0}
+	NervousAI = AIPersonality{Name: "nervous", Rating: 1300, MissChance: 0.1, Jitter: 2.0, Nervousness: 3.0}
+	ShowyAI   = AIPersonality{Name: "showy", Rating: 1200, MissChance: 0.15, Jitter: 1.0, Nervousness: 1.5, TellTime: 0.3}
+)
+
+// All of the built in personalities
+var AIPersonalities = []AIPersonality{SteadyAI, NervousAI, ShowyAI}
+
+func init() {
+	for _, personality := range AIPersonalities {
+		ReserveProfileName(personality.Name)
+	}
+}
+
+// Find a built in personality by name
+func FindAIPersonality(name string) (AIPersonality, bool) {
+	for _, personality := range AIPersonalities {
+		if personality.Name == name {
+			return personality, true
+		}
+	}
+	return AIPersonality{}, false
+}
+
+// Computer controlled player that decides when to hold down the paddle
+type AIPlayer struct {
+
+	// player being controlled
+	player *Player
+
+	// ball being returned
+	ball *Ball
+
+	// how the AI plays
+	personality AIPersonality
+
+	// if the ball is currently moving toward the player
+	approaching bool
+
+	// decisions made each time the ball starts approaching
+	willMiss         bool
+	reactionDistance float64
+
+	random *rand.Rand
+}
+
+// Construct an AIPlayer controlling player
+func NewAIPlayer(player *Player, ball *Ball, personality AIPersonality) *AIPlayer {
+	return &AIPlayer{
+		player:      player,
+		ball:        ball,
+		personality: personality,
+		random:      NewRand(),
+	}
+}
+
+// Distance from the ball to the front of the player's paddle, negative once the ball has passed it
+func (this *AIPlayer) distanceToPaddle() float64 {
+	if this.player.isLeft {
+		return this.ball.position - this.player.paddleRight
+	}
+	return this.player.paddleLeft - this.ball.position
+}
+
+// Decide if the paddle should be held down this frame
+func (this *AIPlayer) PaddleActive() bool {
+
+	approaching := (this.player.isLeft && this.ball.velocity < 0) || (!this.player.isLeft && this.ball.velocity > 0)
+
+	if approaching && !this.approaching {
+		// ball just turned toward us, decide how to handle this return
+		missChance, jitter := this.personality.MissChance, this.personality.Jitter
+		if this.player.life <= MissLifePenalty {
+			missChance *= this.personality.Nervousness
+			jitter *= this.personality.Nervousness
+		}
+
+		this.willMiss = this.random.Float64() < missChance
+		this.reactionDistance = 0.5 + this.random.Float64()*jitter
+	}
+	this.approaching = approaching
+
+	active := approaching && !this.willMiss && this.distanceToPaddle() < this.reactionDistance
+
+	// telegraph the return shortly before committing to it
+	timeToReact := (this.distanceToPaddle() - this.reactionDistance) / math.Abs(this.ball.velocity)
+	this.player.SetTell(approaching && !this.willMiss && !active && timeToReact < this.personality.TellTime)
+
+	return active
+}
+
+// ButtonInput where buttons are pushed by AIPlayers, a nil AIPlayer uses the real button instead
+type AIInput struct {
+	buttons     ButtonInput
+	left, right *AIPlayer
+}
+
+var _ ButtonInput = &AIInput{}
+
+// Construct an AIInput where both players are controlled by the AI
+func NewAIInput(left, right *AIPlayer) *AIInput {
+	return &AIInput{
+		left:  left,
+		right: right,
+	}
+}
+
+// Construct an AIInput where a real player on the left plays against the AI on the right
+func NewAIOpponentInput(buttons ButtonInput, right *AIPlayer) *AIInput {
+	return &AIInput{
+		buttons: buttons,
+		right:   right,
+	}
+}
+
+// Left AI's decision
+func (this *AIInput) LeftButton() bool {
+	if this.left == nil {
+		return this.buttons.LeftButton()
+	}
+	return this.left.PaddleActive()
+}
+
+// Right AI's decision
+func (this *AIInput) RightButton() bool {
+	if this.right == nil {
+		return this.buttons.RightButton()
+	}
+	return this.right.PaddleActive()
+}
+
+// AIPlayer sharing a side with a human, only returning balls the human leaves alone
+type AITeammate struct {
+	ai *AIPlayer
+
+	// seconds before the ball reaches the paddle the human has to press before the AI takes over
+	grace float64
+
+	// if the human pressed while the ball approached
+	humanPressed bool
+}
+
+// Construct an AITeammate
+func NewAITeammate(ai *AIPlayer, grace float64) *AITeammate {
+	return &AITeammate{
+		ai:    ai,
+		grace: grace,
+	}
+}
+
+// Combine the human's button with the AI's decision
+func (this *AITeammate) PaddleActive(humanDown bool) bool {
+
+	// always ask the AI so it keeps track of the ball
+	aiActive := this.ai.PaddleActive()
+
+	if !this.ai.approaching {
+		this.humanPressed = false
+		return humanDown
+	}
+
+	if humanDown {
+		this.humanPressed = true
+		return true
+	}
+	if this.humanPressed {
+		// human took this return, stay out of the way
+		return false
+	}
+
+	timeToPaddle := this.ai.distanceToPaddle() / math.Abs(this.ai.ball.velocity)
+	return aiActive && timeToPaddle <= this.grace
+}
+
+// ButtonInput where each side is a human with an AITeammate, a nil AITeammate leaves the human alone
+type DoublesInput struct {
+	buttons     ButtonInput
+	left, right *AITeammate
+}
+
+var _ ButtonInput = &DoublesInput{}
+
+// Construct a DoublesInput
+func NewDoublesInput(buttons ButtonInput, left, right *AITeammate) *DoublesInput {
+	return &DoublesInput{
+		buttons: buttons,
+		left:    left,
+		right:   right,
+	}
+}
+
+// Left team's paddle
+func (this *DoublesInput) LeftButton() bool {
+	if this.left == nil {
+		return this.buttons.LeftButton()
+	}
+	return this.left.PaddleActive(this.buttons.LeftButton())
+}
+
+// Right team's paddle
+func (this *DoublesInput) RightButton() bool {
+	if this.right == nil {
+		return this.buttons.RightButton()
+	}
+	return this.right.PaddleActive(this.buttons.RightButton())
+}
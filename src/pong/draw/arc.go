@@ -0,0 +1,74 @@
+package draw
+
+import (
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Brightness of an arc while its player isn't pressing
+var arcIdleAlpha uint8 = 60
+
+// Part of a looped field a player defends, centered on a led and lit brightly while the player presses
+type DefendedArc struct {
+	width float64
+	color RGBA
+
+	// led the arc is centered on, and leds it reaches either side of it
+	center, reach float64
+
+	pressed bool
+}
+
+var _ Drawable = &DefendedArc{}
+
+// Construct a DefendedArc in color on field centered on center and reaching reach leds either side
+func NewDefendedArc(field Field, center, reach float64, color RGBA) *DefendedArc {
+	return &DefendedArc{
+		width:  float64(field.Width()),
+		color:  color,
+		center: center,
+		reach:  reach,
+	}
+}
+
+// Light the arc brightly while pressed
+func (this *DefendedArc) SetPressed(pressed bool) {
+	this.pressed = pressed
+}
+
+// Led the arc is centered on
+func (this *DefendedArc) Center() float64 {
+	return this.center
+}
+
+// Leds the arc reaches either side of its center
+func (this *DefendedArc) Reach() float64 {
+	return this.reach
+}
+
+// If position is inside the arc, going around the end of the field if needed
+func (this *DefendedArc) Covers(position float64) bool {
+	return Position(position).DistanceTo(Position(this.center), TopologyLoop, this.width) <= this.reach
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *DefendedArc) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	if !this.Covers(position) {
+		return baseColor
+	}
+	color := this.color
+	if !this.pressed {
+		color.A = arcIdleAlpha
+	}
+	return color.BlendWith(baseColor)
+}
+
+// Under the ball
+func (this *DefendedArc) ZIndex() ZIndex {
+	return 10
+}
+
+// Animate
+func (this *DefendedArc) Animate(dt float64) bool {
+	return true
+}
@@ -1,10 +1,57 @@
 package draw
 
 import (
+	"log"
 	"math"
-	. "pong"
+	"math/rand"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	"github.com/brandonagr/pongpi/src/pong/tables"
 )
 
+func init() {
+	RegisterEffect("sinusoid", func(field Field, zindex ZIndex, params EffectParams) Drawable {
+		return NewSinusoid(field, zindex)
+	})
+	RegisterEffect("hsl", func(field Field, zindex ZIndex, params EffectParams) Drawable {
+		return NewHSLWheel(field, zindex)
+	})
+	RegisterEffect("steps", func(field Field, zindex ZIndex, params EffectParams) Drawable {
+		return NewStepFunction(float64(field.Width())/2.0, params.Float("size", 8), params.Color("color", RGBA{64, 64, 64, 255}), zindex)
+	}, NumberParam("size", 1, 32, 8), ColorParam("color", RGBA{64, 64, 64, 255}))
+	RegisterEffect("fire", func(field Field, zindex ZIndex, params EffectParams) Drawable {
+		return NewFire(field, zindex)
+	})
+	RegisterEffect("noise", func(field Field, zindex ZIndex, params EffectParams) Drawable {
+		return NewNoise(field, zindex)
+	})
+	RegisterEffect("comet", func(field Field, zindex ZIndex, params EffectParams) Drawable {
+		comet := NewComet(field, params.Color("color", RGBA{255, 160, 40, 255}), zindex)
+		comet.tailLength = math.Max(1, params.Float("trail", 0.25)*float64(field.Width()))
+		return comet
+	}, ColorParam("color", RGBA{255, 160, 40, 255}), NumberParam("trail", 0.05, 1, 0.25))
+}
+
+// Names of the backgrounds that can be chosen for a game, every registered effect
+func BackgroundNames() []string {
+	return append([]string{"none"}, EffectNames()...)
+}
+
+// Construct a background from an effect spec such as "comet(color=#00ffff)" or "fire+comet", returns nil for "none"
+// or a spec that can't be made
+func NewBackground(spec string, field Field, zindex ZIndex) Drawable {
+
+	if spec == "none" || spec == "" {
+		return nil
+	}
+	background, err := NewEffect(spec, field, zindex)
+	if err != nil {
+		log.Print(err)
+		return nil
+	}
+	return background
+}
+
 // Represents a background animation of a sinusoid moving forward
 type Sinusoid struct {
 
@@ -15,42 +62,22 @@ type Sinusoid struct {
 	offsets [3]float64
 
 	zindex ZIndex
-
-	sineLookup []uint8
 }
 
 var _ Drawable = &Sinusoid{}
 
 // Construct a Sinusoid
-func NewSinusoid(field *GameField, zindex ZIndex) *Sinusoid {
-	sine := &Sinusoid{
+func NewSinusoid(field Field, zindex ZIndex) *Sinusoid {
+	return &Sinusoid{
 		scale:   float64(field.Width()),
 		offsets: [3]float64{0.0, 0.0, 0.0},
 		zindex:  zindex,
 	}
-
-	sine.buildLookup()
-
-	return sine
-}
-
-// build lookup table to make rendering much faster by precomputing math.Sin
-func (this *Sinusoid) buildLookup() {
-	this.sineLookup = make([]uint8, 256)
-	for index := 0; index < 256; index++ {
-		fieldPercentage := float64(index) / 256
-
-		value := (math.Sin(fieldPercentage*2.0*math.Pi+this.offsets[0]) + 1.0) / 2.0
-		this.sineLookup[index] = uint8(value*255.0) >> 1
-	}
 }
 
 // lookup the sine value instead of computing using math.Sin
 func (this *Sinusoid) lookup(fieldPercentage float64) uint8 {
-	if fieldPercentage >= 1 {
-		fieldPercentage -= 1
-	}
-	return this.sineLookup[byte(fieldPercentage*256)]
+	return tables.SineByte(fieldPercentage) >> 1
 }
 
 // Returns the color at position blended on top of baseColor
@@ -108,7 +135,7 @@ type HSLWheel struct {
 var _ Drawable = &HSLWheel{}
 
 // Construct an HSLWheel
-func NewHSLWheel(field *GameField, zindex ZIndex) *HSLWheel {
+func NewHSLWheel(field Field, zindex ZIndex) *HSLWheel {
 	return &HSLWheel{
 		scale:  float64(field.Width()),
 		hue:    0.0,
@@ -277,7 +304,7 @@ type Countdown struct {
 var _ Drawable = &Countdown{}
 
 // Construct a new StepFunction
-func NewCountdown(field *GameField, totalTime float64) *Countdown {
+func NewCountdown(field Field, totalTime float64) *Countdown {
 	return &Countdown{
 		time:      0.0,
 		totalTime: totalTime,
@@ -342,16 +369,16 @@ type Winner struct {
 
 var _ Drawable = &Winner{}
 
-// Construct a new StepFunction
-func NewWinner(field *GameField, leftWon bool, totalTime float64) *Winner {
+// Construct a Winner flashing the half of the field of the side that won
+func NewWinner(field Field, leftWon bool, totalTime float64) *Winner {
 
-	if !leftWon {
+	if leftWon {
 		return &Winner{
 			time:      0.0,
 			totalTime: totalTime,
 			left:      0,
 			right:     (float64(field.Width()) / 2.0) - 1,
-			color:     RGBA{0, 0, 255, 255},
+			color:     CurrentTheme().LeftColor,
 		}
 	} else {
 		return &Winner{
@@ -359,7 +386,7 @@ func NewWinner(field *GameField, leftWon bool, totalTime float64) *Winner {
 			totalTime: totalTime,
 			left:      (float64(field.Width()) / 2.0),
 			right:     float64(field.Width()) - 1,
-			color:     RGBA{0, 255, 0, 255},
+			color:     CurrentTheme().RightColor,
 		}
 	}
 
@@ -397,3 +424,309 @@ func (this *Winner) Animate(dt float64) bool {
 func (this *Winner) TimeRemaining() float64 {
 	return this.totalTime - this.time
 }
+
+// Represents a background animation of flames burning in from both ends
+type Fire struct {
+
+	// heat of each led from 0 to 1
+	heat []float64
+
+	// time not yet simulated, the fire is simulated at a fixed rate
+	pendingTime float64
+
+	random *rand.Rand
+	zindex ZIndex
+}
+
+var _ Drawable = &Fire{}
+
+// seconds per step of the fire simulation
+var fireStepTime float64 = 1.0 / 60.0
+
+// Construct a Fire
+func NewFire(field Field, zindex ZIndex) *Fire {
+	return &Fire{
+		heat:   make([]float64, field.Width()),
+		random: NewRand(),
+		zindex: zindex,
+	}
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *Fire) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	index := int(position)
+	if index < 0 || index >= len(this.heat) {
+		return baseColor
+	}
+
+	// black to red to yellow to white as heat goes up
+	heat := this.heat[index] * 3.0
+	switch {
+	case heat < 1.0:
+		return RGBA{uint8(heat * 255), 0, 0, 255}
+	case heat < 2.0:
+		return RGBA{255, uint8((heat - 1.0) * 255), 0, 255}
+	default:
+		return RGBA{255, 255, uint8((heat - 2.0) * 255), 255}
+	}
+}
+
+// ZIndex
+func (this *Fire) ZIndex() ZIndex {
+	return this.zindex
+}
+
+// Animate
+func (this *Fire) Animate(dt float64) bool {
+
+	this.pendingTime += dt
+	for this.pendingTime >= fireStepTime {
+		this.pendingTime -= fireStepTime
+		this.step()
+	}
+
+	return true
+}
+
+// Move the fire simulation forward one step
+func (this *Fire) step() {
+
+	count := len(this.heat)
+	half := count / 2
+	if count == 0 {
+		return
+	}
+
+	// sparks ignite within a few leds of each end, all of a field narrower than that
+	sparkLeds := 3
+	if count < sparkLeds {
+		sparkLeds = count
+	}
+
+	// every led cools down a little
+	for index := range this.heat {
+		this.heat[index] -= this.random.Float64() * 0.04
+		if this.heat[index] < 0 {
+			this.heat[index] = 0
+		}
+	}
+
+	// heat drifts from each end toward the middle
+	for index := half - 1; index >= 2; index-- {
+		this.heat[index] = (this.heat[index-1] + this.heat[index-2]*2) / 3
+		mirror := count - 1 - index
+		this.heat[mirror] = (this.heat[mirror+1] + this.heat[mirror+2]*2) / 3
+	}
+
+	// randomly ignite new sparks at the ends
+	if this.random.Float64() < 0.5 {
+		this.heat[this.random.Intn(sparkLeds)] = 0.6 + this.random.Float64()*0.4
+	}
+	if this.random.Float64() < 0.5 {
+		this.heat[count-1-this.random.Intn(sparkLeds)] = 0.6 + this.random.Float64()*0.4
+	}
+}
+
+// Represents a background animation of smoothly changing random colors
+type Noise struct {
+
+	// leds between each random value
+	cellSize float64
+
+	// offset related to time passing, in cells
+	offset float64
+
+	// random values that are interpolated between
+	values []float64
+
+	zindex ZIndex
+}
+
+var _ Drawable = &Noise{}
+
+// Construct a Noise
+func NewNoise(field Field, zindex ZIndex) *Noise {
+
+	noise := &Noise{
+		cellSize: 8,
+		values:   make([]float64, 256),
+		zindex:   zindex,
+	}
+	random := NewRand()
+	for index := range noise.values {
+		noise.values[index] = random.Float64()
+	}
+
+	return noise
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *Noise) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	x := position/this.cellSize + this.offset
+	cell := math.Floor(x)
+	fraction := x - cell
+
+	// smoothstep between the two surrounding values
+	fraction = fraction * fraction * (3 - 2*fraction)
+
+	first := this.values[this.valueIndex(int(cell))]
+	second := this.values[this.valueIndex(int(cell)+1)]
+
+	return hslToRGB(first+(second-first)*fraction, 1.0, 0.25)
+}
+
+// Index of the value of cell, wrapping around the values either way so positions left of 0 still have one
+func (this *Noise) valueIndex(cell int) int {
+	count := len(this.values)
+	return (cell%count + count) % count
+}
+
+// ZIndex
+func (this *Noise) ZIndex() ZIndex {
+	return this.zindex
+}
+
+// Animate
+func (this *Noise) Animate(dt float64) bool {
+
+	this.offset += dt * 0.5
+	if this.offset > float64(len(this.values)) {
+		this.offset -= float64(len(this.values))
+	}
+
+	return true
+}
+
+// Represents a background animation of a comet bouncing between the ends
+type Comet struct {
+
+	// position of the head and its speed in leds / second
+	position, velocity float64
+
+	// max position of the head, min is 0
+	maxPosition float64
+
+	// length of the tail behind the head
+	tailLength float64
+
+	color RGBA
+
+	zindex ZIndex
+}
+
+var _ BoundedDrawable = &Comet{}
+
+// Construct a Comet
+func NewComet(field Field, color RGBA, zindex ZIndex) *Comet {
+	return &Comet{
+		velocity:    float64(field.Width()) / 3.0,
+		maxPosition: float64(field.Width() - 1),
+		tailLength:  float64(field.Width()) / 4.0,
+		color:       color,
+		zindex:      zindex,
+	}
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *Comet) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	// distance behind the head, in the opposite direction of travel
+	behind := this.position - position
+	if this.velocity < 0 {
+		behind = -behind
+	}
+
+	if behind < -0.5 || behind > this.tailLength {
+		return baseColor
+	}
+
+	alpha := 1.0 - math.Max(behind, 0)/this.tailLength
+	color := RGBA{this.color.R, this.color.G, this.color.B, uint8(alpha * alpha * 255)}
+
+	return color.BlendWith(baseColor)
+}
+
+// Range of positions covered by the head and tail
+func (this *Comet) Bounds() (left, right float64) {
+	return this.position - this.tailLength - 0.5, this.position + this.tailLength + 0.5
+}
+
+// ZIndex
+func (this *Comet) ZIndex() ZIndex {
+	return this.zindex
+}
+
+// Animate
+func (this *Comet) Animate(dt float64) bool {
+
+	position, velocity := Reflect(Position(this.position+this.velocity*dt), Velocity(this.velocity), Extent{0, Position(this.maxPosition)})
+	this.position, this.velocity = float64(position), float64(velocity)
+
+	return true
+}
+
+// Represents the animation shown when the game starts up, filling in from both ends and fading away
+type Boot struct {
+
+	// width of the field
+	width float64
+
+	// total time counted so far
+	time float64
+
+	// length of entire animation
+	totalTime float64
+}
+
+var _ Drawable = &Boot{}
+
+// Construct a new Boot
+func NewBoot(field Field, totalTime float64) *Boot {
+	return &Boot{
+		width:     float64(field.Width()),
+		totalTime: totalTime,
+	}
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *Boot) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	// fill in during the first half, fade out during the second
+	progress := this.time / this.totalTime
+	fill, alpha := progress*2.0, 1.0
+	if progress > 0.5 {
+		fill, alpha = 1.0, (1.0-progress)*2.0
+	}
+
+	distanceFromEnd := math.Min(position, this.width-1-position)
+	if distanceFromEnd > fill*this.width/2.0 {
+		return baseColor
+	}
+
+	color := RGBA{0, 255, 64, uint8(alpha * 255)}
+	return color.BlendWith(baseColor)
+}
+
+// ZIndex
+func (this *Boot) ZIndex() ZIndex {
+	return 0
+}
+
+// Animate
+func (this *Boot) Animate(dt float64) bool {
+
+	this.time += dt
+
+	if this.time >= this.totalTime {
+		this.time = this.totalTime
+	}
+
+	return true
+}
+
+// Amount of time remaining in the animation
+func (this *Boot) TimeRemaining() float64 {
+	return this.totalTime - this.time
+}
@@ -0,0 +1,48 @@
+package draw
+
+import (
+	"testing"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Fire should burn on fields narrower than the ends sparks ignite in without running off them
+func Test_Fire_NarrowField(t *testing.T) {
+	for width := 0; width <= 4; width++ {
+		fire := NewFire(NewGameField(width), 0)
+		for step := 0; step < 100; step++ {
+			fire.Animate(fireStepTime)
+		}
+	}
+}
+
+// Noise should have a color left of the field as well as on it, such as for a wrapped or shifted position
+func Test_Noise_NegativePosition(t *testing.T) {
+	noise := NewNoise(NewGameField(10), 0)
+	for _, position := range []float64{-1, -100.5, -2049, 0, 2049} {
+		if color := noise.ColorAt(position, RGBA{}); color.A != 255 {
+			t.Fatal("Noise at", position, "was", color)
+		}
+	}
+}
+
+// The winning side's half of the field should flash in its color, the other half should be left alone
+func Test_Winner_Side(t *testing.T) {
+
+	field := NewGameField(20)
+	black := RGBA{0, 0, 0, 255}
+	for _, leftWon := range []bool{true, false} {
+		winner := NewWinner(field, leftWon, 2)
+
+		winnerColor, winning, losing := CurrentTheme().LeftColor, 2.0, 17.0
+		if !leftWon {
+			winnerColor, winning, losing = CurrentTheme().RightColor, 17.0, 2.0
+		}
+		if winner.ColorAt(winning, black) != winnerColor {
+			t.Fatal("Winning side isn't lit, left won", leftWon)
+		}
+		if winner.ColorAt(losing, black) != black {
+			t.Fatal("Losing side is lit, left won", leftWon)
+		}
+	}
+}
@@ -3,7 +3,9 @@ package draw
 import (
 	"math"
 	"math/rand"
-	. "pong"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	"github.com/brandonagr/pongpi/src/pong/tables"
 )
 
 // Player that is drawn on the board
@@ -12,29 +14,60 @@ type Ball struct {
 	// current position of the ball
 	position float64
 
+	// position before the last step, and where the ball is drawn between the two
+	previousPosition float64
+	drawPosition     float64
+
 	// direction and speed of the ball in leds / second
 	velocity float64
 
 	// max position of ball, min is 0
 	maxPosition float64
 
+	// shape of the field, on a loop the ball wraps around from one end to the other
+	topology Topology
+
 	// the length of the tail of the ball
 	tailLength float64
 
+	// how yellow the flame is at each led of the tail, drawn again every step so the tail flickers
+	tailFlicker []uint8
+	flickers    uint64
+	random      *rand.Rand
+
 	// if the ball should be hidden this frame or not
 	hideBall bool
 
 	// z position of ball
 	zindex ZIndex
+
+	// bumped whenever the ball looks different, and how it looked then
+	version uint64
+	look    ballLook
+}
+
+// What decides how the ball is drawn, the version only changes when this does
+type ballLook struct {
+	drawPosition float64
+	headingRight bool
+	hidden       bool
+	flickers     uint64
 }
 
-var _ Drawable = &Ball{}
+var _ TrackedDrawable = &Ball{}
+var _ InterpolatedDrawable = &Ball{}
+
+// Construct a Ball served from a random end of the field
+func NewBall(field Field) *Ball {
+	return NewServedBall(field, NewRand().Float64() <= 0.5)
+}
 
-// Construct a Line
-func NewBall(field *GameField) *Ball {
+// Construct a Ball served from the left or right end of the field
+func NewServedBall(field Field, fromLeft bool) *Ball {
 
-	if rand.Float64() > 0.5 {
-		return &Ball{
+	var ball *Ball
+	if !fromLeft {
+		ball = &Ball{
 			position:    float64(field.Width()-1),
 			velocity:    -float64(field.Width()) / 2.0,
 			maxPosition: float64(field.Width() -1),
@@ -42,7 +75,7 @@ func NewBall(field *GameField) *Ball {
 			zindex:      100,
 		}
 	} else {
-		return &Ball{
+		ball = &Ball{
 			position:    0.0,
 			velocity:    float64(field.Width()) / 2.0,
 			maxPosition: float64(field.Width() - 1),
@@ -50,23 +83,31 @@ func NewBall(field *GameField) *Ball {
 			zindex:      100,
 		}
 	}
+
+	ball.topology = field.Topology()
+	ball.tailFlicker = make([]uint8, int(ball.tailLength)+1)
+	ball.random = NewRand()
+	ball.flicker()
+	ball.snap()
+	return ball
 }
 
 // Returns the color at position blended on top of baseColor
 func (this *Ball) ColorAt(position float64, baseColor RGBA) (color RGBA) {
 
-	distance := math.Abs(position - this.position)
+	offset := this.offset(position)
+	distance := math.Abs(offset)
 
 	// Add tail flame
-	if distance > 0.5 && distance < this.tailLength && ((this.position < position && this.velocity < 0) || (position < this.position && this.velocity > 0)) {
+	if distance > 0.5 && distance < this.tailLength && ((offset > 0 && this.velocity < 0) || (offset < 0 && this.velocity > 0)) {
 
-		tailColor := RGBA{255, uint8(rand.Intn(255)), 0, uint8(((this.tailLength - distance) / this.tailLength) * 255.0)}
+		tailColor := RGBA{255, this.tailFlicker[int(distance)], 0, uint8(tables.Falloff(distance/this.tailLength) * 255)}
 		baseColor = tailColor.BlendWith(baseColor)
 	}
 
 	// Add ball itself as white
 	if !this.hideBall && distance < 1 {
-		color = RGBA{255, 255, 255, uint8((1.0 - distance) * 255.0)}
+		color = RGBA{255, 255, 255, Falloff(distance, 1)}
 		color = color.BlendWith(baseColor)
 	} else {
 		color = baseColor
@@ -75,18 +116,110 @@ func (this *Ball) ColorAt(position float64, baseColor RGBA) (color RGBA) {
 	return color
 }
 
+// How far position is from where the ball is drawn, the short way around when the field is a loop
+func (this *Ball) offset(position float64) float64 {
+
+	return Position(position).OffsetFrom(Position(this.drawPosition), this.topology, this.maxPosition+1)
+}
+
 // ZIndex of the ball
 func (this *Ball) ZIndex() ZIndex {
 	return this.zindex
 }
 
+// Range of positions covered by the ball and its tail
+func (this *Ball) Bounds() (left, right float64) {
+	left, right = this.drawPosition-this.tailLength, this.drawPosition+this.tailLength
+	if this.topology == TopologyLoop && (left < 0 || right > this.maxPosition) {
+		// the tail wraps around to the other end
+		return math.Inf(-1), math.Inf(1)
+	}
+	return left, right
+}
+
+// Changes when the ball moves, turns, hides or its tail flickers
+func (this *Ball) Version() uint64 {
+	return this.version
+}
+
+// Bump the version if the ball looks different than when it was last bumped
+func (this *Ball) changed() {
+	look := ballLook{this.drawPosition, this.velocity > 0, this.hideBall, this.flickers}
+	if look != this.look {
+		this.look = look
+		this.version++
+	}
+}
+
 // Animate ball
 func (this *Ball) Animate(dt float64) bool {
+	this.previousPosition = this.position
 	this.position += this.velocity * dt
+	if width := this.maxPosition + 1; this.topology == TopologyLoop && (this.position < 0 || this.position >= width) {
+		shift := this.position - float64(Position(this.position).Wrap(width))
+		this.position -= shift
+		this.previousPosition -= shift
+	}
+	this.drawPosition = this.position
+	if this.position != this.previousPosition {
+		// a ball standing still, such as waiting to be served, keeps its tail
+		this.flicker()
+	}
+	this.changed()
 
 	return true
 }
 
+// Pick new colors for the flame of the tail
+func (this *Ball) flicker() {
+	for index := range this.tailFlicker {
+		this.tailFlicker[index] = uint8(this.random.Intn(255))
+	}
+	this.flickers++
+}
+
+// Draw the ball alpha of the way from where it was before the last step to where it is now
+func (this *Ball) Interpolate(alpha float64) {
+	this.drawPosition = this.previousPosition + (this.position-this.previousPosition)*alpha
+	this.changed()
+}
+
+// Stop drawing the ball between steps until it moves again, for when it jumps to a new position
+func (this *Ball) snap() {
+	this.previousPosition = this.position
+	this.drawPosition = this.position
+	this.changed()
+}
+
+// Move the ball to position heading at velocity, for playing back a recorded game
+func (this *Ball) Place(position, velocity float64) {
+	this.position = position
+	this.velocity = velocity
+	this.snap()
+}
+
+// Send the ball back the way it came off a paddle whose edge is at edge, speeding it up by bounceFactor
+func (this *Ball) Bounce(edge, bounceFactor float64) {
+	this.position = float64(Position(this.position).ReflectOff(Position(edge)))
+	this.velocity = this.velocity * -bounceFactor
+	this.changed()
+}
+
+// Multiply the speed of the ball by factor, keeping its direction
+func (this *Ball) Accelerate(factor float64) {
+	this.velocity *= factor
+}
+
+// Current position of the ball
+func (this *Ball) Position() float64 {
+	return this.position
+}
+
+// Current velocity of the ball in leds / second, negative when moving left
+func (this *Ball) Velocity() float64 {
+	return this.velocity
+}
+
 // Check if the ball went past a player, returns nil or the player that missed the ball
 func (this *Ball) MissedByPlayer(leftPlayer, rightPlayer *Player, bounceFactor float64) (missedPlayer *Player, hitBall bool) {
 
@@ -98,8 +231,7 @@ func (this *Ball) MissedByPlayer(leftPlayer, rightPlayer *Player, bounceFactor f
 			return leftPlayer, false
 		} else if leftPlayer.paddleActive {
 			// player hit the ball back
-			this.position = leftPlayer.paddleRight + (leftPlayer.paddleRight - this.position)
-			this.velocity = this.velocity * -bounceFactor
+			this.Bounce(leftPlayer.paddleRight, bounceFactor)
 			go PlaySound(LEFTBOUNCE)
 			return nil, true
 		}
@@ -111,8 +243,7 @@ func (this *Ball) MissedByPlayer(leftPlayer, rightPlayer *Player, bounceFactor f
 			return rightPlayer, false
 		} else if rightPlayer.paddleActive {
 			// player hit the ball back
-			this.position = rightPlayer.paddleLeft - (this.position - rightPlayer.paddleLeft)
-			this.velocity = this.velocity * -bounceFactor
+			this.Bounce(rightPlayer.paddleLeft, bounceFactor)
 			go PlaySound(RIGHTBOUNCE)
 			return nil, true
 		}
@@ -121,8 +252,24 @@ func (this *Ball) MissedByPlayer(leftPlayer, rightPlayer *Player, bounceFactor f
 	return nil, false
 }
 
+// Remember where the ball was when each player first pushed their button as it approached them
+func (this *Ball) TrackPresses(leftPlayer, rightPlayer *Player) {
+
+	trackPress := func(player *Player, approaching bool) {
+		if !approaching {
+			player.pressRecorded = false
+		} else if player.justPressed && !player.pressRecorded {
+			player.pressPosition = this.position
+			player.pressRecorded = true
+		}
+	}
+
+	trackPress(leftPlayer, this.velocity < 0)
+	trackPress(rightPlayer, this.velocity > 0)
+}
+
 // Reset the position to the middle of the field
-func (this *Ball) ResetPosition(field *GameField) {
+func (this *Ball) ResetPosition(field Field) {
 
 	startingOffset := 0.25
 	if this.velocity < 0 {
@@ -130,6 +277,7 @@ func (this *Ball) ResetPosition(field *GameField) {
 	}
 
 	this.position = float64(field.Width()) * startingOffset
+	this.snap()
 }
 
 // Check if the player is doing an offensive hide
@@ -141,4 +289,5 @@ func (this *Ball) UpdateOffensiveHide(leftPlayer, rightPlayer *Player) {
 	} else if this.velocity > 0 && this.position > this.maxPosition/2.0 && leftPlayer.paddleActive {
 		this.hideBall = true
 	}
+	this.changed()
 }
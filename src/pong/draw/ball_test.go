@@ -0,0 +1,247 @@
+package draw
+
+import (
+	"math"
+	"testing"
+	"testing/quick"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Map any float quick generates onto [0, 1)
+func unit(value float64) float64 {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0
+	}
+	return math.Mod(math.Abs(value), 1)
+}
+
+// With both paddles always up the ball should stay on the field, and each return should mirror the ball off the paddle
+// and speed it up by exactly the bounce factor
+func Test_Ball_ReturnProperties(t *testing.T) {
+
+	field := NewGameField(100)
+	bounceFactor := 1.1
+
+	property := func(start, speed, step float64, fromLeft bool) bool {
+
+		ball := NewServedBall(field, fromLeft)
+		leftPlayer, rightPlayer := NewPlayer(true, 3, field), NewPlayer(false, 3, field)
+		leftPlayer.UpdatePaddleActive(true)
+		rightPlayer.UpdatePaddleActive(true)
+
+		// anywhere between the paddles, at up to 4 field widths / second, in steps that move it less than the field
+		position := leftPlayer.paddleRight + unit(start)*(rightPlayer.paddleLeft-leftPlayer.paddleRight)
+		velocity := math.Copysign(1+unit(speed)*400, ball.Velocity())
+		dt := 0.001 + unit(step)*0.049
+		ball.Place(position, velocity)
+
+		for tick := 0; tick < 500 && math.Abs(ball.Velocity())*dt < 20; tick++ {
+			before := ball.Velocity()
+			ball.Animate(dt)
+			overshot := ball.Position()
+
+			missed, bounced := ball.MissedByPlayer(leftPlayer, rightPlayer, bounceFactor)
+			if missed != nil {
+				t.Log("Missed with the paddle up at", ball.Position(), "moving", before)
+				return false
+			}
+			if ball.Position() < 0 || ball.Position() > ball.maxPosition {
+				t.Log("Ball left the field at", ball.Position(), "moving", before, "in steps of", dt)
+				return false
+			}
+			if bounced && math.Abs(ball.Velocity()+before*bounceFactor) > 1e-9*math.Abs(before) {
+				t.Log("Returned at", ball.Velocity(), "from", before)
+				return false
+			}
+
+			// the ball comes back off the paddle as far as it went past it
+			edge := rightPlayer.paddleLeft
+			if before < 0 {
+				edge = leftPlayer.paddleRight
+			}
+			if bounced && math.Abs((ball.Position()-edge)+(overshot-edge)) > 1e-9 {
+				t.Log("Went", overshot-edge, "past the paddle and came back", ball.Position()-edge)
+				return false
+			}
+			if !bounced && ball.Velocity() != before {
+				t.Log("Speed changed without a return")
+				return false
+			}
+		}
+		return true
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// A ball sitting exactly on the edge of a paddle should be returned, not missed, whichever side it is on
+func Test_Ball_BoundaryReturn(t *testing.T) {
+
+	field := NewGameField(100)
+	leftPlayer, rightPlayer := NewPlayer(true, 3, field), NewPlayer(false, 3, field)
+	leftPlayer.UpdatePaddleActive(true)
+	rightPlayer.UpdatePaddleActive(true)
+
+	for _, test := range []struct{ position, velocity float64 }{
+		{leftPlayer.paddleLeft, -50},
+		{leftPlayer.paddleRight, -50},
+		{rightPlayer.paddleLeft, 50},
+		{rightPlayer.paddleRight, 50},
+	} {
+		ball := NewServedBall(field, true)
+		ball.Place(test.position, test.velocity)
+		ball.Animate(0)
+		if missed, _ := ball.MissedByPlayer(leftPlayer, rightPlayer, 1); missed != nil {
+			t.Fatal("Missed at", test.position)
+		}
+		if ball.Position() < 0 || ball.Position() > ball.maxPosition {
+			t.Fatal("Ball left the field at", ball.Position(), "from", test.position)
+		}
+	}
+}
+
+// On a loop the ball should wrap around from the last led to the first, its tail following it across the join
+func Test_BallLooped(t *testing.T) {
+
+	field := NewGameField(20)
+	field.SetTopology(TopologyLoop)
+	ball := NewServedBall(field, false)
+	ball.Place(19, 4)
+
+	ball.Animate(0.5)
+	if ball.Position() != 1 {
+		t.Fatal("Ball wrapped around to", ball.Position())
+	}
+	if left, right := ball.Bounds(); left > 0 || right < 19 {
+		t.Fatal("Bounds of a ball across the join are", left, right)
+	}
+	if color := ball.ColorAt(19, RGBA{0, 0, 0, 255}); color == (RGBA{0, 0, 0, 255}) {
+		t.Fatal("Tail wasn't drawn behind the ball across the join")
+	}
+	if color := ball.ColorAt(3, RGBA{0, 0, 0, 255}); color != (RGBA{0, 0, 0, 255}) {
+		t.Fatal("Tail was drawn in front of the ball")
+	}
+}
+
+// A player with a wider hit zone further from the end should return the ball anywhere in it, miss a ball that gets
+// past it, and have their paddle drawn across it
+func Test_Ball_HitZone(t *testing.T) {
+
+	field := NewGameField(40)
+	leftPlayer, rightPlayer := NewPlayer(true, 3, field), NewPlayer(false, 3, field)
+	rightPlayer.SetHitZone(3, 2)
+	if rightPlayer.paddleLeft != 34.5 || rightPlayer.paddleRight != 37.5 {
+		t.Fatal("Hit zone is from", rightPlayer.paddleLeft, "to", rightPlayer.paddleRight)
+	}
+
+	rightPlayer.UpdatePaddleActive(true)
+	ball := NewServedBall(field, true)
+	ball.Place(35, 10)
+	if _, hit := ball.MissedByPlayer(leftPlayer, rightPlayer, 1); !hit {
+		t.Fatal("Ball wasn't returned inside the hit zone")
+	}
+	for position := 33.0; position <= 39; position++ {
+		drawn := rightPlayer.ColorAt(position, RGBA{}) == rightPlayer.paddleColor
+		if inZone := position >= 35 && position <= 37; drawn != inZone {
+			t.Fatal("Paddle drawn", drawn, "at", position)
+		}
+	}
+
+	rightPlayer.UpdatePaddleActive(false)
+	ball.Place(38, 10)
+	if missed, _ := ball.MissedByPlayer(leftPlayer, rightPlayer, 1); missed != rightPlayer {
+		t.Fatal("Ball past the hit zone wasn't missed")
+	}
+
+	// a zone can't reach past the middle of the field
+	leftPlayer.SetHitZone(30, 5)
+	if leftPlayer.paddleRight > leftPlayer.end+0.5 {
+		t.Fatal("Hit zone reaches", leftPlayer.paddleRight)
+	}
+}
+
+// The player in the lead should have their hit zone shrink for each point of their lead, never below the minimum,
+// and get it back once the score evens out
+func Test_Player_RubberBand(t *testing.T) {
+
+	defer func(leds float64) { Settings.RubberBandLeds = leds }(Settings.RubberBandLeds)
+	Settings.RubberBandLeds = 0.25
+
+	field := NewGameField(40)
+	leftPlayer, rightPlayer := NewPlayer(true, 3, field), NewPlayer(false, 3, field)
+
+	RubberBand(leftPlayer, rightPlayer, 2, 0)
+	if leftPlayer.paddleLeft != -0.5 || leftPlayer.paddleRight != 0 {
+		t.Fatal("Leading hit zone is from", leftPlayer.paddleLeft, "to", leftPlayer.paddleRight)
+	}
+	if rightPlayer.paddleRight-rightPlayer.paddleLeft != 1 {
+		t.Fatal("Trailing hit zone shrank")
+	}
+
+	RubberBand(leftPlayer, rightPlayer, 9, 0)
+	if width := leftPlayer.paddleRight - leftPlayer.paddleLeft; width != minimumHitZoneWidth {
+		t.Fatal("Hit zone shrank to", width)
+	}
+
+	RubberBand(leftPlayer, rightPlayer, 9, 9)
+	if leftPlayer.paddleRight != 0.5 {
+		t.Fatal("Hit zone wasn't restored once the score was even")
+	}
+
+	Settings.RubberBandLeds = 0
+	RubberBand(leftPlayer, rightPlayer, 5, 0)
+	if leftPlayer.paddleRight != 0.5 {
+		t.Fatal("Hit zone shrank with the rubber band disabled")
+	}
+}
+
+// A pulsing paddle should change brightness as it animates and a rainbow one should change color, each giving the
+// player a new version to redraw
+func Test_Player_PaddleEffect(t *testing.T) {
+
+	field := NewGameField(40)
+	for _, effect := range []string{PaddlePulse, PaddleRainbow} {
+		player := NewPlayer(true, 3, field)
+		player.SetPaddleEffect(effect)
+		player.UpdatePaddleActive(true)
+
+		before, version := player.ColorAt(0, RGBA{0, 0, 0, 255}), player.Version()
+		player.Animate(0.1)
+		if player.ColorAt(0, RGBA{0, 0, 0, 255}) == before || player.Version() == version {
+			t.Fatal(effect, "paddle didn't change as it animated")
+		}
+	}
+}
+
+// The ball should only get a new version when it looks different, so a ball standing still isn't redrawn
+func Test_Ball_Version(t *testing.T) {
+
+	field := NewGameField(40)
+	ball := NewServedBall(field, true)
+
+	version := ball.Version()
+	if ball.Version() != version {
+		t.Fatal("Version changed without the ball changing")
+	}
+
+	ball.Animate(0.1)
+	if ball.Version() == version {
+		t.Fatal("Version didn't change as the ball moved")
+	}
+
+	ball.Place(10, 0)
+	version = ball.Version()
+	ball.Animate(0.1)
+	ball.Interpolate(0.5)
+	if ball.Version() != version {
+		t.Fatal("Version changed while the ball stood still")
+	}
+
+	ball.Place(10, 5)
+	if ball.Version() == version {
+		t.Fatal("Version didn't change as the ball turned")
+	}
+}
@@ -0,0 +1,99 @@
+package draw
+
+import (
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Columns / second a banner scrolls at
+var TextBannerSpeed float64 = 12
+
+// Text drawn in the 5x7 font across the middle rows of a matrix, scrolling in from the right and out to the left over
+// and over, or standing still in the middle when its speed is 0. Nothing is drawn on a strip
+type TextBanner struct {
+	width float64
+	color RGBA
+
+	// characters drawn, and the columns they cover
+	text      []rune
+	textWidth float64
+
+	// row the top of the text is drawn on
+	top int
+
+	// columns / second the text moves left, column the text starts at, and times it has scrolled all the way across
+	speed  float64
+	left   float64
+	passes int
+}
+
+var _ Drawable2D = &TextBanner{}
+
+// Construct a TextBanner showing text in color on field, scrolling at speed columns / second
+func NewTextBanner(field Field, text string, color RGBA, speed float64) *TextBanner {
+	banner := &TextBanner{
+		width: float64(field.Width()),
+		color: color,
+		top:   (field.Height() - FontHeight) / 2,
+		speed: speed,
+	}
+	banner.SetText(text)
+	return banner
+}
+
+// Show text instead, starting again from the right, or in the middle if the banner doesn't scroll
+func (this *TextBanner) SetText(text string) {
+	this.text = []rune(text)
+	this.textWidth = float64(TextWidth(text))
+	if this.speed > 0 {
+		this.left = this.width
+	} else {
+		this.left = math.Floor((this.width - this.textWidth) / 2)
+	}
+}
+
+// Times the text has scrolled all the way across the field
+func (this *TextBanner) Passes() int {
+	return this.passes
+}
+
+// Not drawn on a strip
+func (this *TextBanner) ColorAt(position float64, baseColor RGBA) RGBA {
+	return baseColor
+}
+
+// Returns the color at column x of row y blended on top of baseColor
+func (this *TextBanner) ColorAt2D(x, y float64, baseColor RGBA) RGBA {
+
+	column := int(math.Floor(x - this.left))
+	if column < 0 || float64(column) >= this.textWidth {
+		return baseColor
+	}
+
+	character := this.text[column/(FontWidth+FontSpacing)]
+	if fontLit(character, column%(FontWidth+FontSpacing), int(y)-this.top) {
+		return this.color.BlendWith(baseColor)
+	}
+	return baseColor
+}
+
+// Above the game but below the tint of the theme
+func (this *TextBanner) ZIndex() ZIndex {
+	return 200
+}
+
+// Scroll the text left, starting again from the right once it has gone off the left side
+func (this *TextBanner) Animate(dt float64) bool {
+
+	if this.speed <= 0 {
+		return true
+	}
+
+	this.left -= this.speed * dt
+	if this.left <= -this.textWidth {
+		this.left = this.width
+		this.passes++
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package draw
+
+import (
+	"testing"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Text should be drawn in the font, standing in the middle or scrolling off the left and starting again
+func Test_TextBanner(t *testing.T) {
+
+	white := RGBA{255, 255, 255, 255}
+	if width := TextWidth("Hi!"); width != 17 {
+		t.Fatal("Three characters are", width, "columns wide")
+	}
+
+	field := NewMatrixField(15, 9)
+	banner := NewTextBanner(field, "L", white, 0)
+	if banner.left != 5 || banner.top != 1 {
+		t.Fatal("Still banner starts at column", banner.left, "row", banner.top)
+	}
+
+	// L is its left column and bottom row
+	lit := func(x, y float64) bool {
+		return banner.ColorAt2D(x, y, RGBA{}) == white
+	}
+	if !lit(5, 1) || !lit(5, 7) || !lit(9, 7) || lit(6, 1) || lit(10, 7) || lit(5, 8) {
+		t.Fatal("L was drawn wrong")
+	}
+	if banner.ColorAt(5, RGBA{}) != (RGBA{}) {
+		t.Fatal("Banner drawn on a strip")
+	}
+
+	if FontGlyph('é') != FontGlyph('?') {
+		t.Fatal("Character outside the font isn't drawn as ?")
+	}
+
+	banner = NewTextBanner(field, "LL", white, 10)
+	if banner.left != 15 {
+		t.Fatal("Scrolling banner starts at column", banner.left)
+	}
+	banner.Animate(1)
+	if !lit(5, 7) {
+		t.Fatal("Banner scrolled to", banner.left)
+	}
+	banner.Animate(2)
+	if banner.Passes() != 1 || banner.left != 15 {
+		t.Fatal("Banner passed", banner.Passes(), "times and is at", banner.left)
+	}
+}
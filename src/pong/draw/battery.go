@@ -0,0 +1,84 @@
+package draw
+
+import (
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Colors of the charge left in the battery when it is more than half full, when it is getting low and when it is
+// nearly empty, and of the part of the indicator that has run down
+var chargeFullColor = RGBA{0, 255, 0, 200}
+var chargeHalfColor = RGBA{255, 160, 0, 200}
+var chargeLowColor = RGBA{255, 0, 0, 200}
+var chargeEmptyColor = RGBA{255, 255, 255, 32}
+
+// Leds at the right end of the strip the indicator takes, and the charge below which it blinks while on the battery
+var chargeIndicatorLeds = 5.0
+var chargeLowFraction = 0.2
+
+// Charge of the battery of a UPS HAT shown as a small gauge at the right end of the strip, filling from the left
+type ChargeIndicator struct {
+	width float64
+
+	// source of whether the Pi is running on the battery and its charge, NaN when it isn't known
+	battery func() (onBattery bool, charge float64)
+
+	onBattery bool
+	charge    float64
+
+	// seconds shown, for blinking while low
+	time float64
+}
+
+var _ Drawable = &ChargeIndicator{}
+
+// Construct a ChargeIndicator showing the charge returned by battery
+func NewChargeIndicator(field Field, battery func() (bool, float64)) *ChargeIndicator {
+	indicator := &ChargeIndicator{
+		width:   float64(field.Width()),
+		battery: battery,
+	}
+	indicator.Animate(0)
+	return indicator
+}
+
+// Color of the charge left
+func (this *ChargeIndicator) chargeColor() RGBA {
+	switch {
+	case this.charge > 0.5:
+		return chargeFullColor
+	case this.charge > chargeLowFraction:
+		return chargeHalfColor
+	}
+	return chargeLowColor
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *ChargeIndicator) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	start := this.width - chargeIndicatorLeds
+	if math.IsNaN(this.charge) || position < start-0.5 {
+		return baseColor
+	}
+	if this.onBattery && this.charge <= chargeLowFraction && math.Mod(this.time, 1) >= 0.5 {
+		return baseColor
+	}
+
+	if (position-start+0.5)/chargeIndicatorLeds <= this.charge {
+		return this.chargeColor().BlendWith(baseColor)
+	}
+	return chargeEmptyColor.BlendWith(baseColor)
+}
+
+// ZIndex
+func (this *ChargeIndicator) ZIndex() ZIndex {
+	return 20
+}
+
+// Read the charge to show
+func (this *ChargeIndicator) Animate(dt float64) bool {
+	this.time += dt
+	this.onBattery, this.charge = this.battery()
+	return true
+}
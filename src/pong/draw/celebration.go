@@ -0,0 +1,112 @@
+package draw
+
+import (
+	"math"
+	"math/rand"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Seconds between bursts, how long each lasts, and how fast each grows in leds / second
+var celebrationBurstInterval float64 = 0.15
+var celebrationBurstTime float64 = 0.8
+var celebrationBurstSpeed float64 = 20
+
+// A single firework of the celebration
+type burst struct {
+	center, age float64
+}
+
+// Fireworks bursting over the side of the player who unlocked an achievement
+type Celebration struct {
+	color RGBA
+
+	// range of positions the bursts are centered in
+	left, right float64
+
+	bursts    []burst
+	nextBurst float64
+	random    *rand.Rand
+
+	time, totalTime float64
+}
+
+var _ Drawable = &Celebration{}
+
+// Construct a Celebration in color over the left or right half of field, shown for totalTime seconds
+func NewCelebration(field Field, isLeft bool, color RGBA, totalTime float64) *Celebration {
+
+	this := &Celebration{
+		color:     color,
+		left:      0,
+		right:     float64(field.Width())/2.0 - 1,
+		random:    NewRand(),
+		totalTime: totalTime,
+	}
+	if !isLeft {
+		this.left, this.right = float64(field.Width())/2.0, float64(field.Width())-1
+	}
+	return this
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *Celebration) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	if position < this.left || position > this.right {
+		return baseColor
+	}
+
+	// the brightest ring wins where bursts overlap
+	brightest := 0.0
+	for _, burst := range this.bursts {
+		radius := burst.age * celebrationBurstSpeed
+		distance := math.Abs(math.Abs(position-burst.center) - radius)
+		if distance < 1 {
+			brightness := (1 - distance) * (1 - burst.age/celebrationBurstTime)
+			brightest = math.Max(brightest, brightness)
+		}
+	}
+	if brightest <= 0 {
+		return baseColor
+	}
+
+	color := RGBA{this.color.R, this.color.G, this.color.B, uint8(255 * brightest)}
+	return color.BlendWith(baseColor)
+}
+
+// ZIndex
+func (this *Celebration) ZIndex() ZIndex {
+	return 20
+}
+
+// Animate the bursts, starting new ones until the celebration is nearly over
+func (this *Celebration) Animate(dt float64) bool {
+
+	this.time += dt
+	if this.time >= this.totalTime {
+		this.time = this.totalTime
+	}
+
+	alive := this.bursts[:0]
+	for _, burst := range this.bursts {
+		burst.age += dt
+		if burst.age < celebrationBurstTime {
+			alive = append(alive, burst)
+		}
+	}
+	this.bursts = alive
+
+	this.nextBurst -= dt
+	if this.nextBurst <= 0 && this.TimeRemaining() > celebrationBurstTime {
+		this.nextBurst = celebrationBurstInterval
+		center := this.left + this.random.Float64()*(this.right-this.left)
+		this.bursts = append(this.bursts, burst{center: center})
+	}
+
+	return true
+}
+
+// Amount of time remaining in the celebration
+func (this *Celebration) TimeRemaining() float64 {
+	return this.totalTime - this.time
+}
@@ -0,0 +1,79 @@
+package draw
+
+import (
+	"math"
+	"time"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Colors of the hours filled in along the strip, the marks between them, the minute and the second
+var clockHourColor = RGBA{255, 120, 20, 96}
+var clockMarkColor = RGBA{255, 255, 255, 48}
+var clockMinuteColor = RGBA{0, 200, 255, 255}
+var clockSecondColor = RGBA{255, 255, 255, 64}
+
+// The time of day shown along the strip, the hours of the 12 hour clock filled in from the left with the minute and
+// second as markers over them
+type AmbientClock struct {
+	width float64
+
+	// source of the time shown
+	now func() time.Time
+
+	// fractions of the strip the hour, minute and second reach
+	hours, minute, second float64
+}
+
+var _ Drawable = &AmbientClock{}
+
+// Construct an AmbientClock showing the time returned by now
+func NewAmbientClock(field Field, now func() time.Time) *AmbientClock {
+	clock := &AmbientClock{
+		width: float64(field.Width()),
+		now:   now,
+	}
+	clock.Animate(0)
+	return clock
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *AmbientClock) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	near := func(fraction float64) bool {
+		return math.Abs(position-fraction*(this.width-1)) < 0.5
+	}
+
+	if near(this.minute) {
+		return clockMinuteColor.BlendWith(baseColor)
+	}
+	if near(this.second) {
+		baseColor = clockSecondColor.BlendWith(baseColor)
+	}
+	if position <= this.hours*(this.width-1) {
+		baseColor = clockHourColor.BlendWith(baseColor)
+	}
+	for hour := 1; hour < 12; hour++ {
+		if near(float64(hour) / 12) {
+			return clockMarkColor.BlendWith(baseColor)
+		}
+	}
+	return baseColor
+}
+
+// ZIndex
+func (this *AmbientClock) ZIndex() ZIndex {
+	return 10
+}
+
+// Read the time to show
+func (this *AmbientClock) Animate(dt float64) bool {
+
+	now := this.now()
+	hour, minute, second := now.Clock()
+	minutes := float64(minute) + float64(second)/60
+	this.hours = (float64(hour%12) + minutes/60) / 12
+	this.minute = minutes / 60
+	this.second = (float64(second) + float64(now.Nanosecond())/1e9) / 60
+	return true
+}
@@ -0,0 +1,98 @@
+package draw
+
+import (
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Seconds of holding the paddle before the ball arrives that still counts as a good return
+var coachWindowTime float64 = 0.25
+
+// Overlay shown after a miss, green where a press would have returned the ball and red where the player pressed
+type Coach struct {
+
+	// bounds of the window where pressing would have returned the ball
+	windowLeft, windowRight float64
+
+	// ball position when the player pressed
+	pressPosition float64
+	pressed       bool
+
+	// time shown so far and total time to show
+	time, totalTime float64
+
+	// counts calls to Animate
+	version uint64
+}
+
+var _ TrackedDrawable = &Coach{}
+
+// Construct a Coach for player who just missed a ball moving at velocity
+func NewCoach(player *Player, velocity float64, totalTime float64) *Coach {
+
+	coach := &Coach{
+		pressPosition: player.pressPosition,
+		pressed:       player.pressRecorded,
+		totalTime:     totalTime,
+	}
+
+	reach := math.Abs(velocity) * coachWindowTime
+	if player.isLeft {
+		coach.windowLeft = player.paddleLeft
+		coach.windowRight = player.paddleRight + reach
+	} else {
+		coach.windowLeft = player.paddleLeft - reach
+		coach.windowRight = player.paddleRight
+	}
+
+	return coach
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *Coach) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	fade := 1.0 - this.time/this.totalTime
+
+	if this.pressed && math.Abs(position-this.pressPosition) < 0.5 {
+		marker := RGBA{255, 0, 0, uint8(255 * fade)}
+		return marker.BlendWith(baseColor)
+	}
+
+	if this.windowLeft <= position && position <= this.windowRight {
+		window := RGBA{0, 255, 0, uint8(128 * fade)}
+		return window.BlendWith(baseColor)
+	}
+
+	return baseColor
+}
+
+// Range of positions covered by the window and the press marker
+func (this *Coach) Bounds() (left, right float64) {
+
+	left, right = this.windowLeft, this.windowRight
+	if this.pressed {
+		left = math.Min(left, this.pressPosition-0.5)
+		right = math.Max(right, this.pressPosition+0.5)
+	}
+	return
+}
+
+// Changes every frame as the coach fades out
+func (this *Coach) Version() uint64 {
+	return this.version
+}
+
+// ZIndex, above the players but below the ball
+func (this *Coach) ZIndex() ZIndex {
+	return 50
+}
+
+// Animate, removed from the field once totalTime has passed
+func (this *Coach) Animate(dt float64) bool {
+
+	this.time += dt
+	this.version++
+
+	return this.time < this.totalTime
+}
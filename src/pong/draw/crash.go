@@ -0,0 +1,39 @@
+package draw
+
+import (
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Blocks of red flashing along the whole strip, shown after the game crashes
+type CrashPattern struct {
+	time float64
+}
+
+var _ Drawable = &CrashPattern{}
+
+// Construct a CrashPattern
+func NewCrashPattern() *CrashPattern {
+	return &CrashPattern{}
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *CrashPattern) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	// alternate blocks swap over twice a second
+	block := int(position/10) + int(this.time*2)
+	if block%2 == 0 {
+		return RGBA{255, 0, 0, 255}
+	}
+	return baseColor
+}
+
+// ZIndex
+func (this *CrashPattern) ZIndex() ZIndex {
+	return 100
+}
+
+// Animate
+func (this *CrashPattern) Animate(dt float64) bool {
+	this.time += dt
+	return true
+}
@@ -0,0 +1,106 @@
+package draw
+
+import (
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Color the phase indicator is drawn in for each phase, in the order phases are declared
+var debugPhaseColors = []RGBA{
+	PhaseIdle:              {64, 64, 64, 255},
+	PhaseWaitingForPlayers: {255, 255, 255, 255},
+	PhaseCountdown:         {255, 255, 0, 255},
+	PhaseRally:             {0, 255, 0, 255},
+	PhasePointScored:       {255, 128, 0, 255},
+	PhaseGameOver:          {255, 0, 0, 255},
+	PhaseLeaderboard:       {0, 128, 255, 255},
+}
+
+// Color the hit windows of the players are tinted with
+var debugHitZoneColor = RGBA{255, 0, 255, 120}
+
+// Ball speed in field widths / second drawn as full red, slower balls are drawn closer to green
+var debugMaxSpeed float64 = 2
+
+// Draws the state of the game over the field for tuning it: the hit window of each player, and in the middle of the
+// field the phase on either side of a marker showing the speed of the ball on the side it is heading to
+type DebugOverlay struct {
+
+	// field the players and ball are found in
+	field Field
+
+	// phases the game moves through
+	states *StateMachine
+
+	// state read from the field in the last Animate
+	hitZones []Extent
+	velocity float64
+	hasBall  bool
+	phase    Phase
+
+	stopped bool
+}
+
+var _ Drawable = &DebugOverlay{}
+
+// Construct a DebugOverlay showing the drawables of field and the phase of states
+func NewDebugOverlay(field Field, states *StateMachine) *DebugOverlay {
+	return &DebugOverlay{field: field, states: states}
+}
+
+// Remove the overlay from its field on the next Animate
+func (this *DebugOverlay) Stop() {
+	this.stopped = true
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *DebugOverlay) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	for _, zone := range this.hitZones {
+		if zone.Contains(Position(position)) {
+			baseColor = debugHitZoneColor.BlendWith(baseColor)
+		}
+	}
+
+	middle := math.Floor(float64(this.field.Width()) / 2)
+	switch led := math.Floor(position) - middle; led {
+	case -2, 1:
+		if int(this.phase) < len(debugPhaseColors) {
+			return debugPhaseColors[this.phase]
+		}
+	case -1, 0:
+		headingRight := this.velocity > 0
+		if this.hasBall && headingRight == (led == 0) {
+			fraction := math.Min(math.Abs(this.velocity)/float64(this.field.Width())/debugMaxSpeed, 1)
+			return RGBA{uint8(255 * fraction), uint8(255 * (1 - fraction)), 0, 255}
+		}
+		return RGBA{0, 0, 0, 255}
+	}
+
+	return baseColor
+}
+
+// Drawn over everything else
+func (this *DebugOverlay) ZIndex() ZIndex {
+	return 1000
+}
+
+// Read the hit windows, ball and phase for this frame
+func (this *DebugOverlay) Animate(dt float64) bool {
+
+	this.hitZones = this.hitZones[:0]
+	this.hasBall = false
+	for _, drawable := range this.field.Drawables() {
+		switch drawable := drawable.(type) {
+		case *Player:
+			this.hitZones = append(this.hitZones, drawable.HitZone())
+		case *Ball:
+			this.velocity = drawable.velocity
+			this.hasBall = true
+		}
+	}
+	this.phase = this.states.Phase()
+
+	return !this.stopped
+}
@@ -0,0 +1,75 @@
+package draw
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+var benchmarkColor RGBA
+
+// Call ColorAt at every position of a 300 led field
+func benchmarkColorAt(b *testing.B, drawable Drawable) {
+	for iteration := 0; iteration < b.N; iteration++ {
+		benchmarkColor = drawable.ColorAt(float64(iteration%300), RGBA{0, 0, 0, 255})
+	}
+}
+
+func BenchmarkColorAt(b *testing.B) {
+	field := NewGameField(300)
+	ball := NewBall(field)
+	player := NewPlayer(true, 10, field)
+	menu := NewMenu("mode", []MenuOption{{"classic", RGBA{255, 255, 255, 255}}, {"ai", RGBA{255, 0, 0, 255}}})
+
+	drawables := map[string]Drawable{
+		"ball":      ball,
+		"player":    player,
+		"coach":     NewCoach(player, 100, 1),
+		"countdown": NewCountdown(field, 2),
+		"winner":    NewWinner(field, true, 4),
+		"boot":      NewBoot(field, 2),
+		"menu":      NewMenuDisplay(field, menu),
+		"fade":      NewFadeIn(NewSinusoid(field, 1), 1),
+		"rotation":  NewBackgroundRotation(field, []string{"fire", "noise"}, 10, 1, 1),
+	}
+	for _, name := range BackgroundNames() {
+		if background := NewBackground(name, field, 1); background != nil {
+			drawables[name] = background
+		}
+	}
+
+	names := make([]string, 0, len(drawables))
+	for name := range drawables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		drawable := drawables[name]
+		drawable.Animate(0.5)
+		b.Run(name, func(b *testing.B) {
+			benchmarkColorAt(b, drawable)
+		})
+	}
+}
+
+// Animate and render a game with a background at each strip length
+func BenchmarkGameFrame(b *testing.B) {
+	for _, width := range []int{60, 150, 300} {
+		b.Run(fmt.Sprint(width), func(b *testing.B) {
+			field := NewGameField(width)
+			field.Add(NewSinusoid(field, 1))
+			field.Add(NewBall(field))
+			field.Add(NewPlayer(true, 10, field))
+			field.Add(NewPlayer(false, 10, field))
+
+			b.ResetTimer()
+			for iteration := 0; iteration < b.N; iteration++ {
+				field.Animate(1.0 / 60)
+				field.Render()
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package draw
+
+import (
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Wraps a Drawable, mixing its colors with the colors underneath it by an opacity
+type Fade struct {
+	drawable Drawable
+
+	// 0 shows only the colors underneath, 1 shows only the wrapped drawable
+	opacity float64
+
+	// change in opacity per second
+	rate float64
+}
+
+var _ ContextDrawable = &Fade{}
+var _ LifecycleDrawable = &Fade{}
+
+// Construct a Fade starting at opacity and changing by rate per second
+func NewFade(drawable Drawable, opacity, rate float64) *Fade {
+	return &Fade{
+		drawable: drawable,
+		opacity:  opacity,
+		rate:     rate,
+	}
+}
+
+// Construct a Fade going from invisible to fully shown over seconds
+func NewFadeIn(drawable Drawable, seconds float64) *Fade {
+	if seconds <= 0 {
+		return NewFade(drawable, 1, 0)
+	}
+	return NewFade(drawable, 0, 1/seconds)
+}
+
+// Start fading out over seconds, the Fade is removed once it is invisible
+func (this *Fade) FadeOut(seconds float64) {
+	if seconds <= 0 {
+		this.opacity = 0
+		this.rate = -1
+		return
+	}
+	this.rate = -1 / seconds
+}
+
+// Current opacity
+func (this *Fade) Opacity() float64 {
+	return this.opacity
+}
+
+// Returns the wrapped drawable's color mixed with baseColor
+func (this *Fade) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	if this.opacity <= 0 {
+		return baseColor
+	}
+
+	color := this.drawable.ColorAt(position, baseColor)
+	if this.opacity >= 1 {
+		return color
+	}
+
+	return mix(baseColor, color, this.opacity)
+}
+
+// ZIndex of the wrapped drawable
+func (this *Fade) ZIndex() ZIndex {
+	return this.drawable.ZIndex()
+}
+
+// Animate the wrapped drawable and the opacity
+func (this *Fade) Animate(dt float64) bool {
+	return this.AnimateInContext(dt, AnimateContext{})
+}
+
+// Animate the wrapped drawable in context and the opacity
+func (this *Fade) AnimateInContext(dt float64, context AnimateContext) bool {
+
+	this.opacity += this.rate * dt
+	if this.opacity > 1 {
+		this.opacity = 1
+	}
+
+	if this.opacity <= 0 && this.rate < 0 {
+		return false
+	}
+
+	return AnimateDrawable(this.drawable, dt, context)
+}
+
+// Tell the wrapped drawable it was added to field
+func (this *Fade) OnAdd(field Field) {
+	NotifyAdded(this.drawable, field)
+}
+
+// Tell the wrapped drawable it was taken off field
+func (this *Fade) OnRemove(field Field) {
+	NotifyRemoved(this.drawable, field)
+}
+
+// Linear mix from color a to color b
+func mix(a, b RGBA, amount float64) RGBA {
+	return RGBA{
+		uint8(float64(a.R) + (float64(b.R)-float64(a.R))*amount),
+		uint8(float64(a.G) + (float64(b.G)-float64(a.G))*amount),
+		uint8(float64(a.B) + (float64(b.B)-float64(a.B))*amount),
+		255,
+	}
+}
@@ -0,0 +1,83 @@
+package draw
+
+import (
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Fraction of the time a goal effect spends fading in and fading out
+var goalFadeFraction = 0.2
+
+// An effect from the library played at one end of the strip when a point is scored there, fading towards the middle
+// and mirrored at the right end so both players see it the same way round
+type GoalEffect struct {
+	effect Drawable
+
+	// which end it plays at, and how far it reaches in leds
+	isLeft bool
+	width  float64
+	leds   float64
+
+	time, totalTime float64
+}
+
+var _ LifecycleDrawable = &GoalEffect{}
+
+// Construct a GoalEffect playing effect over leds at the left or right end of field for totalTime seconds
+func NewGoalEffect(field Field, effect Drawable, isLeft bool, leds, totalTime float64) *GoalEffect {
+	return &GoalEffect{
+		effect:    effect,
+		isLeft:    isLeft,
+		width:     float64(field.Width()),
+		leds:      math.Min(leds, float64(field.Width())/2),
+		totalTime: totalTime,
+	}
+}
+
+// Returns the color of the effect at position blended on top of baseColor, where position is in reach of the end
+func (this *GoalEffect) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	distance := position
+	if !this.isLeft {
+		distance = this.width - 1 - position
+	}
+	if distance < 0 || distance >= this.leds {
+		return baseColor
+	}
+
+	fade := this.totalTime * goalFadeFraction
+	opacity := math.Min(1, math.Min(this.time, this.TimeRemaining())/fade)
+	opacity *= 1 - distance/this.leds
+
+	color := this.effect.ColorAt(distance, baseColor)
+	color.A = ScaleChannel(255, math.Max(0, opacity))
+	return color.BlendWith(baseColor)
+}
+
+// Over the players so it can be seen at their end
+func (this *GoalEffect) ZIndex() ZIndex {
+	return 15
+}
+
+// Animate the effect, removed from the field once totalTime has passed
+func (this *GoalEffect) Animate(dt float64) bool {
+	this.time += dt
+	AnimateDrawable(this.effect, dt, AnimateContext{})
+	return this.time < this.totalTime
+}
+
+// Amount of time remaining in the effect
+func (this *GoalEffect) TimeRemaining() float64 {
+	return this.totalTime - this.time
+}
+
+// Tell the effect it was added to field
+func (this *GoalEffect) OnAdd(field Field) {
+	NotifyAdded(this.effect, field)
+}
+
+// Tell the effect it was taken off field
+func (this *GoalEffect) OnRemove(field Field) {
+	NotifyRemoved(this.effect, field)
+}
@@ -0,0 +1,101 @@
+package draw
+
+import (
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Colors of the leds where the ball was only returned and where it was only missed, leds with both are mixed by how
+// many of each there were
+var heatmapReturnColor = RGBA{0, 255, 0, 255}
+var heatmapMissColor = RGBA{255, 0, 0, 255}
+
+// Opacity of the leds hit the fewest times, so a single return still shows next to a led hit many times
+var heatmapMinOpacity = 0.2
+
+// Seconds the heatmap takes to fade in and out
+var heatmapFadeSeconds = 0.5
+
+// Where the ball was returned and missed along the strip over a match, brighter where it happened more often
+type HitHeatmap struct {
+
+	// returns and misses on each led, and the most on any led
+	returns, misses []int
+	most            int
+
+	time, totalTime float64
+}
+
+var _ Drawable = &HitHeatmap{}
+
+// Construct a HitHeatmap on field of the leds the ball was returned at and missed at, shown for totalTime seconds
+func NewHitHeatmap(field Field, returns, misses []float64, totalTime float64) *HitHeatmap {
+
+	width := field.Width()
+	this := &HitHeatmap{
+		returns:   make([]int, width),
+		misses:    make([]int, width),
+		totalTime: totalTime,
+	}
+
+	count := func(counts []int, positions []float64) {
+		for _, position := range positions {
+			led := int(math.Max(0, math.Min(float64(width-1), math.Floor(position+0.5))))
+			counts[led]++
+		}
+	}
+	count(this.returns, returns)
+	count(this.misses, misses)
+
+	for led := range this.returns {
+		if total := this.returns[led] + this.misses[led]; total > this.most {
+			this.most = total
+		}
+	}
+	return this
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *HitHeatmap) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	led := int(position)
+	if led < 0 || led >= len(this.returns) {
+		return baseColor
+	}
+	total := this.returns[led] + this.misses[led]
+	if total == 0 {
+		return baseColor
+	}
+
+	missed := float64(this.misses[led]) / float64(total)
+	mix := func(from, to uint8) uint8 {
+		return uint8(float64(from) + (float64(to)-float64(from))*missed + 0.5)
+	}
+
+	fade := math.Min(1, math.Min(this.time, this.TimeRemaining())/heatmapFadeSeconds)
+	opacity := heatmapMinOpacity + (1-heatmapMinOpacity)*float64(total)/float64(this.most)
+	color := RGBA{
+		mix(heatmapReturnColor.R, heatmapMissColor.R),
+		mix(heatmapReturnColor.G, heatmapMissColor.G),
+		mix(heatmapReturnColor.B, heatmapMissColor.B),
+		ScaleChannel(255, math.Max(0, fade)*opacity),
+	}
+	return color.BlendWith(baseColor)
+}
+
+// ZIndex
+func (this *HitHeatmap) ZIndex() ZIndex {
+	return 20
+}
+
+// Fade in and out
+func (this *HitHeatmap) Animate(dt float64) bool {
+	this.time += dt
+	return true
+}
+
+// Amount of time remaining before the heatmap is done
+func (this *HitHeatmap) TimeRemaining() float64 {
+	return this.totalTime - this.time
+}
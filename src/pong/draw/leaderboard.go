@@ -0,0 +1,90 @@
+package draw
+
+import (
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Bars of the leaderboard grow to their full length over this many seconds
+var leaderboardGrowTime float64 = 0.5
+
+// A single player on the leaderboard
+type LeaderboardBar struct {
+	Color RGBA
+	Wins  int
+}
+
+// Shows the leading players as bars from left to right, best first, each as long as its share of the leader's wins
+type Leaderboard struct {
+	bars []LeaderboardBar
+
+	// wins of the leading player
+	maxWins int
+
+	// width of the field
+	width float64
+
+	time, totalTime float64
+}
+
+var _ Drawable = &Leaderboard{}
+
+// Construct a Leaderboard shown for totalTime seconds, bars are in order from the leader down
+func NewLeaderboard(field Field, bars []LeaderboardBar, totalTime float64) *Leaderboard {
+
+	this := &Leaderboard{
+		bars:      bars,
+		width:     float64(field.Width()),
+		totalTime: totalTime,
+	}
+	for _, bar := range bars {
+		if bar.Wins > this.maxWins {
+			this.maxWins = bar.Wins
+		}
+	}
+	return this
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *Leaderboard) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	if len(this.bars) == 0 || this.maxWins == 0 {
+		return baseColor
+	}
+
+	slotWidth := this.width / float64(len(this.bars))
+	index := int(position / slotWidth)
+	if index >= len(this.bars) {
+		return baseColor
+	}
+
+	// leave a gap between bars, and grow them in when first shown
+	grown := min(this.time/leaderboardGrowTime, 1)
+	length := (slotWidth - 1.0) * float64(this.bars[index].Wins) / float64(this.maxWins) * grown
+	offset := position - float64(index)*slotWidth - 1.0
+	if offset < 0 || offset >= length {
+		return baseColor
+	}
+
+	return this.bars[index].Color.BlendWith(baseColor)
+}
+
+// ZIndex
+func (this *Leaderboard) ZIndex() ZIndex {
+	return 20
+}
+
+// Animate
+func (this *Leaderboard) Animate(dt float64) bool {
+
+	this.time += dt
+	if this.time >= this.totalTime {
+		this.time = this.totalTime
+	}
+
+	return true
+}
+
+// Amount of time remaining before the leaderboard is done
+func (this *Leaderboard) TimeRemaining() float64 {
+	return this.totalTime - this.time
+}
@@ -0,0 +1,244 @@
+package draw
+
+import (
+	"math"
+	"math/rand"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Rows / second a paddle moves up or down
+var MatrixPaddleSpeed float64 = 12
+
+// Directions a paddle can be moved in
+const (
+	PaddleUp   float64 = -1
+	PaddleStop float64 = 0
+	PaddleDown float64 = 1
+)
+
+// A paddle at one end of a matrix that moves up and down, drawn only on a matrix
+type MatrixPaddle struct {
+	color RGBA
+
+	// column the paddle is in, rows it covers, and the highest and lowest rows its top can reach
+	column                float64
+	size                  float64
+	highestTop, lowestTop float64
+
+	// row of the top of the paddle
+	top float64
+}
+
+var _ Drawable2D = &MatrixPaddle{}
+
+// Construct a MatrixPaddle at the left or right end of field in color, playing on the rows from firstRow down and a
+// third of them tall
+func NewMatrixPaddle(field Field, firstRow int, isLeft bool, color RGBA) *MatrixPaddle {
+
+	rows := float64(field.Height() - firstRow)
+	size := math.Max(1, math.Floor(rows/3))
+	paddle := &MatrixPaddle{
+		color:      color,
+		size:       size,
+		highestTop: float64(firstRow),
+		lowestTop:  float64(field.Height()) - size,
+	}
+	if !isLeft {
+		paddle.column = float64(field.Width() - 1)
+	}
+	paddle.top = paddle.highestTop + math.Floor((rows-size)/2)
+	return paddle
+}
+
+// Move the paddle in direction, PaddleUp, PaddleStop or PaddleDown, for dt seconds
+func (this *MatrixPaddle) Move(direction float64, dt float64) {
+	this.top += direction * MatrixPaddleSpeed * dt
+	this.top = math.Max(this.highestTop, math.Min(this.lowestTop, this.top))
+}
+
+// Where row is on the paddle, from -1 at the top to 1 at the bottom, false if the paddle doesn't cover it
+func (this *MatrixPaddle) Covers(row float64) (offset float64, covered bool) {
+	top := math.Round(this.top)
+	if row < top-0.5 || row > top+this.size-0.5 {
+		return 0, false
+	}
+	center := top + (this.size-1)/2
+	return (row - center) / math.Max(this.size/2, 1), true
+}
+
+// Not drawn on a strip
+func (this *MatrixPaddle) ColorAt(position float64, baseColor RGBA) RGBA {
+	return baseColor
+}
+
+// Returns the color at column x of row y blended on top of baseColor
+func (this *MatrixPaddle) ColorAt2D(x, y float64, baseColor RGBA) RGBA {
+	if x != this.column {
+		return baseColor
+	}
+	if _, covered := this.Covers(y); covered {
+		return this.color
+	}
+	return baseColor
+}
+
+// ZIndex
+func (this *MatrixPaddle) ZIndex() ZIndex {
+	return 10
+}
+
+// Paddles are moved by Move
+func (this *MatrixPaddle) Animate(dt float64) bool {
+	return true
+}
+
+// A ball bouncing around a matrix, off the top and bottom rows, drawn only on a matrix
+type MatrixBall struct {
+	width, height float64
+
+	// first row the ball plays on
+	firstRow float64
+
+	// position and velocity in leds / second of the ball
+	x, y   float64
+	vx, vy float64
+
+	random *rand.Rand
+}
+
+var _ Drawable2D = &MatrixBall{}
+
+// Construct a MatrixBall in the middle of field, playing on the rows from firstRow down
+func NewMatrixBall(field Field, firstRow int) *MatrixBall {
+	ball := &MatrixBall{
+		width:    float64(field.Width()),
+		height:   float64(field.Height()),
+		firstRow: float64(firstRow),
+		random:   NewRand(),
+	}
+	ball.Serve(ball.random.Float64() < 0.5)
+	return ball
+}
+
+// Move the ball back to the middle and send it towards the left or right player at a random angle
+func (this *MatrixBall) Serve(towardsLeft bool) {
+	rows := this.height - this.firstRow
+	this.x, this.y = (this.width-1)/2, this.firstRow+(rows-1)/2
+	this.vx = this.width / 2
+	if towardsLeft {
+		this.vx = -this.vx
+	}
+	this.vy = (this.random.Float64() - 0.5) * rows
+}
+
+// Position of the ball
+func (this *MatrixBall) Position() (x, y float64) {
+	return this.x, this.y
+}
+
+// Send the ball back the other way, offset from -1 to 1 steers it up or down but never faster than maxVy rows / second
+func (this *MatrixBall) Return(bounceFactor, offset, maxVy float64) {
+	this.vx = -this.vx * bounceFactor
+	this.vy += offset * (this.height - this.firstRow) / 2
+	this.vy = math.Max(-maxVy, math.Min(maxVy, this.vy))
+	if this.vx > 0 {
+		this.x = -this.x
+	} else {
+		this.x = 2*(this.width-1) - this.x
+	}
+}
+
+// Velocity of the ball along the length of the field, negative when moving left
+func (this *MatrixBall) Velocity() float64 {
+	return this.vx
+}
+
+// Not drawn on a strip
+func (this *MatrixBall) ColorAt(position float64, baseColor RGBA) RGBA {
+	return baseColor
+}
+
+// Returns the color at column x of row y blended on top of baseColor
+func (this *MatrixBall) ColorAt2D(x, y float64, baseColor RGBA) RGBA {
+	distance := math.Hypot(x-this.x, y-this.y)
+	if distance >= 1 {
+		return baseColor
+	}
+	return RGBA{255, 255, 255, Falloff(distance, 1)}.BlendWith(baseColor)
+}
+
+// ZIndex
+func (this *MatrixBall) ZIndex() ZIndex {
+	return 100
+}
+
+// Move the ball, bouncing it off the top and bottom rows
+func (this *MatrixBall) Animate(dt float64) bool {
+
+	this.x += this.vx * dt
+	this.y += this.vy * dt
+
+	top, bottom := this.firstRow, this.height-1
+	if this.y < top {
+		this.y, this.vy = 2*top-this.y, -this.vy
+	} else if this.y > bottom {
+		this.y, this.vy = 2*bottom-this.y, -this.vy
+	}
+	return true
+}
+
+// Points of each player shown as dots along the top row of a matrix, from each player's end towards the middle
+type MatrixScore struct {
+	width  float64
+	colors [2]RGBA
+
+	// points of the left and right player
+	scores [2]int
+}
+
+var _ Drawable2D = &MatrixScore{}
+
+// Construct a MatrixScore along the top row of field in the players' colors
+func NewMatrixScore(field Field, leftColor, rightColor RGBA) *MatrixScore {
+	return &MatrixScore{
+		width:  float64(field.Width()),
+		colors: [2]RGBA{leftColor, rightColor},
+	}
+}
+
+// Show the points of each player
+func (this *MatrixScore) SetScore(left, right int) {
+	this.scores = [2]int{left, right}
+}
+
+// Not drawn on a strip
+func (this *MatrixScore) ColorAt(position float64, baseColor RGBA) RGBA {
+	return baseColor
+}
+
+// Returns the color at column x of row y blended on top of baseColor, every other led of the top row is a point
+func (this *MatrixScore) ColorAt2D(x, y float64, baseColor RGBA) RGBA {
+
+	if y != 0 {
+		return baseColor
+	}
+	side, fromEnd := 0, x
+	if x >= this.width/2 {
+		side, fromEnd = 1, this.width-1-x
+	}
+	if int(fromEnd)%2 == 0 && int(fromEnd)/2 < this.scores[side] {
+		return this.colors[side]
+	}
+	return baseColor
+}
+
+// ZIndex
+func (this *MatrixScore) ZIndex() ZIndex {
+	return 10
+}
+
+// The score is changed by SetScore
+func (this *MatrixScore) Animate(dt float64) bool {
+	return true
+}
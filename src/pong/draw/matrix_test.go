@@ -0,0 +1,85 @@
+package draw
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Paddles should stay on the matrix as they move, and the ball should bounce off the top and bottom rows
+func Test_Matrix(t *testing.T) {
+
+	field := NewMatrixField(32, 8)
+	paddle := NewMatrixPaddle(field, 0, false, RGBA{0, 255, 0, 255})
+	if paddle.column != 31 || paddle.size != 2 || paddle.top != 3 {
+		t.Fatal("Paddle starts in column", paddle.column, "at row", paddle.top, "covering", paddle.size)
+	}
+
+	paddle.Move(PaddleUp, 10)
+	if offset, covered := paddle.Covers(0); !covered || offset >= 0 {
+		t.Fatal("Raised paddle covers the top row", covered, "at", offset)
+	}
+	paddle.Move(PaddleDown, 10)
+	if _, covered := paddle.Covers(5); covered || paddle.top != 6 {
+		t.Fatal("Lowered paddle is at row", paddle.top)
+	}
+	if paddle.ColorAt2D(31, 7, RGBA{}) != paddle.color || paddle.ColorAt2D(30, 7, RGBA{}) == paddle.color {
+		t.Fatal("Paddle drawn outside its column")
+	}
+
+	paddle.Move(PaddleStop, 10)
+	if paddle.top != 6 {
+		t.Fatal("Stopped paddle moved to row", paddle.top)
+	}
+
+	ball := NewMatrixBall(field, 0)
+	ball.x, ball.y, ball.vx, ball.vy = 10, 1, 0, -4
+	ball.Animate(0.5)
+	if x, y := ball.Position(); x != 10 || math.Abs(y-1) > 1e-9 || ball.vy != 4 {
+		t.Fatal("Ball bounced off the top to", x, y, "heading", ball.vy)
+	}
+
+	ball.x, ball.vx = 31.5, 16
+	ball.Return(1, 1, 10)
+	if ball.x != 30.5 || ball.vx != -16 || ball.vy != 8 {
+		t.Fatal("Returned ball is at", ball.x, "heading", ball.vx, ball.vy)
+	}
+
+	// steering the same way again would take it past the fastest it can move up or down
+	ball.Return(1, 1, 10)
+	if ball.vx != 16 || ball.vy != 10 {
+		t.Fatal("Returned ball is heading", ball.vx, ball.vy)
+	}
+}
+
+// With a score row the paddles and ball should stay below it, and each player's points should fill in from their end
+func Test_MatrixScoreRow(t *testing.T) {
+
+	field := NewMatrixField(10, 7)
+	paddle := NewMatrixPaddle(field, 1, true, RGBA{255, 0, 0, 255})
+	paddle.Move(PaddleUp, 10)
+	if paddle.size != 2 || paddle.top != 1 {
+		t.Fatal("Raised paddle is at row", paddle.top, "covering", paddle.size)
+	}
+
+	ball := NewMatrixBall(field, 1)
+	ball.x, ball.y, ball.vx, ball.vy = 5, 2, 0, -4
+	ball.Animate(0.5)
+	if _, y := ball.Position(); math.Abs(y-2) > 1e-9 || ball.vy != 4 {
+		t.Fatal("Ball bounced off the score row to", y, "heading", ball.vy)
+	}
+
+	left, right := RGBA{255, 0, 0, 255}, RGBA{0, 0, 255, 255}
+	score := NewMatrixScore(field, left, right)
+	score.SetScore(2, 1)
+	expected := []RGBA{left, {}, left, {}, {}, {}, {}, {}, {}, right}
+	for x, color := range expected {
+		if actual := score.ColorAt2D(float64(x), 0, RGBA{}); actual != color {
+			t.Fatal("Score column", x, "is", actual, "not", color)
+		}
+	}
+	if score.ColorAt2D(0, 1, RGBA{}) != (RGBA{}) {
+		t.Fatal("Score drawn below the top row")
+	}
+}
@@ -0,0 +1,67 @@
+package draw
+
+import (
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Draws a Menu as one segment per option, with the highlighted option at full brightness
+type MenuDisplay struct {
+	menu *Menu
+
+	// width of the field
+	width float64
+
+	// current amount of animation of the highlighted segment, from 0 to 1
+	pulse float64
+}
+
+var _ Drawable = &MenuDisplay{}
+
+// Construct a MenuDisplay
+func NewMenuDisplay(field Field, menu *Menu) *MenuDisplay {
+	return &MenuDisplay{
+		menu:  menu,
+		width: float64(field.Width()),
+	}
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *MenuDisplay) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	segmentWidth := this.width / float64(len(this.menu.Options))
+	index := int(position / segmentWidth)
+
+	// leave a gap between segments
+	if position-float64(index)*segmentWidth < 1.0 || index >= len(this.menu.Options) {
+		return baseColor
+	}
+
+	color := this.menu.Options[index].Color
+	if index == this.menu.SelectedIndex() {
+		pulse := this.pulse
+		if pulse > 0.5 {
+			pulse = 1.0 - pulse
+		}
+		color.A = uint8(155 + 200*pulse)
+	} else {
+		color.A = 40
+	}
+
+	return color.BlendWith(baseColor)
+}
+
+// ZIndex
+func (this *MenuDisplay) ZIndex() ZIndex {
+	return 20
+}
+
+// Animate
+func (this *MenuDisplay) Animate(dt float64) bool {
+
+	this.pulse += dt
+	if this.pulse > 1.0 {
+		this.pulse -= 1.0
+	}
+
+	return true
+}
@@ -0,0 +1,76 @@
+package draw
+
+import (
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Flashes and pulses a second, and leds / second a chase moves at
+var notificationRate float64 = 2
+var notificationChaseSpeed float64 = 40
+
+// Leds between the lit runs of a chase, and the length of each run
+var notificationChaseSpacing, notificationChaseLength float64 = 12, 4
+
+// Lights the whole strip for a notification with one of the NotificationEffects
+type NotificationLight struct {
+	notification Notification
+
+	// seconds the notification has been shown
+	time float64
+}
+
+var _ Drawable = &NotificationLight{}
+
+// Construct a NotificationLight showing notification for its seconds
+func NewNotificationLight(field Field, notification Notification) *NotificationLight {
+	return &NotificationLight{
+		notification: notification,
+	}
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *NotificationLight) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	if this.TimeRemaining() <= 0 {
+		return baseColor
+	}
+
+	alpha := 1.0
+	switch this.notification.Effect {
+	case "flash":
+		if math.Mod(this.time*notificationRate, 1) >= 0.5 {
+			alpha = 0
+		}
+	case "pulse":
+		alpha = 0.5 - 0.5*math.Cos(this.time*notificationRate*2*math.Pi)
+	case "chase":
+		offset := math.Mod(position-this.time*notificationChaseSpeed, notificationChaseSpacing)
+		if offset < 0 {
+			offset += notificationChaseSpacing
+		}
+		if offset >= notificationChaseLength {
+			alpha = 0
+		}
+	}
+
+	color := this.notification.Color
+	return RGBA{color.R, color.G, color.B, uint8(alpha * 255)}.BlendWith(baseColor)
+}
+
+// ZIndex
+func (this *NotificationLight) ZIndex() ZIndex {
+	return 200
+}
+
+// Animate
+func (this *NotificationLight) Animate(dt float64) bool {
+	this.time += dt
+	return true
+}
+
+// Seconds left until the notification is over
+func (this *NotificationLight) TimeRemaining() float64 {
+	return this.notification.Seconds - this.time
+}
@@ -1,7 +1,9 @@
 package draw
 
 import (
-	. "pong"
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
 )
 
 // Player that is drawn on the board
@@ -13,51 +15,104 @@ type Player struct {
 	// bounds of the paddle, used for collision detection
 	paddleLeft, paddleRight float64
 
+	// width of the hit zone and its distance from the end of the field before any shrinking
+	zoneWidth, zoneDistance float64
+
 	// colors that the different parts of the player are drawn
 	lifeColor, paddleColor RGBA
 
 	// zindex of player
 	zindex ZIndex
 
+	// if the player defends the left end of the field
+	isLeft bool
+
 	// if the player is current holding down the button
 	paddleActive bool
 
+	// if the button was pushed this frame after being released
+	justPressed bool
+
+	// ball position when the button was first pushed while the ball approached
+	pressPosition float64
+	pressRecorded bool
+
 	// amount of life left
 	life, lifeTotal float64
 
 	// current amount of animation, goes from 0 to 1 and back
 	lifeAnimation float64
+
+	// if the paddle is flickering to telegraph an upcoming return
+	tell bool
+
+	// time the tell has been shown, used to flicker the paddle
+	tellTime float64
+
+	// how the paddle is drawn, PaddleSolid when empty, and the time it has been animated for
+	paddleEffect string
+	effectTime   float64
+
+	// how the player looked when Version was last called, and how many times that has changed
+	lastLook playerLook
+	version  uint64
+}
+
+// Everything that decides how the player is drawn
+type playerLook struct {
+	life         float64
+	lifeAlpha    uint8
+	paddleActive bool
+	tellShown    bool
+	paddleShade  RGBA
 }
 
 // rate at which lifeAnimation changes
 var lifeAnimationRate float64 = 0.25
 
-var testPlayer Drawable = &Player{}
+// number of times per second the paddle flickers during a tell
+var tellFlickerRate float64 = 15
+
+// times per second a pulsing paddle pulses, and a rainbow paddle goes around the color wheel
+var paddlePulseRate float64 = 3
+var paddleRainbowRate float64 = 0.5
+
+// Narrowest a hit zone shrinks to, in leds
+var minimumHitZoneWidth float64 = 0.4
+
+// Amount of life lost when a player misses the ball
+const MissLifePenalty float64 = 0.75
+
+var testPlayer TrackedDrawable = &Player{}
 
 // Construct a Line
-func NewPlayer(isLeft bool, lifeTime float64, field *GameField) (player *Player) {
+func NewPlayer(isLeft bool, lifeTime float64, field Field) (player *Player) {
 
+	theme := CurrentTheme()
 	if isLeft {
 		player = &Player{
-			lifeColor:   RGBA{0, 0, 255, 150},
-			paddleColor: RGBA{0, 0, 255, 255},
+			lifeColor:   RGBA{theme.LeftColor.R, theme.LeftColor.G, theme.LeftColor.B, 150},
+			paddleColor: theme.LeftColor,
 			zindex:      10,
+			isLeft:      true,
 			start:       0.0,
 			end:         (float64(field.Width()) / 2.0) - 1,
 			paddleLeft:  -0.5,
 			paddleRight: 0.5,
+			zoneWidth:   1,
 			life:        lifeTime,
 			lifeTotal:   lifeTime,
 		}
 	} else {
 		player = &Player{
-			lifeColor:   RGBA{0, 255, 0, 150},
-			paddleColor: RGBA{0, 255, 0, 255},
+			lifeColor:   RGBA{theme.RightColor.R, theme.RightColor.G, theme.RightColor.B, 150},
+			paddleColor: theme.RightColor,
 			zindex:      10,
 			start:       float64(field.Width()) - 1.0,
 			end:         (float64(field.Width()) / 2.0),
 			paddleLeft:  float64(field.Width()) - 1.5,
 			paddleRight: float64(field.Width()) - 0.5,
+			zoneWidth:   1,
 			life:        lifeTime,
 			lifeTotal:   lifeTime,
 		}
@@ -66,8 +121,36 @@ func NewPlayer(isLeft bool, lifeTime float64, field *GameField) (player *Player)
 	return
 }
 
+// Construct a Player with the life and color of profile
+func NewProfilePlayer(isLeft bool, profile PlayerProfile, field Field) *Player {
+
+	player := NewPlayer(isLeft, profile.Life(Settings.LifeInSeconds), field)
+	if color, ok := profile.RGBA(); ok {
+		player.SetColor(color)
+	}
+	player.SetHitZone(profile.HitZone())
+	player.SetPaddleEffect(profile.PaddleEffect)
+	return player
+}
+
+// Hand the button over to the person of profile, drawn in their color with full life
+func (this *Player) TakeOver(profile PlayerProfile) {
+	this.SetColor(CurrentTheme().PlayerColor(profile, this.isLeft))
+	this.life = profile.Life(Settings.LifeInSeconds)
+	this.lifeTotal = this.life
+	this.SetHitZone(profile.HitZone())
+	this.SetPaddleEffect(profile.PaddleEffect)
+}
+
+// Draw the paddle and life in color instead of the color of the side the player is on
+func (this *Player) SetColor(color RGBA) {
+	this.paddleColor = RGBA{color.R, color.G, color.B, 255}
+	this.lifeColor = RGBA{color.R, color.G, color.B, 150}
+}
+
 // Set if the player is holding down the paddle or not
 func (this *Player) UpdatePaddleActive(paddleActive bool) {
+	this.justPressed = paddleActive && !this.paddleActive
 	this.paddleActive = paddleActive
 
 	if this.life <= 0.0 {
@@ -75,6 +158,108 @@ func (this *Player) UpdatePaddleActive(paddleActive bool) {
 	}
 }
 
+// If the player is holding down the paddle
+func (this *Player) PaddleActive() bool {
+	return this.paddleActive
+}
+
+// Fill the life bar back up
+func (this *Player) RefillLife() {
+	this.life = this.lifeTotal
+}
+
+// Seconds before the ball reached the hit window that the player pressed as it approached, negative when pressed after it
+// got there, false if they haven't pressed, velocity is of the approaching ball
+func (this *Player) PressTiming(velocity float64) (float64, bool) {
+
+	if !this.pressRecorded || velocity == 0 {
+		return 0, false
+	}
+
+	distance := this.pressPosition - this.paddleRight
+	if !this.isLeft {
+		distance = this.paddleLeft - this.pressPosition
+	}
+	return distance / math.Abs(velocity), true
+}
+
+// Set the width in leds of the window where holding the paddle returns the ball
+func (this *Player) SetHitWindow(width float64) {
+	if this.isLeft {
+		this.paddleRight = this.paddleLeft + width
+	} else {
+		this.paddleLeft = this.paddleRight - width
+	}
+}
+
+// Move the window where holding the paddle returns the ball to be width leds wide starting distance leds in from the
+// end of the field, kept inside the player's half
+func (this *Player) SetHitZone(width, distance float64) {
+	this.zoneWidth, this.zoneDistance = width, distance
+	this.placeHitZone(width)
+}
+
+// Narrow the hit zone by leds from the width it was set to, down to minimumHitZoneWidth, 0 restores it
+func (this *Player) ShrinkHitZone(leds float64) {
+	this.placeHitZone(math.Max(this.zoneWidth-leds, math.Min(this.zoneWidth, minimumHitZoneWidth)))
+}
+
+// Place a hit zone width leds wide at the distance it was set to
+func (this *Player) placeHitZone(width float64) {
+
+	reach := math.Abs(this.end-this.start) + 1
+	distance := math.Min(math.Max(this.zoneDistance, 0), reach-1)
+	width = math.Min(width, reach-distance)
+
+	if this.isLeft {
+		this.paddleLeft = this.start - 0.5 + distance
+		this.paddleRight = this.paddleLeft + width
+	} else {
+		this.paddleRight = this.start + 0.5 - distance
+		this.paddleLeft = this.paddleRight - width
+	}
+}
+
+// Part of the field where holding the paddle returns the ball
+func (this *Player) HitZone() Extent {
+	return Extent{Position(this.paddleLeft), Position(this.paddleRight)}
+}
+
+// Shrink the hit zone of whoever leads by Settings.RubberBandLeds for every point of their lead, and restore the
+// zone of the other player
+func RubberBand(leftPlayer, rightPlayer *Player, leftScore, rightScore int) {
+	lead := float64(leftScore-rightScore) * Settings.RubberBandLeds
+	leftPlayer.ShrinkHitZone(math.Max(lead, 0))
+	rightPlayer.ShrinkHitZone(math.Max(-lead, 0))
+}
+
+// Draw the paddle with effect, PaddleSolid, PaddlePulse or PaddleRainbow
+func (this *Player) SetPaddleEffect(effect string) {
+	this.paddleEffect = effect
+	this.effectTime = 0
+}
+
+// Color the paddle is drawn in this frame
+func (this *Player) paddleShade() RGBA {
+	switch this.paddleEffect {
+	case PaddlePulse:
+		brightness := 0.6 + 0.4*math.Sin(this.effectTime*paddlePulseRate*2*math.Pi)
+		return RGBA{this.paddleColor.R, this.paddleColor.G, this.paddleColor.B, ScaleChannel(this.paddleColor.A, brightness)}
+	case PaddleRainbow:
+		_, hue := math.Modf(this.effectTime * paddleRainbowRate)
+		return hslToRGB(hue, 1, 0.5)
+	}
+	return this.paddleColor
+}
+
+// Set if the paddle should flicker to telegraph an upcoming return
+func (this *Player) SetTell(tell bool) {
+	if !tell {
+		this.tellTime = 0
+	}
+	this.tell = tell
+}
+
 // Returns the color at position blended on top of baseColor
 func (this *Player) ColorAt(position float64, baseColor RGBA) (color RGBA) {
 
@@ -83,24 +268,17 @@ func (this *Player) ColorAt(position float64, baseColor RGBA) (color RGBA) {
 	left := min(this.start, lifeBarEnd)
 	right := max(this.start, lifeBarEnd)
 
-	if this.paddleActive && position == this.start {
-		color = this.paddleColor.BlendWith(baseColor)
-	} else if left <= position && position <= right && this.life > 0 {
+	// the paddle covers every led of the hit zone
+	inPaddle := this.HitZone().Contains(Position(position))
 
-		// animation results in transparency going up and down from 0 to 0.5 when button not pushed, 0.5 to 1 while button pushed
-		var alphaAmount = this.lifeAnimation
-		if alphaAmount > 0.5 {
-			alphaAmount = 1.0 - alphaAmount
-		}
-		if this.paddleActive {
-			alphaAmount += 0.5
-		}
-		alphaAmount += 0.2
-		if alphaAmount > 1.0 {
-			alphaAmount = 1.0
-		}
-
-		lifeColor := RGBA{this.lifeColor.R, this.lifeColor.G, this.lifeColor.B, uint8(float64(this.lifeColor.A) * alphaAmount)}
+	if this.paddleActive && inPaddle {
+		color = this.paddleShade().BlendWith(baseColor)
+	} else if this.tellShown() && inPaddle {
+		paddleColor := this.paddleShade()
+		tellColor := RGBA{paddleColor.R, paddleColor.G, paddleColor.B, paddleColor.A / 3}
+		color = tellColor.BlendWith(baseColor)
+	} else if left <= position && position <= right && this.life > 0 {
+		lifeColor := RGBA{this.lifeColor.R, this.lifeColor.G, this.lifeColor.B, this.lifeAlpha()}
 
 		color = lifeColor.BlendWith(baseColor)
 	} else {
@@ -110,6 +288,46 @@ func (this *Player) ColorAt(position float64, baseColor RGBA) (color RGBA) {
 	return
 }
 
+// Alpha of the life bar this frame
+func (this *Player) lifeAlpha() uint8 {
+
+	// animation results in transparency going up and down from 0 to 0.5 when button not pushed, 0.5 to 1 while button pushed
+	var alphaAmount = this.lifeAnimation
+	if alphaAmount > 0.5 {
+		alphaAmount = 1.0 - alphaAmount
+	}
+	if this.paddleActive {
+		alphaAmount += 0.5
+	}
+	alphaAmount += 0.2
+	if alphaAmount > 1.0 {
+		alphaAmount = 1.0
+	}
+
+	return ScaleChannel(this.lifeColor.A, alphaAmount)
+}
+
+// If the flickering tell is lit this frame
+func (this *Player) tellShown() bool {
+	return this.tell && int(this.tellTime*tellFlickerRate*2)%2 == 0
+}
+
+// Range of positions covered by the paddle and life bar
+func (this *Player) Bounds() (left, right float64) {
+	return min(this.start, this.end), max(this.start, this.end)
+}
+
+// Changes when the life bar, paddle, or tell look different than at the last call
+func (this *Player) Version() uint64 {
+
+	look := playerLook{this.life, this.lifeAlpha(), this.paddleActive, this.tellShown(), this.paddleShade()}
+	if look != this.lastLook {
+		this.lastLook = look
+		this.version++
+	}
+	return this.version
+}
+
 // ZIndex of the player
 func (this *Player) ZIndex() ZIndex {
 	return this.zindex
@@ -123,6 +341,14 @@ func (this *Player) Animate(dt float64) bool {
 		this.lifeAnimation -= 1.0
 	}
 
+	if this.tell {
+		this.tellTime += dt
+	}
+
+	if this.paddleEffect != "" && this.paddleEffect != PaddleSolid {
+		this.effectTime += dt
+	}
+
 	if this.paddleActive {
 		this.life -= dt
 		if this.life < 0.0 {
@@ -134,6 +360,11 @@ func (this *Player) Animate(dt float64) bool {
 	return true
 }
 
+// If missing the ball once more ends the game
+func (this *Player) OnLastLife() bool {
+	return this.life < MissLifePenalty
+}
+
 // Decrease the amount of life remaining
 func (this *Player) DecreaseLife(dt float64) bool {
 	this.life -= dt
@@ -0,0 +1,64 @@
+package draw
+
+import (
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Alpha of the prediction gradient, kept faint so the countdown shows through
+var predictionAlpha uint8 = 90
+
+// Fraction of the field the two colors blend across
+var predictionBlend float64 = 0.2
+
+// Shades the field from the left player's color to the right's, split where the crowd's predictions divide it, so a
+// favorite takes up more of the field
+type CrowdPrediction struct {
+	leftColor, rightColor RGBA
+
+	// width of the field, and the fraction of it the left color covers
+	width     float64
+	leftShare float64
+}
+
+var _ Drawable = &CrowdPrediction{}
+
+// Construct a CrowdPrediction of left and right predictions drawn in leftColor and rightColor
+func NewCrowdPrediction(field Field, left, right int, leftColor, rightColor RGBA) *CrowdPrediction {
+
+	leftShare := 0.5
+	if left+right > 0 {
+		leftShare = float64(left) / float64(left+right)
+	}
+	return &CrowdPrediction{
+		leftColor:  leftColor,
+		rightColor: rightColor,
+		width:      float64(field.Width()),
+		leftShare:  leftShare,
+	}
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *CrowdPrediction) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	// 0 is all the left color and 1 all the right, blending across the split
+	mix := ((position+0.5)/this.width-this.leftShare)/predictionBlend + 0.5
+	mix = max(0, min(1, mix))
+
+	color := RGBA{
+		uint8(float64(this.leftColor.R)*(1-mix) + float64(this.rightColor.R)*mix),
+		uint8(float64(this.leftColor.G)*(1-mix) + float64(this.rightColor.G)*mix),
+		uint8(float64(this.leftColor.B)*(1-mix) + float64(this.rightColor.B)*mix),
+		predictionAlpha,
+	}
+	return color.BlendWith(baseColor)
+}
+
+// Over the countdown
+func (this *CrowdPrediction) ZIndex() ZIndex {
+	return 20
+}
+
+// Doesn't change
+func (this *CrowdPrediction) Animate(dt float64) bool {
+	return true
+}
@@ -0,0 +1,125 @@
+package draw
+
+import (
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Cycles through backgrounds, crossfading from one to the next
+type BackgroundRotation struct {
+	field Field
+
+	// names of the backgrounds in the order they are shown
+	names []string
+
+	// seconds each background is shown and seconds spent crossfading
+	dwell, fadeTime float64
+
+	// index of the current background and time it has been shown
+	index int
+	time  float64
+
+	// background being shown and the background fading out
+	current, previous *Fade
+
+	// field the rotation is on, nil when it isn't on one, told about backgrounds as they come and go
+	on Field
+
+	zindex ZIndex
+}
+
+var _ LifecycleDrawable = &BackgroundRotation{}
+
+// Construct a BackgroundRotation showing the first of names
+func NewBackgroundRotation(field Field, names []string, dwell, fadeTime float64, zindex ZIndex) *BackgroundRotation {
+
+	rotation := &BackgroundRotation{
+		field:    field,
+		names:    names,
+		dwell:    dwell,
+		fadeTime: fadeTime,
+		zindex:   zindex,
+	}
+	rotation.current = rotation.newBackground(0)
+
+	return rotation
+}
+
+// Construct the background at index faded in, falling back to the sinusoid for unknown names
+func (this *BackgroundRotation) newBackground(seconds float64) *Fade {
+
+	var background Drawable
+	if this.index < len(this.names) {
+		background = NewBackground(this.names[this.index], this.field, this.zindex)
+	}
+	if background == nil {
+		background = NewSinusoid(this.field, this.zindex)
+	}
+
+	return NewFadeIn(background, seconds)
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *BackgroundRotation) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	if this.previous != nil {
+		baseColor = this.previous.ColorAt(position, baseColor)
+	}
+
+	return this.current.ColorAt(position, baseColor)
+}
+
+// ZIndex
+func (this *BackgroundRotation) ZIndex() ZIndex {
+	return this.zindex
+}
+
+// Animate the backgrounds, moving to the next one after dwell seconds
+func (this *BackgroundRotation) Animate(dt float64) bool {
+
+	this.time += dt
+	if this.time >= this.dwell && len(this.names) > 1 {
+		this.time = 0
+		this.index = (this.index + 1) % len(this.names)
+
+		this.dropPrevious()
+		this.previous = this.current
+		this.previous.FadeOut(this.fadeTime)
+		this.current = this.newBackground(this.fadeTime)
+		if this.on != nil {
+			NotifyAdded(this.current, this.on)
+		}
+	}
+
+	if this.previous != nil && !this.previous.Animate(dt) {
+		this.dropPrevious()
+	}
+	this.current.Animate(dt)
+
+	return true
+}
+
+// Stop showing the background fading out
+func (this *BackgroundRotation) dropPrevious() {
+	if this.previous != nil && this.on != nil {
+		NotifyRemoved(this.previous, this.on)
+	}
+	this.previous = nil
+}
+
+// Tell the backgrounds shown they were added to field, and the ones shown later as they come
+func (this *BackgroundRotation) OnAdd(field Field) {
+	this.on = field
+	NotifyAdded(this.current, field)
+	if this.previous != nil {
+		NotifyAdded(this.previous, field)
+	}
+}
+
+// Tell the backgrounds shown they were taken off field
+func (this *BackgroundRotation) OnRemove(field Field) {
+	NotifyRemoved(this.current, field)
+	if this.previous != nil {
+		NotifyRemoved(this.previous, field)
+	}
+	this.on = nil
+}
@@ -0,0 +1,82 @@
+package draw
+
+import (
+	"flag"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	"github.com/brandonagr/pongpi/src/pong/snapshot"
+)
+
+var updateGolden = flag.Bool("update", false, "write the rendered strips as the new golden images in testdata")
+
+// Leds of the field the snapshots are rendered on, and how far each channel may be off
+const snapshotWidth = 60
+const snapshotTolerance = 2
+
+// Drawables rendered for a couple of seconds should look the same as their golden images
+func Test_Snapshots(t *testing.T) {
+
+	snapshots := []struct {
+		name   string
+		frames int
+		create func(field *GameField) []Drawable
+	}{
+		{"countdown", 25, func(field *GameField) []Drawable { return []Drawable{NewCountdown(field, 2)} }},
+		{"winner", 25, func(field *GameField) []Drawable { return []Drawable{NewWinner(field, true, 2)} }},
+		{"boot", 25, func(field *GameField) []Drawable { return []Drawable{NewBoot(field, 2)} }},
+		{"upnext", 25, func(field *GameField) []Drawable {
+			return []Drawable{NewUpNext(field, RGBA{255, 0, 255, 255}, RGBA{0, 255, 255, 255})}
+		}},
+		{"leaderboard", 10, func(field *GameField) []Drawable {
+			bars := []LeaderboardBar{{RGBA{255, 200, 0, 255}, 4}, {RGBA{192, 192, 192, 255}, 2}, {RGBA{205, 127, 50, 255}, 1}}
+			return []Drawable{NewLeaderboard(field, bars, 2)}
+		}},
+		{"celebration", 25, func(field *GameField) []Drawable {
+			return []Drawable{NewCelebration(field, false, RGBA{255, 0, 0, 255}, 2)}
+		}},
+		{"players", 25, func(field *GameField) []Drawable {
+			left, right := NewPlayer(true, 3, field), NewPlayer(false, 2, field)
+			right.UpdatePaddleActive(true)
+			return []Drawable{left, right}
+		}},
+		{"clock", 10, func(field *GameField) []Drawable {
+			now := time.Date(2020, 6, 1, 16, 40, 30, 0, time.UTC)
+			return []Drawable{NewAmbientClock(field, func() time.Time {
+				now = now.Add(time.Second)
+				return now
+			})}
+		}},
+		{"charge", 25, func(field *GameField) []Drawable {
+			return []Drawable{NewChargeIndicator(field, func() (bool, float64) { return true, 0.15 })}
+		}},
+		{"heatmap", 25, func(field *GameField) []Drawable {
+			returns, misses := []float64{1.2, 1.4, 2, 2.1, 3, 57.6, 58, 58.2}, []float64{-0.5, 0.2, 59.4}
+			return []Drawable{NewHitHeatmap(field, returns, misses, 3)}
+		}},
+		{"goal", 25, func(field *GameField) []Drawable {
+			effect, _ := NewBoundEffect("steps(size=2)", field, 15, EffectParams{"color": "#ff00ff"})
+			return []Drawable{NewPlayer(true, 3, field), NewGoalEffect(field, effect, false, 10, 3)}
+		}},
+		{"debug", 25, func(field *GameField) []Drawable {
+			left, right, ball := NewPlayer(true, 3, field), NewPlayer(false, 3, field), NewServedBall(field, true)
+			return []Drawable{left, right, ball, NewDebugOverlay(field, NewStateMachine())}
+		}},
+	}
+
+	for _, test := range snapshots {
+		UseRandSource(rand.NewSource(1))
+		field := NewGameField(snapshotWidth)
+		for _, drawable := range test.create(field) {
+			field.Add(drawable)
+		}
+
+		strip := snapshot.RenderField(field, test.frames, 0.1)
+		field.Release()
+		if err := snapshot.Match(strip, "testdata/"+test.name+".png", snapshotTolerance, *updateGolden); err != nil {
+			t.Error(err)
+		}
+	}
+}
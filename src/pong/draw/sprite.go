@@ -0,0 +1,200 @@
+package draw
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"os"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// A small picture drawn on a matrix, leds with an alpha of 0 are see through
+type SpriteFrame struct {
+	width, height int
+
+	// color of every led a row at a time from the top
+	pixels []RGBA
+}
+
+// Construct a SpriteFrame from rows of characters looked up in palette, characters missing from it are see through
+func NewSpriteFrame(rows []string, palette map[rune]RGBA) SpriteFrame {
+
+	frame := SpriteFrame{height: len(rows)}
+	for _, row := range rows {
+		if width := len([]rune(row)); width > frame.width {
+			frame.width = width
+		}
+	}
+
+	frame.pixels = make([]RGBA, frame.width*frame.height)
+	for y, row := range rows {
+		for x, character := range []rune(row) {
+			frame.pixels[y*frame.width+x] = palette[character]
+		}
+	}
+	return frame
+}
+
+// Read the PNG at path as a strip of frames frameWidth pixels wide side by side, or a single frame if frameWidth is 0
+func LoadSpriteFrames(path string, frameWidth int) ([]SpriteFrame, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	picture, err := png.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("Reading sprite %v: %v", path, err)
+	}
+
+	bounds := picture.Bounds()
+	if frameWidth <= 0 {
+		frameWidth = bounds.Dx()
+	}
+	if bounds.Dx()%frameWidth != 0 {
+		return nil, fmt.Errorf("Sprite %v is %v pixels wide, not a whole number of %v pixel frames", path, bounds.Dx(), frameWidth)
+	}
+
+	var frames []SpriteFrame
+	for left := bounds.Min.X; left < bounds.Max.X; left += frameWidth {
+		frames = append(frames, newImageFrame(picture, image.Rect(left, bounds.Min.Y, left+frameWidth, bounds.Max.Y)))
+	}
+	return frames, nil
+}
+
+// Copy the pixels of picture inside area into a frame
+func newImageFrame(picture image.Image, area image.Rectangle) SpriteFrame {
+
+	frame := SpriteFrame{
+		width:  area.Dx(),
+		height: area.Dy(),
+		pixels: make([]RGBA, area.Dx()*area.Dy()),
+	}
+	for y := 0; y < frame.height; y++ {
+		for x := 0; x < frame.width; x++ {
+			r, g, b, a := picture.At(area.Min.X+x, area.Min.Y+y).RGBA()
+			if a == 0 {
+				continue
+			}
+			// undo the premultiplied alpha, BlendWith expects straight colors
+			frame.pixels[y*frame.width+x] = RGBA{uint8(r * 255 / a), uint8(g * 255 / a), uint8(b * 255 / a), uint8(a >> 8)}
+		}
+	}
+	return frame
+}
+
+// Width and height of the frame in leds
+func (this SpriteFrame) Size() (width, height int) {
+	return this.width, this.height
+}
+
+// Pictures bundled for the 2D modes
+var (
+	SpriteHeart = NewSpriteFrame([]string{
+		".r.r.",
+		"rrrrr",
+		"rrrrr",
+		".rrr.",
+		"..r..",
+	}, map[rune]RGBA{'r': {255, 0, 40, 255}})
+
+	SpriteTrophy = NewSpriteFrame([]string{
+		"yyyyy",
+		"yyyyy",
+		".yyy.",
+		"..y..",
+		".bbb.",
+	}, map[rune]RGBA{'y': {255, 200, 0, 255}, 'b': {120, 60, 0, 255}})
+)
+
+// Frames drawn one after the other with their top left corner at a column and row of a matrix. Nothing is drawn on a
+// strip
+type Sprite struct {
+	frames []SpriteFrame
+
+	// column and row of the top left corner
+	x, y float64
+
+	// frames / second, seconds spent on the current frame and which one it is
+	frameRate  float64
+	frameTime  float64
+	frameIndex int
+
+	zindex ZIndex
+}
+
+var _ Drawable2D = &Sprite{}
+
+// Construct a Sprite at column x and row y showing frames at frameRate frames / second, over and over
+func NewSprite(frames []SpriteFrame, frameRate float64, x, y float64) *Sprite {
+	return &Sprite{
+		frames:    frames,
+		x:         x,
+		y:         y,
+		frameRate: frameRate,
+		zindex:    150,
+	}
+}
+
+// Move the top left corner to column x and row y
+func (this *Sprite) MoveTo(x, y float64) {
+	this.x, this.y = x, y
+}
+
+// Draw above or below other drawables
+func (this *Sprite) SetZIndex(zindex ZIndex) {
+	this.zindex = zindex
+}
+
+// Frame currently shown
+func (this *Sprite) Frame() int {
+	return this.frameIndex
+}
+
+// Not drawn on a strip
+func (this *Sprite) ColorAt(position float64, baseColor RGBA) RGBA {
+	return baseColor
+}
+
+// Returns the color at column x of row y blended on top of baseColor
+func (this *Sprite) ColorAt2D(x, y float64, baseColor RGBA) RGBA {
+
+	if len(this.frames) == 0 {
+		return baseColor
+	}
+
+	frame := this.frames[this.frameIndex]
+	column, row := int(math.Floor(x-this.x)), int(math.Floor(y-this.y))
+	if column < 0 || column >= frame.width || row < 0 || row >= frame.height {
+		return baseColor
+	}
+	color := frame.pixels[row*frame.width+column]
+	if color.A == 0 {
+		return baseColor
+	}
+	return color.BlendWith(baseColor)
+}
+
+// ZIndex
+func (this *Sprite) ZIndex() ZIndex {
+	return this.zindex
+}
+
+// Move on to the next frame when it's time to
+func (this *Sprite) Animate(dt float64) bool {
+
+	if this.frameRate <= 0 || len(this.frames) < 2 {
+		return true
+	}
+
+	this.frameTime += dt
+	for this.frameTime >= 1/this.frameRate {
+		this.frameTime -= 1 / this.frameRate
+		this.frameIndex = (this.frameIndex + 1) % len(this.frames)
+	}
+	return true
+}
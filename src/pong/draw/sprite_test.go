@@ -0,0 +1,84 @@
+package draw
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Sprites should be drawn where they are moved to, see through where they have no color, and step through frames
+func Test_Sprite(t *testing.T) {
+
+	red, blue := RGBA{255, 0, 0, 255}, RGBA{0, 0, 255, 255}
+	first := NewSpriteFrame([]string{"r.", "rr"}, map[rune]RGBA{'r': red})
+	second := NewSpriteFrame([]string{"bb"}, map[rune]RGBA{'b': blue})
+
+	sprite := NewSprite([]SpriteFrame{first, second}, 2, 3, 1)
+	background := RGBA{0, 255, 0, 255}
+	if sprite.ColorAt2D(3, 1, background) != red || sprite.ColorAt2D(4, 1, background) != background ||
+		sprite.ColorAt2D(4, 2, background) != red || sprite.ColorAt2D(2, 2, background) != background {
+		t.Fatal("First frame was drawn wrong")
+	}
+
+	sprite.Animate(0.6)
+	if sprite.Frame() != 1 || sprite.ColorAt2D(4, 1, background) != blue {
+		t.Fatal("Sprite is on frame", sprite.Frame())
+	}
+	sprite.Animate(0.5)
+	if sprite.Frame() != 0 {
+		t.Fatal("Sprite didn't loop back to the first frame")
+	}
+
+	sprite.MoveTo(0, 0)
+	if sprite.ColorAt2D(0, 0, background) != red || sprite.ColorAt2D(3, 1, background) != background {
+		t.Fatal("Moved sprite was drawn at the old position")
+	}
+}
+
+// A PNG should be split into frames side by side, keeping its transparency
+func Test_LoadSpriteFrames(t *testing.T) {
+
+	picture := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	picture.Set(0, 0, color.NRGBA{255, 0, 0, 255})
+	picture.Set(3, 1, color.NRGBA{0, 0, 255, 128})
+
+	directory, err := ioutil.TempDir("", "sprite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	path := filepath.Join(directory, "sprite.png")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	png.Encode(file, picture)
+	file.Close()
+
+	frames, err := LoadSpriteFrames(path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 2 {
+		t.Fatal("Read", len(frames), "frames")
+	}
+	if width, height := frames[1].Size(); width != 2 || height != 2 {
+		t.Fatal("Frame is", width, "by", height)
+	}
+	if frames[0].pixels[0] != (RGBA{255, 0, 0, 255}) || frames[0].pixels[1].A != 0 {
+		t.Fatal("First frame read as", frames[0].pixels)
+	}
+	if pixel := frames[1].pixels[3]; pixel.B != 255 || pixel.A != 128 {
+		t.Fatal("Half transparent pixel read as", pixel)
+	}
+
+	if _, err := LoadSpriteFrames(path, 3); err == nil {
+		t.Fatal("Split a sprite into partial frames")
+	}
+}
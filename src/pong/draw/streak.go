@@ -0,0 +1,50 @@
+package draw
+
+import (
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Points in a row won by the player holding the hill, a dot for each from the middle of the field towards their end
+type StreakMarks struct {
+	middle float64
+
+	// side and color of the champion, and their streak
+	left   bool
+	color  RGBA
+	streak int
+}
+
+var _ Drawable = &StreakMarks{}
+
+// Construct StreakMarks for field showing no streak
+func NewStreakMarks(field Field) *StreakMarks {
+	return &StreakMarks{middle: float64(field.Width() / 2)}
+}
+
+// Show streak dots in color towards the left or right end
+func (this *StreakMarks) SetStreak(left bool, streak int, color RGBA) {
+	this.left, this.streak, this.color = left, streak, color
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *StreakMarks) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	fromMiddle := position - this.middle
+	if this.left {
+		fromMiddle = this.middle - 1 - position
+	}
+	if fromMiddle < 0 || int(fromMiddle)%2 != 0 || int(fromMiddle)/2 >= this.streak {
+		return baseColor
+	}
+	return this.color.BlendWith(baseColor)
+}
+
+// Above the players
+func (this *StreakMarks) ZIndex() ZIndex {
+	return 12
+}
+
+// Animate
+func (this *StreakMarks) Animate(dt float64) bool {
+	return true
+}
@@ -0,0 +1,32 @@
+package draw
+
+import (
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// A color washed over everything else in a scene, for the tint of a theme
+type Tint struct {
+	color RGBA
+}
+
+var _ Drawable = &Tint{}
+
+// Construct a Tint of color, its alpha is how strongly it shows
+func NewTint(color RGBA) *Tint {
+	return &Tint{color: color}
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *Tint) ColorAt(position float64, baseColor RGBA) RGBA {
+	return this.color.BlendWith(baseColor)
+}
+
+// Above every other drawable besides the debug overlay
+func (this *Tint) ZIndex() ZIndex {
+	return 250
+}
+
+// Animate
+func (this *Tint) Animate(dt float64) bool {
+	return true
+}
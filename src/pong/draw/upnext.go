@@ -0,0 +1,58 @@
+package draw
+
+import (
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Leds lit at each end to show who plays next, and how many times a second they pulse
+var upNextWidth float64 = 8
+var upNextPulseRate float64 = 1
+
+// Pulses each end of the field in the color of the player who is up next on that side
+type UpNext struct {
+	leftColor, rightColor RGBA
+
+	// width of the field
+	width float64
+
+	time float64
+}
+
+var _ Drawable = &UpNext{}
+
+// Construct an UpNext showing the players of the next match in leftColor and rightColor
+func NewUpNext(field Field, leftColor, rightColor RGBA) *UpNext {
+	return &UpNext{
+		leftColor:  leftColor,
+		rightColor: rightColor,
+		width:      float64(field.Width()),
+	}
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *UpNext) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	color := this.leftColor
+	if position >= this.width-upNextWidth {
+		color = this.rightColor
+	} else if position >= upNextWidth {
+		return baseColor
+	}
+
+	brightness := 0.6 + 0.4*math.Sin(this.time*upNextPulseRate*2*math.Pi)
+	color = RGBA{color.R, color.G, color.B, uint8(255 * brightness)}
+	return color.BlendWith(baseColor)
+}
+
+// ZIndex
+func (this *UpNext) ZIndex() ZIndex {
+	return 30
+}
+
+// Pulse the ends
+func (this *UpNext) Animate(dt float64) bool {
+	this.time += dt
+	return true
+}
@@ -0,0 +1,82 @@
+package draw
+
+import (
+	. "github.com/brandonagr/pongpi/src/pong"
+	"github.com/brandonagr/pongpi/src/pong/anim"
+)
+
+// The winner's color sweeping across their half of the field from their end, flashing and then fading away
+type Wipe struct {
+	color RGBA
+
+	// end the wipe starts from and how far it reaches, in leds
+	start, length float64
+	isLeft        bool
+
+	// fraction of the half covered and opacity of the color, moved by timeline
+	reach, opacity float64
+	timeline       anim.Animation
+
+	time, totalTime float64
+}
+
+var _ Drawable = &Wipe{}
+
+// Construct a Wipe in color over the left or right half of field, shown for totalTime seconds
+func NewWipe(field Field, isLeft bool, color RGBA, totalTime float64) *Wipe {
+
+	this := &Wipe{
+		color:     color,
+		opacity:   1,
+		length:    float64(field.Width()) / 2,
+		isLeft:    isLeft,
+		totalTime: totalTime,
+	}
+	if !isLeft {
+		this.start = float64(field.Width()) - 1
+	}
+
+	// a quarter of the time sweeping in, half flashing and the rest fading out
+	quarter, flash := totalTime/4, totalTime/12
+	this.timeline = anim.Sequence(
+		anim.Tween(0, 1, quarter, anim.EaseOut).Drive(&this.reach),
+		anim.Repeat(3, anim.Sequence(
+			anim.Tween(1, 0.3, flash, anim.EaseIn).Drive(&this.opacity),
+			anim.Tween(0.3, 1, flash, anim.EaseOut).Drive(&this.opacity),
+		)),
+		anim.Tween(1, 0, quarter, anim.EaseInOut).Drive(&this.opacity),
+	)
+	return this
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *Wipe) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	distance := position - this.start
+	if !this.isLeft {
+		distance = -distance
+	}
+	if distance < 0 || distance >= this.length*this.reach {
+		return baseColor
+	}
+
+	color := RGBA{this.color.R, this.color.G, this.color.B, ScaleChannel(this.color.A, this.opacity)}
+	return color.BlendWith(baseColor)
+}
+
+// ZIndex
+func (this *Wipe) ZIndex() ZIndex {
+	return 20
+}
+
+// Play the timeline
+func (this *Wipe) Animate(dt float64) bool {
+	this.time += dt
+	this.timeline.Advance(dt)
+	return true
+}
+
+// Amount of time remaining in the victory
+func (this *Wipe) TimeRemaining() float64 {
+	return this.totalTime - this.time
+}
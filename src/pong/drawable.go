@@ -13,7 +13,8 @@ type ZIndex int
 // Methods required to draw something
 type Drawable interface {
 
-	// Computes the color with the given baseColor
+	// Computes the color with the given baseColor. With a RenderPool it is called for different positions at the same
+	// time, so it must only read state changed in Animate and never draw randoms
 	ColorAt(position float64, baseColor RGBA) RGBA
 
 	// The ZIndex of this Drawable thing
@@ -23,21 +24,127 @@ type Drawable interface {
 	Animate(dt float64) (keepAlive bool)
 }
 
+// How the game stands while drawables are animated, a field animated outside of a game is idle with no score or ball
+type AnimateContext struct {
+
+	// phase the game is in
+	Phase Phase
+
+	// points won by each side so far
+	LeftScore, RightScore int
+
+	// if the next point can end the game
+	MatchPoint bool
+
+	// position and velocity of the ball in leds and leds / second, HasBall is false when none is in play
+	BallPosition, BallVelocity float64
+	HasBall                    bool
+}
+
+// Implemented by drawables that react to how the game stands, such as a background that builds up at match point.
+// AnimateInContext is called instead of Animate
+type ContextDrawable interface {
+	Drawable
+
+	// Move this Drawable forward in time by dt while the game stands as context says, returns keepAlive
+	AnimateInContext(dt float64, context AnimateContext) (keepAlive bool)
+}
+
+// Animate drawable by dt, in context if it reacts to how the game stands. Drawables wrapping others use it to pass
+// the context on
+func AnimateDrawable(drawable Drawable, dt float64, context AnimateContext) bool {
+	if contextual, ok := drawable.(ContextDrawable); ok {
+		return contextual.AnimateInContext(dt, context)
+	}
+	return drawable.Animate(dt)
+}
+
+// Implemented by drawables that hold on to something only while they are on a field, such as a goroutine or a lookup
+// table, so it is let go of once they are taken off instead of leaking
+type LifecycleDrawable interface {
+	Drawable
+
+	// Called when the drawable is added to field, once for every field it's added to
+	OnAdd(field Field)
+
+	// Called when the drawable is taken off field, once it stops animating or the scene of the field is dropped
+	OnRemove(field Field)
+}
+
+// Tell drawable it was added to field if it wants to know. Drawables wrapping others use it to pass the hook on
+func NotifyAdded(drawable Drawable, field Field) {
+	if lifecycle, ok := drawable.(LifecycleDrawable); ok {
+		lifecycle.OnAdd(field)
+	}
+}
+
+// Tell drawable it was taken off field if it wants to know. Drawables wrapping others use it to pass the hook on
+func NotifyRemoved(drawable Drawable, field Field) {
+	if lifecycle, ok := drawable.(LifecycleDrawable); ok {
+		lifecycle.OnRemove(field)
+	}
+}
+
+// Implemented by drawables that look different on each row of a matrix, other drawables look the same on every row
+type Drawable2D interface {
+	Drawable
+
+	// Computes the color at column x of row y, row 0 being the top, with the given baseColor
+	ColorAt2D(x, y float64, baseColor RGBA) RGBA
+}
+
+// Implemented by drawables that only cover part of the field, so ColorAt isn't called outside of it
+type BoundedDrawable interface {
+	Drawable
+
+	// Range of positions the drawable can change the color of, ColorAt returns baseColor outside it
+	Bounds() (left, right float64)
+}
+
+// Implemented by drawables that report where they draw and when they change, so unchanged leds aren't rendered again
+type TrackedDrawable interface {
+	BoundedDrawable
+
+	// Changes whenever the drawable may look different than before
+	Version() uint64
+}
+
+// Implemented by drawables that move in fixed steps, so they can be drawn between the last two steps
+type InterpolatedDrawable interface {
+	Drawable
+
+	// Draw at alpha of the way from the previous step to the latest one, from 0 to 1
+	Interpolate(alpha float64)
+}
+
 // Helper function to blend two colors together
 func (foreground RGBA) BlendWith(background RGBA) (color RGBA) {
 
 	fr, fg, fb, fa := uint(foreground.R), uint(foreground.G), uint(foreground.B), uint(foreground.A)
-	br, bg, bb, ba := uint(background.R), uint(background.G), uint(background.B), uint(255) // want background to be fully colored
-
-	opacity := fa
-	backgroundOpacity := (ba * (255 - fa)) >> 8
+	br, bg, bb := uint(background.R), uint(background.G), uint(background.B) // want background to be fully colored
 
+	// weigh each channel by the opacity of each color, dividing by 255 once at the end so transparent colors don't
+	// darken what they are blended over
+	backgroundOpacity := 255 - fa
 	newColor := RGBA{
-		uint8((fr*opacity)>>8 + (br*backgroundOpacity)>>8),
-		uint8((fg*opacity)>>8 + (bg*backgroundOpacity)>>8),
-		uint8((fb*opacity)>>8 + (bb*backgroundOpacity)>>8),
-		uint8(opacity),
+		divide255(fr*fa + br*backgroundOpacity),
+		divide255(fg*fa + bg*backgroundOpacity),
+		divide255(fb*fa + bb*backgroundOpacity),
+		uint8(fa),
 	}
 
 	return newColor
 }
+
+// Halfway from the color to white, so a teammate can be told apart from the player they share a side with
+func (this RGBA) Lighter() RGBA {
+	color := RGBA{255, 255, 255, 128}.BlendWith(this)
+	color.A = 255
+	return color
+}
+
+// Round value / 255 to the nearest integer without dividing, exact for value up to 255 * 255
+func divide255(value uint) uint8 {
+	value += 128
+	return uint8((value + value>>8) >> 8)
+}
@@ -0,0 +1,287 @@
+package pong
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Parameters an effect is configured with, by name
+type EffectParams map[string]string
+
+// Parameter name read as a number, fallback if it isn't set or isn't a number
+func (this EffectParams) Float(name string, fallback float64) float64 {
+	text, ok := this[name]
+	if !ok {
+		return fallback
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		log.Print("Effect parameter ", name, " isn't a number: ", text)
+		return fallback
+	}
+	return value
+}
+
+// Parameter name read as a #rrggbb color, fallback if it isn't set or isn't a color
+func (this EffectParams) Color(name string, fallback RGBA) RGBA {
+	text, ok := this[name]
+	if !ok {
+		return fallback
+	}
+	color, err := parseHexColor(text)
+	if err != nil {
+		log.Print("Effect parameter ", name, ": ", err)
+		return fallback
+	}
+	return color
+}
+
+// Creates an effect drawn on field at zindex, configured by params
+type EffectFactory func(field Field, zindex ZIndex, params EffectParams) Drawable
+
+// Kinds of value an effect parameter takes
+const (
+	EffectParamNumber = "number"
+	EffectParamColor  = "color"
+	EffectParamText   = "text"
+	EffectParamFile   = "file"
+)
+
+// A parameter an effect can be tuned with, described so controls for it can be shown without knowing the effect
+type EffectParam struct {
+	Name string
+
+	// EffectParamNumber, EffectParamColor, EffectParamText or EffectParamFile for the name of a file in
+	// EffectScriptsDir
+	Kind string
+
+	// range of a number worth choosing from, and the value used when it isn't set
+	Min     float64 `json:",omitempty"`
+	Max     float64 `json:",omitempty"`
+	Default string
+}
+
+// Describe a number parameter between min and max
+func NumberParam(name string, min, max, fallback float64) EffectParam {
+	return EffectParam{Name: name, Kind: EffectParamNumber, Min: min, Max: max, Default: strconv.FormatFloat(fallback, 'g', -1, 64)}
+}
+
+// Describe a #rrggbb color parameter
+func ColorParam(name string, fallback RGBA) EffectParam {
+	return EffectParam{Name: name, Kind: EffectParamColor, Default: HexColor(fallback)}
+}
+
+// Check value can be given to the parameter
+func (this EffectParam) validate(value string) error {
+	switch this.Kind {
+	case EffectParamNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%v isn't a number", this.Name)
+		}
+		if number < this.Min || number > this.Max {
+			return fmt.Errorf("%v has to be from %v to %v", this.Name, this.Min, this.Max)
+		}
+	case EffectParamColor:
+		if _, err := parseHexColor(value); err != nil {
+			return err
+		}
+	case EffectParamFile:
+		if _, err := EffectScriptPath(value); err != nil {
+			return err
+		}
+	}
+	if strings.ContainsAny(value, ",()+ ") {
+		return fmt.Errorf("%v can't contain , ( ) + or spaces", this.Name)
+	}
+	return nil
+}
+
+// Path of the effect script called name in EffectScriptsDir, error unless name is just the name of a file, so an effect
+// parameter can't be pointed at any other file
+func EffectScriptPath(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("Effect script %q has to be the name of a file in %v", name, Settings.EffectScriptsDir)
+	}
+	return filepath.Join(Settings.EffectScriptsDir, name), nil
+}
+
+// An effect that can be chosen by name
+type registeredEffect struct {
+	name    string
+	factory EffectFactory
+	params  []EffectParam
+}
+
+// every registered effect in the order it was registered
+var effects []registeredEffect
+
+// Make an effect available by name, described by the params it reads, call from init of the package implementing the
+// effect
+func RegisterEffect(name string, factory EffectFactory, params ...EffectParam) {
+
+	for _, effect := range effects {
+		if effect.name == name {
+			log.Panic("Effect ", name, " registered twice")
+		}
+	}
+
+	effects = append(effects, registeredEffect{name, factory, params})
+}
+
+// Names of every registered effect, in the order they were registered
+func EffectNames() (names []string) {
+	for _, effect := range effects {
+		names = append(names, effect.name)
+	}
+	return
+}
+
+// Split an effect written as name or name(key=value,key=value) into its name and parameters
+func ParseEffect(spec string) (string, EffectParams, error) {
+
+	params := EffectParams{}
+	open := strings.IndexByte(spec, '(')
+	if open < 0 {
+		return strings.TrimSpace(spec), params, nil
+	}
+	if !strings.HasSuffix(spec, ")") {
+		return "", nil, fmt.Errorf("Effect %q is missing its closing )", spec)
+	}
+
+	for _, param := range strings.Split(spec[open+1:len(spec)-1], ",") {
+		if strings.TrimSpace(param) == "" {
+			continue
+		}
+		keyValue := strings.SplitN(param, "=", 2)
+		if len(keyValue) != 2 {
+			return "", nil, fmt.Errorf("Parameter %q of effect %q isn't key=value", param, spec)
+		}
+		params[strings.TrimSpace(keyValue[0])] = strings.TrimSpace(keyValue[1])
+	}
+	return strings.TrimSpace(spec[:open]), params, nil
+}
+
+// Create the effects of spec on field at zindex, spec is one effect written as name or name(key=value,...) or several
+// joined by + which are drawn on top of each other, the last on top
+func NewEffect(spec string, field Field, zindex ZIndex) (Drawable, error) {
+	return NewBoundEffect(spec, field, zindex, nil)
+}
+
+// Create the effects of spec like NewEffect, giving each the values in bound for the parameters it registered and spec
+// doesn't set, such as the color of the player the effect is played for
+func NewBoundEffect(spec string, field Field, zindex ZIndex, bound EffectParams) (Drawable, error) {
+
+	stack := &EffectStack{zindex: zindex}
+	for _, layer := range strings.Split(spec, "+") {
+		name, params, err := ParseEffect(layer)
+		if err != nil {
+			return nil, err
+		}
+
+		found := false
+		for _, effect := range effects {
+			if effect.name == name {
+				for _, param := range effect.params {
+					if _, given := params[param.Name]; !given && bound[param.Name] != "" {
+						params[param.Name] = bound[param.Name]
+					}
+				}
+				stack.layers = append(stack.layers, effect.factory(field, zindex, effectTweaks.apply(name, params)))
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("There's no effect called %q", name)
+		}
+	}
+
+	if len(stack.layers) == 1 {
+		return stack.layers[0], nil
+	}
+	return stack, nil
+}
+
+// Check spec names registered effects and only sets parameters they registered to values they take, so a mistake is
+// found when it is chosen rather than when it is shown
+func CheckEffect(spec string) error {
+
+	for _, layer := range strings.Split(spec, "+") {
+		name, params, err := ParseEffect(layer)
+		if err != nil {
+			return err
+		}
+		registered, ok := effectParams(name)
+		if !ok {
+			return fmt.Errorf("There's no effect called %q", name)
+		}
+		for key, value := range params {
+			found := false
+			for _, param := range registered {
+				if param.Name == key {
+					if err := param.validate(value); err != nil {
+						return fmt.Errorf("Effect %v: %v", name, err)
+					}
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("Effect %v has no parameter %q", name, key)
+			}
+		}
+	}
+	return nil
+}
+
+// Effects drawn on top of each other as a single drawable, the last on top
+type EffectStack struct {
+	layers []Drawable
+	zindex ZIndex
+}
+
+var _ ContextDrawable = &EffectStack{}
+var _ LifecycleDrawable = &EffectStack{}
+
+// Returns the color of every layer at position blended on top of baseColor
+func (this *EffectStack) ColorAt(position float64, baseColor RGBA) RGBA {
+	for _, layer := range this.layers {
+		baseColor = layer.ColorAt(position, baseColor)
+	}
+	return baseColor
+}
+
+// ZIndex
+func (this *EffectStack) ZIndex() ZIndex {
+	return this.zindex
+}
+
+// Animate every layer
+func (this *EffectStack) Animate(dt float64) bool {
+	return this.AnimateInContext(dt, AnimateContext{})
+}
+
+// Animate every layer in context
+func (this *EffectStack) AnimateInContext(dt float64, context AnimateContext) bool {
+	for _, layer := range this.layers {
+		AnimateDrawable(layer, dt, context)
+	}
+	return true
+}
+
+// Tell every layer it was added to field
+func (this *EffectStack) OnAdd(field Field) {
+	for _, layer := range this.layers {
+		NotifyAdded(layer, field)
+	}
+}
+
+// Tell every layer it was taken off field
+func (this *EffectStack) OnRemove(field Field) {
+	for _, layer := range this.layers {
+		NotifyRemoved(layer, field)
+	}
+}
@@ -0,0 +1,97 @@
+package pong
+
+import (
+	"testing"
+)
+
+// A solid color effect for the tests, drawn in the color parameter
+type testEffect struct {
+	color RGBA
+}
+
+func (this *testEffect) ColorAt(position float64, baseColor RGBA) RGBA {
+	return this.color.BlendWith(baseColor)
+}
+
+func (this *testEffect) ZIndex() ZIndex {
+	return 0
+}
+
+func (this *testEffect) Animate(dt float64) bool {
+	return true
+}
+
+func init() {
+	RegisterEffect("test", func(field Field, zindex ZIndex, params EffectParams) Drawable {
+		return &testEffect{params.Color("color", RGBA{255, 0, 0, 255})}
+	})
+	RegisterEffect("tinted", func(field Field, zindex ZIndex, params EffectParams) Drawable {
+		return &testEffect{params.Color("color", RGBA{255, 0, 0, 255})}
+	}, ColorParam("color", RGBA{255, 0, 0, 255}), NumberParam("speed", 0, 10, 1))
+}
+
+// Effects should be made by name with parameters, and stacked when joined by +
+func Test_Effects(t *testing.T) {
+
+	field := NewGameField(10)
+	effect, err := NewEffect("test", field, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if color := effect.ColorAt(0, RGBA{}); color != (RGBA{255, 0, 0, 255}) {
+		t.Fatal("Effect without parameters drawn in", color)
+	}
+
+	effect, err = NewEffect("test(color=#00ff00)+test(color=#0000ff, unused=1)", field, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if color := effect.ColorAt(0, RGBA{}); color != (RGBA{0, 0, 255, 255}) || effect.ZIndex() != 1 {
+		t.Fatal("Top of the stack drawn in", color)
+	}
+
+	for _, spec := range []string{"missing", "test(color=#00ff00", "test(color)"} {
+		if _, err := NewEffect(spec, field, 1); err == nil {
+			t.Fatal("Made an effect from", spec)
+		}
+	}
+}
+
+// Bound parameters should only reach effects that registered them, and never override the ones the spec sets
+func Test_BoundEffects(t *testing.T) {
+
+	field := NewGameField(10)
+	bound := EffectParams{"color": "#00ff00"}
+	tests := []struct {
+		spec     string
+		expected RGBA
+	}{
+		{"tinted", RGBA{0, 255, 0, 255}},
+		{"tinted(color=#0000ff)", RGBA{0, 0, 255, 255}},
+		{"test", RGBA{255, 0, 0, 255}},
+	}
+	for _, test := range tests {
+		effect, err := NewBoundEffect(test.spec, field, 1, bound)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if color := effect.ColorAt(0, RGBA{}); color != test.expected {
+			t.Error(test.spec, "drawn in", color, "instead of", test.expected)
+		}
+	}
+}
+
+// Specs should only be accepted naming registered effects with parameters they registered set to values they take
+func Test_CheckEffect(t *testing.T) {
+
+	for _, spec := range []string{"test", "tinted(color=#00ff00,speed=2)+test"} {
+		if err := CheckEffect(spec); err != nil {
+			t.Error(spec, err)
+		}
+	}
+	for _, spec := range []string{"missing", "test(color=#00ff00)", "tinted(speed=11)", "tinted(color=green)", "tinted(speed"} {
+		if err := CheckEffect(spec); err == nil {
+			t.Error("Accepted", spec)
+		}
+	}
+}
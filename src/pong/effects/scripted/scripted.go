@@ -0,0 +1,333 @@
+// Package scripted adds the script background effect and scripted victories, drawn by a script in a file that is read
+// again whenever it changes while the effect is shown, so effects can be worked on while the table is running
+package scripted
+
+import (
+	"io/ioutil"
+	"log"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	"github.com/brandonagr/pongpi/src/pong/script"
+)
+
+func init() {
+	RegisterEffect("script", func(field Field, zindex ZIndex, params EffectParams) Drawable {
+		path, err := EffectScriptPath(params["path"])
+		if err != nil {
+			log.Print(err)
+		}
+		return NewScriptedEffect(field, path, zindex)
+	}, EffectParam{Name: "path", Kind: EffectParamFile})
+}
+
+// Values a script can read, a script sets r, g, b and optionally a from 0 to 1 for the color of the led
+//
+//	x, y           column and row of the led
+//	width, height  leds along the field and rows of leds
+//	t              seconds since the effect started
+//	left_r ...     red, green and blue of the left and right players in the current theme, from 0 to 1
+//	left_won       1 if the left player won, only in victories
+//	progress       fraction of the victory shown so far, only in victories
+//	playing        1 during a game
+//	left_score ... points won by the left and right players so far
+//	match_point    1 if the next point can end the game
+//	ball_x         position of the ball, and ball_speed its velocity in leds / second, 0 without a ball
+var inputs = []string{
+	"x", "y", "width", "height", "t",
+	"left_r", "left_g", "left_b", "right_r", "right_g", "right_b",
+	"left_won", "progress",
+	"playing", "left_score", "right_score", "match_point", "ball_x", "ball_speed",
+	"r", "g", "b", "a",
+}
+
+// Slots of the inputs in a frame, in the same order
+const (
+	slotX = iota
+	slotY
+	slotWidth
+	slotHeight
+	slotTime
+	slotLeftRed
+	slotLeftGreen
+	slotLeftBlue
+	slotRightRed
+	slotRightGreen
+	slotRightBlue
+	slotLeftWon
+	slotProgress
+	slotPlaying
+	slotLeftScore
+	slotRightScore
+	slotMatchPoint
+	slotBallPosition
+	slotBallSpeed
+	slotRed
+	slotGreen
+	slotBlue
+	slotAlpha
+)
+
+// Seconds between checking if the script has changed
+var reloadSeconds float64 = 1
+
+// Effect drawn by the script at a path
+type ScriptedEffect struct {
+	path string
+
+	// compiled script, nil until one compiles, and the inputs it runs with, only changed while animating so they
+	// can be read while rendering
+	program *script.Program
+	values  []float64
+
+	// frames scripts are run in while rendering, several leds can be rendered at once
+	frames sync.Pool
+
+	// scripts compiled since the effect was last animated, by the goroutine watching the file
+	reloaded chan *script.Program
+
+	// fields the effect is on and closed to stop watching the file once it is on none
+	fields int
+	stop   chan struct{}
+
+	// held while the file is read, with the time it was changed when it was last read and if it couldn't be read so
+	// that's only logged once
+	loading  sync.Mutex
+	modified time.Time
+	missing  bool
+
+	// seconds since the effect started
+	elapsed float64
+
+	zindex ZIndex
+}
+
+var _ Drawable2D = &ScriptedEffect{}
+var _ ContextDrawable = &ScriptedEffect{}
+var _ LifecycleDrawable = &ScriptedEffect{}
+
+// Construct a ScriptedEffect on field running the script at path, which is read again when it changes while the
+// effect is on a field
+func NewScriptedEffect(field Field, path string, zindex ZIndex) *ScriptedEffect {
+
+	effect := &ScriptedEffect{
+		path:     path,
+		values:   make([]float64, len(inputs)),
+		reloaded: make(chan *script.Program, 1),
+		zindex:   zindex,
+	}
+	effect.values[slotWidth] = float64(field.Width())
+	effect.values[slotHeight] = float64(field.Height())
+	effect.program = effect.load()
+	return effect
+}
+
+// Compile the script again if its file has changed since it was read, nil if it hasn't or the new one has a mistake
+func (this *ScriptedEffect) load() *script.Program {
+
+	this.loading.Lock()
+	defer this.loading.Unlock()
+
+	info, err := os.Stat(this.path)
+	if err != nil {
+		if !this.missing {
+			log.Print("Reading effect script: ", err)
+		}
+		this.missing = true
+		return nil
+	}
+	this.missing = false
+	if info.ModTime().Equal(this.modified) {
+		return nil
+	}
+	this.modified = info.ModTime()
+
+	source, err := ioutil.ReadFile(this.path)
+	if err != nil {
+		log.Print("Reading effect script: ", err)
+		return nil
+	}
+	program, err := script.Compile(string(source), inputs)
+	if err != nil {
+		log.Print("Effect script ", this.path, ": ", err)
+		return nil
+	}
+
+	log.Print("Loaded effect script ", this.path)
+	return program
+}
+
+// Start watching the file for changes when the effect is first added to a field
+func (this *ScriptedEffect) OnAdd(field Field) {
+	this.fields++
+	if this.fields == 1 {
+		this.stop = make(chan struct{})
+		go this.watch(this.stop)
+	}
+}
+
+// Stop watching the file once the effect is on no field
+func (this *ScriptedEffect) OnRemove(field Field) {
+	this.fields--
+	if this.fields == 0 {
+		close(this.stop)
+	}
+}
+
+// Compile the script whenever the file changes until stop is closed, handing it over to be used from the next frame
+func (this *ScriptedEffect) watch(stop chan struct{}) {
+
+	ticker := time.NewTicker(time.Duration(reloadSeconds * float64(time.Second)))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if program := this.load(); program != nil {
+				// only the newest script matters
+				select {
+				case <-this.reloaded:
+				default:
+				}
+				this.reloaded <- program
+			}
+		}
+	}
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *ScriptedEffect) ColorAt(position float64, baseColor RGBA) RGBA {
+	return this.ColorAt2D(position, 0, baseColor)
+}
+
+// Returns the color at column x of row y blended on top of baseColor
+func (this *ScriptedEffect) ColorAt2D(x, y float64, baseColor RGBA) RGBA {
+
+	if this.program == nil {
+		return baseColor
+	}
+
+	pooled := this.takeFrame()
+	defer this.frames.Put(pooled)
+	frame := *pooled
+
+	copy(frame, this.values)
+	frame[slotX], frame[slotY] = x, y
+	frame[slotRed], frame[slotGreen], frame[slotBlue], frame[slotAlpha] = 0, 0, 0, 1
+	this.program.Run(frame)
+
+	color := RGBA{channel(frame[slotRed]), channel(frame[slotGreen]), channel(frame[slotBlue]), channel(frame[slotAlpha])}
+	return color.BlendWith(baseColor)
+}
+
+// Frame for running the script of one led in, from the pool unless the script has changed size
+func (this *ScriptedEffect) takeFrame() *[]float64 {
+	if frame, ok := this.frames.Get().(*[]float64); ok && len(*frame) == this.program.FrameLength() {
+		return frame
+	}
+	frame := this.program.NewFrame()
+	return &frame
+}
+
+// Channel of a color from a value a script set from 0 to 1
+func channel(value float64) uint8 {
+	if math.IsNaN(value) {
+		return 0
+	}
+	return uint8(math.Max(0, math.Min(1, value))*255 + 0.5)
+}
+
+// ZIndex
+func (this *ScriptedEffect) ZIndex() ZIndex {
+	return this.zindex
+}
+
+// Move time on outside of a game
+func (this *ScriptedEffect) Animate(dt float64) bool {
+	return this.AnimateInContext(dt, AnimateContext{})
+}
+
+// Move time on and take how the game stands and the colors of the current theme, switching to the script read since
+// the last frame if it changed
+func (this *ScriptedEffect) AnimateInContext(dt float64, context AnimateContext) bool {
+
+	select {
+	case program := <-this.reloaded:
+		this.program = program
+	default:
+	}
+
+	this.elapsed += dt
+	this.values[slotTime] = this.elapsed
+
+	playing := context.Phase == PhaseRally || context.Phase == PhasePointScored || context.Phase == PhaseGameOver
+	this.values[slotPlaying] = boolean(playing)
+	this.values[slotLeftScore], this.values[slotRightScore] = float64(context.LeftScore), float64(context.RightScore)
+	this.values[slotMatchPoint] = boolean(context.MatchPoint)
+	this.values[slotBallPosition], this.values[slotBallSpeed] = 0, 0
+	if context.HasBall {
+		this.values[slotBallPosition], this.values[slotBallSpeed] = context.BallPosition, context.BallVelocity
+	}
+
+	theme := CurrentTheme()
+	this.setColor(slotLeftRed, theme.LeftColor)
+	this.setColor(slotRightRed, theme.RightColor)
+	return true
+}
+
+// 1 for true and 0 for false
+func boolean(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// Set the red, green and blue slots starting at slot to color
+func (this *ScriptedEffect) setColor(slot int, color RGBA) {
+	this.values[slot] = float64(color.R) / 255
+	this.values[slot+1] = float64(color.G) / 255
+	this.values[slot+2] = float64(color.B) / 255
+}
+
+// Winner of a game shown by a script for a number of seconds
+type ScriptedVictory struct {
+	*ScriptedEffect
+
+	seconds float64
+}
+
+// Construct a ScriptedVictory on field showing the left or right player winning with the script at path for seconds
+func NewScriptedVictory(field Field, path string, leftWon bool, seconds float64) *ScriptedVictory {
+
+	victory := &ScriptedVictory{
+		ScriptedEffect: NewScriptedEffect(field, path, 20),
+		seconds:        seconds,
+	}
+	if leftWon {
+		victory.values[slotLeftWon] = 1
+	}
+	return victory
+}
+
+// Seconds left until the winner has been shown
+func (this *ScriptedVictory) TimeRemaining() float64 {
+	return this.seconds - this.elapsed
+}
+
+// Move time on outside of a game, finished once the winner has been shown
+func (this *ScriptedVictory) Animate(dt float64) bool {
+	return this.AnimateInContext(dt, AnimateContext{})
+}
+
+// Move time on, finished once the winner has been shown
+func (this *ScriptedVictory) AnimateInContext(dt float64, context AnimateContext) bool {
+	this.ScriptedEffect.AnimateInContext(dt, context)
+	this.values[slotProgress] = math.Min(1, this.elapsed/this.seconds)
+	return this.TimeRemaining() > 0
+}
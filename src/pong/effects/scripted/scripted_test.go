@@ -0,0 +1,89 @@
+package scripted
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Animate effect until the color at position passes check, false if it never does
+func animateUntil(effect *ScriptedEffect, position float64, check func(color RGBA) bool) bool {
+	for tries := 0; tries < 200; tries++ {
+		effect.Animate(0)
+		if check(effect.ColorAt(position, RGBA{})) {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return false
+}
+
+// The effect should be drawn by its script and pick up changes to the file while it is on a field
+func Test_ScriptedEffect_Reload(t *testing.T) {
+
+	reloadSeconds = 0.01
+	defer func() { reloadSeconds = 1 }()
+
+	directory, err := ioutil.TempDir("", "scripted")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	path := filepath.Join(directory, "effect.fx")
+
+	if err := ioutil.WriteFile(path, []byte("r = step(5, x)\nb = t"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	field := NewGameField(10)
+	effect := NewScriptedEffect(field, path, 0)
+	if color := effect.ColorAt(6, RGBA{}); color != (RGBA{255, 0, 0, 255}) {
+		t.Fatal("Script drew", color)
+	}
+	if color := effect.ColorAt(4, RGBA{}); color != (RGBA{0, 0, 0, 255}) {
+		t.Fatal("Script drew", color)
+	}
+
+	effect.Animate(0.5)
+	if color := effect.ColorAt(4, RGBA{}); color != (RGBA{0, 0, 128, 255}) {
+		t.Fatal("Script drew", color, "halfway through a second")
+	}
+
+	field.Add(effect)
+
+	// a mistake keeps the script that worked
+	if err := ioutil.WriteFile(path, []byte("g = oops"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(path, time.Now(), time.Now().Add(time.Minute))
+	time.Sleep(50 * time.Millisecond)
+	if color := effect.ColorAt(6, RGBA{}); color.R != 255 {
+		t.Fatal("Script with a mistake drew", color)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("g = 1\na = 0.5"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(path, time.Now(), time.Now().Add(2*time.Minute))
+	if !animateUntil(effect, 6, func(color RGBA) bool { return color == RGBA{0, 128, 0, 128} }) {
+		t.Fatal("Changed script drew", effect.ColorAt(6, RGBA{}))
+	}
+
+	// once the field is dropped the file isn't watched
+	field.Release()
+	if effect.fields != 0 {
+		t.Fatal("Effect is still on", effect.fields, "fields")
+	}
+	if err := ioutil.WriteFile(path, []byte("b = 1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(path, time.Now(), time.Now().Add(3*time.Minute))
+	time.Sleep(50 * time.Millisecond)
+	effect.Animate(0)
+	if color := effect.ColorAt(6, RGBA{}); color.B != 0 {
+		t.Fatal("Script changed after the effect was removed", color)
+	}
+}
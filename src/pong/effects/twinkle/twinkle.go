@@ -0,0 +1,90 @@
+// Package twinkle adds the twinkle background effect, registering it by name so it can be chosen like the built in
+// backgrounds without the scene code knowing about it
+package twinkle
+
+import (
+	"math"
+	"math/rand"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+func init() {
+	RegisterEffect("twinkle", func(field Field, zindex ZIndex, params EffectParams) Drawable {
+		return NewTwinkle(field, params.Color("color", RGBA{255, 255, 220, 255}), params.Float("rate", 4), zindex)
+	}, ColorParam("color", RGBA{255, 255, 220, 255}), NumberParam("rate", 0.5, 30, 4))
+}
+
+// Seconds a star takes to fade in and back out
+var starLifetime float64 = 1.5
+
+// A star shining at one led
+type star struct {
+	position float64
+	age      float64
+}
+
+// Stars that light up at random leds, brighten and fade away
+type Twinkle struct {
+	width float64
+	color RGBA
+
+	// stars started a second, and the fraction of a star waiting to start
+	rate    float64
+	pending float64
+
+	stars  []star
+	random *rand.Rand
+	zindex ZIndex
+}
+
+var _ Drawable = &Twinkle{}
+
+// Construct a Twinkle in color across field, starting rate stars a second
+func NewTwinkle(field Field, color RGBA, rate float64, zindex ZIndex) *Twinkle {
+	return &Twinkle{
+		width:  float64(field.Width()),
+		color:  color,
+		rate:   rate,
+		random: NewRand(),
+		zindex: zindex,
+	}
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *Twinkle) ColorAt(position float64, baseColor RGBA) RGBA {
+	for _, star := range this.stars {
+		if star.position != position {
+			continue
+		}
+
+		// brightest halfway through its life
+		brightness := 1 - 2*math.Abs(star.age/starLifetime-0.5)
+		color := RGBA{this.color.R, this.color.G, this.color.B, ScaleChannel(this.color.A, brightness)}
+		baseColor = color.BlendWith(baseColor)
+	}
+	return baseColor
+}
+
+// ZIndex
+func (this *Twinkle) ZIndex() ZIndex {
+	return this.zindex
+}
+
+// Age the stars, dropping the ones that have faded and starting new ones
+func (this *Twinkle) Animate(dt float64) bool {
+
+	shining := this.stars[:0]
+	for _, star := range this.stars {
+		star.age += dt
+		if star.age < starLifetime {
+			shining = append(shining, star)
+		}
+	}
+	this.stars = shining
+
+	for this.pending += this.rate * dt; this.pending >= 1; this.pending-- {
+		this.stars = append(this.stars, star{position: float64(this.random.Intn(int(this.width)))})
+	}
+	return true
+}
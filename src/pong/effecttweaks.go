@@ -0,0 +1,264 @@
+package pong
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/brandonagr/pongpi/src/pong/atomicfile"
+)
+
+// A value chosen for a parameter of an effect
+type EffectTweak struct {
+	Effect string `xml:"effect,attr"`
+	Param  string `xml:"param,attr"`
+	Value  string `xml:"value,attr"`
+}
+
+// Values chosen for effect parameters from the web page, used when an effect is made without being given the
+// parameter itself, persisted to a file
+type EffectTweaks struct {
+	XMLName xml.Name      `xml:"EffectTweaks"`
+	Tweaks  []EffectTweak `xml:"Tweak"`
+
+	// file the tweaks are saved to
+	path string
+
+	// tweaks are changed by the web server while the game makes effects
+	lock sync.Mutex
+}
+
+// Tweaks used when effects are made, nil uses every parameter's default
+var effectTweaks *EffectTweaks
+
+// Use tweaks when making effects, nil for none
+func UseEffectTweaks(tweaks *EffectTweaks) {
+	effectTweaks = tweaks
+}
+
+// Load tweaks from path, starting with none if the file doesn't exist yet
+func LoadEffectTweaks(path string) *EffectTweaks {
+
+	tweaks := &EffectTweaks{path: path}
+
+	fileData, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Print(err)
+		}
+		return tweaks
+	}
+	if err := xml.Unmarshal(fileData, tweaks); err != nil {
+		log.Print(err)
+	}
+
+	return tweaks
+}
+
+// Write the tweaks to the file they were loaded from
+func (this *EffectTweaks) Save() error {
+
+	this.lock.Lock()
+	fileData, err := xml.MarshalIndent(this, "", "\t")
+	this.lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(this.path, fileData, 0666)
+}
+
+// Parameters of the effect with name, params registered for it, false if there isn't one
+func effectParams(name string) ([]EffectParam, bool) {
+	for _, effect := range effects {
+		if effect.name == name {
+			return effect.params, true
+		}
+	}
+	return nil, false
+}
+
+// Choose value for param of effect, an empty value goes back to the default
+func (this *EffectTweaks) Set(effect, param, value string) error {
+
+	params, ok := effectParams(effect)
+	if !ok {
+		return fmt.Errorf("There's no effect called %q", effect)
+	}
+	found := false
+	for _, described := range params {
+		if described.Name == param {
+			if value != "" {
+				if err := described.validate(value); err != nil {
+					return err
+				}
+			}
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("Effect %v has no parameter %q", effect, param)
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	kept := this.Tweaks[:0]
+	for _, tweak := range this.Tweaks {
+		if tweak.Effect != effect || tweak.Param != param {
+			kept = append(kept, tweak)
+		}
+	}
+	this.Tweaks = kept
+	if value != "" {
+		this.Tweaks = append(this.Tweaks, EffectTweak{effect, param, value})
+	}
+	return nil
+}
+
+// Value chosen for param of effect, false if none was
+func (this *EffectTweaks) Get(effect, param string) (string, bool) {
+
+	if this == nil {
+		return "", false
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	for _, tweak := range this.Tweaks {
+		if tweak.Effect == effect && tweak.Param == param {
+			return tweak.Value, true
+		}
+	}
+	return "", false
+}
+
+// Params of effect with the tweaks added for the parameters it wasn't given
+func (this *EffectTweaks) apply(effect string, params EffectParams) EffectParams {
+
+	if this == nil {
+		return params
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	for _, tweak := range this.Tweaks {
+		if _, given := params[tweak.Param]; tweak.Effect == effect && !given {
+			params[tweak.Param] = tweak.Value
+		}
+	}
+	return params
+}
+
+// An effect and its parameters as served to the web page, with the value each is set to
+type effectDescription struct {
+	Name   string
+	Params []paramDescription
+}
+
+// A parameter and the value it is set to
+type paramDescription struct {
+	EffectParam
+	Value string
+}
+
+// Serve every effect with its parameters and their values as json, POST effect, param and value to tweak one or
+// DELETE with effect and param in the query to go back to its default, main only lets the admin make changes
+func (this *EffectTweaks) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method == "POST" || r.Method == "DELETE" {
+		value := r.FormValue("value")
+		if r.Method == "DELETE" {
+			value = ""
+		}
+		if err := this.Set(r.FormValue("effect"), r.FormValue("param"), value); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := this.Save(); err != nil {
+			log.Print(err)
+		}
+		log.Print("Tweaked ", r.FormValue("param"), " of effect ", r.FormValue("effect"), " to ", value)
+	}
+
+	descriptions := []effectDescription{}
+	for _, effect := range effects {
+		description := effectDescription{Name: effect.name, Params: []paramDescription{}}
+		for _, param := range effect.params {
+			value, ok := this.Get(effect.name, param.Name)
+			if !ok {
+				value = param.Default
+			}
+			description.Params = append(description.Params, paramDescription{param, value})
+		}
+		descriptions = append(descriptions, description)
+	}
+	sort.Slice(descriptions, func(i, j int) bool { return descriptions[i].Name < descriptions[j].Name })
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(descriptions)
+}
+
+// Serve the page of controls for every effect parameter, made from the descriptions served by ServeHTTP, opened with
+// ?token= for the admin token changes need
+func (this *EffectTweaks) ServePage(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `
+<html>
+	<head><script type="text/javascript"><!--
+		function tweak(effect, param, value)
+		{
+			fetch("api/effects" + location.search, {method: "POST", body: new URLSearchParams({effect: effect, param: param, value: value})})
+				.then(function(response) { return response.ok ? null : response.text(); })
+				.then(function(error) { document.getElementById("error").textContent = error || ""; });
+		}
+
+		function control(effect, param)
+		{
+			var input = document.createElement("input");
+			input.value = param.Value;
+			if (param.Kind == "number") {
+				input.type = "range";
+				input.min = param.Min;
+				input.max = param.Max;
+				input.step = (param.Max - param.Min) / 100;
+			} else if (param.Kind == "color") {
+				input.type = "color";
+			}
+			input.onchange = function() { tweak(effect, param.Name, input.value); };
+			return input;
+		}
+
+		fetch("api/effects").then(function(response) { return response.json(); }).then(function(effects) {
+			var list = document.getElementById("effects");
+			effects.forEach(function(effect) {
+				if (effect.Params.length == 0) {
+					return;
+				}
+				var section = document.createElement("fieldset");
+				section.appendChild(document.createElement("legend")).textContent = effect.Name;
+				effect.Params.forEach(function(param) {
+					var label = section.appendChild(document.createElement("label"));
+					label.textContent = param.Name + " ";
+					label.appendChild(control(effect.Name, param));
+					section.appendChild(document.createElement("br"));
+				});
+				list.appendChild(section);
+			});
+		});
+	--></script></head>
+	<body>
+		<h1>Effects</h1>
+		<p>Changes are used the next time an effect is shown</p>
+		<p id="error"></p>
+		<div id="effects"></div>
+	</body>
+</html>`)
+}
@@ -0,0 +1,89 @@
+package pong
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func init() {
+	RegisterEffect("tweakable", func(field Field, zindex ZIndex, params EffectParams) Drawable {
+		return &testEffect{params.Color("color", RGBA{255, 0, 0, 255})}
+	}, ColorParam("color", RGBA{255, 0, 0, 255}), NumberParam("size", 1, 10, 2))
+	RegisterEffect("scriptfile", func(field Field, zindex ZIndex, params EffectParams) Drawable {
+		return &testEffect{}
+	}, EffectParam{Name: "path", Kind: EffectParamFile})
+}
+
+// Tweaks chosen from the web should be checked against the parameters of the effect, used for the parameters an
+// effect isn't given and kept in their file
+func Test_EffectTweaks(t *testing.T) {
+
+	directory, err := ioutil.TempDir("", "tweaks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	path := filepath.Join(directory, "effecttweaks.xml")
+
+	tweaks := LoadEffectTweaks(path)
+	UseEffectTweaks(tweaks)
+	defer UseEffectTweaks(nil)
+
+	post := func(form url.Values) int {
+		request := httptest.NewRequest("POST", "/api/effects", strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+		tweaks.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	Assert(post(url.Values{"effect": {"tweakable"}, "param": {"color"}, "value": {"#0000ff"}}), 200, "Tweaking a color", t)
+	Assert(post(url.Values{"effect": {"tweakable"}, "param": {"size"}, "value": {"11"}}), 400, "Tweaking out of range", t)
+	Assert(post(url.Values{"effect": {"tweakable"}, "param": {"shape"}, "value": {"1"}}), 400, "Tweaking a missing parameter", t)
+	Assert(post(url.Values{"effect": {"missing"}, "param": {"size"}, "value": {"1"}}), 400, "Tweaking a missing effect", t)
+	Assert(post(url.Values{"effect": {"scriptfile"}, "param": {"path"}, "value": {"glow.fx"}}), 200, "Tweaking a script name", t)
+	for _, path := range []string{"/etc/passwd", "../settings.xml", "effects/glow.fx", ".hidden", `..\glow.fx`} {
+		Assert(post(url.Values{"effect": {"scriptfile"}, "param": {"path"}, "value": {path}}), 400, "Tweaking the script to "+path, t)
+	}
+
+	field := NewGameField(10)
+	effect, _ := NewEffect("tweakable", field, 0)
+	if color := effect.ColorAt(0, RGBA{}); color != (RGBA{0, 0, 255, 255}) {
+		t.Fatal("Tweaked effect drawn in", color)
+	}
+	effect, _ = NewEffect("tweakable(color=#00ff00)", field, 0)
+	if color := effect.ColorAt(0, RGBA{}); color != (RGBA{0, 255, 0, 255}) {
+		t.Fatal("Effect given a color drawn in", color)
+	}
+
+	if value, ok := LoadEffectTweaks(path).Get("tweakable", "color"); !ok || value != "#0000ff" {
+		t.Fatal("Saved tweak is", value)
+	}
+
+	// the listing has every parameter at its value
+	request := httptest.NewRequest("DELETE", "/api/effects?effect=tweakable&param=color", nil)
+	recorder := httptest.NewRecorder()
+	tweaks.ServeHTTP(recorder, request)
+	Assert(recorder.Code, 200, "Going back to the default", t)
+
+	var descriptions []effectDescription
+	if err := json.NewDecoder(recorder.Body).Decode(&descriptions); err != nil {
+		t.Fatal(err)
+	}
+	for _, description := range descriptions {
+		if description.Name != "tweakable" {
+			continue
+		}
+		if len(description.Params) != 2 || description.Params[0].Value != "#ff0000" || description.Params[1].Value != "2" {
+			t.Fatal("Parameters listed as", description.Params)
+		}
+		return
+	}
+	t.Fatal("Tweakable effect isn't listed")
+}
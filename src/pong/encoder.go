@@ -0,0 +1,166 @@
+package pong
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Step taken for every change of the two quadrature pins, indexed by their previous and current states, 0 for no
+// change or a missed state
+var quadratureSteps = [16]int{0, -1, 1, 0, 1, 0, 0, -1, -1, 0, 0, 1, 0, 1, -1, 0}
+
+// Turns the levels of the two pins of a rotary encoder into clicks of the knob
+type QuadratureDecoder struct {
+
+	// quadrature steps between clicks of the knob, 4 for most encoders
+	stepsPerDetent int
+
+	// last state of the pins and the steps counted since the last click
+	state int
+	steps int
+}
+
+// Construct a QuadratureDecoder for an encoder with stepsPerDetent steps a click, starting with neither pin active
+func NewQuadratureDecoder(stepsPerDetent int) *QuadratureDecoder {
+	if stepsPerDetent < 1 {
+		stepsPerDetent = 1
+	}
+	return &QuadratureDecoder{stepsPerDetent: stepsPerDetent}
+}
+
+// Read the pins, true while active, returns 1 when the knob clicks clockwise, -1 anticlockwise, otherwise 0
+func (this *QuadratureDecoder) Update(a, b bool) int {
+
+	state := 0
+	if a {
+		state |= 2
+	}
+	if b {
+		state |= 1
+	}
+	this.steps += quadratureSteps[this.state<<2|state]
+	this.state = state
+
+	switch {
+	case this.steps >= this.stepsPerDetent:
+		this.steps -= this.stepsPerDetent
+		return 1
+	case this.steps <= -this.stepsPerDetent:
+		this.steps += this.stepsPerDetent
+		return -1
+	}
+	return 0
+}
+
+// Polls of the push button it has to stay the same for before a change is believed, so contact bounce isn't counted
+var encoderDebouncePolls = 5
+
+// Knob with a push button wired to gpio pins, polled on its own goroutine so quick turns aren't missed between frames
+type RotaryEncoder struct {
+	a, b, button io.ReadSeeker
+	closers      []io.Closer
+
+	decoder *QuadratureDecoder
+	data    []byte
+
+	// debounced state of the button, and polls the pin has read differently to it
+	down       bool
+	bouncePoll int
+
+	// clicks and presses since they were last taken by the game
+	lock    sync.Mutex
+	clicks  int
+	presses int
+}
+
+// Construct a RotaryEncoder reading the gpio value files of pins a and b and button, which can be nil if the knob has
+// no button, closing closers when it is closed
+func NewRotaryEncoder(a, b, button io.ReadSeeker, stepsPerDetent int, closers ...io.Closer) *RotaryEncoder {
+	return &RotaryEncoder{
+		a:       a,
+		b:       b,
+		button:  button,
+		closers: closers,
+		decoder: NewQuadratureDecoder(stepsPerDetent),
+		data:    make([]byte, 2),
+	}
+}
+
+// Level of the pin read from file, true for low as the pins are pulled up and the encoder shorts them to ground
+func (this *RotaryEncoder) readPin(file io.ReadSeeker) (bool, error) {
+	if _, err := file.Read(this.data); err != nil {
+		return false, err
+	}
+	if _, err := file.Seek(0, 0); err != nil {
+		return false, err
+	}
+	return this.data[0] == '0', nil
+}
+
+// Read the pins once, counting any click of the knob or press of the button
+func (this *RotaryEncoder) Poll() error {
+
+	a, err := this.readPin(this.a)
+	if err != nil {
+		return err
+	}
+	b, err := this.readPin(this.b)
+	if err != nil {
+		return err
+	}
+	click := this.decoder.Update(a, b)
+
+	pressed := false
+	if this.button != nil {
+		down, err := this.readPin(this.button)
+		if err != nil {
+			return err
+		}
+		if down == this.down {
+			this.bouncePoll = 0
+		} else if this.bouncePoll++; this.bouncePoll >= encoderDebouncePolls {
+			this.down, this.bouncePoll = down, 0
+			pressed = down
+		}
+	}
+
+	if click != 0 || pressed {
+		this.lock.Lock()
+		this.clicks += click
+		if pressed {
+			this.presses++
+		}
+		this.lock.Unlock()
+	}
+	return nil
+}
+
+// Poll the pins every interval until one can't be read, run as a goroutine
+func (this *RotaryEncoder) Watch(interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for _ = range ticker.C {
+		if err := this.Poll(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clicks of the knob since the last call, positive clockwise, and presses of the button
+func (this *RotaryEncoder) Take() (clicks, presses int) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	clicks, presses = this.clicks, this.presses
+	this.clicks, this.presses = 0, 0
+	return
+}
+
+// Close the files of the pins
+func (this *RotaryEncoder) Close() error {
+	for _, closer := range this.closers {
+		closer.Close()
+	}
+	return nil
+}
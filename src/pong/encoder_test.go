@@ -0,0 +1,62 @@
+package pong
+
+import (
+	"testing"
+)
+
+// Value file of a gpio pin reading level, '0' or '1'
+type fakePin struct {
+	level byte
+}
+
+func (this *fakePin) Read(data []byte) (int, error) {
+	data[0], data[1] = this.level, '\n'
+	return 2, nil
+}
+
+func (this *fakePin) Seek(offset int64, whence int) (int64, error) {
+	return 0, nil
+}
+
+// Turning the knob a click each way should be counted once, and a bouncing press of its button only once
+func Test_RotaryEncoder(t *testing.T) {
+
+	a, b, button := &fakePin{'1'}, &fakePin{'1'}, &fakePin{'1'}
+	encoder := NewRotaryEncoder(a, b, button, 4)
+
+	turn := func(levels string) {
+		for index := 0; index < len(levels); index += 2 {
+			a.level, b.level = levels[index], levels[index+1]
+			if err := encoder.Poll(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	turn("01001011")
+	if clicks, presses := encoder.Take(); clicks != 1 || presses != 0 {
+		t.Fatal("Clockwise click counted as", clicks, "clicks and", presses, "presses")
+	}
+	turn("10000111")
+	if clicks, _ := encoder.Take(); clicks != -1 {
+		t.Fatal("Anticlockwise click counted as", clicks)
+	}
+	turn("0100011011")
+	if clicks, _ := encoder.Take(); clicks != 0 {
+		t.Fatal("Knob rocked back and forth counted as", clicks, "clicks")
+	}
+
+	for _, level := range "0101000000000011111111" {
+		button.level = byte(level)
+		encoder.Poll()
+	}
+	if _, presses := encoder.Take(); presses != 1 {
+		t.Fatal("Bouncing press counted", presses, "times")
+	}
+
+	menu := NewMenu("test", []MenuOption{{Name: "one"}, {Name: "two"}, {Name: "three"}})
+	menu.Previous()
+	if menu.Selected().Name != "three" {
+		t.Fatal("Previous option from the first is", menu.Selected().Name)
+	}
+}
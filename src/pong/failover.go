@@ -0,0 +1,169 @@
+package pong
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// State of the game on the peer, mirrored from its /api/state
+type PeerState struct {
+	Responding            bool
+	Phase                 string
+	Mode                  string
+	LeftScore, RightScore int
+}
+
+// One of a redundant pair of Pis sharing a strip, driving it while the other waits on standby and takes over when the
+// driving one stops answering, so a failed Pi doesn't leave an installation dark for days
+type Failover struct {
+
+	// web address of the other Pi, such as http://10.0.0.2:8080
+	peer   string
+	client *http.Client
+
+	// how long the peer can go without answering before this one takes over
+	timeout time.Duration
+
+	// value file of the gpio pin switching the data line of the strip to this Pi, empty if each has its own output
+	relayPath, relayPort string
+
+	lock      sync.Mutex
+	driving   bool
+	mirrored  PeerState
+	lastHeard time.Time
+	lastError string
+}
+
+// Construct a Failover for the pair with the peer at the web address in settings
+func NewFailover(settings SettingsData) *Failover {
+	timeout := time.Duration(settings.FailoverSeconds * float64(time.Second))
+	return &Failover{
+		peer:      strings.TrimSuffix(settings.FailoverPeer, "/"),
+		client:    &http.Client{Timeout: timeout},
+		timeout:   timeout,
+		relayPath: settings.FailoverRelayPath,
+		relayPort: settings.FailoverRelayGpioPort,
+	}
+}
+
+// Get path from the peer as json into value
+func (this *Failover) get(path string, value interface{}) error {
+
+	response, err := this.client.Get(this.peer + path)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("Peer answered %v with %v", path, response.Status)
+	}
+	return json.NewDecoder(response.Body).Decode(value)
+}
+
+// If the peer is driving the strip, asked by a Pi starting up so one that comes back after the other has taken over
+// waits on standby instead of fighting it for the strip
+func (this *Failover) PeerDriving() bool {
+	var description failoverDescription
+	return this.get("/api/failover", &description) == nil && description.Driving
+}
+
+// Ask the peer for its state at now, true while it has been heard from within the timeout, a game loop that has
+// stopped answering counts as not being heard from
+func (this *Failover) Check(now time.Time) bool {
+
+	var state PeerState
+	err := this.get("/api/state", &state)
+	if err == nil && !state.Responding {
+		err = fmt.Errorf("Game loop on %v isn't responding", this.peer)
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if this.lastHeard.IsZero() {
+		this.lastHeard = now
+	}
+	message := ""
+	if err != nil {
+		message = err.Error()
+	} else {
+		this.mirrored, this.lastHeard = state, now
+	}
+	if message != this.lastError && err != nil {
+		log.Print("Standby checking the peer: ", err)
+	}
+	this.lastError = message
+
+	return now.Sub(this.lastHeard) <= this.timeout
+}
+
+// Wait on standby, checking the peer every interval until it hasn't been heard from within the timeout, then take
+// over the strip
+func (this *Failover) Standby(interval time.Duration) {
+
+	log.Print("On standby for ", this.peer)
+	ticker := time.NewTicker(interval)
+	for now := range ticker.C {
+		if !this.Check(now) {
+			break
+		}
+	}
+	ticker.Stop()
+
+	this.lock.Lock()
+	mirrored := this.mirrored
+	this.lock.Unlock()
+	log.Printf("Taking over from %v, last heard in %v playing %q %v-%v", this.peer, mirrored.Phase, mirrored.Mode, mirrored.LeftScore, mirrored.RightScore)
+	this.Drive()
+}
+
+// Start driving the strip, switching the data line over to this Pi if it goes through a relay
+func (this *Failover) Drive() {
+
+	if this.relayPath != "" {
+		if err := switchRelay(this.relayPath, this.relayPort); err != nil {
+			log.Print("Switching the strip over: ", err)
+		}
+	}
+
+	this.lock.Lock()
+	this.driving = true
+	this.lock.Unlock()
+}
+
+// Export port as an output if its value file at path doesn't exist yet and set it high
+func switchRelay(path, port string) error {
+
+	if err := exportGpio(path, port, "out"); err != nil {
+		return fmt.Errorf("Relay: %v", err)
+	}
+	if err := ioutil.WriteFile(path, []byte("1\n"), 0644); err != nil {
+		return fmt.Errorf("Relay on gpio %v: %v", port, err)
+	}
+	return nil
+}
+
+type failoverDescription struct {
+	Peer      string
+	Driving   bool
+	Mirrored  PeerState
+	LastHeard time.Time
+	Error     string `json:",omitempty"`
+}
+
+// Serve whether this Pi is driving the strip and what it last heard from the peer as json
+func (this *Failover) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	this.lock.Lock()
+	description := failoverDescription{this.peer, this.driving, this.mirrored, this.lastHeard, this.lastError}
+	this.lock.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(description)
+}
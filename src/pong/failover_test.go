@@ -0,0 +1,76 @@
+package pong
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// The standby should mirror the peer while it answers and only give up on it once it has been silent for the timeout
+func Test_Failover_Check(t *testing.T) {
+
+	responding := true
+	peer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(PeerState{Responding: responding, Phase: "rally", Mode: "classic", LeftScore: 3})
+	}))
+	defer peer.Close()
+
+	failover := NewFailover(SettingsData{FailoverPeer: peer.URL + "/", FailoverSeconds: 5})
+	start := time.Now()
+	if !failover.Check(start) {
+		t.Fatal("Peer that answered wasn't heard from")
+	}
+
+	responding = false
+	if !failover.Check(start.Add(4 * time.Second)) {
+		t.Fatal("Took over before the timeout")
+	}
+	if failover.Check(start.Add(6 * time.Second)) {
+		t.Fatal("Peer with a stuck game loop was still heard from after the timeout")
+	}
+
+	recorder := httptest.NewRecorder()
+	failover.ServeHTTP(recorder, httptest.NewRequest("GET", "/api/failover", nil))
+	var description failoverDescription
+	json.NewDecoder(recorder.Body).Decode(&description)
+	if description.Driving || description.Mirrored.Mode != "classic" || description.Mirrored.LeftScore != 3 || description.Error == "" {
+		t.Fatal("Standby described as", description)
+	}
+}
+
+// A Pi starting up should only wait on standby if its peer is already driving the strip, which switches the relay
+func Test_Failover_PeerDriving(t *testing.T) {
+
+	directory, err := ioutil.TempDir("", "failover")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	relayPath := filepath.Join(directory, "value")
+	ioutil.WriteFile(relayPath, []byte("0\n"), 0644)
+
+	driving := NewFailover(SettingsData{FailoverRelayPath: relayPath})
+	peer := httptest.NewServer(driving)
+	defer peer.Close()
+
+	failover := NewFailover(SettingsData{FailoverPeer: peer.URL, FailoverSeconds: 5})
+	if failover.PeerDriving() {
+		t.Fatal("Peer driving before it started")
+	}
+	driving.Drive()
+	if !failover.PeerDriving() {
+		t.Fatal("Peer not driving after it started")
+	}
+	if value, _ := ioutil.ReadFile(relayPath); string(value) != "1\n" {
+		t.Fatalf("Relay set to %q", value)
+	}
+
+	if NewFailover(SettingsData{FailoverPeer: "http://127.0.0.1:1", FailoverSeconds: 1}).PeerDriving() {
+		t.Fatal("Peer that doesn't answer is driving")
+	}
+}
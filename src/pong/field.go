@@ -0,0 +1,40 @@
+package pong
+
+// How the ends of a field are joined
+type Topology int
+
+const (
+	// the field ends at both sides, the ball bounces back or is missed
+	TopologyLine Topology = iota
+
+	// the strip runs all the way around, such as the edge of a table, so the last led is next to the first
+	TopologyLoop
+)
+
+// Names of the topologies used in the settings
+var topologyNames = map[string]Topology{"line": TopologyLine, "loop": TopologyLoop}
+
+// Find the topology called name, line or loop, returns false if there isn't one
+func FindTopology(name string) (Topology, bool) {
+	topology, ok := topologyNames[name]
+	return topology, ok
+}
+
+// Where drawables are placed, a strip of leds or a matrix of rows of them
+type Field interface {
+
+	// Leds along the length of the field, the direction the ball travels
+	Width() int
+
+	// Rows of leds, 1 for a strip
+	Height() int
+
+	// How the ends of the field are joined
+	Topology() Topology
+
+	// Adds a drawable to the field
+	Add(drawable Drawable)
+
+	// All of the drawables in increasing ZIndex order
+	Drawables() []Drawable
+}
@@ -0,0 +1,126 @@
+package pong
+
+import (
+	"math"
+	"time"
+)
+
+// Fraction of the frame budget that can be spent working before the frame rate is lowered
+var frameBudgetUse float64 = 0.9
+
+// Fraction of the next higher rate's budget the work has to fit in before the frame rate is raised
+var frameHeadroomUse float64 = 0.6
+
+// Amount the frame rate is multiplied by each time it is lowered
+var frameRateStep float64 = 0.75
+
+// Seconds to wait after changing the frame rate before changing it again
+var frameRateSettleTime float64 = 1
+
+// Weight given to the newest frame in the average work time
+var frameWorkSmoothing float64 = 0.1
+
+// Lowers the frame rate when frames take longer than their budget, and raises it again once there is headroom
+type FrameRateGovernor struct {
+	minFPS, maxFPS float64
+
+	// highest rate allowed right now, such as while the board is too hot
+	limitFPS float64
+
+	// current target frame rate
+	fps float64
+
+	// smoothed seconds spent working on each frame
+	averageWork float64
+
+	// seconds since the rate last changed
+	settleTime float64
+
+	// if frames are over budget even at minFPS
+	overloaded bool
+}
+
+// Construct a FrameRateGovernor starting at maxFPS
+func NewFrameRateGovernor(minFPS, maxFPS float64) *FrameRateGovernor {
+	if minFPS <= 0 || minFPS > maxFPS {
+		minFPS = maxFPS
+	}
+	return &FrameRateGovernor{
+		minFPS:   minFPS,
+		maxFPS:   maxFPS,
+		limitFPS: maxFPS,
+		fps:      maxFPS,
+	}
+}
+
+// Record the seconds spent working on the last frame, returns true if the target frame rate changed
+func (this *FrameRateGovernor) Update(work float64) bool {
+
+	this.averageWork += (work - this.averageWork) * frameWorkSmoothing
+	this.settleTime += 1 / this.fps
+
+	budget := 1 / this.fps
+	this.overloaded = this.fps <= this.minFPS && this.averageWork > budget*frameBudgetUse
+
+	if this.settleTime < frameRateSettleTime {
+		return false
+	}
+
+	if this.averageWork > budget*frameBudgetUse && this.fps > this.minFPS {
+		this.setFPS(this.fps * frameRateStep)
+		return true
+	}
+
+	if next := this.fps / frameRateStep; this.fps < this.limitFPS && this.averageWork < frameHeadroomUse/next {
+		this.setFPS(next)
+		return true
+	}
+
+	return false
+}
+
+// Change the target, staying between the min and max
+func (this *FrameRateGovernor) setFPS(fps float64) {
+
+	if fps < this.minFPS {
+		fps = this.minFPS
+	}
+	if fps > this.limitFPS {
+		fps = this.limitFPS
+	}
+
+	this.fps = fps
+	this.settleTime = 0
+}
+
+// Keep the frame rate at or under fps, from the min up to the max it was constructed with, returns true if the target
+// frame rate changed
+func (this *FrameRateGovernor) LimitFPS(fps float64) bool {
+
+	this.limitFPS = math.Max(this.minFPS, math.Min(this.maxFPS, fps))
+	if this.fps > this.limitFPS {
+		this.setFPS(this.limitFPS)
+		return true
+	}
+	return false
+}
+
+// Target frame rate
+func (this *FrameRateGovernor) FPS() float64 {
+	return this.fps
+}
+
+// Time between frames at the target frame rate
+func (this *FrameRateGovernor) FrameTime() time.Duration {
+	return time.Duration(float64(time.Second) / this.fps)
+}
+
+// Longest time a single frame should move the game forward, longer gaps slow the game down instead of skipping ahead
+func (this *FrameRateGovernor) MaxDt() float64 {
+	return 1 / this.minFPS
+}
+
+// True when frames are over budget even at the lowest frame rate, so expensive effects should be skipped
+func (this *FrameRateGovernor) Overloaded() bool {
+	return this.overloaded
+}
@@ -0,0 +1,62 @@
+package pong
+
+import (
+	"testing"
+)
+
+// Slow frames should lower the frame rate to the minimum, and fast frames should bring it back up
+func Test_FrameRateGovernor(t *testing.T) {
+	governor := NewFrameRateGovernor(20, 60)
+
+	for frame := 0; frame < 1000; frame++ {
+		governor.Update(0.06)
+	}
+	Assert(int(governor.FPS()), 20, "FPS under load", t)
+	if !governor.Overloaded() {
+		t.Fatal("Not overloaded with frames over budget at the minimum rate")
+	}
+
+	for frame := 0; frame < 1000; frame++ {
+		governor.Update(0.001)
+	}
+	Assert(int(governor.FPS()), 60, "FPS without load", t)
+	if governor.Overloaded() {
+		t.Fatal("Still overloaded")
+	}
+}
+
+// The rate should hold steady when frames fit in the budget
+func Test_FrameRateGovernor_Steady(t *testing.T) {
+	governor := NewFrameRateGovernor(20, 60)
+
+	for frame := 0; frame < 1000; frame++ {
+		if governor.Update(0.012) {
+			t.Fatal("Frame rate changed to", governor.FPS())
+		}
+	}
+}
+
+// A limit should lower the rate straight away and keep it from rising above the limit until it is lifted
+func Test_FrameRateGovernor_Limit(t *testing.T) {
+	governor := NewFrameRateGovernor(20, 60)
+
+	if !governor.LimitFPS(30) {
+		t.Fatal("Limit didn't change the frame rate")
+	}
+	Assert(int(governor.FPS()), 30, "Limited FPS", t)
+	for frame := 0; frame < 1000; frame++ {
+		governor.Update(0.001)
+	}
+	Assert(int(governor.FPS()), 30, "Limited FPS without load", t)
+
+	governor.LimitFPS(10)
+	Assert(int(governor.FPS()), 20, "FPS limited below the minimum", t)
+
+	if governor.LimitFPS(100) {
+		t.Fatal("Lifting the limit changed the frame rate straight away")
+	}
+	for frame := 0; frame < 1000; frame++ {
+		governor.Update(0.001)
+	}
+	Assert(int(governor.FPS()), 60, "FPS once the limit is lifted", t)
+}
@@ -0,0 +1,69 @@
+// Package game gathers the rules engine, the game modes and the phases a game moves through, so other projects can add
+// modes or run games of their own. The names are part of the stable API described in the pong package
+package game
+
+import (
+	"github.com/brandonagr/pongpi/src/pong"
+)
+
+// Game modes and the optional interfaces a mode can implement
+type (
+	GameMode           = pong.GameMode
+	SummarizedGameMode = pong.SummarizedGameMode
+	DownButtonGameMode = pong.DownButtonGameMode
+	RecordedGameMode   = pong.RecordedGameMode
+	RatedGameMode      = pong.RatedGameMode
+	StatsGameMode      = pong.StatsGameMode
+	ScoredGameMode     = pong.ScoredGameMode
+	ContextGameMode    = pong.ContextGameMode
+	TopologyGameMode   = pong.TopologyGameMode
+	GameModeFactory    = pong.GameModeFactory
+	GameConfig         = pong.GameConfig
+	GameStats          = pong.GameStats
+	GameOutcome        = pong.GameOutcome
+	Phase              = pong.Phase
+	PhaseHook          = pong.PhaseHook
+	PhaseUpdate        = pong.PhaseUpdate
+	StateMachine       = pong.StateMachine
+	FixedTimestep      = pong.FixedTimestep
+)
+
+// How a game stands after a tick
+const (
+	GameInProgress  = pong.GameInProgress
+	GamePointScored = pong.GamePointScored
+	GameLeftWon     = pong.GameLeftWon
+	GameRightWon    = pong.GameRightWon
+	GameFinished    = pong.GameFinished
+)
+
+// Every phase of a game
+const (
+	PhaseIdle              = pong.PhaseIdle
+	PhaseWaitingForPlayers = pong.PhaseWaitingForPlayers
+	PhaseCountdown         = pong.PhaseCountdown
+	PhaseRally             = pong.PhaseRally
+	PhasePointScored       = pong.PhasePointScored
+	PhaseGameOver          = pong.PhaseGameOver
+	PhaseLeaderboard       = pong.PhaseLeaderboard
+)
+
+// Make a mode available by name, shown in the menu in color, call from init of the package implementing the mode
+func RegisterGameMode(name string, color pong.RGBA, factory GameModeFactory) {
+	pong.RegisterGameMode(name, color, factory)
+}
+
+// Create the mode registered as name, false if there isn't one
+func NewGameMode(name string) (GameMode, bool) {
+	return pong.NewGameMode(name)
+}
+
+// Construct a StateMachine starting in the idle phase
+func NewStateMachine() *StateMachine {
+	return pong.NewStateMachine()
+}
+
+// Construct a FixedTimestep stepping hz times a second
+func NewFixedTimestep(hz float64) *FixedTimestep {
+	return pong.NewFixedTimestep(hz)
+}
@@ -2,34 +2,116 @@ package pong
 
 import (
 	"container/list"
+	"math"
+	"sync"
 )
 
 // Defines all of the information
 type GameField struct {
 
-	// Size of the field, from 0 to width exclusive
-	width int
+	// Size of the field, from 0 to width exclusive, and rows of leds, 1 for a strip
+	width  int
+	height int
+
+	// how the ends are joined, a line unless set
+	topology Topology
 
 	// All of the drawable items, stored in increasing ZIndex order
 	drawables *list.List
 
 	// Buffer used to render the field
 	renderBuffer []RGBA
+
+	// Drawables and their bounds for the frame being rendered, in increasing ZIndex order
+	layers []fieldLayer
+
+	// State of each tracked drawable when the field was last rendered
+	tracked map[Drawable]trackedState
+
+	// If every position has to be rendered again, such as after a drawable is removed
+	allDirty bool
+}
+
+// A drawable and the range of positions it covers this frame
+type fieldLayer struct {
+	drawable    Drawable
+	left, right float64
 }
 
+// What a tracked drawable looked like when it was last rendered
+type trackedState struct {
+	version     uint64
+	left, right float64
+}
+
+var _ Field = &GameField{}
+
 // Initialized a new field
 func NewGameField(width int) *GameField {
+	return NewMatrixField(width, 1)
+}
+
+// Initialize a new field of height rows of width leds each, rendered a row at a time from the top
+func NewMatrixField(width, height int) *GameField {
 
+	if height < 1 {
+		height = 1
+	}
 	return &GameField{
 		width:        width,
+		height:       height,
 		drawables:    list.New(),
-		renderBuffer: make([]RGBA, width),
+		renderBuffer: takeFrame(width * height),
+		tracked:      make(map[Drawable]trackedState),
+		allDirty:     true,
+	}
+}
+
+// Frames of released fields by length, reused by new fields so changing scenes doesn't allocate
+var framePool = struct {
+	sync.Mutex
+	free map[int][][]RGBA
+}{free: make(map[int][][]RGBA)}
+
+// Get a frame of length leds from the pool, or allocate one if there are none free
+func takeFrame(length int) []RGBA {
+
+	framePool.Lock()
+	defer framePool.Unlock()
+
+	free := framePool.free[length]
+	if len(free) == 0 {
+		return make([]RGBA, length)
+	}
+
+	frame := free[len(free)-1]
+	framePool.free[length] = free[:len(free)-1]
+	return frame
+}
+
+// Give the render buffer back to be reused and take every drawable off, the field can't be rendered afterwards
+func (field *GameField) Release() {
+
+	if field.renderBuffer == nil {
+		return
+	}
+
+	for curElement := field.drawables.Front(); curElement != nil; curElement = curElement.Next() {
+		NotifyRemoved(curElement.Value.(Drawable), field)
 	}
+
+	framePool.Lock()
+	length := len(field.renderBuffer)
+	framePool.free[length] = append(framePool.free[length], field.renderBuffer)
+	framePool.Unlock()
+
+	field.renderBuffer = nil
 }
 
 // Adds a drawable to the field
 func (field *GameField) Add(addDrawable Drawable) {
 
+	NotifyAdded(addDrawable, field)
 	curElement := field.drawables.Front()
 
 	if curElement == nil {
@@ -60,22 +142,36 @@ func (field *GameField) ColorAt(position float64) RGBA {
 
 		drawable := curElement.Value.(Drawable)
 
+		if bounded, ok := drawable.(BoundedDrawable); ok {
+			if left, right := bounded.Bounds(); position < left || position > right {
+				continue
+			}
+		}
+
 		color = drawable.ColorAt(position, color)
 	}
 
 	return color
 }
 
-// Animate all Drawables
+// Animate all Drawables outside of a game
 func (field *GameField) Animate(dt float64) {
+	field.AnimateInContext(dt, AnimateContext{})
+}
+
+// Animate all Drawables while the game stands as context says
+func (field *GameField) AnimateInContext(dt float64, context AnimateContext) {
 
 	for curElement := field.drawables.Front(); curElement != nil; {
 
 		drawable := curElement.Value.(Drawable)
 
-		if !drawable.Animate(dt) {
+		if !AnimateDrawable(drawable, dt, context) {
 			nextElement := curElement.Next()
 			field.drawables.Remove(curElement)
+			delete(field.tracked, drawable)
+			NotifyRemoved(drawable, field)
+			field.allDirty = true
 			curElement = nextElement
 		} else {
 			curElement = curElement.Next()
@@ -83,13 +179,134 @@ func (field *GameField) Animate(dt float64) {
 	}
 }
 
+// Draw every InterpolatedDrawable at alpha of the way between its last two steps
+func (field *GameField) Interpolate(alpha float64) {
+
+	for curElement := field.drawables.Front(); curElement != nil; curElement = curElement.Next() {
+		if drawable, ok := curElement.Value.(InterpolatedDrawable); ok {
+			drawable.Interpolate(alpha)
+		}
+	}
+}
+
 // Render each integer position and pass that to the Display
 func (field *GameField) RenderTo(display Display) {
+	display.Render(field.Render())
+}
+
+// Render each integer position, the returned buffer is reused by the next Render
+func (field *GameField) Render() []RGBA {
+
+	field.updateLayers()
+	start, end := field.dirtyRange()
+	if start >= end {
+		return field.renderBuffer
+	}
+
+	if renderPool != nil {
+		renderPool.Render(field, field.renderBuffer, start, end)
+	} else {
+		field.renderRange(field.renderBuffer, start, end)
+	}
+	return field.renderBuffer
+}
+
+// Get the bounds of every drawable for this frame, unbounded drawables cover the whole field
+func (field *GameField) updateLayers() {
+
+	field.layers = field.layers[:0]
+	for curElement := field.drawables.Front(); curElement != nil; curElement = curElement.Next() {
+
+		layer := fieldLayer{curElement.Value.(Drawable), math.Inf(-1), math.Inf(1)}
+		if bounded, ok := layer.drawable.(BoundedDrawable); ok {
+			layer.left, layer.right = bounded.Bounds()
+		}
+		field.layers = append(field.layers, layer)
+	}
+}
+
+// Range of integer positions that may have changed since the last Render, from start to end exclusive
+func (field *GameField) dirtyRange() (start, end int) {
+
+	allDirty := field.allDirty
+	field.allDirty = false
+
+	dirtyLeft, dirtyRight := math.Inf(1), math.Inf(-1)
+
+	for _, layer := range field.layers {
+
+		tracked, ok := layer.drawable.(TrackedDrawable)
+		if !ok {
+			// nothing is known about when it changes
+			allDirty = true
+			continue
+		}
+
+		current := trackedState{tracked.Version(), layer.left, layer.right}
+		previous, seen := field.tracked[tracked]
+		field.tracked[tracked] = current
+
+		if !seen {
+			allDirty = true
+		} else if current != previous {
+			dirtyLeft = math.Min(dirtyLeft, math.Min(previous.left, current.left))
+			dirtyRight = math.Max(dirtyRight, math.Max(previous.right, current.right))
+		}
+	}
+
+	if allDirty {
+		return 0, field.width
+	}
+	if dirtyLeft > dirtyRight {
+		return 0, 0
+	}
+
+	start = int(math.Max(0, math.Floor(dirtyLeft)))
+	end = int(math.Min(float64(field.width), math.Ceil(dirtyRight)+1))
+	return
+}
 
-	for ledIndex := 0; ledIndex < field.width; ledIndex++ {
-		field.renderBuffer[ledIndex] = field.ColorAt(float64(ledIndex))
+// Render the integer positions from start to end exclusive into buffer, on a matrix every row of those columns
+func (field *GameField) renderRange(buffer []RGBA, start, end int) {
+
+	if field.height > 1 {
+		field.renderColumns(buffer, start, end)
+		return
+	}
+
+	for ledIndex := start; ledIndex < end; ledIndex++ {
+		position := float64(ledIndex)
+
+		color := RGBA{0, 0, 0, 255}
+		for _, layer := range field.layers {
+			if layer.left <= position && position <= layer.right {
+				color = layer.drawable.ColorAt(position, color)
+			}
+		}
+		buffer[ledIndex] = color
+	}
+}
+
+// Render every row of the columns from start to end exclusive into buffer, drawables that don't implement Drawable2D
+// look the same on every row
+func (field *GameField) renderColumns(buffer []RGBA, start, end int) {
+
+	for row := 0; row < field.height; row++ {
+		y := float64(row)
+		for column := start; column < end; column++ {
+			x := float64(column)
+
+			color := RGBA{0, 0, 0, 255}
+			for _, layer := range field.layers {
+				if drawable, ok := layer.drawable.(Drawable2D); ok {
+					color = drawable.ColorAt2D(x, y, color)
+				} else if layer.left <= x && x <= layer.right {
+					color = layer.drawable.ColorAt(x, color)
+				}
+			}
+			buffer[row*field.width+column] = color
+		}
 	}
-	display.Render(field.renderBuffer)
 }
 
 // Returns true if the field of drawables is valid
@@ -113,6 +330,16 @@ func (field *GameField) IsValid() bool {
 	return true
 }
 
+// All of the drawables in increasing ZIndex order
+func (field *GameField) Drawables() []Drawable {
+
+	drawables := make([]Drawable, 0, field.drawables.Len())
+	for curElement := field.drawables.Front(); curElement != nil; curElement = curElement.Next() {
+		drawables = append(drawables, curElement.Value.(Drawable))
+	}
+	return drawables
+}
+
 // Return number of drawables in the field
 func (field *GameField) DrawableLen() int {
 	return field.drawables.Len()
@@ -122,3 +349,18 @@ func (field *GameField) DrawableLen() int {
 func (field *GameField) Width() int {
 	return field.width
 }
+
+// Rows of leds in the field, 1 for a strip
+func (field *GameField) Height() int {
+	return field.height
+}
+
+// How the ends of the field are joined
+func (field *GameField) Topology() Topology {
+	return field.topology
+}
+
+// Join the ends of the field as topology
+func (field *GameField) SetTopology(topology Topology) {
+	field.topology = topology
+}
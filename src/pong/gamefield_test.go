@@ -22,6 +22,80 @@ func (countdown *CountdownDrawable) Animate(dt float64) (keepAlive bool) {
 	return countdown.curLife < countdown.maxLife
 }
 
+// Remembers the context it was last animated in
+type contextRecorder struct {
+	CountdownDrawable
+	context AnimateContext
+}
+
+func (recorder *contextRecorder) AnimateInContext(dt float64, context AnimateContext) bool {
+	recorder.context = context
+	return recorder.Animate(dt)
+}
+
+// Drawables that react to the game should be given the context the field is animated in, through lanes and effect
+// stacks, and an idle context without one
+func Test_GameField_AnimateInContext(t *testing.T) {
+
+	field := NewGameField(10)
+	newRecorder := func() *contextRecorder {
+		return &contextRecorder{CountdownDrawable: CountdownDrawable{maxLife: 1}}
+	}
+	direct, inLane, stacked := newRecorder(), newRecorder(), newRecorder()
+	field.Add(direct)
+	field.Add(InLane(inLane, 0))
+	field.Add(&EffectStack{layers: []Drawable{stacked}})
+
+	context := AnimateContext{Phase: PhaseRally, LeftScore: 2, RightScore: 1, MatchPoint: true, BallPosition: 4.5, HasBall: true}
+	field.AnimateInContext(0.1, context)
+	for _, recorder := range []*contextRecorder{direct, inLane, stacked} {
+		if recorder.context != context || recorder.curLife != 0.1 {
+			t.Fatal("Drawable was animated in", recorder.context, "for", recorder.curLife)
+		}
+	}
+
+	field.Animate(0.1)
+	if direct.context != (AnimateContext{}) || direct.context.Phase != PhaseIdle {
+		t.Fatal("Field animated outside a game gave", direct.context)
+	}
+}
+
+// Counts the fields it is on
+type lifecycleCounter struct {
+	CountdownDrawable
+	fields int
+}
+
+func (counter *lifecycleCounter) OnAdd(field Field) {
+	counter.fields++
+}
+
+func (counter *lifecycleCounter) OnRemove(field Field) {
+	counter.fields--
+}
+
+// Drawables should be told when they're added to a field and when they're taken off, by finishing or by the field
+// being released, through lanes and effect stacks
+func Test_GameField_Lifecycle(t *testing.T) {
+
+	finishing := &lifecycleCounter{CountdownDrawable: CountdownDrawable{maxLife: 1}}
+	lasting := &lifecycleCounter{CountdownDrawable: CountdownDrawable{maxLife: 10}}
+	stacked := &lifecycleCounter{CountdownDrawable: CountdownDrawable{maxLife: 10}}
+
+	field := NewGameField(10)
+	field.Add(InLane(finishing, 0))
+	field.Add(lasting)
+	field.Add(&EffectStack{layers: []Drawable{stacked}})
+	Assert(finishing.fields+lasting.fields+stacked.fields, 3, "Drawables added", t)
+
+	field.Animate(1)
+	Assert(finishing.fields, 0, "Finished drawable on fields", t)
+	Assert(lasting.fields, 1, "Lasting drawable on fields", t)
+
+	field.Release()
+	Assert(lasting.fields+stacked.fields, 0, "Drawables on a released field", t)
+}
+
 // Animate call to a Drawable that returns false should result in that drawable being removed from the field
 func Test_GameField_AddAnimate(t *testing.T) {
 	field := NewGameField(100)
@@ -58,6 +132,76 @@ func Test_GameField_AddAnimate(t *testing.T) {
 	Assert(field.DrawableLen(), 0, "Field should be empty", t)
 }
 
+// Drawable covering a small range that counts how often it is asked for a color
+type DotDrawable struct {
+	position float64
+	version  uint64
+	calls    int
+}
+
+func (dot *DotDrawable) ColorAt(position float64, baseColor RGBA) RGBA {
+	dot.calls++
+	if position == dot.position {
+		return RGBA{255, 255, 255, 255}
+	}
+	return baseColor
+}
+
+func (dot *DotDrawable) ZIndex() ZIndex {
+	return 1
+}
+
+func (dot *DotDrawable) Animate(dt float64) (keepAlive bool) {
+	return true
+}
+
+func (dot *DotDrawable) Bounds() (left, right float64) {
+	return dot.position - 5, dot.position + 5
+}
+
+func (dot *DotDrawable) Version() uint64 {
+	return dot.version
+}
+
+// Only positions a tracked drawable moved between should be rendered again, and only inside its bounds
+func Test_GameField_DirtyRender(t *testing.T) {
+	field := NewGameField(100)
+	dot := &DotDrawable{position: 10}
+	field.Add(dot)
+
+	field.Render()
+	Assert(dot.calls, 11, "First render calls", t)
+
+	dot.calls = 0
+	field.Render()
+	Assert(dot.calls, 0, "Unchanged render calls", t)
+
+	dot.calls = 0
+	dot.position = 20
+	dot.version++
+	frame := field.Render()
+	Assert(dot.calls, 11, "Calls after moving", t)
+	Assert(int(frame[10].R), 0, "Old position cleared", t)
+	Assert(int(frame[20].R), 255, "New position drawn", t)
+
+	// on a matrix every row of the columns that changed is rendered again
+	matrix := NewMatrixField(100, 3)
+	dot = &DotDrawable{position: 10}
+	matrix.Add(dot)
+	matrix.Render()
+
+	dot.calls = 0
+	matrix.Render()
+	Assert(dot.calls, 0, "Unchanged matrix render calls", t)
+
+	dot.position = 20
+	dot.version++
+	frame = matrix.Render()
+	Assert(dot.calls, 33, "Matrix calls after moving", t)
+	Assert(int(frame[200+10].R), 0, "Old position cleared on the last row", t)
+	Assert(int(frame[200+20].R), 255, "New position drawn on the last row", t)
+}
+
 // Helper assert method
 func Assert(actual, expected int, message string, t *testing.T) {
 	if actual != expected {
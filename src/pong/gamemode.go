@@ -0,0 +1,218 @@
+package pong
+
+import (
+	"log"
+
+	"github.com/brandonagr/pongpi/src/pong/stats"
+)
+
+// Options chosen for a single game, each mode uses the ones that apply to it
+type GameConfig struct {
+
+	// every player is controlled by the AI until a button is pressed
+	Demo bool
+
+	// name of the AI personality chosen from the difficulty menu
+	Difficulty string
+
+	// recorded game to play against, nil if there isn't one
+	Ghost *GameRecording
+
+	// recorded game to play back, nil if there isn't one
+	Replay *GameRecording
+
+	// people playing at the left and right buttons, guests if not set
+	LeftProfile, RightProfile PlayerProfile
+
+	// players waiting their turn at king of the hill, nil if there isn't a queue
+	Hill *HillQueue
+
+	// phones pushing the buttons of each side in the crowd mode, nil if there isn't a crowd
+	Crowd *CrowdInput
+}
+
+// How a game stands after a tick
+type GameOutcome int
+
+const (
+	GameInProgress GameOutcome = iota
+	GamePointScored
+	GameLeftWon
+	GameRightWon
+	GameFinished
+)
+
+// A set of rules for playing a game on the field
+type GameMode interface {
+
+	// Add the drawables of a new game to field
+	Setup(field Field, config GameConfig)
+
+	// Use the state of the buttons for this frame
+	HandleInput(left, right bool)
+
+	// Advance the game by dt seconds
+	Tick(dt float64) GameOutcome
+
+	// Drawables added by the mode
+	Drawables() []Drawable
+}
+
+// Implemented by modes that have something to say when the game ends
+type SummarizedGameMode interface {
+	GameMode
+
+	// Text spoken and logged once the game ends
+	Summary() string
+}
+
+// Implemented by modes that use a second button for each player when there is one
+type DownButtonGameMode interface {
+	GameMode
+
+	// Use the state of the second buttons for this frame, called before HandleInput
+	HandleDownInput(leftDown, rightDown bool)
+}
+
+// Implemented by modes that record the buttons pressed during a game
+type RecordedGameMode interface {
+	GameMode
+
+	// Recording of the game so far
+	Recording() *GameRecording
+}
+
+// Implemented by modes whose results count towards player ratings
+type RatedGameMode interface {
+	GameMode
+
+	// Names of the left and right players, rated is false if this game shouldn't count
+	PlayerNames() (left, right string, rated bool)
+}
+
+// Records set during a single game
+type GameStats struct {
+
+	// speed of the fastest ball a player hit back, in leds / second
+	FastestReturn float64
+
+	// points won by each side
+	LeftScore, RightScore int
+
+	// bounces in each finished rally, in the order they were played
+	Rallies []int
+
+	// every time the ball reached a player, for balancing the rules
+	Hits []stats.Hit
+}
+
+// Implemented by modes that keep track of records set during the game
+type StatsGameMode interface {
+	GameMode
+
+	// Records set in the game so far
+	Stats() GameStats
+}
+
+// Implemented by modes played for a high score rather than against the other side
+type ScoredGameMode interface {
+	GameMode
+
+	// Score reached in the game so far
+	Score() int
+}
+
+// Implemented by modes with a ball or a deciding point that drawables can react to
+type ContextGameMode interface {
+	GameMode
+
+	// Fill in the ball and match point of context
+	UpdateContext(context *AnimateContext)
+}
+
+// Implemented by modes played around a loop, every other mode is played on a line even when the strip is a loop, as
+// their ball has to go past a player to score
+type TopologyGameMode interface {
+	GameMode
+
+	// How the ends of the field the mode is played on are joined
+	Topology() Topology
+}
+
+// How the ends of the field mode is played on are joined, a loop only when the mode asks for one and the strip in
+// settings is one
+func FieldTopologyFor(mode GameMode, settings SettingsData) Topology {
+	if looped, ok := mode.(TopologyGameMode); ok && looped.Topology() == TopologyLoop {
+		return settings.FieldTopology()
+	}
+	return TopologyLine
+}
+
+// Move mode forward a single step of dt seconds with buttons as they are held now, calling animate to move the field
+// between taking the input and the tick. The game loop and the scripted games of pong/harness both play rallies
+// through this so they play by the same rules
+func StepRally(mode GameMode, buttons ButtonInput, dt float64, animate func(dt float64)) GameOutcome {
+
+	if downMode, ok := mode.(DownButtonGameMode); ok {
+		if down, ok := buttons.(DownButtonInput); ok && down.HasDownButtons() {
+			downMode.HandleDownInput(down.LeftDownButton(), down.RightDownButton())
+		}
+	}
+	mode.HandleInput(buttons.LeftButton(), buttons.RightButton())
+	animate(dt)
+	return mode.Tick(dt)
+}
+
+// Phase a rally moves to after a step ends with outcome, false while the rally carries on
+func PhaseAfter(outcome GameOutcome) (Phase, bool) {
+	switch outcome {
+	case GamePointScored:
+		return PhasePointScored, true
+	case GameLeftWon, GameRightWon, GameFinished:
+		return PhaseGameOver, true
+	}
+	return PhaseRally, false
+}
+
+// Creates a GameMode ready to be set up
+type GameModeFactory func() GameMode
+
+// A mode that can be chosen from the menu
+type registeredGameMode struct {
+	name    string
+	color   RGBA
+	factory GameModeFactory
+}
+
+// every registered mode in the order it was registered
+var gameModes []registeredGameMode
+
+// Make a mode available by name, shown in the menu in color, call from init of the package implementing the mode
+func RegisterGameMode(name string, color RGBA, factory GameModeFactory) {
+
+	for _, mode := range gameModes {
+		if mode.name == name {
+			log.Panic("Game mode ", name, " registered twice")
+		}
+	}
+
+	gameModes = append(gameModes, registeredGameMode{name, color, factory})
+}
+
+// Names and colors of every registered mode, in the order they were registered
+func GameModeOptions() (options []MenuOption) {
+	for _, mode := range gameModes {
+		options = append(options, MenuOption{mode.name, mode.color})
+	}
+	return
+}
+
+// Create the mode registered as name, returns false if there isn't one
+func NewGameMode(name string) (GameMode, bool) {
+	for _, mode := range gameModes {
+		if mode.name == name {
+			return mode.factory(), true
+		}
+	}
+	return nil, false
+}
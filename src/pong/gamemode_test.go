@@ -0,0 +1,44 @@
+package pong
+
+import (
+	"testing"
+)
+
+// Mode that finishes on the first tick
+type FinishedMode struct {
+	setup bool
+}
+
+func (this *FinishedMode) Setup(field Field, config GameConfig) { this.setup = true }
+func (this *FinishedMode) HandleInput(left, right bool)         {}
+func (this *FinishedMode) Tick(dt float64) GameOutcome          { return GameFinished }
+func (this *FinishedMode) Drawables() []Drawable                { return nil }
+
+// Registered modes should be listed in order and created by name
+func Test_GameMode_Registry(t *testing.T) {
+	previous := gameModes
+	defer func() { gameModes = previous }()
+	gameModes = nil
+
+	RegisterGameMode("first", RGBA{255, 0, 0, 255}, func() GameMode { return &FinishedMode{} })
+	RegisterGameMode("second", RGBA{0, 255, 0, 255}, func() GameMode { return &FinishedMode{} })
+
+	options := GameModeOptions()
+	Assert(len(options), 2, "Number of modes", t)
+	if options[0].Name != "first" || options[1].Name != "second" {
+		t.Fatal("Modes out of order", options)
+	}
+
+	mode, ok := NewGameMode("second")
+	if !ok {
+		t.Fatal("Registered mode not found")
+	}
+	mode.Setup(NewGameField(10), GameConfig{})
+	if !mode.(*FinishedMode).setup || mode.Tick(0.1) != GameFinished {
+		t.Fatal("Mode not created from its factory")
+	}
+
+	if _, ok := NewGameMode("missing"); ok {
+		t.Fatal("Found a mode that wasn't registered")
+	}
+}
@@ -0,0 +1,67 @@
+package pong
+
+import (
+	"math"
+)
+
+// Place along the field in leds, the direction the ball travels
+type Position float64
+
+// Speed along the field in leds / second, negative toward the left
+type Velocity float64
+
+// Part of the field from Left to Right, both included
+type Extent struct {
+	Left, Right Position
+}
+
+// The position moved onto a loop of width leds, from 0 to width exclusive
+func (this Position) Wrap(width float64) Position {
+	return this - Position(math.Floor(float64(this)/width)*width)
+}
+
+// The nearest position inside extent
+func (this Position) Clamp(extent Extent) Position {
+	return Position(math.Max(float64(extent.Left), math.Min(float64(extent.Right), float64(this))))
+}
+
+// The position mirrored across edge, where something that went past edge ends up once it bounces off it
+func (this Position) ReflectOff(edge Position) Position {
+	return edge + (edge - this)
+}
+
+// How far the position is from origin, negative when it is to the left, the short way around on a loop of width leds
+func (this Position) OffsetFrom(origin Position, topology Topology, width float64) float64 {
+	offset := float64(this - origin)
+	if topology == TopologyLoop {
+		offset -= math.Floor(offset/width+0.5) * width
+	}
+	return offset
+}
+
+// How far the position is from other either way, the short way around on a loop of width leds
+func (this Position) DistanceTo(other Position, topology Topology, width float64) float64 {
+	return math.Abs(this.OffsetFrom(other, topology, width))
+}
+
+// If position is inside the extent
+func (this Extent) Contains(position Position) bool {
+	return this.Left <= position && position <= this.Right
+}
+
+// Leds from one end of the extent to the other
+func (this Extent) Width() float64 {
+	return float64(this.Right - this.Left)
+}
+
+// Where something at position moving at velocity ends up after bouncing off whichever end of extent it went past,
+// turned around if it bounced
+func Reflect(position Position, velocity Velocity, extent Extent) (Position, Velocity) {
+	switch {
+	case position > extent.Right:
+		return position.ReflectOff(extent.Right), -velocity
+	case position < extent.Left:
+		return position.ReflectOff(extent.Left), -velocity
+	}
+	return position, velocity
+}
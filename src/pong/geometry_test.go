@@ -0,0 +1,43 @@
+package pong
+
+import (
+	"testing"
+)
+
+// Positions should wrap, clamp and reflect, and distances should go the short way around a loop
+func Test_Geometry(t *testing.T) {
+
+	for _, wrap := range []struct{ position, wrapped Position }{{-1, 9}, {10, 0}, {23.5, 3.5}, {4, 4}} {
+		if wrapped := wrap.position.Wrap(10); wrapped != wrap.wrapped {
+			t.Fatal(wrap.position, "wrapped to", wrapped)
+		}
+	}
+
+	extent := Extent{2, 8}
+	if Position(1).Clamp(extent) != 2 || Position(9).Clamp(extent) != 8 || Position(5).Clamp(extent) != 5 {
+		t.Fatal("Clamped outside", extent)
+	}
+	if !extent.Contains(2) || !extent.Contains(8) || extent.Contains(8.5) || extent.Width() != 6 {
+		t.Fatal("Extent", extent, "is wrong")
+	}
+
+	if offset := Position(9).OffsetFrom(1, TopologyLine, 10); offset != 8 {
+		t.Fatal("Offset along a line is", offset)
+	}
+	if offset := Position(9).OffsetFrom(1, TopologyLoop, 10); offset != -2 {
+		t.Fatal("Offset around a loop is", offset)
+	}
+	if distance := Position(1).DistanceTo(9, TopologyLoop, 10); distance != 2 {
+		t.Fatal("Distance around a loop is", distance)
+	}
+
+	if position, velocity := Reflect(9, 5, extent); position != 7 || velocity != -5 {
+		t.Fatal("Reflected off the right end to", position, velocity)
+	}
+	if position, velocity := Reflect(1.5, -5, extent); position != 2.5 || velocity != 5 {
+		t.Fatal("Reflected off the left end to", position, velocity)
+	}
+	if position, velocity := Reflect(5, 5, extent); position != 5 || velocity != 5 {
+		t.Fatal("Reflected inside the extent to", position, velocity)
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/brandonagr/pongpi/src/pong
+
+go 1.21
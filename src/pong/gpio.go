@@ -3,7 +3,9 @@
 package pong
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"os/exec"
 )
@@ -15,43 +17,155 @@ type GpioReader struct {
 	rightButtonFile *os.File
 	rightPrevious   bool
 	data            []byte
+
+	// second button of each player, nil if it isn't wired up
+	leftDownFile, rightDownFile *os.File
+
+	// first error reading a button since ReadError was last called
+	readErr error
 }
 
+var _ DownButtonInput = &GpioReader{}
+var _ FallibleButtonInput = &GpioReader{}
+
 // exports already run: gpio export 27 in and gpio export 22 in
-func NewGpioReader(settings SettingsData) *GpioReader {
+func NewGpioReader(settings SettingsData) (*GpioReader, error) {
 
 	reader := &GpioReader{
 		data: make([]byte, 32),
 	}
 
-	var err error
-	_, err = os.Stat(settings.LeftButtonPath)
-	if err != nil && os.IsNotExist(err) {
-		cmd := exec.Command("/usr/local/bin/gpio", "export", settings.LeftButtonGpioPort, "in")
-		err = cmd.Run()
+	buttons := []struct {
+		file             **os.File
+		name, path, port string
+		required         bool
+	}{
+		{&reader.leftButtonFile, "Left button", settings.LeftButtonPath, settings.LeftButtonGpioPort, true},
+		{&reader.rightButtonFile, "Right button", settings.RightButtonPath, settings.RightButtonGpioPort, true},
+		{&reader.leftDownFile, "Left down button", settings.LeftDownButtonPath, settings.LeftDownButtonGpioPort, false},
+		{&reader.rightDownFile, "Right down button", settings.RightDownButtonPath, settings.RightDownButtonGpioPort, false},
+	}
+	for _, button := range buttons {
+		if button.required && button.path == "" {
+			reader.Close()
+			return nil, fmt.Errorf("%v on gpio %v has no value file path set", button.name, button.port)
+		}
+		file, err := openButton(button.name, button.path, button.port)
 		if err != nil {
-			log.Fatal(err)
+			reader.Close()
+			return nil, err
 		}
+		*button.file = file
 	}
-	reader.leftButtonFile, err = os.Open(settings.LeftButtonPath)
+
+	return reader, nil
+}
+
+// Export port and open the value file of the button called name at path, nil if there's no path
+func openButton(name, path, port string) (*os.File, error) {
+
+	if path == "" {
+		return nil, nil
+	}
+	if _, err := os.Stat(path); err != nil && os.IsNotExist(err) {
+		if err := exec.Command(GpioCommand, "export", port, "in").Run(); err != nil {
+			return nil, fmt.Errorf("%v on gpio %v: exporting the pin with %v: %v", name, port, GpioCommand, err)
+		}
+	}
+	file, err := os.Open(path)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("%v on gpio %v: %v", name, port, err)
 	}
+	return file, nil
+}
+
+// Export and open the pins of the rotary encoder in settings, nil if it isn't wired up
+func NewGpioEncoder(settings SettingsData) (*RotaryEncoder, error) {
 
-	_, err = os.Stat(settings.RightButtonPath)
-	if err != nil && os.IsNotExist(err) {
-		cmd := exec.Command("/usr/local/bin/gpio", "export", settings.RightButtonGpioPort, "in")
-		err = cmd.Run()
+	if settings.EncoderAPath == "" {
+		return nil, nil
+	}
+
+	var files []*os.File
+	closeAll := func() {
+		for _, file := range files {
+			if file != nil {
+				file.Close()
+			}
+		}
+	}
+	for _, pin := range []struct {
+		name, path, port string
+		required         bool
+	}{
+		{"Encoder pin A", settings.EncoderAPath, settings.EncoderAGpioPort, true},
+		{"Encoder pin B", settings.EncoderBPath, settings.EncoderBGpioPort, true},
+		{"Encoder button", settings.EncoderButtonPath, settings.EncoderButtonGpioPort, false},
+	} {
+		if pin.required && pin.path == "" {
+			closeAll()
+			return nil, fmt.Errorf("%v on gpio %v has no value file path set", pin.name, pin.port)
+		}
+		file, err := openButton(pin.name, pin.path, pin.port)
 		if err != nil {
-			log.Fatal(err)
+			closeAll()
+			return nil, err
 		}
+		files = append(files, file)
+	}
+
+	var button io.ReadSeeker
+	closers := []io.Closer{files[0], files[1]}
+	if files[2] != nil {
+		button = files[2]
+		closers = append(closers, files[2])
+	}
+	return NewRotaryEncoder(files[0], files[1], button, settings.EncoderStepsPerDetent, closers...), nil
+}
+
+// State of the button read from file, false if it isn't wired up or can't be read, keeping the error for ReadError
+func (this *GpioReader) readButton(file *os.File) bool {
+
+	if file == nil {
+		return false
+	}
+	count, err := file.Read(this.data)
+	if err == nil && count != 2 {
+		err = fmt.Errorf("Expected 2 bytes for %v read and got %v", file.Name(), count)
+	}
+	if _, seekErr := file.Seek(0, 0); err == nil {
+		err = seekErr
 	}
-	reader.rightButtonFile, err = os.Open(settings.RightButtonPath)
 	if err != nil {
-		log.Fatal(err)
+		if this.readErr == nil {
+			this.readErr = err
+		}
+		return false
 	}
 
-	return reader
+	return this.data[0] == 48 // ascii '0'
+}
+
+// First error reading a button since the last call, nil if every read worked
+func (this *GpioReader) ReadError() error {
+	err := this.readErr
+	this.readErr = nil
+	return err
+}
+
+// Both players have a second button
+func (this *GpioReader) HasDownButtons() bool {
+	return this.leftDownFile != nil && this.rightDownFile != nil
+}
+
+// Get state of the left player's second button
+func (this *GpioReader) LeftDownButton() bool {
+	return this.readButton(this.leftDownFile)
+}
+
+// Get state of the right player's second button
+func (this *GpioReader) RightDownButton() bool {
+	return this.readButton(this.rightDownFile)
 }
 
 type ButtonEvent int
@@ -62,64 +176,26 @@ const (
 	ButtonRelease
 )
 
-// get state of the right button
+// Get state of the left button
 func (this *GpioReader) LeftButton() bool {
-	count, err := this.leftButtonFile.Read(this.data)
-	if err != nil {
-		log.Fatal(err)
-	}
-	if count != 2 {
-		log.Fatal("Expected 2 bytes for left button read and got", count)
-	}
-
-	// seek back to beginning of file
-	_, err = this.leftButtonFile.Seek(0, 0)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	buttonDown := this.data[0] == 48 // ascii '0'
-
-	return buttonDown
-
-	// if buttonDown != this.leftPrevious {
-	// 	this.leftPrevious = buttonDown
-
-	// 	if buttonDown {			
-	// 		return ButtonPush
-	// 	} else {
-	// 		return ButtonRelease
-	// 	}
-	// }
+	return this.readButton(this.leftButtonFile)
 }
 
 // Get state of the right button
 func (this *GpioReader) RightButton() bool {
-	count, err := this.rightButtonFile.Read(this.data)
-	if err != nil {
-		log.Fatal(err)
-	}
-	if count != 2 {
-		log.Fatal("Expected 2 bytes for right button read and got", count)
-	}
+	return this.readButton(this.rightButtonFile)
+}
 
-	// seek back to beginning of file
-	_, err = this.rightButtonFile.Seek(0, 0)
-	if err != nil {
-		log.Fatal(err)
+// Close the button files
+func (this *GpioReader) Close() error {
+	var errs []error
+	for _, file := range []*os.File{this.leftButtonFile, this.rightButtonFile, this.leftDownFile, this.rightDownFile} {
+		if file == nil {
+			continue
+		}
+		if err := file.Close(); err != nil {
+			errs = append(errs, err)
+		}
 	}
-
-	buttonDown := this.data[0] == 48 // ascii '0'
-
-	return buttonDown
-
-	// if buttonDown != this.rightPrevious {
-	// 	this.rightPrevious = buttonDown
-
-	// 	if buttonDown {
-	// 		return ButtonPush
-	// 	} else {
-	// 		return ButtonRelease
-	// 	}
-	// }
+	return errors.Join(errs...)
 }
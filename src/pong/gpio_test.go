@@ -0,0 +1,60 @@
+//go:build !windows
+// +build !windows
+
+package pong
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// Closing should close every button file even when one of them fails, reporting each failure
+func Test_GpioReader_Close(t *testing.T) {
+
+	open := func() *os.File {
+		file, err := ioutil.TempFile("", "gpio")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(file.Name())
+		return file
+	}
+	reader := &GpioReader{leftButtonFile: open(), rightButtonFile: open(), leftDownFile: open()}
+
+	// already closed, so closing it again fails
+	reader.leftButtonFile.Close()
+	reader.leftDownFile.Close()
+
+	if err := reader.Close(); err == nil {
+		t.Fatal("Closing a closed file didn't fail")
+	}
+	if err := reader.rightButtonFile.Close(); err == nil {
+		t.Fatal("Right button file was left open")
+	}
+}
+
+// A button that can't be read should read released and report the error instead of stopping the game
+func Test_GpioReader_ReadError(t *testing.T) {
+
+	file, err := ioutil.TempFile("", "gpio")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString("0\n")
+	file.Seek(0, 0)
+
+	reader := &GpioReader{leftButtonFile: file, data: make([]byte, 32)}
+	if !reader.LeftButton() || reader.ReadError() != nil {
+		t.Fatal("Pushed button not read")
+	}
+
+	file.Close()
+	if reader.LeftButton() {
+		t.Fatal("Button that can't be read is pushed")
+	}
+	if reader.ReadError() == nil {
+		t.Fatal("Failed read not reported")
+	}
+}
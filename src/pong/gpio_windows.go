@@ -8,8 +8,12 @@ import ()
 type GpioReader struct {
 }
 
-func NewGpioReader(settings SettingsData) *GpioReader {
-	return &GpioReader{}
+func NewGpioReader(settings SettingsData) (*GpioReader, error) {
+	return &GpioReader{}, nil
+}
+
+func NewGpioEncoder(settings SettingsData) (*RotaryEncoder, error) {
+	return nil, nil
 }
 
 type ButtonEvent int
@@ -27,3 +31,23 @@ func (this *GpioReader) LeftButton() bool {
 func (this *GpioReader) RightButton() bool {
 	return false
 }
+
+func (this *GpioReader) ReadError() error {
+	return nil
+}
+
+func (this *GpioReader) HasDownButtons() bool {
+	return false
+}
+
+func (this *GpioReader) LeftDownButton() bool {
+	return false
+}
+
+func (this *GpioReader) RightDownButton() bool {
+	return false
+}
+
+func (this *GpioReader) Close() error {
+	return nil
+}
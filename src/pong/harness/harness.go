@@ -0,0 +1,246 @@
+// Plays games headlessly from scripted button presses, so rule changes can be checked without the strip or buttons
+package harness
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Seconds a scripted press is held for when the script doesn't say
+var defaultPressSeconds float64 = 0.1
+
+// A button pushed by the script
+type Press struct {
+
+	// side the button is on, and if it is the second button of that side
+	Left bool
+	Down bool
+
+	// seconds into the game the button is pushed, and how long it is held
+	At, For float64
+}
+
+// Every press of a scripted game
+type Script []Press
+
+// Parse a script of one press per line written as "left 3.21" or "right 4.5s 0.3s", the optional second time is how
+// long the button is held, "left down 3.21" pushes the second button of a side, blank lines and lines starting with #
+// are skipped
+func ParseScript(text string) (Script, error) {
+
+	script := Script{}
+	for number, line := range strings.Split(text, "\n") {
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		down := len(fields) > 1 && fields[1] == "down"
+		if down {
+			fields = append(fields[:1], fields[2:]...)
+		}
+		if len(fields) > 3 || (fields[0] != "left" && fields[0] != "right") {
+			return nil, fmt.Errorf("Line %v of the script isn't written as side [down] seconds [held seconds]: %q", number+1, line)
+		}
+
+		press := Press{Left: fields[0] == "left", Down: down, For: defaultPressSeconds}
+		times := []*float64{&press.At, &press.For}
+		for index, field := range fields[1:] {
+			value, err := strconv.ParseFloat(strings.TrimSuffix(field, "s"), 64)
+			if err != nil {
+				return nil, fmt.Errorf("Line %v of the script has a bad time %q", number+1, field)
+			}
+			*times[index] = value
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("Line %v of the script doesn't say when the button is pushed", number+1)
+		}
+		script = append(script, press)
+	}
+
+	return script, nil
+}
+
+// If the script holds down the left and right buttons at time
+func (this Script) Buttons(time float64) (left, right bool) {
+	return this.held(time, false)
+}
+
+// If the script holds down the second left and right buttons at time
+func (this Script) DownButtons(time float64) (left, right bool) {
+	return this.held(time, true)
+}
+
+// If the script pushes any second buttons
+func (this Script) HasDownButtons() bool {
+	for _, press := range this {
+		if press.Down {
+			return true
+		}
+	}
+	return false
+}
+
+// If the script holds down the left and right main or second buttons at time
+func (this Script) held(time float64, down bool) (left, right bool) {
+	for _, press := range this {
+		if press.Down == down && time >= press.At && time < press.At+press.For {
+			if press.Left {
+				left = true
+			} else {
+				right = true
+			}
+		}
+	}
+	return
+}
+
+// The buttons held by a script at the time of the game being played
+type scriptInput struct {
+	script Script
+	time   *float64
+}
+
+var _ DownButtonInput = scriptInput{}
+
+func (this scriptInput) LeftButton() bool {
+	left, _ := this.script.Buttons(*this.time)
+	return left
+}
+
+func (this scriptInput) RightButton() bool {
+	_, right := this.script.Buttons(*this.time)
+	return right
+}
+
+func (this scriptInput) HasDownButtons() bool {
+	return this.script.HasDownButtons()
+}
+
+func (this scriptInput) LeftDownButton() bool {
+	left, _ := this.script.DownButtons(*this.time)
+	return left
+}
+
+func (this scriptInput) RightDownButton() bool {
+	_, right := this.script.DownButtons(*this.time)
+	return right
+}
+
+// A phase the game entered while it was played
+type Event struct {
+
+	// seconds into the game
+	Time float64
+
+	Phase Phase
+
+	// points won by each side when the phase was entered, zero if the mode doesn't keep stats
+	LeftScore, RightScore int
+}
+
+// How a scripted game went
+type Result struct {
+
+	// every phase entered, in order
+	Events []Event
+
+	// how the game ended
+	Outcome GameOutcome
+
+	// stats of the game, empty if the mode doesn't keep them
+	Stats GameStats
+
+	// seconds the game lasted
+	Time float64
+}
+
+// A game played by a script instead of people, on a field of Settings.LedCount leds
+type Game struct {
+
+	// name of the registered mode, and the options it is set up with
+	Mode   string
+	Config GameConfig
+
+	Script Script
+
+	// seeds the random numbers, so serves and AI players play out the same every run
+	Seed int64
+
+	// steps per second the game is played at, Settings.PhysicsHz if 0
+	StepHz float64
+
+	// seconds of game time before giving up on a game that hasn't finished
+	Timeout float64
+}
+
+// Play the game through the state machine until it is over, stepping the rally the way the game loop does. The random
+// numbers go back to where they were afterwards
+func (this Game) Run() (*Result, error) {
+
+	mode, ok := NewGameMode(this.Mode)
+	if !ok {
+		return nil, errors.New("Unknown game mode " + this.Mode)
+	}
+	stepHz := this.StepHz
+	if stepHz <= 0 {
+		stepHz = Settings.PhysicsHz
+	}
+	if stepHz <= 0 || this.Timeout <= 0 {
+		return nil, errors.New("A scripted game needs a step rate and a timeout")
+	}
+	dt := 1 / stepHz
+
+	defer UseRandSource(UseRandSource(rand.NewSource(this.Seed)))
+	field := NewMatrixField(Settings.LedCount, Settings.MatrixRows)
+	field.SetTopology(FieldTopologyFor(mode, Settings))
+	defer field.Release()
+	mode.Setup(field, this.Config)
+
+	result := &Result{Outcome: GameInProgress}
+	states := NewStateMachine()
+
+	record := func(phase Phase) {
+		event := Event{Time: result.Time, Phase: phase}
+		if recorded, ok := mode.(StatsGameMode); ok {
+			event.LeftScore, event.RightScore = recorded.Stats().LeftScore, recorded.Stats().RightScore
+		}
+		result.Events = append(result.Events, event)
+	}
+	for _, phase := range []Phase{PhaseRally, PhasePointScored, PhaseGameOver} {
+		states.OnEnter(phase, record)
+	}
+
+	// the mode has already served again by the time a point is scored, like in the real game
+	states.OnEnter(PhasePointScored, func(phase Phase) { states.Transition(PhaseRally) })
+
+	buttons := scriptInput{this.Script, &result.Time}
+	states.OnUpdate(PhaseRally, func(dt float64) {
+		outcome := StepRally(mode, buttons, dt, field.Animate)
+		if next, over := PhaseAfter(outcome); over {
+			if next == PhaseGameOver {
+				result.Outcome = outcome
+			}
+			states.Transition(next)
+		}
+	})
+
+	states.Transition(PhaseRally)
+	for states.Phase() != PhaseGameOver {
+		if result.Time >= this.Timeout {
+			return result, fmt.Errorf("Game still in progress after %v seconds", this.Timeout)
+		}
+		states.Update(dt)
+		result.Time += dt
+	}
+
+	if recorded, ok := mode.(StatsGameMode); ok {
+		result.Stats = recorded.Stats()
+	}
+	return result, nil
+}
@@ -0,0 +1,391 @@
+package harness
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/breakout"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/circular"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/classic"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/coop"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/crowd"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/fourplayer"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/hill"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/lanes"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/relay"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/simon"
+	_ "github.com/brandonagr/pongpi/src/pong/modes/tugofwar"
+)
+
+func Assert(actual, expected int, msg string, t *testing.T) {
+	if actual != expected {
+		t.Fatal(msg, ": Got", actual, "expected", expected)
+	}
+}
+
+// Settings the scripted games are played with, the ball crosses the 20 leds in about two seconds. The settings and
+// random numbers are put back once the test is over
+func useTestSettings(t *testing.T) {
+	settings := Settings
+	seeds := UseRandSource(rand.NewSource(1))
+	t.Cleanup(func() {
+		Settings = settings
+		UseRandSource(seeds)
+	})
+
+	Settings.LedCount = 20
+	Settings.MatrixRows = 1
+	Settings.Topology = "line"
+	Settings.LifeInSeconds = 3
+	Settings.BounceVelocityIncrease = 1.1
+	Settings.PhysicsHz = 120
+}
+
+// Scripts should be read one press per line
+func Test_ParseScript(t *testing.T) {
+
+	script, err := ParseScript("# serve\nleft 3.21s\n\nright 4.5 0.3s\nleft down 5 1\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	Assert(len(script), 3, "Presses", t)
+	if !script[0].Left || script[0].At != 3.21 || script[0].For != defaultPressSeconds {
+		t.Fatal("First press", script[0])
+	}
+	if script[1].Left || script[1].At != 4.5 || script[1].For != 0.3 {
+		t.Fatal("Second press", script[1])
+	}
+	if left, _ := script.DownButtons(5.5); !script[2].Down || !left || !script.HasDownButtons() {
+		t.Fatal("Press of the second button", script[2])
+	}
+	if left, _ := script.Buttons(5.5); left {
+		t.Fatal("Second button held the main one")
+	}
+
+	for _, bad := range []string{"middle 1", "left", "left soon", "left 1 2 3"} {
+		if _, err := ParseScript(bad); err == nil {
+			t.Fatal("Parsed", bad)
+		}
+	}
+}
+
+// Nobody pushing a button should lose every point on the side the ball was served towards
+func Test_Classic_NobodyPlays(t *testing.T) {
+	useTestSettings(t)
+
+	result, err := Game{Mode: "classic", Seed: 1, Timeout: 60}.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 3 seconds of life lose 0.75 a miss, the fifth miss takes it below zero
+	winnerScore, loserScore := result.Stats.LeftScore, result.Stats.RightScore
+	if result.Outcome == GameRightWon {
+		winnerScore, loserScore = loserScore, winnerScore
+	}
+	Assert(winnerScore, 5, "Winner score", t)
+	Assert(loserScore, 0, "Loser score", t)
+
+	Assert(len(result.Events), 10, "Events", t)
+	last := result.Events[len(result.Events)-1]
+	if last.Phase != PhaseGameOver {
+		t.Fatal("Last event was", last.Phase)
+	}
+}
+
+// Running a scripted game shouldn't change the random numbers of whatever runs after it
+func Test_Run_KeepsRandomNumbers(t *testing.T) {
+	useTestSettings(t)
+
+	UseRandSource(rand.NewSource(7))
+	expected := NewRand().Int63()
+
+	UseRandSource(rand.NewSource(7))
+	if _, err := (Game{Mode: "classic", Seed: 1, Timeout: 60}).Run(); err != nil {
+		t.Fatal(err)
+	}
+	if NewRand().Int63() != expected {
+		t.Fatal("Random numbers changed by a scripted game")
+	}
+}
+
+// Classic played on a strip that is a loop should still be played on a line, so the ball goes past the players and the
+// game ends as quickly as it does on a line
+func Test_Classic_OnLoopedStrip(t *testing.T) {
+	useTestSettings(t)
+	Settings.Topology = "loop"
+
+	result, err := Game{Mode: "classic", Seed: 1, Timeout: 60}.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Outcome != GameLeftWon && result.Outcome != GameRightWon {
+		t.Fatal("Game on a looped strip ended with", result.Outcome, "after", result.Time)
+	}
+}
+
+// Holding the button as the ball arrives should return it, whichever side it was served to
+func Test_Classic_ScriptedReturn(t *testing.T) {
+	useTestSettings(t)
+
+	script, err := ParseScript("left 1.6 0.4\nright 1.6 0.4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := Game{Mode: "classic", Script: script, Seed: 1, Timeout: 60}.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Stats.Hits) < 2 || !result.Stats.Hits[0].Returned {
+		t.Fatal("First ball wasn't returned", result.Stats.Hits)
+	}
+	Assert(result.Stats.Rallies[0], 1, "Bounces in the first rally", t)
+}
+
+// Nobody returning the ball in breakout should lose every life and finish the game
+func Test_Breakout_NobodyPlays(t *testing.T) {
+	useTestSettings(t)
+
+	result, err := Game{Mode: "breakout", Seed: 1, Timeout: 60}.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Outcome != GameFinished {
+		t.Fatal("Game ended with", result.Outcome)
+	}
+
+	// the single brick of the first levels breaks on each hit, speeding the ball up a level, so the three lives are lost
+	// at 10, 12 and 14 leds / second after 3.0, 2.5 and 2.4 seconds
+	if result.Time < 7.8 || result.Time > 8 {
+		t.Fatal("Game lasted", result.Time)
+	}
+}
+
+// Mashing the left button while the right player does nothing should pull the divider past the right end
+func Test_TugOfWar_LeftMashes(t *testing.T) {
+	useTestSettings(t)
+
+	script := Script{}
+	for at := 0.0; at < 20; at += 0.125 {
+		script = append(script, Press{Left: true, At: at, For: 0.05})
+	}
+
+	result, err := Game{Mode: "tugofwar", Script: script, Seed: 1, Timeout: 60}.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Outcome != GameLeftWon {
+		t.Fatal("Game ended with", result.Outcome)
+	}
+
+	// 8 presses a second move the divider 4 leds / second once the window fills, 9.5 leds from the right end
+	if result.Time < 2.4 || result.Time > 4 {
+		t.Fatal("Game lasted", result.Time)
+	}
+}
+
+// Nobody echoing the first pulse in simon should end the run once the time for a press runs out
+func Test_Simon_NobodyPlays(t *testing.T) {
+	useTestSettings(t)
+
+	result, err := Game{Mode: "simon", Seed: 1, Timeout: 60}.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Outcome != GameFinished {
+		t.Fatal("Game ended with", result.Outcome)
+	}
+
+	// a second's pause, a pulse and the gap after it, then five seconds to press
+	if result.Time < 6.6 || result.Time > 6.8 {
+		t.Fatal("Game lasted", result.Time)
+	}
+}
+
+// With two lanes and nobody playing both players should miss the balls served towards them at the same time
+func Test_Lanes_NobodyPlays(t *testing.T) {
+	useTestSettings(t)
+	Settings.MatrixRows = 2
+
+	result, err := Game{Mode: "lanes", Seed: 1, Timeout: 60}.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every miss in one lane comes with a miss in the other, so the loser's fifth miss finds the winner on four
+	if result.Outcome != GameLeftWon && result.Outcome != GameRightWon {
+		t.Fatal("Game ended with", result.Outcome)
+	}
+	Assert(result.Stats.LeftScore+result.Stats.RightScore, 9, "Misses in both lanes", t)
+	if result.Time > 8 {
+		t.Fatal("Game lasted", result.Time)
+	}
+}
+
+// Around a loop nobody defending lets the ball through the same arc every time, as each serve heads for it again
+func Test_Circular_NobodyPlays(t *testing.T) {
+	useTestSettings(t)
+	Settings.Topology = "loop"
+
+	result, err := Game{Mode: "circular", Seed: 1, Timeout: 60}.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Outcome != GameLeftWon && result.Outcome != GameRightWon {
+		t.Fatal("Game ended with", result.Outcome)
+	}
+	Assert(result.Stats.LeftScore*result.Stats.RightScore, 0, "Points of the player defending the other arc", t)
+
+	// each serve is half the loop from the arc, less the arc it starts past and the one it leaves
+	if result.Time > 5 {
+		t.Fatal("Game lasted", result.Time)
+	}
+
+	Settings.Topology = "line"
+	result, err = Game{Mode: "circular", Seed: 1, Timeout: 60}.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Outcome != GameFinished || result.Time > 0.1 {
+		t.Fatal("Game on a line ended with", result.Outcome, "after", result.Time)
+	}
+}
+
+// The far players should return the ball before it reaches their teammates, unless both go for it
+func Test_FourPlayer_ScriptedReturn(t *testing.T) {
+	useTestSettings(t)
+
+	script, err := ParseScript("left down 1.3 0.4\nright down 1.3 0.4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := Game{Mode: "fourplayer", Script: script, Seed: 1, Timeout: 60}.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the serve is returned from in front of the left end and nobody is on the right end for the return
+	if result.Stats.FastestReturn != 10 || result.Events[1].Phase != PhasePointScored || result.Events[1].LeftScore != 1 {
+		t.Fatal("Far return wasn't made", result.Stats, result.Events[1])
+	}
+
+	script, err = ParseScript("left down 1.3 0.4\nright down 1.3 0.4\nleft 1.3 0.4\nright 1.3 0.4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = Game{Mode: "fourplayer", Script: script, Seed: 1, Timeout: 60}.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Stats.FastestReturn != 0 || result.Events[1].RightScore != 1 || result.Events[1].Time > 2 {
+		t.Fatal("Teammates both went for the serve and still returned it", result.Stats, result.Events[1])
+	}
+}
+
+// Nobody playing king of the hill should let the same side miss every serve, rotating the queue through it until the
+// other side's streak wins the game
+func Test_Hill_NobodyPlays(t *testing.T) {
+	useTestSettings(t)
+
+	queue := NewHillQueue(nil)
+	for _, name := range []string{"ann", "bob", "cat", "dan"} {
+		queue.Join(name)
+	}
+	result, err := Game{Mode: "hill", Config: GameConfig{Hill: queue}, Seed: 1, Timeout: 60}.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Outcome != GameLeftWon && result.Outcome != GameRightWon {
+		t.Fatal("Game ended with", result.Outcome)
+	}
+	Assert(result.Stats.LeftScore+result.Stats.RightScore, 7, "Points in the champion's streak", t)
+
+	// the three players facing the champion rotated through twice and a point, putting cat up next
+	var served struct{ Left, Right string }
+	response := httptest.NewRecorder()
+	queue.ServeHTTP(response, httptest.NewRequest("GET", "/api/hill", nil))
+	json.NewDecoder(response.Body).Decode(&served)
+	if !(served.Left == "ann" && served.Right == "cat") && !(served.Left == "cat" && served.Right == "bob") {
+		t.Fatal("Players at the buttons are", served.Left, served.Right)
+	}
+}
+
+// Returning only the serve in coop should end the run on the next miss with a single return between both players
+func Test_Coop_ScriptedReturn(t *testing.T) {
+	useTestSettings(t)
+
+	script, err := ParseScript("left 1.5 0.4\nright 1.5 0.4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := Game{Mode: "coop", Script: script, Seed: 1, Timeout: 60}.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Outcome != GameFinished {
+		t.Fatal("Game ended with", result.Outcome)
+	}
+	Assert(result.Stats.Rallies[0], 1, "Returns together", t)
+
+	// the ball speeds up, so the return comes back faster than the serve went out
+	if result.Stats.FastestReturn <= 10 || result.Time > 3.6 {
+		t.Fatal("Fastest return", result.Stats.FastestReturn, "in a game lasting", result.Time)
+	}
+}
+
+// Teammates of a relay should have to take turns, the first teammate returning twice in a row loses the point while
+// handing over to the second keeps the rally going
+func Test_Relay_Alternation(t *testing.T) {
+	useTestSettings(t)
+
+	play := func(text string) *Result {
+		script, err := ParseScript(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := Game{Mode: "relay", Script: script, Seed: 1, Timeout: 60}.Run()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	result := play("left down 1.3 0.4")
+	if result.Events[1].RightScore != 1 || result.Events[1].Time > 1.4 {
+		t.Fatal("Second teammate pushed out of turn and didn't lose the point", result.Events[1])
+	}
+
+	// the late press wires up the second buttons
+	result = play("left 1.7 0.4\nright 3.4 0.4\nleft 4.8 0.4\nright down 30 0.1")
+	if result.Events[1].RightScore != 1 || result.Events[1].Time > 4.9 || result.Stats.FastestReturn == 0 {
+		t.Fatal("First teammate returned twice in a row", result.Stats, result.Events[1])
+	}
+
+	result = play("left 1.7 0.4\nright 3.4 0.4\nleft down 4.8 0.4")
+	if result.Events[1].LeftScore != 1 || result.Events[1].Time < 6 {
+		t.Fatal("Second teammate didn't take their turn", result.Events[1])
+	}
+}
+
+// The wide hit zones of the crowd mode should catch a press that comes too early for the paddle at the end
+func Test_Crowd_WideHitZone(t *testing.T) {
+	useTestSettings(t)
+
+	script, err := ParseScript("left 1.7 0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mode := range []string{"crowd", "classic"} {
+		result, err := Game{Mode: mode, Script: script, Seed: 1, Timeout: 60}.Run()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if returned := result.Stats.FastestReturn > 0; returned != (mode == "crowd") {
+			t.Fatal("Early press returned", returned, "in", mode)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package pong
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Players waiting their turn at king of the hill, the loser of each point steps out to the back of the queue and the
+// next player in it takes their button
+type HillQueue struct {
+
+	// names of the players at the left and right buttons, and of the players waiting in the order they play
+	left, right string
+	waiting     nameQueue
+
+	// side of the player who won the last point, and how many points in a row they have won
+	championLeft bool
+	streak       int
+
+	// finds the profile of a player by name
+	profile func(name string) PlayerProfile
+
+	// the web server queues players while the game plays them
+	lock sync.Mutex
+}
+
+// Construct an empty HillQueue finding the profiles of players with profile
+func NewHillQueue(profile func(name string) PlayerProfile) *HillQueue {
+	return &HillQueue{profile: profile}
+}
+
+// Profile of the player called name
+func (this *HillQueue) Profile(name string) PlayerProfile {
+	if this.profile == nil {
+		return PlayerProfile{Name: name}
+	}
+	return this.profile(name)
+}
+
+// Add name to the back of the queue, unless they are already playing or waiting
+func (this *HillQueue) Join(name string) error {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	return this.waiting.join(name, this.left, this.right)
+}
+
+// Take name out of the queue, players at the buttons finish their point
+func (this *HillQueue) Leave(name string) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.waiting.leave(name)
+}
+
+// Put the first two players in the queue at the buttons for a new game, left and right play when there aren't
+// enough, returns who plays on each side
+func (this *HillQueue) Start(left, right string) (string, string) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	players := []*string{&this.left, &this.right}
+	for index, name := range []string{left, right} {
+		*players[index] = name
+		if called := this.waiting.callUp(1); len(called) > 0 {
+			*players[index] = called[0]
+		}
+	}
+	this.streak = 0
+	return this.left, this.right
+}
+
+// Count a point won by the player on the left or right, the loser steps out to the back of the queue and the next
+// player takes their side. Returns who now plays on the losing side, false if nobody was waiting so the loser stays,
+// and the streak of the winner
+func (this *HillQueue) PointWon(leftWon bool) (next string, replaced bool, streak int) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if leftWon != this.championLeft || this.streak == 0 {
+		this.championLeft = leftWon
+		this.streak = 0
+	}
+	this.streak++
+
+	loser := &this.right
+	if !leftWon {
+		loser = &this.left
+	}
+	if len(this.waiting) == 0 {
+		return *loser, false, this.streak
+	}
+
+	steppingOut := *loser
+	*loser = this.waiting[0]
+	this.waiting = append(this.waiting[1:], steppingOut)
+	return *loser, true, this.streak
+}
+
+// Serve the players at the buttons, the champion's streak and the queue as json, POST a name to join or DELETE one to
+// take them out, which is served behind AdminMethodsOnly so only the admin can clear the queue
+func (this *HillQueue) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	switch r.Method {
+	case "POST":
+		if err := this.Join(name); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Print(name, " joined king of the hill")
+	case "DELETE":
+		this.Leave(name)
+		log.Print(name, " left king of the hill")
+	}
+
+	this.lock.Lock()
+	served := struct {
+		Left, Right string
+		Champion    string
+		Streak      int
+		Queue       []string
+	}{Left: this.left, Right: this.right, Streak: this.streak, Queue: append([]string{}, this.waiting...)}
+	if this.streak > 0 {
+		served.Champion = this.right
+		if this.championLeft {
+			served.Champion = this.left
+		}
+	}
+	this.lock.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(served)
+}
@@ -0,0 +1,85 @@
+package pong
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// The loser of each point should go to the back of the queue with the next player taking their side
+func Test_HillQueue(t *testing.T) {
+
+	queue := NewHillQueue(nil)
+	for _, name := range []string{"ann", "bob", "cat"} {
+		if err := queue.Join(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := queue.Join("bob"); err == nil {
+		t.Fatal("Bob joined twice")
+	}
+	if err := queue.Join(strings.Repeat("x", maxQueueNameLength+1)); err == nil {
+		t.Fatal("Joined with a name past the longest")
+	}
+
+	if left, right := queue.Start("guest", "guest"); left != "ann" || right != "bob" {
+		t.Fatal("Game started with", left, "and", right)
+	}
+
+	next, replaced, streak := queue.PointWon(true)
+	if next != "cat" || !replaced || streak != 1 {
+		t.Fatal("After the first point", next, "is up with a streak of", streak)
+	}
+	next, _, streak = queue.PointWon(true)
+	if next != "bob" || streak != 2 {
+		t.Fatal("After the second point", next, "is up with a streak of", streak)
+	}
+	if next, _, streak = queue.PointWon(false); next != "cat" || streak != 1 {
+		t.Fatal("New champion has a streak of", streak, "against", next)
+	}
+
+	// with nobody waiting the loser stays on
+	queue.Leave("ann")
+	if next, replaced, _ = queue.PointWon(false); replaced || next != "cat" {
+		t.Fatal(next, "replaced the loser with nobody waiting")
+	}
+}
+
+// Players should be turned away once the queue is full
+func Test_HillQueue_Full(t *testing.T) {
+
+	queue := NewHillQueue(nil)
+	for player := 0; player < maxQueueLength; player++ {
+		if err := queue.Join(fmt.Sprint("player", player)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := queue.Join("late"); err == nil {
+		t.Fatal("Joined a full queue")
+	}
+}
+
+// The queue should be served as json, and players should join with a POST
+func Test_HillQueue_ServeHTTP(t *testing.T) {
+
+	queue := NewHillQueue(nil)
+	request := httptest.NewRequest("POST", "/api/hill", strings.NewReader(url.Values{"name": {"ann"}}.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	response := httptest.NewRecorder()
+	queue.ServeHTTP(response, request)
+	if response.Code != http.StatusOK {
+		t.Fatal("Joining returned", response.Code)
+	}
+
+	var served struct{ Queue []string }
+	if err := json.NewDecoder(response.Body).Decode(&served); err != nil {
+		t.Fatal(err)
+	}
+	if len(served.Queue) != 1 || served.Queue[0] != "ann" {
+		t.Fatal("Served queue", served.Queue)
+	}
+}
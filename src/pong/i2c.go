@@ -0,0 +1,48 @@
+// +build !windows
+
+package pong
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// ioctl choosing the address of the device the next reads and writes go to
+const i2cSlave = 0x0703
+
+// Connection to one device on an I2C bus
+type I2cDevice struct {
+	file *os.File
+}
+
+// Open the I2C bus at busFilePath, such as /dev/i2c-1, to talk to the device at address
+func OpenI2cDevice(busFilePath string, address int) (*I2cDevice, error) {
+
+	file, err := os.OpenFile(busFilePath, os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("I2C bus %v: %v", busFilePath, err)
+	}
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), i2cSlave, uintptr(address)); errno != 0 {
+		file.Close()
+		return nil, fmt.Errorf("I2C bus %v: choosing device %#x: %v", busFilePath, address, errno)
+	}
+
+	return &I2cDevice{file: file}, nil
+}
+
+// Read from the device
+func (this *I2cDevice) Read(data []byte) (int, error) {
+	return this.file.Read(data)
+}
+
+// Write to the device
+func (this *I2cDevice) Write(data []byte) (int, error) {
+	return this.file.Write(data)
+}
+
+// Close the connection to the bus
+func (this *I2cDevice) Close() error {
+	return this.file.Close()
+}
@@ -0,0 +1,27 @@
+// +build windows
+
+package pong
+
+import (
+	"errors"
+)
+
+// Connection to one device on an I2C bus
+type I2cDevice struct {
+}
+
+func OpenI2cDevice(busFilePath string, address int) (*I2cDevice, error) {
+	return nil, errors.New("I2C bus " + busFilePath + ": i2c not implemented on windows")
+}
+
+func (this *I2cDevice) Read(data []byte) (int, error) {
+	return 0, errors.New("i2c not implemented on windows")
+}
+
+func (this *I2cDevice) Write(data []byte) (int, error) {
+	return 0, errors.New("i2c not implemented on windows")
+}
+
+func (this *I2cDevice) Close() error {
+	return nil
+}
@@ -0,0 +1,287 @@
+package pong
+
+// Source of the state of the two player buttons
+type ButtonInput interface {
+
+	// true while the left button is held down
+	LeftButton() bool
+
+	// true while the right button is held down
+	RightButton() bool
+}
+
+var _ ButtonInput = &GpioReader{}
+
+// ButtonInput with a second button for each player, for moving a paddle both ways on a matrix
+type DownButtonInput interface {
+	ButtonInput
+
+	// true if the second buttons are wired up
+	HasDownButtons() bool
+
+	// true while the left player's second button is held down
+	LeftDownButton() bool
+
+	// true while the right player's second button is held down
+	RightDownButton() bool
+}
+
+// ButtonInput that can fail to be read, such as buttons on gpio pins, a button that can't be read is released
+type FallibleButtonInput interface {
+	ButtonInput
+
+	// First error reading the buttons since the last call, nil if every read worked
+	ReadError() error
+}
+
+// ButtonInput whose state depends on game time, such as a recording being played back
+type TimedInput interface {
+	ButtonInput
+
+	// Move the input forward in time by dt
+	Advance(dt float64)
+}
+
+// ButtonInput holding a state set each frame, for code that polls input that is pushed to it
+type ButtonState struct {
+	Left, Right bool
+}
+
+var _ ButtonInput = &ButtonState{}
+
+// true while the left button is held down
+func (this *ButtonState) LeftButton() bool {
+	return this.Left
+}
+
+// true while the right button is held down
+func (this *ButtonState) RightButton() bool {
+	return this.Right
+}
+
+// Turns a button's state into short and long presses
+type PressDetector struct {
+
+	// seconds a button has to be held to count as a long press
+	longPressTime float64
+
+	// state of the current press
+	down     bool
+	holdTime float64
+	consumed bool
+}
+
+// Construct a PressDetector
+func NewPressDetector(longPressTime float64) *PressDetector {
+	return &PressDetector{
+		longPressTime: longPressTime,
+	}
+}
+
+// Ignore the press in progress so releasing it doesn't count as a press
+func (this *PressDetector) Consume() {
+	this.consumed = this.down
+}
+
+// Update with the current button state, long presses fire while held and short presses fire on release
+func (this *PressDetector) Update(down bool, dt float64) (shortPress, longPress bool) {
+
+	if down {
+		if !this.down {
+			this.holdTime = 0
+			this.consumed = false
+		}
+		this.holdTime += dt
+
+		if !this.consumed && this.holdTime >= this.longPressTime {
+			this.consumed = true
+			longPress = true
+		}
+	} else if this.down && !this.consumed {
+		shortPress = true
+	}
+
+	this.down = down
+	return
+}
+
+// Detects both buttons being pushed at nearly the same time and held together
+type ChordDetector struct {
+
+	// seconds between the two presses that still counts as together, and seconds both must then be held
+	window, hold float64
+
+	// state of each button and seconds since it was pushed
+	leftDown, rightDown bool
+	leftAge, rightAge   float64
+
+	// seconds both buttons have been held since being pushed together, negative if they weren't, and if the chord
+	// has already been reported for this hold
+	heldTime float64
+	reported bool
+}
+
+// Construct a ChordDetector for buttons pushed within window seconds of each other and held for hold seconds
+func NewChordDetector(window, hold float64) *ChordDetector {
+	return &ChordDetector{
+		window:   window,
+		hold:     hold,
+		heldTime: -1,
+	}
+}
+
+// Update with the current button states, returns true once on the frame both buttons pushed together have been held
+// long enough, a quick press of both such as two returns at once never counts
+func (this *ChordDetector) Update(left, right bool, dt float64) bool {
+
+	this.leftAge += dt
+	this.rightAge += dt
+
+	leftPushed := left && !this.leftDown
+	rightPushed := right && !this.rightDown
+	if leftPushed {
+		this.leftAge = 0
+	}
+	if rightPushed {
+		this.rightAge = 0
+	}
+	this.leftDown, this.rightDown = left, right
+
+	switch {
+	case !left || !right:
+		this.heldTime, this.reported = -1, false
+	case leftPushed || rightPushed:
+		if this.leftAge <= this.window && this.rightAge <= this.window {
+			this.heldTime, this.reported = 0, false
+		}
+	case this.heldTime >= 0:
+		this.heldTime += dt
+	}
+
+	if this.heldTime >= this.hold && !this.reported {
+		this.reported = true
+		return true
+	}
+	return false
+}
+
+// Measures how fast a button is being mashed
+type PressRate struct {
+
+	// seconds presses are counted over
+	window float64
+
+	// state of the button and seconds since each press in the window, oldest first
+	down bool
+	ages []float64
+}
+
+// Construct a PressRate counting presses over the last window seconds
+func NewPressRate(window float64) *PressRate {
+	return &PressRate{
+		window: window,
+	}
+}
+
+// Update with the current button state, returns true on the frame the button is pushed
+func (this *PressRate) Update(down bool, dt float64) bool {
+
+	expired := 0
+	for i := range this.ages {
+		this.ages[i] += dt
+		if this.ages[i] > this.window {
+			expired = i + 1
+		}
+	}
+	this.ages = this.ages[expired:]
+
+	pushed := down && !this.down
+	if pushed {
+		this.ages = append(this.ages, 0)
+	}
+	this.down = down
+	return pushed
+}
+
+// Presses / second over the window
+func (this *PressRate) Rate() float64 {
+	return float64(len(this.ages)) / this.window
+}
+
+// Reads after a button changes that further changes are ignored for, so contact bounce isn't read as extra presses
+var buttonDebounceReads int = 3
+
+// Debounces a button, a change is passed through at once then held until the contacts have settled
+type Debouncer struct {
+	down bool
+
+	// reads left before another change is believed
+	settling int
+}
+
+// Update with the button read now, returns the debounced state
+func (this *Debouncer) Update(down bool) bool {
+
+	if this.settling > 0 {
+		this.settling--
+	} else if down != this.down {
+		this.down = down
+		this.settling = buttonDebounceReads
+	}
+	return this.down
+}
+
+// Buttons read through a Debouncer each, so the game sees one press however much the contacts bounce
+type DebouncedButtons struct {
+	buttons ButtonInput
+
+	left, right, leftDown, rightDown Debouncer
+}
+
+var _ DownButtonInput = &DebouncedButtons{}
+var _ FallibleButtonInput = &DebouncedButtons{}
+
+// Construct DebouncedButtons reading buttons
+func NewDebouncedButtons(buttons ButtonInput) *DebouncedButtons {
+	return &DebouncedButtons{buttons: buttons}
+}
+
+// true while the left button is held down
+func (this *DebouncedButtons) LeftButton() bool {
+	return this.left.Update(this.buttons.LeftButton())
+}
+
+// true while the right button is held down
+func (this *DebouncedButtons) RightButton() bool {
+	return this.right.Update(this.buttons.RightButton())
+}
+
+// If the wrapped buttons have second buttons wired up
+func (this *DebouncedButtons) HasDownButtons() bool {
+	buttons, ok := this.buttons.(DownButtonInput)
+	return ok && buttons.HasDownButtons()
+}
+
+// true while the left player's second button is held down
+func (this *DebouncedButtons) LeftDownButton() bool {
+	if !this.HasDownButtons() {
+		return false
+	}
+	return this.leftDown.Update(this.buttons.(DownButtonInput).LeftDownButton())
+}
+
+// true while the right player's second button is held down
+func (this *DebouncedButtons) RightDownButton() bool {
+	if !this.HasDownButtons() {
+		return false
+	}
+	return this.rightDown.Update(this.buttons.(DownButtonInput).RightDownButton())
+}
+
+// Error reading the wrapped buttons, if they can fail
+func (this *DebouncedButtons) ReadError() error {
+	if buttons, ok := this.buttons.(FallibleButtonInput); ok {
+		return buttons.ReadError()
+	}
+	return nil
+}
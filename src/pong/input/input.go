@@ -0,0 +1,56 @@
+// Package input gathers the buttons players push and the detectors that turn pushes into long presses, chords and
+// press rates. The names are part of the stable API described in the pong package
+package input
+
+import (
+	"io"
+
+	"github.com/brandonagr/pongpi/src/pong"
+)
+
+// Buttons a game reads and the detectors built on them
+type (
+	ButtonInput     = pong.ButtonInput
+	DownButtonInput = pong.DownButtonInput
+	TimedInput      = pong.TimedInput
+	ButtonState     = pong.ButtonState
+	PressDetector   = pong.PressDetector
+	ChordDetector   = pong.ChordDetector
+	PressRate       = pong.PressRate
+	Debouncer       = pong.Debouncer
+
+	DebouncedButtons = pong.DebouncedButtons
+
+	QuadratureDecoder = pong.QuadratureDecoder
+	RotaryEncoder     = pong.RotaryEncoder
+)
+
+// Construct a PressDetector telling presses held for longPressTime seconds from short ones
+func NewPressDetector(longPressTime float64) *PressDetector {
+	return pong.NewPressDetector(longPressTime)
+}
+
+// Construct a ChordDetector for buttons pushed within window seconds of each other and held for hold seconds
+func NewChordDetector(window, hold float64) *ChordDetector {
+	return pong.NewChordDetector(window, hold)
+}
+
+// Construct DebouncedButtons reading buttons
+func NewDebouncedButtons(buttons ButtonInput) *DebouncedButtons {
+	return pong.NewDebouncedButtons(buttons)
+}
+
+// Construct a PressRate counting presses over the last window seconds
+func NewPressRate(window float64) *PressRate {
+	return pong.NewPressRate(window)
+}
+
+// Construct a QuadratureDecoder for an encoder with stepsPerDetent quadrature steps between clicks
+func NewQuadratureDecoder(stepsPerDetent int) *QuadratureDecoder {
+	return pong.NewQuadratureDecoder(stepsPerDetent)
+}
+
+// Construct a RotaryEncoder reading the value files of its pins a and b and its button, which can be nil
+func NewRotaryEncoder(a, b, button io.ReadSeeker, stepsPerDetent int, closers ...io.Closer) *RotaryEncoder {
+	return pong.NewRotaryEncoder(a, b, button, stepsPerDetent, closers...)
+}
@@ -0,0 +1,69 @@
+package pong
+
+import (
+	"testing"
+)
+
+// Mashing should be measured over the window, forgetting presses once they're older than it
+func Test_PressRate(t *testing.T) {
+
+	rate := NewPressRate(1)
+	dt := 0.01
+
+	// 10 presses a second, held for half of each press
+	pushes := 0
+	for i := 0; i < 200; i++ {
+		if rate.Update(i%10 < 5, dt) {
+			pushes++
+		}
+	}
+	Assert(pushes, 20, "Pushes counted", t)
+	Assert(int(rate.Rate()), 10, "Presses / second while mashing", t)
+
+	// pressing and holding the button only counts once
+	for i := 0; i < 50; i++ {
+		rate.Update(true, dt)
+	}
+	Assert(int(rate.Rate()), 6, "Presses / second after holding for half the window", t)
+	for i := 0; i < 60; i++ {
+		rate.Update(false, dt)
+	}
+	Assert(int(rate.Rate()), 0, "Presses / second after stopping", t)
+}
+
+// Both buttons should have to be pushed together and held, a quick press of both never counts
+func Test_ChordDetector(t *testing.T) {
+
+	chord := NewChordDetector(0.15, 1)
+	dt := 0.01
+	update := func(left, right bool, frames int) (chords int) {
+		for i := 0; i < frames; i++ {
+			if chord.Update(left, right, dt) {
+				chords++
+			}
+		}
+		return
+	}
+
+	Assert(update(true, true, 20), 0, "Chords from a quick press of both", t)
+	update(false, false, 1)
+	Assert(update(true, true, 200), 1, "Chords from holding both", t)
+	update(false, false, 1)
+
+	// pushed too far apart
+	update(true, false, 50)
+	Assert(update(true, true, 200), 0, "Chords from pushes apart", t)
+}
+
+// A change should come through at once, then bounces are ignored until the contacts settle
+func Test_Debouncer(t *testing.T) {
+
+	var debouncer Debouncer
+	reads := []bool{true, false, true, false, true, true, false, true, false, true, true}
+	debounced := []bool{true, true, true, true, true, true, false, false, false, false, true}
+	for index, down := range reads {
+		if debouncer.Update(down) != debounced[index] {
+			t.Fatal("Read", index, "debounced wrong")
+		}
+	}
+}
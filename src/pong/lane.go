@@ -0,0 +1,102 @@
+package pong
+
+// Draws a drawable made for a strip in some of the rows of a matrix, so parallel strips can be played on as separate
+// lanes of one field. On a strip it is only drawn if its lanes include the first one
+type LaneDrawable struct {
+	drawable Drawable
+
+	// first and last lane the drawable is drawn in
+	first, last int
+}
+
+var _ Drawable2D = &LaneDrawable{}
+var _ InterpolatedDrawable = &LaneDrawable{}
+var _ ContextDrawable = &LaneDrawable{}
+var _ LifecycleDrawable = &LaneDrawable{}
+
+// Construct a LaneDrawable drawing drawable in lane, row 0 being the top
+func InLane(drawable Drawable, lane int) *LaneDrawable {
+	return InLanes(drawable, lane, lane)
+}
+
+// Construct a LaneDrawable drawing drawable across every lane from first to last, such as a ball spanning lanes
+func InLanes(drawable Drawable, first, last int) *LaneDrawable {
+	return &LaneDrawable{
+		drawable: drawable,
+		first:    first,
+		last:     last,
+	}
+}
+
+// Lanes the drawable is drawn across
+func (this *LaneDrawable) Lanes() (first, last int) {
+	return this.first, this.last
+}
+
+// Move the drawable to the lanes from first to last
+func (this *LaneDrawable) MoveTo(first, last int) {
+	this.first, this.last = first, last
+}
+
+// The drawable in the lanes
+func (this *LaneDrawable) Drawable() Drawable {
+	return this.drawable
+}
+
+// Returns the color at position blended on top of baseColor, a strip being the first lane
+func (this *LaneDrawable) ColorAt(position float64, baseColor RGBA) RGBA {
+	if this.first > 0 {
+		return baseColor
+	}
+	return this.colorAt(position, baseColor)
+}
+
+// Returns the color at column x of row y blended on top of baseColor
+func (this *LaneDrawable) ColorAt2D(x, y float64, baseColor RGBA) RGBA {
+	if int(y) < this.first || int(y) > this.last {
+		return baseColor
+	}
+	return this.colorAt(x, baseColor)
+}
+
+// Color of the drawable at position, which only has to be asked inside its bounds
+func (this *LaneDrawable) colorAt(position float64, baseColor RGBA) RGBA {
+	if bounded, ok := this.drawable.(BoundedDrawable); ok {
+		if left, right := bounded.Bounds(); position < left || position > right {
+			return baseColor
+		}
+	}
+	return this.drawable.ColorAt(position, baseColor)
+}
+
+// ZIndex of the drawable
+func (this *LaneDrawable) ZIndex() ZIndex {
+	return this.drawable.ZIndex()
+}
+
+// Animate the drawable
+func (this *LaneDrawable) Animate(dt float64) bool {
+	return this.drawable.Animate(dt)
+}
+
+// Animate the drawable in context
+func (this *LaneDrawable) AnimateInContext(dt float64, context AnimateContext) bool {
+	return AnimateDrawable(this.drawable, dt, context)
+}
+
+// Tell the drawable it was added to field
+func (this *LaneDrawable) OnAdd(field Field) {
+	NotifyAdded(this.drawable, field)
+}
+
+// Tell the drawable it was taken off field
+func (this *LaneDrawable) OnRemove(field Field) {
+	NotifyRemoved(this.drawable, field)
+}
+
+// Draw the drawable between steps if it can be
+func (this *LaneDrawable) Interpolate(alpha float64) {
+	if interpolated, ok := this.drawable.(InterpolatedDrawable); ok {
+		interpolated.Interpolate(alpha)
+	}
+}
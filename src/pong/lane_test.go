@@ -0,0 +1,23 @@
+package pong
+
+import (
+	"testing"
+)
+
+// A drawable in a lane should only be drawn in its rows of a matrix, and on a strip only if it is in the first lane
+func Test_LaneDrawable(t *testing.T) {
+
+	field := NewMatrixField(4, 3)
+	field.Add(InLanes(gradient2D{}, 1, 2))
+	frame := field.Render()
+	black, blue := RGBA{0, 0, 0, 255}, RGBA{0, 0, 255, 255}
+	if frame[0] != black || frame[4] != blue || frame[8+3] != blue {
+		t.Fatal("Lanes were drawn", frame)
+	}
+
+	strip := NewGameField(4)
+	strip.Add(InLane(gradient2D{}, 1))
+	if strip.Render()[0] != black {
+		t.Fatal("Second lane was drawn on a strip")
+	}
+}
@@ -0,0 +1,403 @@
+package pong
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Ambient light sensors that can be read from the I2C bus
+const (
+	LightSensorBH1750  = "bh1750"
+	LightSensorTSL2561 = "tsl2561"
+)
+
+// Measures how bright the room is
+type LightSensor interface {
+	io.Closer
+
+	// Ambient light in lux
+	Lux() (float64, error)
+}
+
+// Open the light sensor of kind at address on the I2C bus at busFilePath, 0 uses the usual address of the sensor
+func NewLightSensor(kind, busFilePath string, address int) (LightSensor, error) {
+
+	var open func(device io.ReadWriteCloser) (LightSensor, error)
+	switch kind {
+	case LightSensorBH1750:
+		open, address = NewBH1750, defaultAddress(address, 0x23)
+	case LightSensorTSL2561:
+		open, address = NewTSL2561, defaultAddress(address, 0x39)
+	default:
+		return nil, fmt.Errorf("Light sensor %q isn't %v or %v", kind, LightSensorBH1750, LightSensorTSL2561)
+	}
+
+	device, err := OpenI2cDevice(busFilePath, address)
+	if err != nil {
+		return nil, fmt.Errorf("Light sensor %v: %v", kind, err)
+	}
+	sensor, err := open(device)
+	if err != nil {
+		device.Close()
+		return nil, fmt.Errorf("Light sensor %v at %#x on %v: %v", kind, address, busFilePath, err)
+	}
+	return sensor, nil
+}
+
+// address, or fallback if it is 0
+func defaultAddress(address, fallback int) int {
+	if address == 0 {
+		return fallback
+	}
+	return address
+}
+
+// BH1750 light sensor measuring continuously at its high resolution
+type BH1750 struct {
+	device io.ReadWriteCloser
+	data   []byte
+}
+
+// Power on the BH1750 on device and start it measuring
+func NewBH1750(device io.ReadWriteCloser) (LightSensor, error) {
+	for _, command := range []byte{0x01, 0x10} {
+		if _, err := device.Write([]byte{command}); err != nil {
+			return nil, err
+		}
+	}
+	return &BH1750{device: device, data: make([]byte, 2)}, nil
+}
+
+// Latest measurement
+func (this *BH1750) Lux() (float64, error) {
+	if _, err := io.ReadFull(this.device, this.data); err != nil {
+		return 0, err
+	}
+	return float64(binary.BigEndian.Uint16(this.data)) / 1.2, nil
+}
+
+// Close the connection to the sensor
+func (this *BH1750) Close() error {
+	return this.device.Close()
+}
+
+// TSL2561 light sensor at its power on gain and integration time, 1x and 402ms
+type TSL2561 struct {
+	device io.ReadWriteCloser
+	data   []byte
+}
+
+// Power on the TSL2561 on device
+func NewTSL2561(device io.ReadWriteCloser) (LightSensor, error) {
+	// command bit with the control register, then power on
+	if _, err := device.Write([]byte{0x80, 0x03}); err != nil {
+		return nil, err
+	}
+	return &TSL2561{device: device, data: make([]byte, 2)}, nil
+}
+
+// Latest measurement of both channels worked out as lux
+func (this *TSL2561) Lux() (float64, error) {
+	visible, err := this.channel(0xac)
+	if err != nil {
+		return 0, err
+	}
+	infrared, err := this.channel(0xae)
+	if err != nil {
+		return 0, err
+	}
+	return tsl2561Lux(visible, infrared), nil
+}
+
+// Read the word in register, a command byte selecting the low byte of a channel
+func (this *TSL2561) channel(register byte) (float64, error) {
+	if _, err := this.device.Write([]byte{register}); err != nil {
+		return 0, err
+	}
+	if _, err := io.ReadFull(this.device, this.data); err != nil {
+		return 0, err
+	}
+	return float64(binary.LittleEndian.Uint16(this.data)), nil
+}
+
+// Close the connection to the sensor
+func (this *TSL2561) Close() error {
+	return this.device.Close()
+}
+
+// Lux from the broadband and infrared channels of a TSL2561 in the T package at 1x gain, from its datasheet
+func tsl2561Lux(broadband, infrared float64) float64 {
+
+	if broadband == 0 {
+		return 0
+	}
+
+	// the datasheet coefficients are for 16x gain
+	ratio := infrared / broadband
+	var lux float64
+	switch {
+	case ratio <= 0.5:
+		lux = 0.0304*broadband - 0.062*broadband*math.Pow(ratio, 1.4)
+	case ratio <= 0.61:
+		lux = 0.0224*broadband - 0.031*infrared
+	case ratio <= 0.8:
+		lux = 0.0128*broadband - 0.0153*infrared
+	case ratio <= 1.3:
+		lux = 0.00146*broadband - 0.00112*infrared
+	}
+	return math.Max(lux*16, 0)
+}
+
+// Display that follows the ambient light, bright during the day and gentle at night, unless a brightness is chosen on
+// the brightness page
+type AutoBrightness struct {
+	dimmed *DimmedDisplay
+	wrappedDisplay
+	sensor LightSensor
+
+	// lux at or below which the display is at nightBrightness and at or above which it is at full brightness
+	darkLux, brightLux float64
+	nightBrightness    float64
+
+	// change in brightness the light has to call for before the display follows it
+	hysteresis float64
+
+	lock       sync.Mutex
+	lux        float64
+	brightness float64
+	override   float64
+	overridden bool
+	lastError  string
+}
+
+var _ ResettableDisplay = &AutoBrightness{}
+
+// Construct an AutoBrightness wrapping display, following sensor as set in settings. Without a sensor the display
+// stays at full brightness unless one is chosen
+func NewAutoBrightness(display Display, sensor LightSensor, settings SettingsData) *AutoBrightness {
+	return &AutoBrightness{
+		dimmed:          NewDimmedDisplay(display, 1),
+		wrappedDisplay:  wrappedDisplay{display},
+		sensor:          sensor,
+		darkLux:         settings.DarkLux,
+		brightLux:       settings.BrightLux,
+		nightBrightness: settings.NightBrightness,
+		hysteresis:      settings.BrightnessHysteresis,
+		brightness:      1,
+	}
+}
+
+// Brightness the light level of lux calls for, nightBrightness in the dark up to 1 in bright light, following the
+// log of lux the way eyes do
+func (this *AutoBrightness) brightnessFor(lux float64) float64 {
+
+	if lux <= this.darkLux || lux <= 0 {
+		return this.nightBrightness
+	}
+	if lux >= this.brightLux {
+		return 1
+	}
+	dark := math.Max(this.darkLux, 1)
+	fraction := math.Max(0, math.Log(lux/dark)/math.Log(this.brightLux/dark))
+	return this.nightBrightness + (1-this.nightBrightness)*fraction
+}
+
+// Follow a light level of lux, only changing the brightness once it moves by more than the hysteresis or reaches
+// either end of the range
+func (this *AutoBrightness) Update(lux float64) {
+
+	target := this.brightnessFor(lux)
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.lux = lux
+	atEnd := target == this.nightBrightness || target == 1
+	if math.Abs(target-this.brightness) > this.hysteresis || (atEnd && target != this.brightness) {
+		this.brightness = target
+	}
+}
+
+// Read the sensor every interval forever, run as a goroutine
+func (this *AutoBrightness) Watch(interval time.Duration) {
+
+	if this.sensor == nil {
+		return
+	}
+	for _ = range time.Tick(interval) {
+		lux, err := this.sensor.Lux()
+		if err != nil {
+			this.reportError(err)
+			continue
+		}
+		this.reportError(nil)
+		this.Update(lux)
+	}
+}
+
+// Log err if it is different to the last one, so a sensor that stops answering doesn't fill the log
+func (this *AutoBrightness) reportError(err error) {
+
+	message := ""
+	if err != nil {
+		message = err.Error()
+	}
+
+	this.lock.Lock()
+	changed := message != this.lastError
+	this.lastError = message
+	this.lock.Unlock()
+
+	if changed && err != nil {
+		log.Print("Reading the light sensor: ", err)
+	}
+}
+
+// Keep the display at brightness from 0 to 1 whatever the light
+func (this *AutoBrightness) SetOverride(brightness float64) error {
+
+	if brightness < 0 || brightness > 1 || math.IsNaN(brightness) {
+		return fmt.Errorf("Brightness %v isn't from 0 to 1", brightness)
+	}
+
+	this.lock.Lock()
+	this.override, this.overridden = brightness, true
+	this.lock.Unlock()
+	return nil
+}
+
+// Keep the display change brighter than it is now, or dimmer if change is negative, returns the brightness chosen
+func (this *AutoBrightness) Adjust(change float64) float64 {
+
+	brightness, _ := this.Brightness()
+	brightness = math.Max(0, math.Min(1, brightness+change))
+	this.SetOverride(brightness)
+	return brightness
+}
+
+// Go back to following the light
+func (this *AutoBrightness) ClearOverride() {
+	this.lock.Lock()
+	this.overridden = false
+	this.lock.Unlock()
+}
+
+// Brightness the display is rendered at, and if it was chosen rather than following the light
+func (this *AutoBrightness) Brightness() (brightness float64, overridden bool) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if this.overridden {
+		return this.override, true
+	}
+	return this.brightness, false
+}
+
+// Scale the colorData by the brightness and render it to the wrapped display
+func (this *AutoBrightness) Render(colorData []RGBA) {
+
+	brightness, _ := this.Brightness()
+	if brightness == 1 {
+		this.display.Render(colorData)
+		return
+	}
+	this.dimmed.SetBrightness(brightness)
+	this.dimmed.Render(colorData)
+}
+
+// Close the sensor and the wrapped display if it can be
+func (this *AutoBrightness) Close() error {
+	if this.sensor != nil {
+		this.sensor.Close()
+	}
+	return this.wrappedDisplay.Close()
+}
+
+// Brightness and light level as served to the web page
+type brightnessDescription struct {
+	Brightness float64
+	Overridden bool
+	Lux        float64
+	HasSensor  bool
+	Error      string `json:",omitempty"`
+}
+
+// Serve the brightness and light level as json, POST brightness from 0 to 1 to choose one or DELETE to follow the
+// light again
+func (this *AutoBrightness) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	switch r.Method {
+	case "POST":
+		brightness, err := strconv.ParseFloat(r.FormValue("brightness"), 64)
+		if err == nil {
+			err = this.SetOverride(brightness)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Print("Brightness set to ", brightness)
+	case "DELETE":
+		this.ClearOverride()
+		log.Print("Brightness following the light")
+	}
+
+	brightness, overridden := this.Brightness()
+	this.lock.Lock()
+	description := brightnessDescription{brightness, overridden, this.lux, this.sensor != nil, this.lastError}
+	this.lock.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(description)
+}
+
+// Serve the page showing the light level with a slider choosing the brightness
+func (this *AutoBrightness) ServePage(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `
+<html>
+	<head><script type="text/javascript"><!--
+		function show(response)
+		{
+			if (!response.ok) {
+				response.text().then(function(error) { document.getElementById("error").textContent = error; });
+				return;
+			}
+			response.json().then(function(state) {
+				document.getElementById("brightness").value = state.Brightness;
+				document.getElementById("mode").textContent = state.Overridden ? "chosen" : "following the light";
+				document.getElementById("lux").textContent = state.HasSensor ? Math.round(state.Lux) + " lux" : "no sensor";
+				document.getElementById("error").textContent = state.Error || "";
+			});
+		}
+
+		function choose(value)
+		{
+			fetch("api/brightness", {method: "POST", body: new URLSearchParams({brightness: value})}).then(show);
+		}
+
+		function follow()
+		{
+			fetch("api/brightness", {method: "DELETE"}).then(show);
+		}
+
+		fetch("api/brightness").then(show);
+		setInterval(function() { fetch("api/brightness").then(show); }, 2000);
+	--></script></head>
+	<body>
+		<h1>Brightness</h1>
+		<p>Light: <span id="lux"></span>, brightness <span id="mode"></span></p>
+		<p><input id="brightness" type="range" min="0" max="1" step="0.01" onchange="choose(this.value)">
+			<button onclick="follow()">Follow the light</button></p>
+		<p id="error"></p>
+	</body>
+</html>`)
+}
@@ -0,0 +1,87 @@
+package pong
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+// Device on the I2C bus answering reads with replies and remembering what was written to it
+type fakeI2cDevice struct {
+	replies *bytes.Reader
+	written []byte
+}
+
+func (this *fakeI2cDevice) Read(data []byte) (int, error) {
+	return this.replies.Read(data)
+}
+
+func (this *fakeI2cDevice) Write(data []byte) (int, error) {
+	this.written = append(this.written, data...)
+	return len(data), nil
+}
+
+func (this *fakeI2cDevice) Close() error {
+	return nil
+}
+
+// Sensors should turn what they read into lux, and the brightness should follow the light without flickering
+func Test_AutoBrightness(t *testing.T) {
+
+	device := &fakeI2cDevice{replies: bytes.NewReader([]byte{0x01, 0xe0})}
+	sensor, err := NewBH1750(device)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lux, err := sensor.Lux(); err != nil || lux != 400 {
+		t.Fatal("BH1750 read", lux, err)
+	}
+	if !bytes.Equal(device.written, []byte{0x01, 0x10}) {
+		t.Fatal("BH1750 was started with", device.written)
+	}
+
+	// 1000 broadband and 250 infrared is a ratio of 0.25
+	device = &fakeI2cDevice{replies: bytes.NewReader([]byte{0xe8, 0x03, 0xfa, 0x00})}
+	if sensor, err = NewTSL2561(device); err != nil {
+		t.Fatal(err)
+	}
+	if lux, err := sensor.Lux(); err != nil || int(lux) != int(tsl2561Lux(1000, 250)) || int(lux) != 343 {
+		t.Fatal("TSL2561 read", lux, err)
+	}
+	if tsl2561Lux(0, 0) != 0 || tsl2561Lux(100, 200) != 0 {
+		t.Fatal("TSL2561 in the dark or under infrared isn't 0 lux")
+	}
+
+	display := &lastFrameDisplay{}
+	auto := NewAutoBrightness(display, nil, SettingsData{DarkLux: 10, BrightLux: 1000, NightBrightness: 0.2, BrightnessHysteresis: 0.05})
+
+	auto.Update(1)
+	if brightness, _ := auto.Brightness(); brightness != 0.2 {
+		t.Fatal("Brightness in the dark is", brightness)
+	}
+	auto.Update(100)
+	if brightness, _ := auto.Brightness(); math.Abs(brightness-0.6) > 1e-9 {
+		t.Fatal("Brightness halfway up is", brightness)
+	}
+	auto.Update(110)
+	if brightness, _ := auto.Brightness(); math.Abs(brightness-0.6) > 1e-9 {
+		t.Fatal("Brightness flickered to", brightness)
+	}
+	auto.Update(5000)
+	if brightness, _ := auto.Brightness(); brightness != 1 {
+		t.Fatal("Brightness in daylight is", brightness)
+	}
+
+	if auto.SetOverride(2) == nil {
+		t.Fatal("Brightness above 1 was chosen")
+	}
+	auto.SetOverride(0.5)
+	auto.Render([]RGBA{{200, 100, 0, 255}})
+	if display.frame[0] != (RGBA{100, 50, 0, 255}) {
+		t.Fatal("Chosen brightness rendered", display.frame[0])
+	}
+	auto.ClearOverride()
+	if brightness, overridden := auto.Brightness(); brightness != 1 || overridden {
+		t.Fatal("Brightness didn't follow the light again")
+	}
+}
@@ -0,0 +1,87 @@
+package pong
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Ways the rows of a matrix can be wired, every row starting at the same side, or every other row running back the
+// other way. Any other layout lists the rows in the order they are wired, such as "1 0r", with an r after each row
+// that runs from right to left
+const (
+	MatrixProgressive = "progressive"
+	MatrixSerpentine  = "serpentine"
+)
+
+// Display of a matrix, reordering frames rendered a row at a time from the top into the order its leds are wired in
+type MatrixDisplay struct {
+	wrappedDisplay
+
+	// leds in each row, and for every row the position it is wired at and if it runs backwards
+	width    int
+	wiredRow []int
+	reversed []bool
+
+	wiredData []RGBA
+}
+
+var _ ResettableDisplay = &MatrixDisplay{}
+
+// Construct a MatrixDisplay wrapping the display of a matrix with rows of width leds, wired in layout
+func NewMatrixDisplay(display Display, width, rows int, layout string) (*MatrixDisplay, error) {
+
+	matrix := &MatrixDisplay{
+		wrappedDisplay: wrappedDisplay{display},
+		width:          width,
+		wiredRow:       make([]int, rows),
+		reversed:       make([]bool, rows),
+	}
+
+	switch layout {
+	case MatrixProgressive, MatrixSerpentine:
+		for row := range matrix.wiredRow {
+			matrix.wiredRow[row] = row
+			matrix.reversed[row] = layout == MatrixSerpentine && row%2 == 1
+		}
+		return matrix, nil
+	}
+
+	fields := strings.Fields(layout)
+	if len(fields) != rows {
+		return nil, fmt.Errorf("Matrix layout %q isn't %v, %v or a list of the %v rows", layout, MatrixProgressive, MatrixSerpentine, rows)
+	}
+	wired := make([]bool, rows)
+	for position, field := range fields {
+		row, err := strconv.Atoi(strings.TrimSuffix(field, "r"))
+		if err != nil || row < 0 || row >= rows || wired[row] {
+			return nil, fmt.Errorf("Matrix layout %q has a bad row %q", layout, field)
+		}
+		wired[row] = true
+		matrix.wiredRow[row] = position
+		matrix.reversed[row] = strings.HasSuffix(field, "r")
+	}
+	return matrix, nil
+}
+
+// Index of the led wired at column x of row y
+func (this *MatrixDisplay) Index(x, y int) int {
+	if this.reversed[y] {
+		return this.wiredRow[y]*this.width + this.width - 1 - x
+	}
+	return this.wiredRow[y]*this.width + x
+}
+
+// Reorder colorData and render it to the wrapped display
+func (this *MatrixDisplay) Render(colorData []RGBA) {
+
+	if len(this.wiredData) != len(colorData) {
+		this.wiredData = make([]RGBA, len(colorData))
+	}
+
+	for index, color := range colorData {
+		this.wiredData[this.Index(index%this.width, index/this.width)] = color
+	}
+
+	this.display.Render(this.wiredData)
+}
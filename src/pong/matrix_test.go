@@ -0,0 +1,186 @@
+package pong
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Records the last frame rendered to it
+type lastFrameDisplay struct {
+	frame []RGBA
+}
+
+func (this *lastFrameDisplay) Render(colorData []RGBA) {
+	this.frame = append(this.frame[:0], colorData...)
+}
+
+// Draws column x of row y in red x and green y on a matrix, and blue on a strip
+type gradient2D struct{}
+
+func (this gradient2D) ColorAt(position float64, baseColor RGBA) RGBA {
+	return RGBA{0, 0, 255, 255}
+}
+
+func (this gradient2D) ColorAt2D(x, y float64, baseColor RGBA) RGBA {
+	return RGBA{uint8(x), uint8(y), 0, 255}
+}
+
+func (this gradient2D) ZIndex() ZIndex {
+	return 0
+}
+
+func (this gradient2D) Animate(dt float64) bool {
+	return true
+}
+
+// A matrix field should render every row, and the display should wire them in the layout asked for
+func Test_Matrix(t *testing.T) {
+
+	field := NewMatrixField(4, 3)
+	field.Add(gradient2D{})
+	frame := field.Render()
+	Assert(len(frame), 12, "Leds in the frame", t)
+	if frame[4*2+1] != (RGBA{1, 2, 0, 255}) {
+		t.Fatal("Column 1 of row 2 was", frame[4*2+1])
+	}
+
+	// drawables without a 2D color look the same on every row
+	strip := NewGameField(4)
+	strip.Add(gradient2D{})
+	if strip.Render()[3] != (RGBA{0, 0, 255, 255}) {
+		t.Fatal("Strip was drawn", strip.Render())
+	}
+
+	output := &lastFrameDisplay{}
+	serpentine, err := NewMatrixDisplay(output, 4, 3, MatrixSerpentine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serpentine.Render(frame)
+	if output.frame[4] != (RGBA{3, 1, 0, 255}) || output.frame[8] != (RGBA{0, 2, 0, 255}) {
+		t.Fatal("Serpentine rows were wired", output.frame)
+	}
+
+	progressive, err := NewMatrixDisplay(output, 4, 3, MatrixProgressive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	progressive.Render(frame)
+	if output.frame[4] != (RGBA{0, 1, 0, 255}) {
+		t.Fatal("Progressive rows were wired", output.frame)
+	}
+
+	// lanes wired in any order, each running either way
+	lanes, err := NewMatrixDisplay(output, 4, 3, "2 0r 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lanes.Render(frame)
+	if output.frame[0] != (RGBA{0, 2, 0, 255}) || output.frame[4] != (RGBA{3, 0, 0, 255}) || output.frame[8] != (RGBA{0, 1, 0, 255}) {
+		t.Fatal("Lanes were wired", output.frame)
+	}
+
+	for _, layout := range []string{"zigzag", "0 1", "0 0 1", "0 1 3"} {
+		if _, err := NewMatrixDisplay(output, 4, 3, layout); err == nil {
+			t.Fatal("Made a display with the layout", layout)
+		}
+	}
+}
+
+// A pixel map should pick every led's color out of the frame, leaving leds off the frame dark
+func Test_PixelMap(t *testing.T) {
+
+	directory, err := ioutil.TempDir("", "pixelmap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	csvPath := filepath.Join(directory, "table.csv")
+	ioutil.WriteFile(csvPath, []byte("# down the left edge and back along the top\n0,2\n0,1,0.5\n-1,-1\n3, 0\n"), 0644)
+	pixels, err := LoadPixelMap(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Assert(len(pixels), 4, "Leds in the CSV map", t)
+	if brightness := pixels.Brightness(); brightness[0] != 1 || brightness[1] != 0.5 {
+		t.Fatal("Brightness of the leds in the map was", brightness)
+	}
+
+	jsonPath := filepath.Join(directory, "table.json")
+	ioutil.WriteFile(jsonPath, []byte(`[{"x": 0, "y": 2}, {"x": 0, "y": 1, "brightness": 0.5}, {"x": -1, "y": -1}, {"x": 3, "y": 0}]`), 0644)
+	jsonPixels, err := LoadPixelMap(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jsonPixels) != len(pixels) || jsonPixels[1] != pixels[1] || jsonPixels[3] != pixels[3] {
+		t.Fatal("JSON map was read as", jsonPixels, "not", pixels)
+	}
+
+	field := NewMatrixField(4, 3)
+	field.Add(gradient2D{})
+	output := &lastFrameDisplay{}
+	mapped, err := NewPixelMapDisplay(output, 4, 3, pixels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapped.Render(field.Render())
+	expected := []RGBA{{0, 2, 0, 255}, {0, 1, 0, 255}, {}, {3, 0, 0, 255}}
+	for led, color := range expected {
+		if output.frame[led] != color {
+			t.Fatal("Mapped leds were", output.frame)
+		}
+	}
+
+	if _, err := NewPixelMapDisplay(output, 4, 2, pixels); err == nil {
+		t.Fatal("Mapped a led outside the frame")
+	}
+}
+
+// Transforms should flip the frame before it is wired, and strips should be mirrored end to end
+func Test_TransformDisplay(t *testing.T) {
+
+	field := NewMatrixField(4, 3)
+	field.Add(gradient2D{})
+	frame := field.Render()
+	output := &lastFrameDisplay{}
+
+	rotated, err := NewTransformDisplay(output, 4, 3, "rotate180")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated.Render(frame)
+	if output.frame[0] != (RGBA{3, 2, 0, 255}) || output.frame[11] != (RGBA{0, 0, 0, 255}) {
+		t.Fatal("Rotated frame was", output.frame)
+	}
+
+	mirrored, err := NewTransformDisplay(output, 4, 3, "mirrorY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mirrored.Render(frame)
+	if output.frame[1] != (RGBA{1, 2, 0, 255}) {
+		t.Fatal("Mirrored frame was", output.frame)
+	}
+
+	// flipping both ways then rotating leaves the frame as it was
+	unflipped, err := NewTransformDisplay(output, 4, 3, "mirrorx mirrory rotate180")
+	if err != nil || unflipped.Flipped() {
+		t.Fatal("Flipped back frame is still flipped", err)
+	}
+
+	strip, err := NewTransformDisplay(output, 3, 1, "mirror")
+	if err != nil {
+		t.Fatal(err)
+	}
+	strip.Render([]RGBA{{1, 0, 0, 255}, {2, 0, 0, 255}, {3, 0, 0, 255}})
+	if output.frame[0] != (RGBA{3, 0, 0, 255}) || output.frame[2] != (RGBA{1, 0, 0, 255}) {
+		t.Fatal("Mirrored strip was", output.frame)
+	}
+
+	if _, err := NewTransformDisplay(output, 4, 3, "rotate90"); err == nil {
+		t.Fatal("Made a display with an unknown transform")
+	}
+}
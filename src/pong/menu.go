@@ -0,0 +1,71 @@
+package pong
+
+// A single choice in a Menu
+type MenuOption struct {
+
+	// value the option stands for
+	Name string
+
+	// color the option's segment is drawn in
+	Color RGBA
+}
+
+// A list of options where one is highlighted
+type Menu struct {
+
+	// what is being chosen
+	Name string
+
+	Options []MenuOption
+
+	// index of the highlighted option
+	selected int
+}
+
+// Construct a Menu with the first option highlighted
+func NewMenu(name string, options []MenuOption) *Menu {
+	return &Menu{
+		Name:    name,
+		Options: options,
+	}
+}
+
+// Highlight the next option, wrapping back to the first
+func (this *Menu) Next() {
+	this.selected = (this.selected + 1) % len(this.Options)
+}
+
+// Highlight the previous option, wrapping around to the last
+func (this *Menu) Previous() {
+	this.selected = (this.selected + len(this.Options) - 1) % len(this.Options)
+}
+
+// Replace the options, keeping the highlighted option if it is still there
+func (this *Menu) SetOptions(options []MenuOption) {
+	selected := ""
+	if len(this.Options) > 0 {
+		selected = this.Selected().Name
+	}
+	this.Options = options
+	this.selected = 0
+	this.SelectName(selected)
+}
+
+// Highlight the option with name, if it exists
+func (this *Menu) SelectName(name string) {
+	for index, option := range this.Options {
+		if option.Name == name {
+			this.selected = index
+		}
+	}
+}
+
+// Index of the highlighted option
+func (this *Menu) SelectedIndex() int {
+	return this.selected
+}
+
+// The highlighted option
+func (this *Menu) Selected() MenuOption {
+	return this.Options[this.selected]
+}
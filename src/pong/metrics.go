@@ -0,0 +1,94 @@
+package pong
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Weight given to the newest value in the running average of a measurement
+var metricSmoothing float64 = 0.05
+
+// Running statistics of a single measurement
+type Metric struct {
+	Last    float64
+	Average float64
+	Max     float64
+	Count   int
+}
+
+// Named measurements of how the game is running, served as json
+type Metrics struct {
+
+	// measurements are read by the web server while the game loop updates them
+	lock   sync.Mutex
+	values map[string]*Metric
+}
+
+// Measurements shared by everything in the process
+var GameMetrics = NewMetrics()
+
+// Construct an empty Metrics
+func NewMetrics() *Metrics {
+	return &Metrics{
+		values: make(map[string]*Metric),
+	}
+}
+
+// Add a new value of the measurement called name
+func (this *Metrics) Observe(name string, value float64) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	metric, ok := this.values[name]
+	if !ok {
+		metric = &Metric{Average: value}
+		this.values[name] = metric
+	}
+
+	metric.Last = value
+	metric.Average += (value - metric.Average) * metricSmoothing
+	if value > metric.Max {
+		metric.Max = value
+	}
+	metric.Count++
+}
+
+// Add the milliseconds since start to the measurement called name
+func (this *Metrics) ObserveSince(name string, start time.Time) {
+	this.Observe(name, time.Since(start).Seconds()*1000)
+}
+
+// Copy of the measurement called name, false if nothing has been observed
+func (this *Metrics) Get(name string) (Metric, bool) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	metric, ok := this.values[name]
+	if !ok {
+		return Metric{}, false
+	}
+	return *metric, true
+}
+
+// Copy of every measurement by name
+func (this *Metrics) Snapshot() map[string]Metric {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	values := make(map[string]Metric, len(this.values))
+	for name, metric := range this.values {
+		values[name] = *metric
+	}
+	return values
+}
+
+// Serve every measurement as json
+func (this *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(this.Snapshot())
+}
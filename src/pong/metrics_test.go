@@ -0,0 +1,35 @@
+package pong
+
+import (
+	"testing"
+)
+
+// Observing a measurement should track the last value, the max, and how many were seen
+func Test_Metrics_Observe(t *testing.T) {
+	metrics := NewMetrics()
+
+	if _, ok := metrics.Get("frame"); ok {
+		t.Fatal("Found a measurement that was never observed")
+	}
+
+	metrics.Observe("frame", 4)
+	metrics.Observe("frame", 10)
+	metrics.Observe("frame", 6)
+
+	metric, ok := metrics.Get("frame")
+	if !ok {
+		t.Fatal("Measurement not found")
+	}
+	Assert(int(metric.Last), 6, "Last", t)
+	Assert(int(metric.Max), 10, "Max", t)
+	Assert(metric.Count, 3, "Count", t)
+	if metric.Average <= 4 || metric.Average >= 10 {
+		t.Fatal("Average was", metric.Average)
+	}
+}
+
+// Frames should be padded to whole words with room for the null bytes on each end
+func Test_LedFrameSize(t *testing.T) {
+	Assert(ledFrameSize(300), 908, "300 leds", t)
+	Assert(ledFrameSize(61), 192, "61 leds", t)
+}
@@ -0,0 +1,65 @@
+package breakout
+
+import (
+	"fmt"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+func init() {
+	RegisterGameMode("breakout", RGBA{255, 160, 0, 255}, func() GameMode { return &Breakout{} })
+}
+
+// The left player returns the ball to break the bricks at the far end, each level has more bricks and a faster ball
+type Breakout struct {
+	player     *Player
+	controller *BreakoutController
+	drawables  []Drawable
+}
+
+var _ SummarizedGameMode = &Breakout{}
+var _ ScoredGameMode = &Breakout{}
+
+// Add the ball, the left player, the bricks and the lives left, and serve
+func (this *Breakout) Setup(field Field, config GameConfig) {
+
+	ball := NewServedBall(field, true)
+	this.player = NewProfilePlayer(true, config.LeftProfile, field)
+	bricks := NewBricks()
+	lives := NewLivesDisplay(field, BreakoutLives)
+	this.drawables = []Drawable{ball, this.player, bricks, lives}
+	for _, drawable := range this.drawables {
+		field.Add(drawable)
+	}
+
+	this.controller = NewBreakoutController(field, ball, this.player, bricks, lives)
+}
+
+// Only the left button is used
+func (this *Breakout) HandleInput(left, right bool) {
+	this.player.UpdatePaddleActive(left)
+}
+
+// Bounce the ball, finishing once the player runs out of lives
+func (this *Breakout) Tick(dt float64) GameOutcome {
+	if this.controller.Update() {
+		return GameFinished
+	}
+	return GameInProgress
+}
+
+// Drawables added by the game
+func (this *Breakout) Drawables() []Drawable {
+	return this.drawables
+}
+
+// Bricks broken
+func (this *Breakout) Score() int {
+	return this.controller.Broken
+}
+
+// Level reached and bricks broken
+func (this *Breakout) Summary() string {
+	return fmt.Sprint("Game over. Level ", this.controller.Level+1, ", ", this.controller.Broken, " bricks")
+}
@@ -0,0 +1,215 @@
+package breakout
+
+import (
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+// Width of each brick and the gap between them, in leds
+var breakoutBrickWidth float64 = 3
+var breakoutBrickGap float64 = 1
+
+// Lives a Breakout game starts with
+var BreakoutLives int = 3
+
+// Speed of the ball on the first level as a fraction of the field width per second, and how much faster each level is
+var breakoutSpeed float64 = 0.5
+var breakoutLevelSpeed float64 = 0.1
+
+// Color of bricks by the hits they have left
+var brickColors = []RGBA{{255, 160, 0, 255}, {255, 0, 0, 255}, {160, 0, 255, 255}}
+
+// A single brick, chipped away by each hit
+type brick struct {
+	left, right float64
+	strength    int
+}
+
+// Wall of bricks stacked against the right end of the field
+type Bricks struct {
+	bricks []brick
+}
+
+var _ Drawable = &Bricks{}
+
+// Construct an empty wall, Build it for each level
+func NewBricks() *Bricks {
+	return &Bricks{}
+}
+
+// Stack the bricks of level against the right end of field, later levels have more bricks that take more hits
+func (this *Bricks) Build(field Field, level int) {
+
+	// leave the left half to the player's life bar
+	maxBricks := int((float64(field.Width())/2 - breakoutBrickWidth) / (breakoutBrickWidth + breakoutBrickGap))
+	count := int(math.Min(float64(4+2*level), float64(maxBricks)))
+	strength := int(math.Min(float64(1+level/2), float64(len(brickColors))))
+
+	this.bricks = this.bricks[:0]
+	right := float64(field.Width()) - 1
+	for index := 0; index < count; index++ {
+		this.bricks = append(this.bricks, brick{right - breakoutBrickWidth + 1, right, strength})
+		right -= breakoutBrickWidth + breakoutBrickGap
+	}
+}
+
+// Number of bricks still standing
+func (this *Bricks) Standing() int {
+	return len(this.bricks)
+}
+
+// Left edge of the nearest brick still standing, false if they are all gone
+func (this *Bricks) front() (float64, bool) {
+	if len(this.bricks) == 0 {
+		return 0, false
+	}
+	return this.bricks[len(this.bricks)-1].left, true
+}
+
+// Take a hit off the nearest brick, returns true if it broke
+func (this *Bricks) hit() bool {
+	nearest := &this.bricks[len(this.bricks)-1]
+	nearest.strength--
+	if nearest.strength > 0 {
+		return false
+	}
+	this.bricks = this.bricks[:len(this.bricks)-1]
+	return true
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *Bricks) ColorAt(position float64, baseColor RGBA) RGBA {
+	for _, brick := range this.bricks {
+		if position >= brick.left && position <= brick.right {
+			return brickColors[brick.strength-1].BlendWith(baseColor)
+		}
+	}
+	return baseColor
+}
+
+// ZIndex
+func (this *Bricks) ZIndex() ZIndex {
+	return 5
+}
+
+// Bricks don't move
+func (this *Bricks) Animate(dt float64) bool {
+	return true
+}
+
+// Lives left shown as dots in the middle of the field
+type LivesDisplay struct {
+	lives  int
+	middle float64
+}
+
+var _ Drawable = &LivesDisplay{}
+
+// Construct a LivesDisplay in the middle of field showing lives
+func NewLivesDisplay(field Field, lives int) *LivesDisplay {
+	return &LivesDisplay{lives: lives, middle: math.Floor(float64(field.Width()) / 2)}
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *LivesDisplay) ColorAt(position float64, baseColor RGBA) RGBA {
+	offset := position - this.middle
+	if offset >= 0 && offset < float64(this.lives*2) && int(offset)%2 == 0 {
+		return RGBA{255, 255, 255, 96}.BlendWith(baseColor)
+	}
+	return baseColor
+}
+
+// ZIndex
+func (this *LivesDisplay) ZIndex() ZIndex {
+	return 5
+}
+
+// Dots don't move
+func (this *LivesDisplay) Animate(dt float64) bool {
+	return true
+}
+
+// Runs a game of Breakout, the left player returns the ball to chip away at the bricks until they run out of lives
+type BreakoutController struct {
+	field  Field
+	ball   *Ball
+	player *Player
+	bricks *Bricks
+	lives  *LivesDisplay
+
+	// level being played, from 0, and bricks broken in the game so far
+	Level  int
+	Broken int
+}
+
+// Construct a BreakoutController, building the first level and serving the ball
+func NewBreakoutController(field Field, ball *Ball, player *Player, bricks *Bricks, lives *LivesDisplay) *BreakoutController {
+
+	this := &BreakoutController{field: field, ball: ball, player: player, bricks: bricks, lives: lives}
+	this.lives.lives = BreakoutLives
+	this.bricks.Build(field, 0)
+	this.serve()
+	return this
+}
+
+// Lives left
+func (this *BreakoutController) Lives() int {
+	return this.lives.lives
+}
+
+// Speed of the ball on the current level in leds / second
+func (this *BreakoutController) levelSpeed() float64 {
+	return (breakoutSpeed + breakoutLevelSpeed*float64(this.Level)) * float64(this.field.Width())
+}
+
+// Put the ball on the player's paddle heading for the bricks at the speed of the level, with a full life bar
+func (this *BreakoutController) serve() {
+	this.ball.Place(float64(this.player.HitZone().Right), this.levelSpeed())
+	this.player.RefillLife()
+}
+
+// Bounce the ball off the paddle and bricks, returns true once the last life is lost
+func (this *BreakoutController) Update() (over bool) {
+
+	if this.ball.Velocity() > 0 {
+		front, ok := this.bricks.front()
+		if edge := front - 0.5; ok && this.ball.Position() >= edge {
+			this.ball.Bounce(edge, 1)
+			go PlaySound(RIGHTBOUNCE)
+
+			if this.bricks.hit() {
+				this.Broken++
+			}
+			if this.bricks.Standing() == 0 {
+				// the ball comes back at the speed of the next level
+				this.Level++
+				this.bricks.Build(this.field, this.Level)
+				this.ball.Accelerate(this.levelSpeed() / math.Abs(this.ball.Velocity()))
+			}
+		}
+		return false
+	}
+
+	zone := this.player.HitZone()
+	if this.ball.Position() >= float64(zone.Right) {
+		return false
+	}
+	if this.player.PaddleActive() {
+		this.ball.Bounce(float64(zone.Right), 1)
+		go PlaySound(LEFTBOUNCE)
+		return false
+	}
+	if this.ball.Position() >= float64(zone.Left) {
+		return false
+	}
+
+	go PlaySound(MISS)
+	this.lives.lives--
+	if this.lives.lives <= 0 {
+		return true
+	}
+	this.serve()
+	return false
+}
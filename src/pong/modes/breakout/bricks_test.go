@@ -0,0 +1,52 @@
+package breakout
+
+import (
+	"testing"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+// Returning every ball should break the bricks and move up levels, missing should cost lives until the game is over
+func Test_BreakoutController(t *testing.T) {
+
+	field := NewGameField(40)
+	ball, player, bricks := NewServedBall(field, true), NewPlayer(true, 3, field), NewBricks()
+	controller := NewBreakoutController(field, ball, player, bricks, NewLivesDisplay(field, BreakoutLives))
+	if bricks.Standing() != 4 {
+		t.Fatal("First level has", bricks.Standing(), "bricks")
+	}
+
+	play := func(returning bool, seconds float64) (over bool) {
+		dt := 1.0 / 120
+		for time := 0.0; time < seconds; time += dt {
+			player.UpdatePaddleActive(returning && ball.Velocity() < 0 && ball.Position() < 3)
+			ball.Animate(dt)
+			player.Animate(dt)
+			if controller.Update() {
+				return true
+			}
+		}
+		return false
+	}
+
+	if play(true, 60) {
+		t.Fatal("Game over while returning every ball")
+	}
+	if controller.Lives() != BreakoutLives {
+		t.Fatal("Lost lives while returning every ball")
+	}
+	if controller.Level < 2 || controller.Broken < 10 {
+		t.Fatal("Reached level", controller.Level, "breaking", controller.Broken)
+	}
+	if ball.Position() < 0 || ball.Position() > float64(field.Width()-1) {
+		t.Fatal("Ball left the field at", ball.Position())
+	}
+
+	if !play(false, 60) {
+		t.Fatal("Game not over after missing every ball")
+	}
+	if controller.Lives() != 0 {
+		t.Fatal("Game over with", controller.Lives(), "lives left")
+	}
+}
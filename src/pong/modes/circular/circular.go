@@ -0,0 +1,173 @@
+package circular
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+func init() {
+	RegisterGameMode("circular", RGBA{255, 0, 128, 255}, func() GameMode { return &Circular{} })
+}
+
+// Points a player has to score to win, and the fraction of the loop each player defends
+var circularPointsToWin int = 5
+var circularArcFraction float64 = 0.25
+
+// Pong around a strip that loops around a table, the ball keeps going around and each player defends an arc on
+// opposite sides of it, pressing while the ball is in their arc sends it on faster towards the other player and
+// letting it through scores for the other side
+type Circular struct {
+	field     Field
+	ball      *Ball
+	arcs      [2]*DefendedArc
+	drawables []Drawable
+
+	// buttons for this and the last frame, the arc the ball is in or -1, and if it has been returned from there
+	buttons, previous [2]bool
+	inArc             int
+	returned          bool
+
+	// if the field isn't a loop so the game can't be played
+	line bool
+
+	stats  GameStats
+	random *rand.Rand
+}
+
+var _ SummarizedGameMode = &Circular{}
+var _ StatsGameMode = &Circular{}
+var _ TopologyGameMode = &Circular{}
+
+// Played around a loop
+func (this *Circular) Topology() Topology {
+	return TopologyLoop
+}
+
+// Add the arcs on opposite sides of the loop and the ball, on a line the ball can't go around so the game ends straight
+// away
+func (this *Circular) Setup(field Field, config GameConfig) {
+
+	if field.Topology() != TopologyLoop {
+		log.Print("The circular mode needs Topology loop")
+		this.line = true
+		return
+	}
+
+	this.field = field
+	this.random = NewRand()
+	width := float64(field.Width())
+	reach := math.Floor(width * circularArcFraction / 2)
+	theme := CurrentTheme()
+	this.arcs[0] = NewDefendedArc(field, 0, reach, theme.PlayerColor(config.LeftProfile, true))
+	this.arcs[1] = NewDefendedArc(field, math.Floor(width/2), reach, theme.PlayerColor(config.RightProfile, false))
+	this.ball = NewBall(field)
+	this.serve(this.random.Intn(2))
+	this.inArc = -1
+
+	this.drawables = []Drawable{this.arcs[0], this.arcs[1], this.ball}
+	for _, drawable := range this.drawables {
+		field.Add(drawable)
+	}
+}
+
+// Start the ball just past the arc of the player at side heading away from it, at the speed it was first served at
+func (this *Circular) serve(side int) {
+
+	velocity := float64(this.field.Width()) / 2
+	if this.random.Float64() < 0.5 {
+		velocity = -velocity
+	}
+	position := this.arcs[side].Center() + math.Copysign(this.arcs[side].Reach()+1, velocity)
+	this.ball.Place(float64(Position(position).Wrap(float64(this.field.Width()))), velocity)
+}
+
+// Remember the buttons for the next tick
+func (this *Circular) HandleInput(left, right bool) {
+	this.previous = this.buttons
+	this.buttons = [2]bool{left, right}
+	if !this.line {
+		this.arcs[0].SetPressed(left)
+		this.arcs[1].SetPressed(right)
+	}
+}
+
+// Send the ball on when the player whose arc it is in presses, scoring for the other side if it gets through
+func (this *Circular) Tick(dt float64) GameOutcome {
+
+	if this.line {
+		return GameFinished
+	}
+
+	position := this.ball.Position()
+	inArc := -1
+	for side, arc := range this.arcs {
+		if arc.Covers(position) {
+			inArc = side
+		}
+	}
+
+	if inArc != this.inArc {
+		leftArc, returned := this.inArc, this.returned
+		this.inArc, this.returned = inArc, false
+		if leftArc >= 0 && !returned {
+			return this.missed(leftArc)
+		}
+	}
+
+	// a press that starts while the ball is in the arc sends it on, holding the button down doesn't
+	if inArc >= 0 && !this.returned && this.buttons[inArc] && !this.previous[inArc] {
+		this.returned = true
+		speed := math.Abs(this.ball.Velocity())
+		if speed > this.stats.FastestReturn {
+			this.stats.FastestReturn = speed
+		}
+		this.ball.Accelerate(Settings.BounceVelocityIncrease)
+		if inArc == 0 {
+			go PlaySound(LEFTBOUNCE)
+		} else {
+			go PlaySound(RIGHTBOUNCE)
+		}
+	}
+	return GameInProgress
+}
+
+// Score for the other side of the player at side who let the ball through
+func (this *Circular) missed(side int) GameOutcome {
+
+	go PlaySound(MISS)
+	if side == 0 {
+		this.stats.RightScore++
+	} else {
+		this.stats.LeftScore++
+	}
+	switch {
+	case this.stats.LeftScore >= circularPointsToWin:
+		return GameLeftWon
+	case this.stats.RightScore >= circularPointsToWin:
+		return GameRightWon
+	}
+
+	this.serve(1 - side)
+	this.inArc = -1
+	return GamePointScored
+}
+
+// Drawables added by the game
+func (this *Circular) Drawables() []Drawable {
+	return this.drawables
+}
+
+// Points scored by each side
+func (this *Circular) Summary() string {
+	return fmt.Sprint("Game over. ", this.stats.LeftScore, " to ", this.stats.RightScore)
+}
+
+// Points scored and the fastest return
+func (this *Circular) Stats() GameStats {
+	return this.stats
+}
@@ -0,0 +1,236 @@
+package classic
+
+import (
+	"fmt"
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+	"github.com/brandonagr/pongpi/src/pong/stats"
+)
+
+// Who controls the players besides the two people at the buttons
+type variant int
+
+const (
+	twoPlayers variant = iota
+	aiOpponent
+	aiTeammates
+	ghostOpponent
+)
+
+func init() {
+	RegisterGameMode("classic", RGBA{255, 255, 255, 255}, func() GameMode { return &Classic{variant: twoPlayers} })
+	RegisterGameMode("ai", RGBA{255, 0, 0, 255}, func() GameMode { return &Classic{variant: aiOpponent} })
+	RegisterGameMode("doubles", RGBA{255, 128, 0, 255}, func() GameMode { return &Classic{variant: aiTeammates} })
+	RegisterGameMode("ghost", RGBA{128, 0, 255, 255}, func() GameMode { return &Classic{variant: ghostOpponent} })
+}
+
+// Two players hit the ball back and forth, each miss costs life until one player runs out
+type Classic struct {
+	variant variant
+	config  GameConfig
+
+	// AI used for the opponent or teammates
+	personality AIPersonality
+
+	field                   Field
+	ball                    *Ball
+	leftPlayer, rightPlayer *Player
+	drawables               []Drawable
+
+	// buttons pushed this frame, and the input the players are controlled by
+	buttons *ButtonState
+	input   ButtonInput
+
+	recording    *GameRecording
+	totalBounces int
+
+	// bounces in the current rally, and the stats of the game so far
+	rallyBounces int
+	stats        GameStats
+}
+
+var _ SummarizedGameMode = &Classic{}
+var _ RecordedGameMode = &Classic{}
+var _ RatedGameMode = &Classic{}
+var _ StatsGameMode = &Classic{}
+var _ ContextGameMode = &Classic{}
+
+// Add the ball and players, and hook the AI or ghost up to the input
+func (this *Classic) Setup(field Field, config GameConfig) {
+
+	this.config = config
+	this.field = field
+	this.buttons = &ButtonState{}
+
+	if this.variant == ghostOpponent && config.Ghost != nil {
+		this.ball = NewServedBall(field, config.Ghost.ServedFromLeft)
+	} else {
+		this.ball = NewBall(field)
+	}
+	this.add(this.ball)
+	this.recording = NewGameRecording(this.ball.Velocity() > 0)
+	this.recording.LeftLife = config.LeftProfile.Life(Settings.LifeInSeconds)
+	this.recording.RightLife = config.RightProfile.Life(Settings.LifeInSeconds)
+	this.recording.RecordBall(this.ball.Position(), this.ball.Velocity(), "")
+
+	this.leftPlayer = NewProfilePlayer(true, config.LeftProfile, field)
+	this.add(this.leftPlayer)
+	this.rightPlayer = NewProfilePlayer(false, config.RightProfile, field)
+	this.add(this.rightPlayer)
+
+	var ok bool
+	if this.personality, ok = FindAIPersonality(config.Difficulty); !ok {
+		this.personality = AIPersonalities[0]
+	}
+
+	this.input = this.buttons
+	switch {
+	case config.Demo:
+		random := NewRand()
+		leftAI := NewAIPlayer(this.leftPlayer, this.ball, AIPersonalities[random.Intn(len(AIPersonalities))])
+		rightAI := NewAIPlayer(this.rightPlayer, this.ball, AIPersonalities[random.Intn(len(AIPersonalities))])
+		this.input = NewAIInput(leftAI, rightAI)
+	case this.variant == ghostOpponent && config.Ghost != nil:
+		this.input = NewGhostInput(this.buttons, config.Ghost)
+	case this.variant == aiOpponent:
+		this.input = NewAIOpponentInput(this.buttons, NewAIPlayer(this.rightPlayer, this.ball, this.personality))
+	case this.variant == aiTeammates:
+		leftTeammate := NewAITeammate(NewAIPlayer(this.leftPlayer, this.ball, this.personality), Settings.DoublesGraceSeconds)
+		rightTeammate := NewAITeammate(NewAIPlayer(this.rightPlayer, this.ball, this.personality), Settings.DoublesGraceSeconds)
+		this.input = NewDoublesInput(this.buttons, leftTeammate, rightTeammate)
+	}
+}
+
+// Add drawable to the field
+func (this *Classic) add(drawable Drawable) {
+	this.field.Add(drawable)
+	this.drawables = append(this.drawables, drawable)
+}
+
+// Remember the buttons for the next tick
+func (this *Classic) HandleInput(left, right bool) {
+	this.buttons.Left = left
+	this.buttons.Right = right
+}
+
+// Move the paddles and check if the ball was missed
+func (this *Classic) Tick(dt float64) GameOutcome {
+
+	if timedInput, ok := this.input.(TimedInput); ok {
+		timedInput.Advance(dt)
+	}
+
+	leftButton, rightButton := this.input.LeftButton(), this.input.RightButton()
+	this.recording.Record(dt, leftButton, rightButton)
+
+	this.leftPlayer.UpdatePaddleActive(leftButton)
+	this.rightPlayer.UpdatePaddleActive(rightButton)
+
+	this.ball.UpdateOffensiveHide(this.leftPlayer, this.rightPlayer)
+	this.ball.TrackPresses(this.leftPlayer, this.rightPlayer)
+
+	velocity, position := this.ball.Velocity(), this.ball.Position()
+	speed := math.Abs(velocity)
+	playerMissed, bounce := this.ball.MissedByPlayer(this.leftPlayer, this.rightPlayer, Settings.BounceVelocityIncrease)
+	if bounce || playerMissed != nil {
+		this.recordHit(velocity, position, bounce)
+	}
+	if bounce {
+		this.recording.RecordBall(this.ball.Position(), this.ball.Velocity(), "")
+		this.totalBounces++
+		this.rallyBounces++
+		if speed > this.stats.FastestReturn {
+			this.stats.FastestReturn = speed
+		}
+	}
+	if playerMissed == nil {
+		return GameInProgress
+	}
+
+	this.stats.Rallies = append(this.stats.Rallies, this.rallyBounces)
+	this.rallyBounces = 0
+	if playerMissed == this.leftPlayer {
+		this.stats.RightScore++
+	} else {
+		this.stats.LeftScore++
+	}
+	RubberBand(this.leftPlayer, this.rightPlayer, this.stats.LeftScore, this.stats.RightScore)
+
+	// take life from the player who missed and serve again, or end the game
+	if Settings.CoachSeconds > 0 && !this.config.Demo {
+		this.field.Add(NewCoach(playerMissed, this.ball.Velocity(), Settings.CoachSeconds))
+	}
+	this.ball.ResetPosition(this.field)
+	missed := "right"
+	if playerMissed == this.leftPlayer {
+		missed = "left"
+	}
+	this.recording.RecordBall(this.ball.Position(), this.ball.Velocity(), missed)
+
+	if !playerMissed.DecreaseLife(MissLifePenalty) {
+		return GamePointScored
+	}
+	if playerMissed == this.leftPlayer {
+		return GameRightWon
+	}
+	return GameLeftWon
+}
+
+// Remember how the ball moving at velocity reached the player it was heading for at position, before it bounced
+func (this *Classic) recordHit(velocity, position float64, returned bool) {
+
+	player := this.rightPlayer
+	if velocity < 0 {
+		player = this.leftPlayer
+	}
+
+	hit := stats.Hit{
+		Rally:    len(this.stats.Rallies),
+		Left:     player == this.leftPlayer,
+		Speed:    math.Abs(velocity),
+		Position: position,
+		Returned: returned,
+	}
+	hit.Timing, hit.Pressed = player.PressTiming(velocity)
+	this.stats.Hits = append(this.stats.Hits, hit)
+}
+
+// Where the ball is, at match point when a miss by either player ends the game
+func (this *Classic) UpdateContext(context *AnimateContext) {
+	context.BallPosition, context.BallVelocity, context.HasBall = this.ball.Position(), this.ball.Velocity(), true
+	context.MatchPoint = this.leftPlayer.OnLastLife() || this.rightPlayer.OnLastLife()
+}
+
+// Drawables added when the game was set up
+func (this *Classic) Drawables() []Drawable {
+	return this.drawables
+}
+
+// Number of bounces in the game
+func (this *Classic) Summary() string {
+	return fmt.Sprint("Game over. Score ", this.totalBounces)
+}
+
+// Buttons pushed during the game
+func (this *Classic) Recording() *GameRecording {
+	return this.recording
+}
+
+// Rallies, score and fastest return of the game
+func (this *Classic) Stats() GameStats {
+	return this.stats
+}
+
+// The right player is the AI when playing against one, games against a ghost aren't rated
+func (this *Classic) PlayerNames() (left, right string, rated bool) {
+
+	left = this.config.LeftProfile.PlayerName(Settings.LeftPlayerName)
+	right = this.config.RightProfile.PlayerName(Settings.RightPlayerName)
+	if this.variant == aiOpponent {
+		right = this.personality.Name
+	}
+
+	return left, right, !this.config.Demo && this.variant != ghostOpponent
+}
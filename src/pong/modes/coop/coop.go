@@ -0,0 +1,93 @@
+package coop
+
+import (
+	"fmt"
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+func init() {
+	RegisterGameMode("coop", RGBA{0, 255, 255, 255}, func() GameMode { return &Coop{} })
+}
+
+// Fraction the ball speeds up by every second, returns don't speed it up any further
+var coopAcceleration float64 = 0.05
+
+// Both players keep a single rally going against a ball that never stops speeding up, each has to return it in turn
+// and the first miss ends the run, the returns of both players are the score
+type Coop struct {
+	field                   Field
+	ball                    *Ball
+	leftPlayer, rightPlayer *Player
+	drawables               []Drawable
+
+	// returns made together, and the records of the run
+	returns int
+	stats   GameStats
+}
+
+var _ SummarizedGameMode = &Coop{}
+var _ ScoredGameMode = &Coop{}
+var _ StatsGameMode = &Coop{}
+
+// Add the ball and both players
+func (this *Coop) Setup(field Field, config GameConfig) {
+
+	this.field = field
+	this.ball = NewBall(field)
+	this.leftPlayer = NewProfilePlayer(true, config.LeftProfile, field)
+	this.rightPlayer = NewProfilePlayer(false, config.RightProfile, field)
+	this.drawables = []Drawable{this.ball, this.leftPlayer, this.rightPlayer}
+	for _, drawable := range this.drawables {
+		field.Add(drawable)
+	}
+}
+
+// Hold each paddle while its button is down
+func (this *Coop) HandleInput(left, right bool) {
+	this.leftPlayer.UpdatePaddleActive(left)
+	this.rightPlayer.UpdatePaddleActive(right)
+}
+
+// Speed the ball up, counting every return and ending the run on the first miss
+func (this *Coop) Tick(dt float64) GameOutcome {
+
+	this.ball.Accelerate(1 + coopAcceleration*dt)
+
+	speed := math.Abs(this.ball.Velocity())
+	playerMissed, bounce := this.ball.MissedByPlayer(this.leftPlayer, this.rightPlayer, 1)
+	if bounce {
+		this.returns++
+		if speed > this.stats.FastestReturn {
+			this.stats.FastestReturn = speed
+		}
+	}
+	if playerMissed == nil {
+		return GameInProgress
+	}
+
+	this.stats.Rallies = append(this.stats.Rallies, this.returns)
+	return GameFinished
+}
+
+// Drawables added by the game
+func (this *Coop) Drawables() []Drawable {
+	return this.drawables
+}
+
+// Returns made together
+func (this *Coop) Score() int {
+	return this.returns
+}
+
+// Fastest return of the run
+func (this *Coop) Stats() GameStats {
+	return this.stats
+}
+
+// Returns made together and how fast the ball got
+func (this *Coop) Summary() string {
+	return fmt.Sprintf("Game over. %v returns together, up to %.0f leds a second", this.returns, this.stats.FastestReturn)
+}
@@ -0,0 +1,117 @@
+package crowd
+
+import (
+	"fmt"
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+func init() {
+	RegisterGameMode("crowd", RGBA{255, 0, 128, 255}, func() GameMode { return &Crowd{} })
+}
+
+// Leds wide the hit zones are, wide enough for a crowd tapping on phones over wifi to catch the ball
+var crowdHitZoneWidth float64 = 3
+
+// The crowd on each side plays with their phones, a side returns the ball when enough of its crowd taps as the ball
+// reaches its wide hit zone. The buttons at the ends still work for anyone standing at them
+type Crowd struct {
+	field     Field
+	crowd     *CrowdInput
+	ball      *Ball
+	players   [2]*Player
+	drawables []Drawable
+
+	// buttons held at the ends of the field
+	left, right bool
+
+	returns int
+	stats   GameStats
+}
+
+var _ SummarizedGameMode = &Crowd{}
+var _ StatsGameMode = &Crowd{}
+
+// Add the ball and both players with wide hit zones
+func (this *Crowd) Setup(field Field, config GameConfig) {
+
+	this.field = field
+	this.crowd = config.Crowd
+	this.ball = NewBall(field)
+	this.drawables = []Drawable{this.ball}
+
+	for side, profile := range []PlayerProfile{config.LeftProfile, config.RightProfile} {
+		this.players[side] = NewProfilePlayer(side == 0, profile, field)
+		this.players[side].SetHitZone(crowdHitZoneWidth, 0)
+		this.drawables = append(this.drawables, this.players[side])
+	}
+
+	for _, drawable := range this.drawables {
+		field.Add(drawable)
+	}
+}
+
+// Remember the buttons at the ends for the next tick
+func (this *Crowd) HandleInput(left, right bool) {
+	this.left, this.right = left, right
+}
+
+// Hold each paddle while its crowd or button pushes it, and check if the ball was missed
+func (this *Crowd) Tick(dt float64) GameOutcome {
+
+	left, right := this.left, this.right
+	if this.crowd != nil {
+		left = left || this.crowd.LeftButton()
+		right = right || this.crowd.RightButton()
+	}
+	this.players[0].UpdatePaddleActive(left)
+	this.players[1].UpdatePaddleActive(right)
+
+	speed := math.Abs(this.ball.Velocity())
+	playerMissed, bounce := this.ball.MissedByPlayer(this.players[0], this.players[1], Settings.BounceVelocityIncrease)
+	if bounce {
+		this.returns++
+		if speed > this.stats.FastestReturn {
+			this.stats.FastestReturn = speed
+		}
+	}
+	if playerMissed == nil {
+		return GameInProgress
+	}
+
+	if playerMissed == this.players[0] {
+		this.stats.RightScore++
+	} else {
+		this.stats.LeftScore++
+	}
+	this.ball.ResetPosition(this.field)
+
+	if !playerMissed.DecreaseLife(MissLifePenalty) {
+		return GamePointScored
+	}
+	if playerMissed == this.players[0] {
+		return GameRightWon
+	}
+	return GameLeftWon
+}
+
+// Drawables added by the game
+func (this *Crowd) Drawables() []Drawable {
+	return this.drawables
+}
+
+// Returns made by the crowds, and how many phones were playing
+func (this *Crowd) Summary() string {
+	left, right := 0, 0
+	if this.crowd != nil {
+		left, right = this.crowd.Connected()
+	}
+	return fmt.Sprint("Game over. ", left+right, " phones made ", this.returns, " returns")
+}
+
+// Points scored and the fastest return
+func (this *Crowd) Stats() GameStats {
+	return this.stats
+}
@@ -0,0 +1,151 @@
+package drill
+
+import (
+	"fmt"
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+// A single scripted serve in a drill
+type DrillServe struct {
+
+	// speed of the ball as a fraction of the field width per second
+	Speed float64
+
+	// width in leds of the window where holding the paddle returns the ball
+	HitWindow float64
+}
+
+// Alternates fast and slow serves while shrinking the hit window
+var StandardDrill = []DrillServe{
+	{0.4, 3.0}, {0.8, 3.0},
+	{0.4, 2.5}, {0.8, 2.5},
+	{0.5, 2.0}, {0.9, 2.0},
+	{0.5, 1.5}, {1.0, 1.5},
+	{0.6, 1.0}, {1.1, 1.0},
+}
+
+// Outcome of a single serve in a drill
+type DrillResult struct {
+
+	// if the ball was returned
+	Returned bool
+
+	// seconds between the press and the ball reaching the center of the hit window, positive when early
+	Offset float64
+
+	// if the player pressed at all during the serve
+	Pressed bool
+}
+
+// Serves the ball to the left player following a script and measures the timing of each return
+type DrillController struct {
+	field  Field
+	ball   *Ball
+	player *Player
+
+	// script being followed and the serve currently in play
+	serves     []DrillServe
+	serveIndex int
+
+	// button state from the previous update
+	buttonDown bool
+
+	// result for the serve currently in play
+	current DrillResult
+
+	// results of each finished serve
+	Results []DrillResult
+}
+
+// Construct a DrillController and serve the first ball
+func NewDrillController(field Field, ball *Ball, player *Player, serves []DrillServe) *DrillController {
+	drill := &DrillController{
+		field:  field,
+		ball:   ball,
+		player: player,
+		serves: serves,
+	}
+
+	drill.serve()
+
+	return drill
+}
+
+// Put the ball at the right end moving toward the player
+func (this *DrillController) serve() {
+
+	serve := this.serves[this.serveIndex]
+
+	this.ball.Place(float64(this.field.Width()-1), -serve.Speed*float64(this.field.Width()))
+	this.player.SetHitWindow(serve.HitWindow)
+	this.player.RefillLife()
+	this.current = DrillResult{}
+}
+
+// Update the drill with the current button state, returns true once every serve is finished
+func (this *DrillController) Update(buttonDown bool) (finished bool) {
+
+	zone := this.player.HitZone()
+	if buttonDown && !this.buttonDown && !this.current.Pressed {
+		center := float64(zone.Left+zone.Right) / 2.0
+		this.current.Pressed = true
+		this.current.Offset = (this.ball.Position() - center) / math.Abs(this.ball.Velocity())
+	}
+	this.buttonDown = buttonDown
+
+	if this.ball.Position() >= float64(zone.Right) {
+		return false
+	}
+
+	if this.player.PaddleActive() {
+		this.current.Returned = true
+		go PlaySound(LEFTBOUNCE)
+	} else if this.ball.Position() < float64(zone.Left) {
+		go PlaySound(MISS)
+	} else {
+		return false
+	}
+
+	this.Results = append(this.Results, this.current)
+
+	this.serveIndex++
+	if this.serveIndex >= len(this.serves) {
+		return true
+	}
+
+	this.serve()
+	return false
+}
+
+// Describe the results of a drill so it can be read out
+func DrillSummary(results []DrillResult) string {
+
+	returned := 0
+	totalOffset := 0.0
+	pressed := 0
+
+	for _, result := range results {
+		if result.Returned {
+			returned++
+		}
+		if result.Pressed {
+			pressed++
+			totalOffset += result.Offset
+		}
+	}
+
+	summary := fmt.Sprint("Drill complete. Returned ", returned, " of ", len(results), ".")
+	if pressed > 0 {
+		averageMilliseconds := int(math.Abs(totalOffset/float64(pressed)) * 1000)
+		if totalOffset > 0 {
+			summary += fmt.Sprint(" Average ", averageMilliseconds, " milliseconds early.")
+		} else {
+			summary += fmt.Sprint(" Average ", averageMilliseconds, " milliseconds late.")
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,64 @@
+package drill
+
+import (
+	"log"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+func init() {
+	RegisterGameMode("drill", RGBA{0, 255, 255, 255}, func() GameMode { return &Drill{} })
+}
+
+// Timing drills for the left player, serves at set speeds and hit windows report how early or late each press was
+type Drill struct {
+	player     *Player
+	controller *DrillController
+	drawables  []Drawable
+
+	// if the left button is held this frame
+	buttonDown bool
+}
+
+var _ SummarizedGameMode = &Drill{}
+
+// Add the ball and the left player, and serve the first drill
+func (this *Drill) Setup(field Field, config GameConfig) {
+
+	ball := NewBall(field)
+	this.player = NewProfilePlayer(true, config.LeftProfile, field)
+	this.drawables = []Drawable{ball, this.player}
+	for _, drawable := range this.drawables {
+		field.Add(drawable)
+	}
+
+	this.controller = NewDrillController(field, ball, this.player, StandardDrill)
+}
+
+// Only the left button is used
+func (this *Drill) HandleInput(left, right bool) {
+	this.buttonDown = left
+	this.player.UpdatePaddleActive(left)
+}
+
+// Move on to the next serve, finishing once every serve is done
+func (this *Drill) Tick(dt float64) GameOutcome {
+	if this.controller.Update(this.buttonDown) {
+		for index, result := range this.controller.Results {
+			log.Print("Drill serve ", index, ": ", result)
+		}
+		return GameFinished
+	}
+	return GameInProgress
+}
+
+// Drawables added by the drill
+func (this *Drill) Drawables() []Drawable {
+	return this.drawables
+}
+
+// How the serves went
+func (this *Drill) Summary() string {
+	return DrillSummary(this.controller.Results)
+}
@@ -0,0 +1,80 @@
+package fourplayer
+
+import (
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Brightness of the zone of a far paddle while its player isn't pressing
+var farPaddleIdleAlpha uint8 = 40
+
+// Paddle of the second player of a team, covering the leds just in front of the paddle at the end of the field so
+// the ball reaches it first
+type FarPaddle struct {
+	color RGBA
+
+	// range of positions the paddle covers, and the edge the ball is returned off
+	left, right float64
+	edge        float64
+
+	active bool
+}
+
+var _ Drawable = &FarPaddle{}
+
+// Construct a FarPaddle in color at the left or right end of field, depth leds deep in front of the end paddle
+func NewFarPaddle(field Field, isLeft bool, depth float64, color RGBA) *FarPaddle {
+
+	paddle := &FarPaddle{color: color}
+	if isLeft {
+		paddle.left, paddle.right = 0.5, 0.5+depth
+		paddle.edge = paddle.right
+	} else {
+		end := float64(field.Width()) - 1.5
+		paddle.left, paddle.right = end-depth, end
+		paddle.edge = paddle.left
+	}
+	return paddle
+}
+
+// Set if the player is holding down the button
+func (this *FarPaddle) SetActive(active bool) {
+	this.active = active
+}
+
+// If the player is holding down the button
+func (this *FarPaddle) Active() bool {
+	return this.active
+}
+
+// If position is in front of the paddle, where it can return the ball
+func (this *FarPaddle) Covers(position float64) bool {
+	return this.left < position && position < this.right
+}
+
+// Edge of the paddle facing the other end, the ball is returned off it
+func (this *FarPaddle) Edge() float64 {
+	return this.edge
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *FarPaddle) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	if !this.Covers(position) {
+		return baseColor
+	}
+	color := this.color
+	if !this.active {
+		color.A = farPaddleIdleAlpha
+	}
+	return color.BlendWith(baseColor)
+}
+
+// Above the life bars of the players
+func (this *FarPaddle) ZIndex() ZIndex {
+	return 11
+}
+
+// Animate
+func (this *FarPaddle) Animate(dt float64) bool {
+	return true
+}
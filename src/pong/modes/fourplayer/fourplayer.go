@@ -0,0 +1,157 @@
+package fourplayer
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+func init() {
+	RegisterGameMode("fourplayer", RGBA{255, 255, 0, 255}, func() GameMode { return &FourPlayer{} })
+}
+
+// Leds the zone of each far player reaches in front of the end paddle
+var farZoneDepth float64 = 2
+
+// Two players at each end, one on the end paddle with the main button and one on the zone in front of it with the
+// second button. The ball reaches the far zone first, a press there returns it, but if both teammates are pressing as
+// it comes through they get in each other's way and the team loses the point
+type FourPlayer struct {
+	field     Field
+	ball      *Ball
+	teams     [2]*team
+	drawables []Drawable
+
+	// returns made by the near and far players of both teams
+	nearReturns, farReturns int
+	stats                   GameStats
+}
+
+// The players at one end of the field
+type team struct {
+	near *Player
+	far  *FarPaddle
+
+	// if the near player is holding their button
+	nearPressed bool
+}
+
+var _ SummarizedGameMode = &FourPlayer{}
+var _ StatsGameMode = &FourPlayer{}
+var _ DownButtonGameMode = &FourPlayer{}
+
+// Add the ball and both teams, the near player of each team carries its life
+func (this *FourPlayer) Setup(field Field, config GameConfig) {
+
+	this.field = field
+	this.ball = NewBall(field)
+	this.drawables = []Drawable{this.ball}
+
+	theme := CurrentTheme()
+	for side, profile := range []PlayerProfile{config.LeftProfile, config.RightProfile} {
+		isLeft := side == 0
+		near := NewProfilePlayer(isLeft, profile, field)
+		far := NewFarPaddle(field, isLeft, farZoneDepth, theme.PlayerColor(profile, isLeft).Lighter())
+		this.teams[side] = &team{near: near, far: far}
+		this.drawables = append(this.drawables, near, far)
+	}
+
+	for _, drawable := range this.drawables {
+		field.Add(drawable)
+	}
+}
+
+// Hold the end paddles while the main buttons are down
+func (this *FourPlayer) HandleInput(left, right bool) {
+	for side, pressed := range []bool{left, right} {
+		this.teams[side].near.UpdatePaddleActive(pressed)
+		this.teams[side].nearPressed = pressed
+	}
+}
+
+// Hold the far paddles while the second buttons are down, without them only the near players play
+func (this *FourPlayer) HandleDownInput(leftDown, rightDown bool) {
+	this.teams[0].far.SetActive(leftDown)
+	this.teams[1].far.SetActive(rightDown)
+}
+
+// Return the ball off whichever paddle it reaches first, taking life from the team that missed or fumbled it
+func (this *FourPlayer) Tick(dt float64) GameOutcome {
+
+	velocity := this.ball.Velocity()
+	speed := math.Abs(velocity)
+	defending := this.teams[1]
+	if velocity < 0 {
+		defending = this.teams[0]
+	}
+
+	if defending.far.Covers(this.ball.Position()) && defending.far.Active() {
+		if defending.nearPressed {
+			log.Print("Both players went for the ball")
+			return this.missed(defending)
+		}
+		this.ball.Bounce(defending.far.Edge(), Settings.BounceVelocityIncrease)
+		this.returned(speed)
+		this.farReturns++
+		if defending == this.teams[0] {
+			go PlaySound(LEFTBOUNCE)
+		} else {
+			go PlaySound(RIGHTBOUNCE)
+		}
+		return GameInProgress
+	}
+
+	playerMissed, bounce := this.ball.MissedByPlayer(this.teams[0].near, this.teams[1].near, Settings.BounceVelocityIncrease)
+	if bounce {
+		this.returned(speed)
+		this.nearReturns++
+	}
+	if playerMissed == nil {
+		return GameInProgress
+	}
+	return this.missed(defending)
+}
+
+// Count a return of the ball moving at speed
+func (this *FourPlayer) returned(speed float64) {
+	if speed > this.stats.FastestReturn {
+		this.stats.FastestReturn = speed
+	}
+}
+
+// Score for the other team, taking life from the team that missed and serving again or ending the game
+func (this *FourPlayer) missed(missing *team) GameOutcome {
+
+	if missing == this.teams[0] {
+		this.stats.RightScore++
+	} else {
+		this.stats.LeftScore++
+	}
+	this.ball.ResetPosition(this.field)
+
+	if !missing.near.DecreaseLife(MissLifePenalty) {
+		return GamePointScored
+	}
+	if missing == this.teams[0] {
+		return GameRightWon
+	}
+	return GameLeftWon
+}
+
+// Drawables added by the game
+func (this *FourPlayer) Drawables() []Drawable {
+	return this.drawables
+}
+
+// Returns made by the near and far players
+func (this *FourPlayer) Summary() string {
+	return fmt.Sprint("Game over. ", this.nearReturns, " returns on the end paddles and ", this.farReturns, " in front of them")
+}
+
+// Points scored and the fastest return
+func (this *FourPlayer) Stats() GameStats {
+	return this.stats
+}
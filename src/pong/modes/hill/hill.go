@@ -0,0 +1,131 @@
+package hill
+
+import (
+	"fmt"
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+func init() {
+	RegisterGameMode("hill", RGBA{128, 255, 128, 255}, func() GameMode { return &Hill{} })
+}
+
+// Points in a row the champion has to win to take the game
+var hillStreakToWin int = 7
+
+// King of the hill, every point is a game of its own, the loser steps out and the next player in the queue takes their
+// button, the game is won by holding the hill for a streak of points
+type Hill struct {
+	field Field
+	queue *HillQueue
+	ball  *Ball
+
+	// players at each button, their names, and the dots showing the champion's streak
+	players   [2]*Player
+	names     [2]string
+	streak    *StreakMarks
+	drawables []Drawable
+
+	// side holding the hill and how long they have, and the records of the game
+	championLeft bool
+	points       int
+	stats        GameStats
+}
+
+var _ SummarizedGameMode = &Hill{}
+var _ StatsGameMode = &Hill{}
+
+// Put the first two players of the queue at the buttons, the chosen profiles play when nobody is queued
+func (this *Hill) Setup(field Field, config GameConfig) {
+
+	this.field = field
+	this.queue = config.Hill
+	if this.queue == nil {
+		this.queue = NewHillQueue(nil)
+	}
+	this.names[0], this.names[1] = this.queue.Start(config.LeftProfile.Name, config.RightProfile.Name)
+
+	this.ball = NewBall(field)
+	this.streak = NewStreakMarks(field)
+	this.drawables = []Drawable{this.ball, this.streak}
+	for side, name := range this.names {
+		this.players[side] = NewProfilePlayer(side == 0, this.queue.Profile(name), field)
+		this.drawables = append(this.drawables, this.players[side])
+	}
+
+	for _, drawable := range this.drawables {
+		field.Add(drawable)
+	}
+}
+
+// Hold each paddle while its button is down
+func (this *Hill) HandleInput(left, right bool) {
+	this.players[0].UpdatePaddleActive(left)
+	this.players[1].UpdatePaddleActive(right)
+}
+
+// Play the point, rotating the next player in for the loser once it is over
+func (this *Hill) Tick(dt float64) GameOutcome {
+
+	speed := math.Abs(this.ball.Velocity())
+	playerMissed, bounce := this.ball.MissedByPlayer(this.players[0], this.players[1], Settings.BounceVelocityIncrease)
+	if bounce && speed > this.stats.FastestReturn {
+		this.stats.FastestReturn = speed
+	}
+	if playerMissed == nil {
+		return GameInProgress
+	}
+
+	leftWon := playerMissed == this.players[1]
+	if leftWon {
+		this.stats.LeftScore++
+	} else {
+		this.stats.RightScore++
+	}
+
+	next, replaced, streak := this.queue.PointWon(leftWon)
+	winner := 1
+	if leftWon {
+		winner = 0
+	}
+	this.championLeft, this.points = leftWon, streak
+	this.streak.SetStreak(leftWon, streak, CurrentTheme().PlayerColor(this.queue.Profile(this.names[winner]), leftWon))
+	if streak >= hillStreakToWin {
+		if leftWon {
+			return GameLeftWon
+		}
+		return GameRightWon
+	}
+
+	// every point starts with fresh life, the next player taking over from the loser
+	loser := 1 - winner
+	this.names[loser] = next
+	this.players[loser].TakeOver(this.queue.Profile(next))
+	this.players[winner].TakeOver(this.queue.Profile(this.names[winner]))
+	if replaced {
+		go PlayTTS(next + " is up")
+	}
+	this.ball.ResetPosition(this.field)
+	return GamePointScored
+}
+
+// Drawables added by the game
+func (this *Hill) Drawables() []Drawable {
+	return this.drawables
+}
+
+// Who held the hill and for how long
+func (this *Hill) Summary() string {
+	champion := this.names[1]
+	if this.championLeft {
+		champion = this.names[0]
+	}
+	return fmt.Sprint("Game over. ", champion, " held the hill for ", this.points, " points")
+}
+
+// Points won on each side and the fastest return
+func (this *Hill) Stats() GameStats {
+	return this.stats
+}
@@ -0,0 +1,114 @@
+package lanes
+
+import (
+	"fmt"
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+func init() {
+	RegisterGameMode("lanes", RGBA{0, 128, 255, 255}, func() GameMode { return &Lanes{} })
+}
+
+// Most lanes a rally is played in at once
+var maxLanes int = 4
+
+// A rally in every lane of the field at once, each served from the other end to the lane before it. Each paddle covers
+// every lane so one press can return several balls, each miss costs life until one player runs out
+type Lanes struct {
+	field                   Field
+	balls                   []*Ball
+	leftPlayer, rightPlayer *Player
+	drawables               []Drawable
+
+	// returns made in every lane together, and the records of the game
+	returns int
+	stats   GameStats
+}
+
+var _ SummarizedGameMode = &Lanes{}
+var _ StatsGameMode = &Lanes{}
+
+// Add the players across every lane and a ball in each, a strip only has the one lane
+func (this *Lanes) Setup(field Field, config GameConfig) {
+
+	this.field = field
+	this.leftPlayer = NewProfilePlayer(true, config.LeftProfile, field)
+	this.rightPlayer = NewProfilePlayer(false, config.RightProfile, field)
+	this.drawables = []Drawable{this.leftPlayer, this.rightPlayer}
+
+	lanes := int(math.Min(float64(field.Height()), float64(maxLanes)))
+	fromLeft := NewRand().Float64() < 0.5
+	for lane := 0; lane < lanes; lane++ {
+		ball := NewServedBall(field, fromLeft)
+		this.balls = append(this.balls, ball)
+		this.drawables = append(this.drawables, InLane(ball, lane))
+		fromLeft = !fromLeft
+	}
+
+	for _, drawable := range this.drawables {
+		field.Add(drawable)
+	}
+}
+
+// Hold each paddle while its button is down
+func (this *Lanes) HandleInput(left, right bool) {
+	this.leftPlayer.UpdatePaddleActive(left)
+	this.rightPlayer.UpdatePaddleActive(right)
+}
+
+// Return or serve again the ball in every lane, taking life from whoever missed
+func (this *Lanes) Tick(dt float64) GameOutcome {
+
+	outcome := GameInProgress
+	for _, ball := range this.balls {
+
+		speed := math.Abs(ball.Velocity())
+		playerMissed, bounce := ball.MissedByPlayer(this.leftPlayer, this.rightPlayer, Settings.BounceVelocityIncrease)
+		if bounce {
+			this.returns++
+			if speed > this.stats.FastestReturn {
+				this.stats.FastestReturn = speed
+			}
+		}
+		if playerMissed == nil {
+			continue
+		}
+
+		if playerMissed == this.leftPlayer {
+			this.stats.RightScore++
+		} else {
+			this.stats.LeftScore++
+		}
+		ball.ResetPosition(this.field)
+
+		switch {
+		case !playerMissed.DecreaseLife(MissLifePenalty):
+			if outcome == GameInProgress {
+				outcome = GamePointScored
+			}
+		case playerMissed == this.leftPlayer:
+			return GameRightWon
+		default:
+			return GameLeftWon
+		}
+	}
+	return outcome
+}
+
+// Drawables added by the game
+func (this *Lanes) Drawables() []Drawable {
+	return this.drawables
+}
+
+// Returns made across the lanes
+func (this *Lanes) Summary() string {
+	return fmt.Sprint("Game over. ", this.returns, " returns in ", len(this.balls), " lanes")
+}
+
+// Points scored and the fastest return
+func (this *Lanes) Stats() GameStats {
+	return this.stats
+}
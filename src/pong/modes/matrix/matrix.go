@@ -0,0 +1,174 @@
+package matrix
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+func init() {
+	RegisterGameMode("matrix", RGBA{128, 255, 0, 255}, func() GameMode { return &Matrix{} })
+}
+
+// Points a player has to score to win
+var matrixPointsToWin int = 5
+
+// Rows a matrix needs before the top one is used to show the score
+var matrixScoreRowHeight int = 4
+
+// Fastest the ball can move up or down, in heights of the rows it plays on a second, so returns off the edge of a
+// paddle can't keep speeding it up until it skips rows
+var matrixMaxRowSpeed float64 = 1.5
+
+// Pong on a matrix, a ball that reaches an end without the paddle in its row scores for the other side. With one
+// button each paddle rises while it is held and falls when it is let go, with a second button each paddle moves up or
+// down while one of them is held and stays put otherwise
+type Matrix struct {
+	ball                    *MatrixBall
+	leftPaddle, rightPaddle *MatrixPaddle
+	score                   *MatrixScore
+	drawables               []Drawable
+
+	// column the right paddle is in, and the fastest the ball can move up or down in rows / second
+	rightColumn float64
+	maxVy       float64
+
+	// buttons for this frame, if there are second buttons, and if the field is only a strip so the game can't be played
+	left, right         bool
+	leftDown, rightDown bool
+	twoButtons          bool
+	strip               bool
+
+	stats GameStats
+}
+
+var _ SummarizedGameMode = &Matrix{}
+var _ StatsGameMode = &Matrix{}
+var _ DownButtonGameMode = &Matrix{}
+
+// Add the paddles, the ball and the score row when the matrix is tall enough to spare one, on a strip there is nowhere
+// for the paddles to move so the game ends straight away
+func (this *Matrix) Setup(field Field, config GameConfig) {
+
+	if field.Height() < 2 {
+		log.Print("The matrix mode needs MatrixRows of at least 2")
+		this.strip = true
+		return
+	}
+
+	this.rightColumn = float64(field.Width() - 1)
+	theme := CurrentTheme()
+	leftColor, rightColor := theme.PlayerColor(config.LeftProfile, true), theme.PlayerColor(config.RightProfile, false)
+
+	firstRow := 0
+	if field.Height() >= matrixScoreRowHeight {
+		firstRow = 1
+		this.score = NewMatrixScore(field, leftColor, rightColor)
+		this.drawables = append(this.drawables, this.score)
+	}
+
+	this.maxVy = matrixMaxRowSpeed * float64(field.Height()-firstRow)
+	this.leftPaddle = NewMatrixPaddle(field, firstRow, true, leftColor)
+	this.rightPaddle = NewMatrixPaddle(field, firstRow, false, rightColor)
+	this.ball = NewMatrixBall(field, firstRow)
+	this.drawables = append(this.drawables, this.leftPaddle, this.rightPaddle, this.ball)
+	for _, drawable := range this.drawables {
+		field.Add(drawable)
+	}
+}
+
+// Remember the buttons for the next tick
+func (this *Matrix) HandleInput(left, right bool) {
+	this.left, this.right = left, right
+}
+
+// Remember the second buttons for the next tick, only called when there are some
+func (this *Matrix) HandleDownInput(leftDown, rightDown bool) {
+	this.twoButtons = true
+	this.leftDown, this.rightDown = leftDown, rightDown
+}
+
+// Direction to move a paddle in for its buttons
+func (this *Matrix) direction(up, down bool) float64 {
+	switch {
+	case !this.twoButtons && up, this.twoButtons && up && !down:
+		return PaddleUp
+	case !this.twoButtons, down && !up:
+		return PaddleDown
+	}
+	return PaddleStop
+}
+
+// Move the paddles, returning the ball off them or scoring when it gets past
+func (this *Matrix) Tick(dt float64) GameOutcome {
+
+	if this.strip {
+		return GameFinished
+	}
+
+	this.leftPaddle.Move(this.direction(this.left, this.leftDown), dt)
+	this.rightPaddle.Move(this.direction(this.right, this.rightDown), dt)
+
+	x, y := this.ball.Position()
+	velocity := this.ball.Velocity()
+	var paddle *MatrixPaddle
+	switch {
+	case velocity < 0 && x <= 0:
+		paddle = this.leftPaddle
+	case velocity > 0 && x >= this.rightColumn:
+		paddle = this.rightPaddle
+	default:
+		return GameInProgress
+	}
+
+	if offset, covered := paddle.Covers(math.Round(y)); covered {
+		this.ball.Return(Settings.BounceVelocityIncrease, offset, this.maxVy)
+		if math.Abs(velocity) > this.stats.FastestReturn {
+			this.stats.FastestReturn = math.Abs(velocity)
+		}
+		if paddle == this.leftPaddle {
+			go PlaySound(LEFTBOUNCE)
+		} else {
+			go PlaySound(RIGHTBOUNCE)
+		}
+		return GameInProgress
+	}
+
+	go PlaySound(MISS)
+	if paddle == this.leftPaddle {
+		this.stats.RightScore++
+	} else {
+		this.stats.LeftScore++
+	}
+	if this.score != nil {
+		this.score.SetScore(this.stats.LeftScore, this.stats.RightScore)
+	}
+	switch {
+	case this.stats.LeftScore >= matrixPointsToWin:
+		return GameLeftWon
+	case this.stats.RightScore >= matrixPointsToWin:
+		return GameRightWon
+	}
+
+	// serve towards the player who just lost the point
+	this.ball.Serve(paddle == this.leftPaddle)
+	return GamePointScored
+}
+
+// Drawables added by the game
+func (this *Matrix) Drawables() []Drawable {
+	return this.drawables
+}
+
+// Points scored by each side
+func (this *Matrix) Summary() string {
+	return fmt.Sprint("Game over. ", this.stats.LeftScore, " to ", this.stats.RightScore)
+}
+
+// Points scored and the fastest return
+func (this *Matrix) Stats() GameStats {
+	return this.stats
+}
@@ -0,0 +1,90 @@
+package race
+
+import (
+	"fmt"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+func init() {
+	RegisterGameMode("race", RGBA{0, 128, 255, 255}, func() GameMode { return &Race{} })
+}
+
+// Each player's dot races to the other end, pressing as it crosses a boost zone speeds it up and pressing anywhere else
+// stalls it, the first to the end wins
+type Race struct {
+	left, right *Racer
+	drawables   []Drawable
+
+	// buttons from the last frame, only new presses count
+	leftDown, rightDown bool
+
+	// accuracy of every boost of each player, from 0 to 1
+	leftBoosts, rightBoosts []float64
+}
+
+var _ SummarizedGameMode = &Race{}
+
+// Add a racer at each end in the players' colors
+func (this *Race) Setup(field Field, config GameConfig) {
+
+	theme := CurrentTheme()
+	leftColor := theme.PlayerColor(config.LeftProfile, true)
+	rightColor := theme.PlayerColor(config.RightProfile, false)
+
+	this.left = NewRacer(field, leftColor, true)
+	this.right = NewRacer(field, rightColor, false)
+	this.drawables = []Drawable{this.left, this.right}
+	for _, drawable := range this.drawables {
+		field.Add(drawable)
+	}
+}
+
+// Press for each racer as its button goes down
+func (this *Race) HandleInput(left, right bool) {
+	if left && !this.leftDown {
+		if accuracy, boosted := this.left.Press(); boosted {
+			this.leftBoosts = append(this.leftBoosts, accuracy)
+		}
+	}
+	if right && !this.rightDown {
+		if accuracy, boosted := this.right.Press(); boosted {
+			this.rightBoosts = append(this.rightBoosts, accuracy)
+		}
+	}
+	this.leftDown, this.rightDown = left, right
+}
+
+// The first racer to reach the far end wins, the left player if both get there on the same tick
+func (this *Race) Tick(dt float64) GameOutcome {
+	switch {
+	case this.left.Finished():
+		return GameLeftWon
+	case this.right.Finished():
+		return GameRightWon
+	}
+	return GameInProgress
+}
+
+// Drawables added by the game
+func (this *Race) Drawables() []Drawable {
+	return this.drawables
+}
+
+// Boosts of each player and how well they were timed
+func (this *Race) Summary() string {
+	return fmt.Sprint("Game over. Boosts ", len(this.leftBoosts), " at ", accuracy(this.leftBoosts), " percent to ",
+		len(this.rightBoosts), " at ", accuracy(this.rightBoosts), " percent")
+}
+
+// Average accuracy of boosts as a whole percentage
+func accuracy(boosts []float64) int {
+	if len(boosts) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, boost := range boosts {
+		total += boost
+	}
+	return int(total / float64(len(boosts)) * 100)
+}
@@ -0,0 +1,140 @@
+package race
+
+import (
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Where each racer's boost zones are centered, as a fraction of the way along the race from their start
+var raceBoostZones = []float64{0.15, 0.35, 0.55, 0.75}
+
+// Leds either side of a zone's center a press still boosts in
+var raceZoneHalfWidth float64 = 2
+
+// Field widths / second a racer moves at, extra speed a perfectly timed boost adds and how long it lasts
+var raceSpeed, raceBoostSpeed, raceBoostSeconds float64 = 0.1, 0.1, 1
+
+// Seconds a racer stalls for pressing outside a zone
+var raceStallSeconds float64 = 0.3
+
+// A dot racing from one end of the field to the other, pressing as it crosses a boost zone speeds it up
+type Racer struct {
+	color RGBA
+
+	// end the racer starts from, and leds from there to the far end
+	fromLeft bool
+	length   float64
+
+	// leds travelled, and the zones still to boost in, in leds from the start
+	travelled float64
+	zones     []float64
+	used      []bool
+
+	// extra leds / second and seconds left of the current boost, seconds left stalled
+	boost, boostLeft, stallLeft float64
+}
+
+var _ Drawable = &Racer{}
+
+// Construct a Racer in color starting from the left or right end of field
+func NewRacer(field Field, color RGBA, fromLeft bool) *Racer {
+
+	racer := &Racer{
+		color:    color,
+		fromLeft: fromLeft,
+		length:   float64(field.Width() - 1),
+	}
+	for _, zone := range raceBoostZones {
+		racer.zones = append(racer.zones, math.Floor(zone*racer.length))
+		racer.used = append(racer.used, false)
+	}
+	return racer
+}
+
+// Position on the field of a point leds along the race
+func (this *Racer) position(leds float64) float64 {
+	if this.fromLeft {
+		return leds
+	}
+	return this.length - leds
+}
+
+// Boost if the racer is in a zone it hasn't used, stall if it isn't, returns how close to the zone's center the press
+// was from 1 dead center falling off towards its edges, false if it missed
+func (this *Racer) Press() (accuracy float64, boosted bool) {
+
+	for i, zone := range this.zones {
+		offset := math.Abs(this.travelled - zone)
+		if this.used[i] || offset > raceZoneHalfWidth {
+			continue
+		}
+
+		this.used[i] = true
+		accuracy = 1 - offset/(raceZoneHalfWidth+1)
+		this.boost = accuracy * raceBoostSpeed * (this.length + 1)
+		this.boostLeft = raceBoostSeconds
+		go PlaySound(LEFTBOUNCE)
+		return accuracy, true
+	}
+
+	this.stallLeft = raceStallSeconds
+	return 0, false
+}
+
+// Zones boosted in so far
+func (this *Racer) Boosts() (boosts int) {
+	for _, used := range this.used {
+		if used {
+			boosts++
+		}
+	}
+	return
+}
+
+// If the racer has reached the far end
+func (this *Racer) Finished() bool {
+	return this.travelled >= this.length
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *Racer) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	dot := this.position(this.travelled)
+	if distance := math.Abs(position - dot); distance < 1 {
+		color := this.color
+		if this.boostLeft > 0 {
+			color = RGBA{255, 255, 255, 255}
+		}
+		return RGBA{color.R, color.G, color.B, Falloff(distance, 1)}.BlendWith(baseColor)
+	}
+
+	for i, zone := range this.zones {
+		if !this.used[i] && math.Abs(position-this.position(zone)) <= raceZoneHalfWidth {
+			return RGBA{this.color.R, this.color.G, this.color.B, 48}.BlendWith(baseColor)
+		}
+	}
+	return baseColor
+}
+
+// ZIndex
+func (this *Racer) ZIndex() ZIndex {
+	return 60
+}
+
+// Move the racer towards the far end, faster while boosted and not at all while stalled
+func (this *Racer) Animate(dt float64) bool {
+
+	if this.stallLeft > 0 {
+		this.stallLeft -= dt
+		return true
+	}
+
+	speed := raceSpeed * (this.length + 1)
+	if this.boostLeft > 0 {
+		speed += this.boost
+		this.boostLeft -= dt
+	}
+	this.travelled = math.Min(this.travelled+speed*dt, this.length)
+	return true
+}
@@ -0,0 +1,50 @@
+package race
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Move racer in steps of dt until it finishes, pressing as it reaches each led in presses, returns the seconds taken
+func race(racer *Racer, dt float64, presses ...float64) (seconds float64) {
+	for !racer.Finished() && seconds < 60 {
+		for len(presses) > 0 && racer.travelled >= presses[0] {
+			racer.Press()
+			presses = presses[1:]
+		}
+		racer.Animate(dt)
+		seconds += dt
+	}
+	return seconds
+}
+
+// Boosts should only be given in a zone, each zone once, and pressing anywhere else should stall the racer
+func Test_Racer(t *testing.T) {
+
+	field := NewGameField(100)
+	dt := 0.001
+
+	// 99 leds at 10 leds / second
+	plain := race(NewRacer(field, RGBA{0, 0, 255, 255}, true), dt)
+	if math.Abs(plain-9.9) > 0.01 {
+		t.Fatal("Unboosted race took", plain)
+	}
+
+	// dead center of every zone, and a second press in the first zone that stalls
+	boosted := NewRacer(field, RGBA{0, 0, 255, 255}, false)
+	zones := boosted.zones
+	seconds := race(boosted, dt, zones[0], zones[0]+0.5, zones[1], zones[2], zones[3])
+	if boosted.Boosts() != 4 {
+		t.Fatal("Boosts given", boosted.Boosts())
+	}
+
+	// each boost doubles the speed for a second, saving 10 leds, less the stall
+	if math.Abs(seconds-(plain-4+raceStallSeconds)) > 0.05 {
+		t.Fatal("Boosted race took", seconds)
+	}
+	if boosted.position(boosted.travelled) != 0 {
+		t.Fatal("Right racer finished at", boosted.position(boosted.travelled))
+	}
+}
@@ -0,0 +1,118 @@
+package reaction
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+func init() {
+	RegisterGameMode("reaction", RGBA{255, 255, 0, 255}, func() GameMode { return &Reaction{} })
+}
+
+// Rounds a player has to win to win the game
+var reactionRoundsToWin int = 3
+
+// Slowest and fastest sweeps in field widths / second, and the seconds between them
+var reactionMinSpeed, reactionMaxSpeed float64 = 0.8, 2
+var reactionRoundDelay float64 = 1.5
+
+// A pixel sweeps along the strip and each player presses as it crosses their marker, the closest press wins the round
+type Reaction struct {
+	config    GameConfig
+	timer     *ReactionTimer
+	drawables []Drawable
+
+	// buttons from the last frame, only new presses count
+	left, right bool
+
+	// sweeps started, and points won by each side
+	rounds int
+	stats  GameStats
+	random *rand.Rand
+}
+
+var _ SummarizedGameMode = &Reaction{}
+var _ StatsGameMode = &Reaction{}
+
+// Add the markers in the players' colors, and start the first sweep
+func (this *Reaction) Setup(field Field, config GameConfig) {
+
+	this.config = config
+	this.random = NewRand()
+	theme := CurrentTheme()
+	leftColor := theme.PlayerColor(config.LeftProfile, true)
+	rightColor := theme.PlayerColor(config.RightProfile, false)
+
+	this.timer = NewReactionTimer(field, leftColor, rightColor)
+	this.drawables = []Drawable{this.timer}
+	field.Add(this.timer)
+	this.sweep()
+}
+
+// Sweep at a random speed, alternating the end it starts from
+func (this *Reaction) sweep() {
+	speed := reactionMinSpeed + this.random.Float64()*(reactionMaxSpeed-reactionMinSpeed)
+	this.timer.Sweep(speed, this.rounds%2 == 0, reactionRoundDelay)
+	this.rounds++
+}
+
+// Press on the timer as each button goes down
+func (this *Reaction) HandleInput(left, right bool) {
+	if left && !this.left {
+		this.timer.Press(true)
+	}
+	if right && !this.right {
+		this.timer.Press(false)
+	}
+	this.left, this.right = left, right
+}
+
+// Score each sweep once it finishes, the round is swept again if nobody pressed or both were equally close
+func (this *Reaction) Tick(dt float64) GameOutcome {
+
+	if !this.timer.Finished() {
+		return GameInProgress
+	}
+
+	leftError, rightError, leftPressed, rightPressed := this.timer.Errors()
+	leftWon := leftPressed && (!rightPressed || leftError < rightError)
+	rightWon := rightPressed && (!leftPressed || rightError < leftError)
+	log.Printf("Reaction round %v: left %.0fms, right %.0fms", this.rounds, leftError*1000, rightError*1000)
+
+	this.sweep()
+	switch {
+	case leftWon:
+		this.stats.LeftScore++
+		if this.stats.LeftScore >= reactionRoundsToWin {
+			return GameLeftWon
+		}
+		go PlaySound(LEFTBOUNCE)
+	case rightWon:
+		this.stats.RightScore++
+		if this.stats.RightScore >= reactionRoundsToWin {
+			return GameRightWon
+		}
+		go PlaySound(RIGHTBOUNCE)
+	default:
+		return GameInProgress
+	}
+	return GamePointScored
+}
+
+// Drawables added by the game
+func (this *Reaction) Drawables() []Drawable {
+	return this.drawables
+}
+
+// Rounds won by each side
+func (this *Reaction) Summary() string {
+	return fmt.Sprint("Game over. ", this.stats.LeftScore, " to ", this.stats.RightScore)
+}
+
+// Rounds won by each side
+func (this *Reaction) Stats() GameStats {
+	return this.stats
+}
@@ -0,0 +1,136 @@
+package reaction
+
+import (
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Markers the players press on, as a fraction of the way along the field from the left
+var reactionMarkers = [2]float64{0.25, 0.75}
+
+// Leds a press is drawn at, the sweeping pixel leaves this trail behind it
+var reactionTrail float64 = 4
+
+// A pixel sweeping along the strip, each player presses as it crosses their marker
+type ReactionTimer struct {
+	width float64
+
+	// marker positions and colors, left player first
+	markers [2]float64
+	colors  [2]RGBA
+
+	// the sweeping pixel, and seconds before it starts moving
+	position, velocity float64
+	delay              float64
+	sweeping           bool
+
+	// where the pixel was when each player pressed during this sweep
+	pressed   [2]bool
+	pressedAt [2]float64
+}
+
+var _ Drawable = &ReactionTimer{}
+
+// Construct a ReactionTimer with the left and right markers in leftColor and rightColor
+func NewReactionTimer(field Field, leftColor, rightColor RGBA) *ReactionTimer {
+	width := float64(field.Width())
+	return &ReactionTimer{
+		width:   width,
+		markers: [2]float64{math.Floor(width * reactionMarkers[0]), math.Floor(width * reactionMarkers[1])},
+		colors:  [2]RGBA{leftColor, rightColor},
+	}
+}
+
+// Start a sweep at speed field widths / second from the left or right end after delay seconds, forgetting the presses
+// of the last sweep
+func (this *ReactionTimer) Sweep(speed float64, fromLeft bool, delay float64) {
+
+	this.velocity = speed * this.width
+	this.position = -reactionTrail
+	if !fromLeft {
+		this.velocity = -this.velocity
+		this.position = this.width - 1 + reactionTrail
+	}
+	this.delay = delay
+	this.sweeping = true
+	this.pressed = [2]bool{}
+}
+
+// Remember where the pixel is as the left or right player presses, only the first press of a sweep counts
+func (this *ReactionTimer) Press(left bool) {
+
+	side := 1
+	if left {
+		side = 0
+	}
+	if !this.sweeping || this.delay > 0 || this.pressed[side] {
+		return
+	}
+	this.pressed[side] = true
+	this.pressedAt[side] = this.position
+}
+
+// If the pixel has swept off the far end of the field
+func (this *ReactionTimer) Finished() bool {
+	return !this.sweeping
+}
+
+// Seconds between each player's press and the pixel crossing their marker, false for players who didn't press
+func (this *ReactionTimer) Errors() (left, right float64, leftPressed, rightPressed bool) {
+
+	errorOf := func(side int) float64 {
+		return math.Abs(this.pressedAt[side]-this.markers[side]) / math.Abs(this.velocity)
+	}
+	return errorOf(0), errorOf(1), this.pressed[0], this.pressed[1]
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *ReactionTimer) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	for side := range this.markers {
+		color := this.colors[side]
+		if this.pressed[side] && math.Abs(position-this.pressedAt[side]) < 0.5 {
+			baseColor = color.BlendWith(baseColor)
+		} else if position == this.markers[side] {
+			baseColor = RGBA{color.R, color.G, color.B, 128}.BlendWith(baseColor)
+		}
+	}
+
+	if !this.sweeping || this.delay > 0 {
+		return baseColor
+	}
+
+	// bright pixel with a fading trail behind it
+	distance := position - this.position
+	if this.velocity > 0 {
+		distance = -distance
+	}
+	if distance > -0.5 && distance < reactionTrail {
+		return RGBA{255, 255, 255, Falloff(math.Max(distance, 0), reactionTrail)}.BlendWith(baseColor)
+	}
+	return baseColor
+}
+
+// ZIndex
+func (this *ReactionTimer) ZIndex() ZIndex {
+	return 50
+}
+
+// Wait out the delay then move the pixel, the sweep finishes once the trail is off the far end
+func (this *ReactionTimer) Animate(dt float64) bool {
+
+	if !this.sweeping {
+		return true
+	}
+	if this.delay > 0 {
+		this.delay -= dt
+		return true
+	}
+
+	this.position += this.velocity * dt
+	if this.position < -reactionTrail || this.position > this.width-1+reactionTrail {
+		this.sweeping = false
+	}
+	return true
+}
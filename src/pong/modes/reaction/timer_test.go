@@ -0,0 +1,48 @@
+package reaction
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Presses should be timed against each player's marker, only the first press of a sweep counting
+func Test_ReactionTimer(t *testing.T) {
+
+	field := NewGameField(100)
+	timer := NewReactionTimer(field, RGBA{0, 0, 255, 255}, RGBA{0, 255, 0, 255})
+	timer.Sweep(1, true, 0.5)
+
+	// presses before the sweep starts don't count
+	timer.Press(true)
+	timer.Animate(0.5)
+
+	dt := 0.001
+	for !timer.Finished() {
+		timer.Animate(dt)
+		if math.Abs(timer.position-20) < 0.05 {
+			timer.Press(true)
+		}
+		if math.Abs(timer.position-75) < 0.05 {
+			timer.Press(false)
+		}
+		if math.Abs(timer.position-90) < 0.05 {
+			timer.Press(false)
+		}
+	}
+
+	// the left player pressed 5 leds early at 100 leds / second, the right player right on their marker
+	left, right, leftPressed, rightPressed := timer.Errors()
+	if !leftPressed || !rightPressed {
+		t.Fatal("Presses missing", leftPressed, rightPressed)
+	}
+	if math.Abs(left-0.05) > 0.002 || right > 0.002 {
+		t.Fatal("Errors of", left, right)
+	}
+
+	timer.Sweep(1, false, 0)
+	if _, _, leftPressed, rightPressed := timer.Errors(); leftPressed || rightPressed {
+		t.Fatal("Presses kept for the next sweep")
+	}
+}
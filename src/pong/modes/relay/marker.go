@@ -0,0 +1,53 @@
+package relay
+
+import (
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Leds in from the end of the field the turn marker is drawn
+var relayMarkerOffset float64 = 2
+
+// Small marker near one end of the field in the color of the teammate whose turn it is to return the ball
+type RelayMarker struct {
+
+	// colors of the two teammates, and whose turn it is
+	colors [2]RGBA
+	turn   int
+
+	position float64
+}
+
+var _ Drawable = &RelayMarker{}
+
+// Construct a RelayMarker near the left or right end of field for teammates drawn in first and second
+func NewRelayMarker(field Field, isLeft bool, first, second RGBA) *RelayMarker {
+
+	position := relayMarkerOffset
+	if !isLeft {
+		position = float64(field.Width()) - 1 - relayMarkerOffset
+	}
+	return &RelayMarker{colors: [2]RGBA{first, second}, position: position}
+}
+
+// Show that it is the turn of the first teammate, 0, or the second, 1
+func (this *RelayMarker) SetTurn(turn int) {
+	this.turn = turn
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *RelayMarker) ColorAt(position float64, baseColor RGBA) RGBA {
+	if position != this.position {
+		return baseColor
+	}
+	return this.colors[this.turn].BlendWith(baseColor)
+}
+
+// Above the life bars of the players
+func (this *RelayMarker) ZIndex() ZIndex {
+	return 11
+}
+
+// Animate
+func (this *RelayMarker) Animate(dt float64) bool {
+	return true
+}
@@ -0,0 +1,163 @@
+package relay
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+func init() {
+	RegisterGameMode("relay", RGBA{0, 255, 255, 255}, func() GameMode { return &Relay{} })
+}
+
+// Two players at each end sharing the paddle, one on the main button and one on the second, who have to take turns
+// returning the ball. Pushing the button out of turn loses the point, a marker near each end shows whose turn it is
+type Relay struct {
+	field     Field
+	ball      *Ball
+	teams     [2]*team
+	drawables []Drawable
+
+	// if the second buttons are wired, without them only the main buttons play and there are no turns
+	relayed bool
+
+	// returns made in turn, and presses made out of turn
+	returns, fumbles int
+	stats            GameStats
+}
+
+// The players at one end of the field
+type team struct {
+	player *Player
+	marker *RelayMarker
+
+	// which teammate returns the ball next, 0 on the main button and 1 on the second
+	turn int
+
+	// if each teammate is holding their button, and if the one not on turn pushed theirs since the last tick
+	pressed   [2]bool
+	outOfTurn bool
+}
+
+var _ SummarizedGameMode = &Relay{}
+var _ StatsGameMode = &Relay{}
+var _ DownButtonGameMode = &Relay{}
+
+// Add the ball, both players and the turn markers, the first teammate of each team starts
+func (this *Relay) Setup(field Field, config GameConfig) {
+
+	this.field = field
+	this.ball = NewBall(field)
+	this.drawables = []Drawable{this.ball}
+
+	theme := CurrentTheme()
+	for side, profile := range []PlayerProfile{config.LeftProfile, config.RightProfile} {
+		isLeft := side == 0
+		color := theme.PlayerColor(profile, isLeft)
+		player := NewProfilePlayer(isLeft, profile, field)
+		marker := NewRelayMarker(field, isLeft, color, color.Lighter())
+		this.teams[side] = &team{player: player, marker: marker}
+		this.drawables = append(this.drawables, player, marker)
+	}
+
+	for _, drawable := range this.drawables {
+		field.Add(drawable)
+	}
+}
+
+// Note the main buttons, the paddle of each team is held by whichever teammate's turn it is
+func (this *Relay) HandleInput(left, right bool) {
+	for side, pressed := range []bool{left, right} {
+		team := this.teams[side]
+		this.press(team, 0, pressed)
+		team.player.UpdatePaddleActive(team.pressed[team.turn])
+	}
+}
+
+// Note the second buttons, which are read before the main ones
+func (this *Relay) HandleDownInput(leftDown, rightDown bool) {
+	this.relayed = true
+	this.press(this.teams[0], 1, leftDown)
+	this.press(this.teams[1], 1, rightDown)
+}
+
+// Record the button of teammate, noting a push made out of turn
+func (this *Relay) press(team *team, teammate int, pressed bool) {
+	if pressed && !team.pressed[teammate] && teammate != team.turn {
+		team.outOfTurn = true
+	}
+	team.pressed[teammate] = pressed
+}
+
+// Take the point from a team that pushed out of turn, otherwise return the ball and pass the turn to the other
+// teammate
+func (this *Relay) Tick(dt float64) GameOutcome {
+
+	for _, team := range this.teams {
+		if team.outOfTurn {
+			team.outOfTurn = false
+			this.fumbles++
+			log.Print("Pushed out of turn")
+			return this.missed(team)
+		}
+	}
+
+	defending := this.teams[1]
+	if this.ball.Velocity() < 0 {
+		defending = this.teams[0]
+	}
+	speed := math.Abs(this.ball.Velocity())
+
+	playerMissed, bounce := this.ball.MissedByPlayer(this.teams[0].player, this.teams[1].player, Settings.BounceVelocityIncrease)
+	if bounce {
+		this.returns++
+		if speed > this.stats.FastestReturn {
+			this.stats.FastestReturn = speed
+		}
+		if this.relayed {
+			defending.turn = 1 - defending.turn
+			defending.marker.SetTurn(defending.turn)
+		}
+	}
+	if playerMissed == nil {
+		return GameInProgress
+	}
+	return this.missed(defending)
+}
+
+// Score for the other team, taking life from the team that missed and serving again or ending the game
+func (this *Relay) missed(missing *team) GameOutcome {
+
+	if missing == this.teams[0] {
+		this.stats.RightScore++
+	} else {
+		this.stats.LeftScore++
+	}
+	this.ball.ResetPosition(this.field)
+
+	if !missing.player.DecreaseLife(MissLifePenalty) {
+		return GamePointScored
+	}
+	if missing == this.teams[0] {
+		return GameRightWon
+	}
+	return GameLeftWon
+}
+
+// Drawables added by the game
+func (this *Relay) Drawables() []Drawable {
+	return this.drawables
+}
+
+// Returns made in turn and presses made out of turn
+func (this *Relay) Summary() string {
+	return fmt.Sprint("Game over. ", this.returns, " returns in turn and ", this.fumbles, " pushes out of turn")
+}
+
+// Points scored and the fastest return
+func (this *Relay) Stats() GameStats {
+	return this.stats
+}
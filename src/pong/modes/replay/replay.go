@@ -0,0 +1,110 @@
+package replay
+
+import (
+	"log"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+func init() {
+	RegisterGameMode("replay", RGBA{255, 0, 255, 255}, func() GameMode { return &Replay{} })
+}
+
+// Plays a recorded game back on the field, pushing either button stops it
+type Replay struct {
+	recording *GameRecording
+	input     *ReplayInput
+
+	ball                    *Ball
+	leftPlayer, rightPlayer *Player
+	drawables               []Drawable
+
+	// playback state
+	time     float64
+	nextBall int
+	stopped  bool
+}
+
+// Add the ball and players as they were at the start of the recording
+func (this *Replay) Setup(field Field, config GameConfig) {
+
+	this.recording = config.Replay
+	if this.recording == nil || len(this.recording.Balls) == 0 {
+		log.Print("Nothing to replay, the recording doesn't have the ball in it")
+		this.stopped = true
+		return
+	}
+	this.input = NewReplayInput(this.recording)
+
+	this.ball = NewServedBall(field, this.recording.ServedFromLeft)
+	this.leftPlayer = NewPlayer(true, startingLife(this.recording.LeftLife), field)
+	this.rightPlayer = NewPlayer(false, startingLife(this.recording.RightLife), field)
+	this.drawables = []Drawable{this.ball, this.leftPlayer, this.rightPlayer}
+	for _, drawable := range this.drawables {
+		field.Add(drawable)
+	}
+
+	this.placeBall()
+}
+
+// Life a player started the recorded game with, recordings made before handicaps all started the same
+func startingLife(recorded float64) float64 {
+	if recorded <= 0 {
+		return Settings.LifeInSeconds
+	}
+	return recorded
+}
+
+// Stop once a button is pushed
+func (this *Replay) HandleInput(left, right bool) {
+	if left || right {
+		this.stopped = true
+	}
+}
+
+// Move playback forward, putting the ball where it was recorded each time it was served or hit
+func (this *Replay) Tick(dt float64) GameOutcome {
+
+	if this.stopped {
+		return GameFinished
+	}
+
+	this.time += dt
+	this.input.Advance(dt)
+	this.leftPlayer.UpdatePaddleActive(this.input.LeftButton())
+	this.rightPlayer.UpdatePaddleActive(this.input.RightButton())
+	this.placeBall()
+
+	if this.time >= this.recording.Duration && this.nextBall >= len(this.recording.Balls) {
+		return GameFinished
+	}
+	return GameInProgress
+}
+
+// Apply every recorded ball up to the current time, taking life from the side that missed
+func (this *Replay) placeBall() {
+
+	for ; this.nextBall < len(this.recording.Balls); this.nextBall++ {
+
+		recorded := this.recording.Balls[this.nextBall]
+		if recorded.Time > this.time {
+			break
+		}
+
+		// the ball has kept moving since it was recorded
+		this.ball.Place(recorded.Position+recorded.Velocity*(this.time-recorded.Time), recorded.Velocity)
+
+		switch recorded.Missed {
+		case "left":
+			this.leftPlayer.DecreaseLife(MissLifePenalty)
+		case "right":
+			this.rightPlayer.DecreaseLife(MissLifePenalty)
+		}
+	}
+}
+
+// Drawables added by the replay
+func (this *Replay) Drawables() []Drawable {
+	return this.drawables
+}
@@ -0,0 +1,58 @@
+package simon
+
+import (
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// A pulse lighting up the left or right half of the strip, for showing and echoing a sequence
+type SimonPulse struct {
+	width  float64
+	colors [2]RGBA
+
+	// side being lit, and seconds left and total of the pulse
+	left            bool
+	timeLeft, total float64
+}
+
+var _ Drawable = &SimonPulse{}
+
+// Construct a SimonPulse lighting the halves of field in leftColor and rightColor
+func NewSimonPulse(field Field, leftColor, rightColor RGBA) *SimonPulse {
+	return &SimonPulse{
+		width:  float64(field.Width()),
+		colors: [2]RGBA{leftColor, rightColor},
+	}
+}
+
+// Light the left or right half, fading out over seconds
+func (this *SimonPulse) Flash(left bool, seconds float64) {
+	this.left = left
+	this.timeLeft = seconds
+	this.total = seconds
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *SimonPulse) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	if this.timeLeft <= 0 || (position < this.width/2) != this.left {
+		return baseColor
+	}
+	color := this.colors[1]
+	if this.left {
+		color = this.colors[0]
+	}
+	return RGBA{color.R, color.G, color.B, uint8(255 * this.timeLeft / this.total)}.BlendWith(baseColor)
+}
+
+// ZIndex
+func (this *SimonPulse) ZIndex() ZIndex {
+	return 50
+}
+
+// Fade the pulse out
+func (this *SimonPulse) Animate(dt float64) bool {
+	if this.timeLeft > 0 {
+		this.timeLeft -= dt
+	}
+	return true
+}
@@ -0,0 +1,134 @@
+package simon
+
+import (
+	"fmt"
+	"math/rand"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+func init() {
+	RegisterGameMode("simon", RGBA{255, 255, 128, 255}, func() GameMode { return &Simon{} })
+}
+
+// Seconds each pulse of the sequence is shown for, the gap between pulses, and the pause before each showing
+var simonPulseSeconds, simonGapSeconds, simonPauseSeconds float64 = 0.5, 0.2, 1
+
+// Seconds the players have for each press of their echo before the run ends
+var simonPressSeconds float64 = 5
+
+// The strip flashes a sequence of left and right pulses, one longer each time, that the players echo with their
+// buttons, the run ends on the first mistake and the longest sequence echoed is the score
+type Simon struct {
+	pulse     *SimonPulse
+	drawables []Drawable
+
+	// sides of the sequence, true for left, and how far through showing or echoing it the game is
+	sequence []bool
+	next     int
+	echoing  bool
+
+	// seconds until the next pulse is shown, or left for the next press of the echo
+	timer float64
+
+	// buttons from the last frame and new presses since the last tick
+	leftDown, rightDown     bool
+	leftPushed, rightPushed bool
+
+	random *rand.Rand
+}
+
+var _ SummarizedGameMode = &Simon{}
+var _ ScoredGameMode = &Simon{}
+
+// Add the pulse in the players' colors and start showing a sequence of one
+func (this *Simon) Setup(field Field, config GameConfig) {
+
+	this.random = NewRand()
+	theme := CurrentTheme()
+	leftColor := theme.PlayerColor(config.LeftProfile, true)
+	rightColor := theme.PlayerColor(config.RightProfile, false)
+
+	this.pulse = NewSimonPulse(field, leftColor, rightColor)
+	this.drawables = []Drawable{this.pulse}
+	field.Add(this.pulse)
+
+	this.extend()
+}
+
+// Add a random side to the sequence and show it from the start
+func (this *Simon) extend() {
+	this.sequence = append(this.sequence, this.random.Float64() < 0.5)
+	this.next = 0
+	this.echoing = false
+	this.timer = simonPauseSeconds
+}
+
+// Remember new presses for the next tick
+func (this *Simon) HandleInput(left, right bool) {
+	this.leftPushed = this.leftPushed || (left && !this.leftDown)
+	this.rightPushed = this.rightPushed || (right && !this.rightDown)
+	this.leftDown, this.rightDown = left, right
+}
+
+// Show the sequence a pulse at a time, then check each press of the echo against it
+func (this *Simon) Tick(dt float64) GameOutcome {
+
+	leftPushed, rightPushed := this.leftPushed, this.rightPushed
+	this.leftPushed, this.rightPushed = false, false
+	this.timer -= dt
+
+	if !this.echoing {
+		if this.timer > 0 {
+			return GameInProgress
+		}
+		if this.next == len(this.sequence) {
+			this.next = 0
+			this.echoing = true
+			this.timer = simonPressSeconds
+			return GameInProgress
+		}
+		this.pulse.Flash(this.sequence[this.next], simonPulseSeconds)
+		this.next++
+		this.timer = simonPulseSeconds + simonGapSeconds
+		return GameInProgress
+	}
+
+	if !leftPushed && !rightPushed {
+		if this.timer <= 0 {
+			go PlaySound(MISS)
+			return GameFinished
+		}
+		return GameInProgress
+	}
+
+	// both at once is never right
+	if leftPushed == rightPushed || leftPushed != this.sequence[this.next] {
+		go PlaySound(MISS)
+		return GameFinished
+	}
+
+	this.pulse.Flash(leftPushed, simonPulseSeconds/2)
+	this.next++
+	this.timer = simonPressSeconds
+	if this.next == len(this.sequence) {
+		go PlaySound(GAMESTART)
+		this.extend()
+	}
+	return GameInProgress
+}
+
+// Drawables added by the game
+func (this *Simon) Drawables() []Drawable {
+	return this.drawables
+}
+
+// Longest sequence echoed without a mistake
+func (this *Simon) Score() int {
+	return len(this.sequence) - 1
+}
+
+// Longest sequence echoed
+func (this *Simon) Summary() string {
+	return fmt.Sprint("Game over. Remembered ", this.Score())
+}
@@ -0,0 +1,175 @@
+package snake
+
+import (
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// A snake crawling around the strip one led at a time, wrapping from one end to the other
+type SnakeBody struct {
+	width int
+
+	// leds covered by each segment, head first
+	body []int
+
+	// +1 when crawling right, -1 when crawling left
+	direction int
+
+	// segments still to be added at the tail
+	growing int
+}
+
+var _ Drawable = &SnakeBody{}
+
+// Construct a SnakeBody of length segments in the middle of field, crawling right
+func NewSnakeBody(field Field, length int) *SnakeBody {
+
+	snake := &SnakeBody{width: field.Width(), direction: 1}
+	head := field.Width() / 2
+	for i := 0; i < length; i++ {
+		snake.body = append(snake.body, snake.wrap(head-i))
+	}
+	return snake
+}
+
+// Led at offset, wrapped around the ends of the strip
+func (this *SnakeBody) wrap(led int) int {
+	return ((led % this.width) + this.width) % this.width
+}
+
+// Move the head one led, the body following it, returns false without moving if the head would crawl onto the body
+func (this *SnakeBody) Step() bool {
+
+	// the tail moves out of the way unless the snake is growing
+	head := this.wrap(this.body[0] + this.direction)
+	body := this.body[:len(this.body)-1]
+	if this.growing > 0 {
+		body = this.body
+	}
+	for _, segment := range body {
+		if segment == head {
+			return false
+		}
+	}
+
+	if this.growing > 0 {
+		this.body = append(this.body, 0)
+		this.growing--
+	}
+	copy(this.body[1:], this.body)
+	this.body[0] = head
+	return true
+}
+
+// Turn around, the tail becoming the head
+func (this *SnakeBody) Reverse() {
+	for i, j := 0, len(this.body)-1; i < j; i, j = i+1, j-1 {
+		this.body[i], this.body[j] = this.body[j], this.body[i]
+	}
+	this.direction = -this.direction
+}
+
+// Add segments at the tail over the next steps
+func (this *SnakeBody) Grow(segments int) {
+	this.growing += segments
+}
+
+// Led the head is on
+func (this *SnakeBody) Head() int {
+	return this.body[0]
+}
+
+// Number of segments, not counting ones still growing
+func (this *SnakeBody) Length() int {
+	return len(this.body)
+}
+
+// If any segment is on led
+func (this *SnakeBody) Covers(led int) bool {
+	for _, segment := range this.body {
+		if segment == led {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the color at position blended on top of baseColor, the body fading from the head to the tail
+func (this *SnakeBody) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	led := int(position)
+	for i, segment := range this.body {
+		if segment != led {
+			continue
+		}
+		if i == 0 {
+			return RGBA{255, 255, 255, 255}
+		}
+		fade := float64(i) / float64(len(this.body))
+		return RGBA{0, 255, 0, uint8(255 - fade*191)}.BlendWith(baseColor)
+	}
+	return baseColor
+}
+
+// ZIndex
+func (this *SnakeBody) ZIndex() ZIndex {
+	return 60
+}
+
+// The snake is moved by Step
+func (this *SnakeBody) Animate(dt float64) bool {
+	return true
+}
+
+// A single led of food for a SnakeBody, dimming as it spoils
+type SnakeFood struct {
+	led int
+
+	// seconds until the food spoils, and how long it lasts
+	left, lifetime float64
+}
+
+var _ Drawable = &SnakeFood{}
+
+// Construct SnakeFood that hasn't been placed yet
+func NewSnakeFood() *SnakeFood {
+	return &SnakeFood{led: -1}
+}
+
+// Put fresh food on led that spoils after lifetime seconds
+func (this *SnakeFood) Place(led int, lifetime float64) {
+	this.led = led
+	this.left = lifetime
+	this.lifetime = lifetime
+}
+
+// Led the food is on
+func (this *SnakeFood) Led() int {
+	return this.led
+}
+
+// If the food has been left too long
+func (this *SnakeFood) Spoiled() bool {
+	return this.left <= 0
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *SnakeFood) ColorAt(position float64, baseColor RGBA) RGBA {
+	if int(position) != this.led || this.lifetime <= 0 {
+		return baseColor
+	}
+	fresh := this.left / this.lifetime
+	return RGBA{255, uint8(fresh * 255), 0, uint8(64 + fresh*191)}.BlendWith(baseColor)
+}
+
+// ZIndex
+func (this *SnakeFood) ZIndex() ZIndex {
+	return 55
+}
+
+// Count down until the food spoils
+func (this *SnakeFood) Animate(dt float64) bool {
+	if this.left > 0 {
+		this.left -= dt
+	}
+	return true
+}
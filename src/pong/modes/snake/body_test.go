@@ -0,0 +1,44 @@
+package snake
+
+import (
+	"testing"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// The snake should wrap around the ends, turn around in place, and only run into itself once it fills the strip
+func Test_SnakeBody(t *testing.T) {
+
+	field := NewGameField(10)
+	snake := NewSnakeBody(field, 3)
+	if snake.Head() != 5 || !snake.Covers(3) || snake.Covers(6) {
+		t.Fatal("Snake starts at", snake.body)
+	}
+
+	for i := 0; i < 5; i++ {
+		if !snake.Step() {
+			t.Fatal("Snake ran into itself at", snake.body)
+		}
+	}
+	if snake.Head() != 0 || !snake.Covers(8) {
+		t.Fatal("Snake didn't wrap around to", snake.body)
+	}
+
+	snake.Reverse()
+	if snake.Head() != 8 || !snake.Step() || snake.Head() != 7 {
+		t.Fatal("Snake didn't turn around", snake.body)
+	}
+
+	// growing past the width of the strip runs the head into the tail
+	snake.Grow(8)
+	steps := 0
+	for snake.Step() {
+		steps++
+		if steps > 10 {
+			t.Fatal("Snake never ran into itself", snake.body)
+		}
+	}
+	if snake.Length() != 10 {
+		t.Fatal("Snake ran into itself at length", snake.Length())
+	}
+}
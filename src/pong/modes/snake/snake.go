@@ -0,0 +1,120 @@
+package snake
+
+import (
+	"fmt"
+	"math/rand"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+func init() {
+	RegisterGameMode("snake", RGBA{0, 255, 64, 255}, func() GameMode { return &Snake{} })
+}
+
+// Segments the snake starts with, and segments added for eating food or letting it spoil
+var snakeStartLength, snakeFoodGrowth, snakeSpoiledGrowth int = 3, 1, 3
+
+// Leds / second the snake starts crawling at, and how much faster it gets for each food eaten
+var snakeStartSpeed, snakeSpeedIncrease float64 = 6, 0.5
+
+// Seconds food lasts before it spoils
+var snakeFoodLifetime float64 = 5
+
+// The snake crawls around the strip eating food, either button turns it around, the game ends once it runs into itself
+type Snake struct {
+	field     Field
+	snake     *SnakeBody
+	food      *SnakeFood
+	drawables []Drawable
+
+	// buttons from the last frame, only new presses turn the snake
+	left, right bool
+
+	// seconds until the snake next steps, and food eaten
+	untilStep float64
+	eaten     int
+
+	random *rand.Rand
+}
+
+var _ SummarizedGameMode = &Snake{}
+var _ ScoredGameMode = &Snake{}
+
+// Add the snake and its first food
+func (this *Snake) Setup(field Field, config GameConfig) {
+
+	this.field = field
+	this.random = NewRand()
+	this.snake = NewSnakeBody(field, snakeStartLength)
+	this.food = NewSnakeFood()
+	this.drawables = []Drawable{this.snake, this.food}
+	for _, drawable := range this.drawables {
+		field.Add(drawable)
+	}
+
+	this.untilStep = 1 / snakeStartSpeed
+	this.placeFood()
+}
+
+// Put fresh food on a led the snake isn't covering, nowhere if it covers the whole strip so the food spoils
+func (this *Snake) placeFood() {
+	width := this.field.Width()
+	led := this.random.Intn(width)
+	for tries := 0; this.snake.Covers(led); tries++ {
+		if tries == width {
+			led = -1
+			break
+		}
+		led = (led + 1) % width
+	}
+	this.food.Place(led, snakeFoodLifetime)
+}
+
+// Turn the snake around as either button goes down
+func (this *Snake) HandleInput(left, right bool) {
+	if (left && !this.left) || (right && !this.right) {
+		this.snake.Reverse()
+	}
+	this.left, this.right = left, right
+}
+
+// Step the snake at its speed, growing it when it eats or the food spoils
+func (this *Snake) Tick(dt float64) GameOutcome {
+
+	if this.food.Spoiled() {
+		this.snake.Grow(snakeSpoiledGrowth)
+		go PlaySound(MISS)
+		this.placeFood()
+	}
+
+	this.untilStep -= dt
+	for this.untilStep <= 0 {
+		this.untilStep += 1 / (snakeStartSpeed + float64(this.eaten)*snakeSpeedIncrease)
+
+		if !this.snake.Step() {
+			return GameFinished
+		}
+		if this.snake.Head() == this.food.Led() {
+			this.eaten++
+			this.snake.Grow(snakeFoodGrowth)
+			go PlaySound(LEFTBOUNCE)
+			this.placeFood()
+		}
+	}
+	return GameInProgress
+}
+
+// Drawables added by the game
+func (this *Snake) Drawables() []Drawable {
+	return this.drawables
+}
+
+// Food eaten
+func (this *Snake) Score() int {
+	return this.eaten
+}
+
+// Food eaten and the length the snake reached
+func (this *Snake) Summary() string {
+	return fmt.Sprint("Game over. Ate ", this.eaten, ", length ", this.snake.Length())
+}
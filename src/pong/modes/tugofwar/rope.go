@@ -0,0 +1,86 @@
+package tugofwar
+
+import (
+	"math"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Leds at each end lighting up with how fast that player is mashing, full at TugFullRate presses / second
+var tugMeterLength float64 = 5
+var TugFullRate float64 = 10
+
+// The rope of a tug of war, each side in its player's color either side of the dividing pixel
+type TugRope struct {
+	width float64
+
+	colors [2]RGBA
+
+	// position of the dividing pixel, and presses / second of each player
+	divider  float64
+	rates    [2]float64
+	leftWon  bool
+	rightWon bool
+}
+
+var _ Drawable = &TugRope{}
+
+// Construct a TugRope with the divider in the middle of field
+func NewTugRope(field Field, leftColor, rightColor RGBA) *TugRope {
+	width := float64(field.Width())
+	return &TugRope{
+		width:   width,
+		colors:  [2]RGBA{leftColor, rightColor},
+		divider: (width - 1) / 2,
+	}
+}
+
+// Move the divider towards the right by leds, negative to move it left, returns the side that pushed it past the end
+func (this *TugRope) Pull(leds float64) (leftWon, rightWon bool) {
+	this.divider += leds
+	this.leftWon = this.divider > this.width-1
+	this.rightWon = this.divider < 0
+	this.divider = math.Max(0, math.Min(this.width-1, this.divider))
+	return this.leftWon, this.rightWon
+}
+
+// Show how fast each player is mashing
+func (this *TugRope) SetRates(left, right float64) {
+	this.rates = [2]float64{left, right}
+}
+
+// Position of the dividing pixel
+func (this *TugRope) Divider() float64 {
+	return this.divider
+}
+
+// Returns the color at position blended on top of baseColor
+func (this *TugRope) ColorAt(position float64, baseColor RGBA) RGBA {
+
+	distance := math.Abs(position - this.divider)
+	if distance < 1 {
+		return RGBA{255, 255, 255, Falloff(distance, 1)}.BlendWith(baseColor)
+	}
+
+	side, fromEnd := 0, position
+	if position > this.divider {
+		side, fromEnd = 1, this.width-1-position
+	}
+	color := this.colors[side]
+
+	// the meter at each end, then the rest of that player's side of the rope
+	if meter := math.Min(this.rates[side]/TugFullRate, 1) * tugMeterLength; fromEnd < meter {
+		return color
+	}
+	return RGBA{color.R, color.G, color.B, 64}.BlendWith(baseColor)
+}
+
+// ZIndex
+func (this *TugRope) ZIndex() ZIndex {
+	return 50
+}
+
+// The divider is moved by Pull
+func (this *TugRope) Animate(dt float64) bool {
+	return true
+}
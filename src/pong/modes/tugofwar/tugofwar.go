@@ -0,0 +1,93 @@
+package tugofwar
+
+import (
+	"fmt"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+func init() {
+	RegisterGameMode("tugofwar", RGBA{255, 0, 128, 255}, func() GameMode { return &TugOfWar{} })
+}
+
+// Seconds presses are counted over when measuring how fast a player is mashing
+var tugRateWindow float64 = 1
+
+// Leds / second the divider moves for each press / second one player is ahead of the other
+var tugLedsPerPress float64 = 0.5
+
+// Both players mash their buttons to push the divider towards the other end, the first to push it past the end wins
+type TugOfWar struct {
+	rope      *TugRope
+	drawables []Drawable
+
+	// how fast each player is mashing, and the buttons for this frame
+	leftRate, rightRate     *PressRate
+	leftButton, rightButton bool
+
+	// presses and the fastest presses / second of each player
+	leftPresses, rightPresses int
+	leftBest, rightBest       float64
+}
+
+var _ SummarizedGameMode = &TugOfWar{}
+
+// Add the rope with the divider in the middle
+func (this *TugOfWar) Setup(field Field, config GameConfig) {
+
+	theme := CurrentTheme()
+	leftColor := theme.PlayerColor(config.LeftProfile, true)
+	rightColor := theme.PlayerColor(config.RightProfile, false)
+
+	this.rope = NewTugRope(field, leftColor, rightColor)
+	this.drawables = []Drawable{this.rope}
+	field.Add(this.rope)
+
+	this.leftRate = NewPressRate(tugRateWindow)
+	this.rightRate = NewPressRate(tugRateWindow)
+}
+
+// Remember the buttons for the next tick
+func (this *TugOfWar) HandleInput(left, right bool) {
+	this.leftButton, this.rightButton = left, right
+}
+
+// Measure how fast each player is mashing and pull the divider towards whoever is slower
+func (this *TugOfWar) Tick(dt float64) GameOutcome {
+
+	if this.leftRate.Update(this.leftButton, dt) {
+		this.leftPresses++
+	}
+	if this.rightRate.Update(this.rightButton, dt) {
+		this.rightPresses++
+	}
+
+	left, right := this.leftRate.Rate(), this.rightRate.Rate()
+	if left > this.leftBest {
+		this.leftBest = left
+	}
+	if right > this.rightBest {
+		this.rightBest = right
+	}
+	this.rope.SetRates(left, right)
+
+	leftWon, rightWon := this.rope.Pull((left - right) * tugLedsPerPress * dt)
+	switch {
+	case leftWon:
+		return GameLeftWon
+	case rightWon:
+		return GameRightWon
+	}
+	return GameInProgress
+}
+
+// Drawables added by the game
+func (this *TugOfWar) Drawables() []Drawable {
+	return this.drawables
+}
+
+// Presses and fastest mashing of each player
+func (this *TugOfWar) Summary() string {
+	return fmt.Sprintf("Game over. %v to %v presses, fastest %.0f to %.0f a second",
+		this.leftPresses, this.rightPresses, this.leftBest, this.rightBest)
+}
@@ -0,0 +1,74 @@
+package pong
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Ways a notification can light up the strip
+var NotificationEffects = []string{"flash", "pulse", "chase"}
+
+// A short named animation pushed by another system, shown over the idle scene before returning to it
+type Notification struct {
+	Name    string
+	Color   RGBA
+	Effect  string
+	Seconds float64
+}
+
+// Notifications that can be pushed by name alone
+var NotificationPresets = []Notification{
+	{"doorbell", RGBA{255, 200, 0, 255}, "chase", 5},
+	{"build failed", RGBA{255, 0, 0, 255}, "flash", 8},
+	{"meeting in 5", RGBA{0, 80, 255, 255}, "pulse", 10},
+}
+
+// Longest a notification can be shown for, in seconds
+var maxNotificationSeconds float64 = 60
+
+// Build a notification from the fields of a request, starting from the preset called name if there is one, color is
+// written as #rrggbb and seconds is a decimal number, empty fields keep the preset's
+func ParseNotification(name, color, effect, seconds string) (Notification, error) {
+
+	notification := Notification{Name: name, Effect: "flash", Seconds: 5}
+	preset := false
+	for _, existing := range NotificationPresets {
+		if existing.Name == name {
+			notification = existing
+			preset = true
+		}
+	}
+
+	if name == "" {
+		return notification, fmt.Errorf("A notification needs a name")
+	}
+	if color != "" {
+		var err error
+		if notification.Color, err = parseHexColor(color); err != nil {
+			return notification, err
+		}
+	} else if !preset {
+		return notification, fmt.Errorf("Notification %q isn't a preset, so it needs a color", name)
+	}
+
+	if effect != "" {
+		notification.Effect = effect
+	}
+	known := false
+	for _, existing := range NotificationEffects {
+		known = known || existing == notification.Effect
+	}
+	if !known {
+		return notification, fmt.Errorf("Effect %q isn't one of %v", notification.Effect, NotificationEffects)
+	}
+
+	if seconds != "" {
+		value, err := strconv.ParseFloat(seconds, 64)
+		if err != nil || value <= 0 || value > maxNotificationSeconds {
+			return notification, fmt.Errorf("Seconds %q isn't a number of seconds up to %v", seconds, maxNotificationSeconds)
+		}
+		notification.Seconds = value
+	}
+
+	return notification, nil
+}
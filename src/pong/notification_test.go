@@ -0,0 +1,38 @@
+package pong
+
+import (
+	"testing"
+)
+
+// Presets should be found by name with their fields overridable, and anything else needs a color
+func Test_ParseNotification(t *testing.T) {
+
+	doorbell, err := ParseNotification("doorbell", "", "", "")
+	if err != nil || doorbell != NotificationPresets[0] {
+		t.Fatal("Doorbell was", doorbell, err)
+	}
+
+	custom, err := ParseNotification("doorbell", "#00ff00", "pulse", "2.5")
+	if err != nil || custom.Color != (RGBA{0, 255, 0, 255}) || custom.Effect != "pulse" || custom.Seconds != 2.5 {
+		t.Fatal("Overridden doorbell was", custom, err)
+	}
+
+	lunch, err := ParseNotification("lunch", "#ff8000", "", "")
+	if err != nil || lunch.Effect != "flash" || lunch.Seconds != 5 {
+		t.Fatal("Lunch was", lunch, err)
+	}
+
+	bad := [][4]string{
+		{"", "#ff0000", "", ""},
+		{"lunch", "", "", ""},
+		{"doorbell", "red", "", ""},
+		{"doorbell", "", "sparkle", ""},
+		{"doorbell", "", "", "-1"},
+		{"doorbell", "", "", "3600"},
+	}
+	for _, fields := range bad {
+		if _, err := ParseNotification(fields[0], fields[1], fields[2], fields[3]); err == nil {
+			t.Fatal("Parsed", fields)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package pong
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Column and row of the frame shown by a physical led, negative for a led that isn't part of the frame and stays dark,
+// and the brightness it is shown at, 0 for full
+type PixelCoordinate struct {
+	X          int
+	Y          int
+	Brightness float64 `json:",omitempty"`
+}
+
+// Coordinate of every physical led in the order they are wired, for layouts that aren't plain rows such as a strip
+// wrapped around the edge of a table or split into several runs
+type PixelMap []PixelCoordinate
+
+// Read the pixel map at path, a .csv file has an x,y or x,y,brightness line for every led and anything else is read as
+// a JSON array of {"x": 0, "y": 0} objects, which can also have a brightness
+func LoadPixelMap(path string) (PixelMap, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return readPixelMapCsv(file)
+	}
+
+	var pixels PixelMap
+	if err := json.NewDecoder(file).Decode(&pixels); err != nil {
+		return nil, fmt.Errorf("Reading pixel map %v: %v", path, err)
+	}
+	return pixels, nil
+}
+
+// Read a pixel map with an x,y or x,y,brightness line for every led, lines starting with # are skipped
+func readPixelMapCsv(reader io.Reader) (PixelMap, error) {
+
+	records := csv.NewReader(reader)
+	records.Comment = '#'
+	records.FieldsPerRecord = -1
+	records.TrimLeadingSpace = true
+
+	var pixels PixelMap
+	for {
+		record, err := records.Read()
+		if err == io.EOF {
+			return pixels, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(record) != 2 && len(record) != 3 {
+			return nil, fmt.Errorf("Led %v of the pixel map isn't x,y or x,y,brightness", len(pixels))
+		}
+		x, err := strconv.Atoi(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("Led %v of the pixel map: %v", len(pixels), err)
+		}
+		y, err := strconv.Atoi(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("Led %v of the pixel map: %v", len(pixels), err)
+		}
+		pixel := PixelCoordinate{X: x, Y: y}
+		if len(record) == 3 {
+			if pixel.Brightness, err = strconv.ParseFloat(record[2], 64); err != nil {
+				return nil, fmt.Errorf("Led %v of the pixel map: %v", len(pixels), err)
+			}
+		}
+		pixels = append(pixels, pixel)
+	}
+}
+
+// Brightness of every led, 1 for the ones the map doesn't give one
+func (this PixelMap) Brightness() []float64 {
+
+	brightness := make([]float64, len(this))
+	for led, pixel := range this {
+		brightness[led] = 1
+		if pixel.Brightness > 0 {
+			brightness[led] = pixel.Brightness
+		}
+	}
+	return brightness
+}
+
+// Display of leds laid out by a pixel map, picking the color of every physical led out of frames rendered a row at a
+// time from the top
+type PixelMapDisplay struct {
+	wrappedDisplay
+
+	// index into the frame of every physical led, -1 for a dark one
+	indexes []int
+
+	wiredData []RGBA
+}
+
+var _ ResettableDisplay = &PixelMapDisplay{}
+
+// Construct a PixelMapDisplay wrapping the display of the leds in pixels, showing frames of width by height leds
+func NewPixelMapDisplay(display Display, width, height int, pixels PixelMap) (*PixelMapDisplay, error) {
+
+	if len(pixels) == 0 {
+		return nil, fmt.Errorf("Pixel map has no leds")
+	}
+
+	indexes := make([]int, len(pixels))
+	for led, pixel := range pixels {
+		switch {
+		case pixel.X < 0 || pixel.Y < 0:
+			indexes[led] = -1
+		case pixel.X >= width || pixel.Y >= height:
+			return nil, fmt.Errorf("Led %v of the pixel map is at %v,%v outside the %vx%v frame", led, pixel.X, pixel.Y, width, height)
+		default:
+			indexes[led] = pixel.Y*width + pixel.X
+		}
+	}
+
+	return &PixelMapDisplay{
+		wrappedDisplay: wrappedDisplay{display},
+		indexes:        indexes,
+		wiredData:      make([]RGBA, len(pixels)),
+	}, nil
+}
+
+// Pick the color of every led out of colorData and render them to the wrapped display
+func (this *PixelMapDisplay) Render(colorData []RGBA) {
+
+	for led, index := range this.indexes {
+		if index < 0 || index >= len(colorData) {
+			this.wiredData[led] = RGBA{}
+		} else {
+			this.wiredData[led] = colorData[index]
+		}
+	}
+
+	this.display.Render(this.wiredData)
+}
@@ -0,0 +1,87 @@
+package pong
+
+import (
+	"github.com/brandonagr/pongpi/src/pong/tables"
+)
+
+// Display that keeps the current drawn by the leds within what the power supply can give, dimming any frame that would
+// draw more so a flash of white on a long strip doesn't brown it out
+type PowerLimitDisplay struct {
+	wrappedDisplay
+
+	// mA the supply can give the leds
+	budget float64
+
+	// mA each led draws while dark, and each color channel at every value after gamma correction
+	idleMilliamps    float64
+	channelMilliamps [256]float64
+
+	limitedData []RGBA
+}
+
+var _ ResettableDisplay = &PowerLimitDisplay{}
+
+// Construct a PowerLimitDisplay wrapping display, keeping it under budgetMilliamps with every color channel drawing
+// channelMilliamps at full brightness and every led idleMilliamps while dark
+func NewPowerLimitDisplay(display Display, budgetMilliamps, channelMilliamps, idleMilliamps float64) *PowerLimitDisplay {
+
+	limited := &PowerLimitDisplay{
+		wrappedDisplay: wrappedDisplay{display},
+		budget:         budgetMilliamps,
+		idleMilliamps:  idleMilliamps,
+	}
+	// the leds are driven by the gamma corrected 7 bit value, so that's what the current follows
+	for value := range limited.channelMilliamps {
+		limited.channelMilliamps[value] = float64(tables.Gamma(uint8(value))) / 127 * channelMilliamps
+	}
+	return limited
+}
+
+// Estimated mA drawn showing colorData scaled by scale
+func (this *PowerLimitDisplay) Current(colorData []RGBA, scale float64) float64 {
+
+	current := this.idleMilliamps * float64(len(colorData))
+	for _, color := range colorData {
+		current += this.channelMilliamps[ScaleChannel(color.R, scale)] +
+			this.channelMilliamps[ScaleChannel(color.G, scale)] +
+			this.channelMilliamps[ScaleChannel(color.B, scale)]
+	}
+	return current
+}
+
+// Largest scale colorData can be shown at within the budget, searched for as gamma correction isn't linear
+func (this *PowerLimitDisplay) limitScale(colorData []RGBA) float64 {
+
+	low, high := 0.0, 1.0
+	for step := 0; step < 10; step++ {
+		middle := (low + high) / 2
+		if this.Current(colorData, middle) <= this.budget {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+	return low
+}
+
+// Render colorData to the wrapped display, dimmed if it would draw more than the budget
+func (this *PowerLimitDisplay) Render(colorData []RGBA) {
+
+	current := this.Current(colorData, 1)
+	GameMetrics.Observe("power_ma", current)
+	if current <= this.budget {
+		this.display.Render(colorData)
+		return
+	}
+
+	scale := this.limitScale(colorData)
+	GameMetrics.Observe("power_limit_scale", scale)
+
+	if len(this.limitedData) != len(colorData) {
+		this.limitedData = make([]RGBA, len(colorData))
+	}
+	for led, color := range colorData {
+		this.limitedData[led] = RGBA{ScaleChannel(color.R, scale), ScaleChannel(color.G, scale), ScaleChannel(color.B, scale), color.A}
+	}
+	this.display.Render(this.limitedData)
+}
@@ -0,0 +1,37 @@
+package pong
+
+import (
+	"testing"
+)
+
+// Frames within the budget should pass through untouched and brighter ones should be dimmed until they fit
+func Test_PowerLimitDisplay(t *testing.T) {
+
+	display := &lastFrameDisplay{}
+	limited := NewPowerLimitDisplay(display, 500, 20, 1)
+
+	white := make([]RGBA, 10)
+	for led := range white {
+		white[led] = RGBA{255, 255, 255, 255}
+	}
+	if current := limited.Current(white, 1); current != 610 {
+		t.Fatal("10 white leds draw", current, "mA")
+	}
+	if current := limited.Current(make([]RGBA, 10), 1); current != 10 {
+		t.Fatal("10 dark leds draw", current, "mA")
+	}
+
+	limited.Render(white)
+	if current := limited.Current(display.frame, 1); current > 500 || current < 450 {
+		t.Fatal("Limited frame draws", current, "mA")
+	}
+	if display.frame[0].A != 255 || display.frame[0].R >= 255 {
+		t.Fatal("White was limited to", display.frame[0])
+	}
+
+	dim := []RGBA{{100, 0, 0, 255}, {0, 50, 0, 255}}
+	limited.Render(dim)
+	if display.frame[0] != dim[0] || display.frame[1] != dim[1] {
+		t.Fatal("Frame within the budget was changed to", display.frame)
+	}
+}
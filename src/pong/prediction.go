@@ -0,0 +1,173 @@
+package pong
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Most spectators whose predictions are kept, later ones are turned away so the predictions can't grow without limit
+var maxPredictions int = 200
+
+// How long a prediction counts for, older ones are dropped so those made long before a match don't count
+var predictionLifetime = time.Hour
+
+// Predictions are closed while a match is played
+var ErrPredictionsClosed = errors.New("Predictions are closed until the match is over")
+
+// Every spectator that can predict already has
+var ErrTooManyPredictions = errors.New("Too many spectators have predicted this match")
+
+// Winners predicted by spectators on the web page before a match, one prediction per spectator that they can change
+// until the match starts
+type Predictions struct {
+
+	// side each spectator picked by the address they predicted from
+	picks map[string]*prediction
+
+	// if predictions are taken, they close while a match is played
+	open bool
+
+	// source of the time predictions are made at
+	now func() time.Time
+
+	// the web server takes predictions while the game reads them
+	lock sync.Mutex
+}
+
+// The side one spectator picked
+type prediction struct {
+	left bool
+	made time.Time
+}
+
+// Construct Predictions open for the first match
+func NewPredictions() *Predictions {
+	return &Predictions{picks: map[string]*prediction{}, open: true, now: time.Now}
+}
+
+// Record that the spectator at client thinks the left or right player will win
+func (this *Predictions) Predict(client string, left bool) error {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if !this.open {
+		return ErrPredictionsClosed
+	}
+	this.expire()
+	if _, ok := this.picks[client]; !ok && len(this.picks) >= maxPredictions {
+		return ErrTooManyPredictions
+	}
+	this.picks[client] = &prediction{left: left, made: this.now()}
+	return nil
+}
+
+// Drop predictions made longer than predictionLifetime ago, called with the lock held
+func (this *Predictions) expire() {
+	now := this.now()
+	for client, pick := range this.picks {
+		if now.Sub(pick.made) > predictionLifetime {
+			delete(this.picks, client)
+		}
+	}
+}
+
+// Number of spectators who picked each side
+func (this *Predictions) Count() (left, right int) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.expire()
+	for _, pick := range this.picks {
+		if pick.left {
+			left++
+		} else {
+			right++
+		}
+	}
+	return
+}
+
+// If predictions are being taken
+func (this *Predictions) IsOpen() bool {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.open
+}
+
+// Stop taking predictions while the match is played
+func (this *Predictions) Close() {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.open = false
+}
+
+// Score the predictions against the winner of the match, returns how many spectators were right out of how many
+// predicted, and opens predictions for the next match
+func (this *Predictions) Resolve(leftWon bool) (correct, total int) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.expire()
+	for _, pick := range this.picks {
+		if pick.left == leftWon {
+			correct++
+		}
+	}
+	total = len(this.picks)
+
+	this.picks = map[string]*prediction{}
+	this.open = true
+	return
+}
+
+// Throw away the predictions of a match nobody won and open them for the next one
+func (this *Predictions) Discard() {
+	this.Resolve(false)
+}
+
+// Serve the number of predictions for each side as json, POST side as left or right to predict the winner of the next
+// match. Spectators are told apart by the address they post from, so clearing cookies doesn't give another vote
+func (this *Predictions) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method == "POST" {
+		side := r.FormValue("side")
+		if side != "left" && side != "right" {
+			http.Error(w, "Side has to be left or right", http.StatusBadRequest)
+			return
+		}
+
+		switch err := this.Predict(spectatorAddress(r), side == "left"); err {
+		case nil:
+		case ErrTooManyPredictions:
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		log.Print("Spectator predicted ", side, " will win")
+	}
+
+	left, right := this.Count()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Open        bool
+		Left, Right int
+	}{this.IsOpen(), left, right})
+}
+
+// Address of the spectator making request r, without the port so each connection from the same phone is the same
+func spectatorAddress(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,71 @@
+package pong
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Each spectator should get one prediction they can change until the match starts, and be scored once it's over
+func Test_Predictions(t *testing.T) {
+	predictions := NewPredictions()
+
+	predict := func(side string, address string) *httptest.ResponseRecorder {
+		form := url.Values{"side": {side}}
+		request := httptest.NewRequest("POST", "/api/predictions", strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		request.RemoteAddr = address + ":41000"
+		recorder := httptest.NewRecorder()
+		predictions.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	Assert(predict("left", "10.0.0.1").Code, 200, "Status", t)
+	Assert(predict("right", "10.0.0.1").Code, 200, "Changed prediction status", t)
+	predict("right", "10.0.0.2")
+	predict("left", "10.0.0.3")
+	Assert(predict("middle", "10.0.0.4").Code, 400, "Unknown side status", t)
+
+	left, right := predictions.Count()
+	Assert(left, 1, "Left predictions", t)
+	Assert(right, 2, "Right predictions", t)
+
+	predictions.Close()
+	Assert(predict("left", "10.0.0.4").Code, 409, "Prediction during the match status", t)
+
+	correct, total := predictions.Resolve(false)
+	Assert(correct, 2, "Correct predictions", t)
+	Assert(total, 3, "Predictions", t)
+	if left, right := predictions.Count(); left+right != 0 || !predictions.IsOpen() {
+		t.Fatal("Predictions weren't cleared and opened for the next match")
+	}
+}
+
+// Predictions should be capped, and old ones should expire to make room
+func Test_Predictions_Limits(t *testing.T) {
+
+	defer func(max int) { maxPredictions = max }(maxPredictions)
+	maxPredictions = 2
+
+	now := time.Now()
+	predictions := NewPredictions()
+	predictions.now = func() time.Time { return now }
+
+	predictions.Predict("a", true)
+	predictions.Predict("b", false)
+	if err := predictions.Predict("c", true); err != ErrTooManyPredictions {
+		t.Fatal("Prediction past the cap", err)
+	}
+	if err := predictions.Predict("a", false); err != nil {
+		t.Fatal("Changing a prediction at the cap", err)
+	}
+
+	now = now.Add(predictionLifetime + time.Second)
+	if err := predictions.Predict("c", true); err != nil {
+		t.Fatal("Prediction once the others expired", err)
+	}
+	left, right := predictions.Count()
+	Assert(left+right, 1, "Predictions after expiring", t)
+}
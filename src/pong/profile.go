@@ -0,0 +1,325 @@
+package pong
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/brandonagr/pongpi/src/pong/atomicfile"
+)
+
+// Profile anyone can play as without creating their own, it is never saved
+const GuestProfileName = "Guest"
+
+// A person who plays regularly, their stats, colors and handicap follow them from game to game
+type PlayerProfile struct {
+	Name string `xml:"name,attr"`
+
+	// color the paddle and life are drawn in as #rrggbb, empty to use the color of the side played on
+	Color string `xml:"color,attr,omitempty"`
+
+	// seconds of life added to LifeInSeconds at the start of each game, negative makes games harder
+	Handicap float64 `xml:"handicap,attr,omitempty"`
+
+	// leds wide the window where holding the button returns the ball, 0 for the usual single led
+	HitZoneWidth float64 `xml:"hitZoneWidth,attr,omitempty"`
+
+	// leds between the end of the field and the hit zone, so the ball has to be returned before it reaches the end
+	HitZoneDistance float64 `xml:"hitZoneDistance,attr,omitempty"`
+
+	// name of the sound in Fanfares played when they win, and VictoryFlash, VictoryFireworks or VictoryWipe to show
+	// their wins that way, empty for the theme's
+	Fanfare string `xml:"fanfare,attr,omitempty"`
+	Victory string `xml:"victory,attr,omitempty"`
+
+	// PaddleSolid, PaddlePulse or PaddleRainbow, how their paddle is drawn, empty for solid
+	PaddleEffect string `xml:"paddleEffect,attr,omitempty"`
+
+	// effects from the registered library played at their end when they score a point and when they concede one,
+	// written as for NewEffect and checked with CheckEffect, so scripts can only be named from EffectScriptsDir, empty
+	// for the theme's
+	ScoreEffect   string `xml:"scoreEffect,attr,omitempty"`
+	ConcedeEffect string `xml:"concedeEffect,attr,omitempty"`
+}
+
+// Profile used when nobody picked one
+var GuestProfile = PlayerProfile{Name: GuestProfileName}
+
+// Names results are already recorded under for players without a profile, such as the AI personalities, a profile
+// can't take one of them or its results would be mixed up with theirs
+var reservedProfileNames = []string{GuestProfileName}
+
+// Stop profiles being created called name, ignoring case
+func ReserveProfileName(name string) {
+	reservedProfileNames = append(reservedProfileNames, name)
+}
+
+// If name can't be used for a profile, the side names guests are recorded under are reserved as well
+func isReservedProfileName(name string) bool {
+	for _, reserved := range append([]string{Settings.LeftPlayerName, Settings.RightPlayerName}, reservedProfileNames...) {
+		if reserved != "" && strings.EqualFold(strings.TrimSpace(name), reserved) {
+			return true
+		}
+	}
+	return false
+}
+
+// If this is the guest profile, including a profile that was never set
+func (this PlayerProfile) IsGuest() bool {
+	return this.Name == "" || this.Name == GuestProfileName
+}
+
+// If other is the same player, guests are never the same as anyone
+func (this PlayerProfile) SamePlayer(other PlayerProfile) bool {
+	return !this.IsGuest() && this.Name == other.Name
+}
+
+// Name results are recorded under, guests are recorded as sideName
+func (this PlayerProfile) PlayerName(sideName string) string {
+	if this.IsGuest() {
+		return sideName
+	}
+	return this.Name
+}
+
+// Seconds of life the player starts a game with when everyone else starts with lifeTime
+func (this PlayerProfile) Life(lifeTime float64) float64 {
+	if life := lifeTime + this.Handicap; life > 0 {
+		return life
+	}
+	return lifeTime
+}
+
+// Width of the hit zone and how far it is from the end of the field, in leds
+func (this PlayerProfile) HitZone() (width, distance float64) {
+	width = 1
+	if this.HitZoneWidth > 0 {
+		width = this.HitZoneWidth
+	}
+	return width, math.Max(this.HitZoneDistance, 0)
+}
+
+// Color of the player, false if the profile doesn't have one
+func (this PlayerProfile) RGBA() (RGBA, bool) {
+	color, err := parseHexColor(this.Color)
+	return color, err == nil
+}
+
+// Parse a color written as #rrggbb
+func parseHexColor(text string) (RGBA, error) {
+
+	if len(text) != 7 || text[0] != '#' {
+		return RGBA{}, fmt.Errorf("Color %q isn't written as #rrggbb", text)
+	}
+	value, err := strconv.ParseUint(text[1:], 16, 32)
+	if err != nil {
+		return RGBA{}, fmt.Errorf("Color %q isn't written as #rrggbb", text)
+	}
+
+	return RGBA{uint8(value >> 16), uint8(value >> 8), uint8(value), 255}, nil
+}
+
+// Color written as #rrggbb, ignoring its alpha
+func HexColor(color RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", color.R, color.G, color.B)
+}
+
+// Every player profile, persisted to a file
+type Profiles struct {
+	XMLName  xml.Name         `xml:"Profiles"`
+	Profiles []*PlayerProfile `xml:"Profile"`
+
+	// file the profiles are saved to
+	path string
+
+	// profiles are created by the web server while the game reads them
+	lock sync.Mutex
+}
+
+// Load profiles from path, starting with only the guest if the file doesn't exist yet
+func LoadProfiles(path string) *Profiles {
+
+	profiles := &Profiles{path: path}
+
+	fileData, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Print(err)
+		}
+		return profiles
+	}
+	if err := xml.Unmarshal(fileData, profiles); err != nil {
+		log.Print(err)
+	}
+
+	return profiles
+}
+
+// Write the profiles to the file they were loaded from
+func (this *Profiles) Save() error {
+
+	this.lock.Lock()
+	fileData, err := xml.MarshalIndent(this, "", "\t")
+	this.lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(this.path, fileData, 0666)
+}
+
+// Create a new profile, names have to be unique
+func (this *Profiles) Add(profile PlayerProfile) error {
+
+	if profile.IsGuest() {
+		return errors.New("Profile needs a name other than " + GuestProfileName)
+	}
+	if isReservedProfileName(profile.Name) {
+		return errors.New("Profile can't be called " + profile.Name + ", that name is already used by the game")
+	}
+	if profile.Color != "" {
+		if _, err := parseHexColor(profile.Color); err != nil {
+			return err
+		}
+	}
+	for _, number := range []float64{profile.Handicap, profile.HitZoneWidth, profile.HitZoneDistance} {
+		if math.IsNaN(number) || math.IsInf(number, 0) {
+			return errors.New("Handicap and hit zone of " + profile.Name + " have to be numbers")
+		}
+	}
+	if profile.HitZoneWidth < 0 || profile.HitZoneDistance < 0 {
+		return errors.New("Hit zone of " + profile.Name + " can't be a negative number of leds")
+	}
+	// a hit zone past the player's half of the field could never be reached
+	if half := float64(Settings.LedCount) / 2; half > 0 {
+		profile.HitZoneWidth = math.Min(profile.HitZoneWidth, half)
+		profile.HitZoneDistance = math.Min(profile.HitZoneDistance, half-1)
+	}
+	if _, ok := Fanfares[profile.Fanfare]; profile.Fanfare != "" && !ok {
+		return errors.New("There's no fanfare called " + profile.Fanfare)
+	}
+	switch profile.Victory {
+	case "", VictoryFlash, VictoryFireworks, VictoryWipe:
+	default:
+		return errors.New("Wins can't be shown with " + profile.Victory)
+	}
+	switch profile.PaddleEffect {
+	case "", PaddleSolid, PaddlePulse, PaddleRainbow:
+	default:
+		return errors.New("There's no paddle effect called " + profile.PaddleEffect)
+	}
+	for _, effect := range []string{profile.ScoreEffect, profile.ConcedeEffect} {
+		if effect == "" {
+			continue
+		}
+		if err := CheckEffect(effect); err != nil {
+			return err
+		}
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	for _, existing := range this.Profiles {
+		if existing.Name == profile.Name {
+			return errors.New("Profile " + profile.Name + " already exists")
+		}
+	}
+	this.Profiles = append(this.Profiles, &profile)
+	return nil
+}
+
+// Profile called name, the guest profile and false if there isn't one
+func (this *Profiles) Find(name string) (PlayerProfile, bool) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	for _, profile := range this.Profiles {
+		if profile.Name == name {
+			return *profile, true
+		}
+	}
+	return GuestProfile, name == GuestProfileName
+}
+
+// Guest followed by every profile in the order they were created, drawn in their colors
+func (this *Profiles) MenuOptions() []MenuOption {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	options := []MenuOption{{GuestProfileName, RGBA{128, 128, 128, 255}}}
+	for _, profile := range this.Profiles {
+		color, ok := profile.RGBA()
+		if !ok {
+			color = RGBA{255, 255, 255, 255}
+		}
+		options = append(options, MenuOption{profile.Name, color})
+	}
+	return options
+}
+
+// Serve the profiles as json, or create one with a POST of name, and optionally color, handicap, hitZoneWidth,
+// hitZoneDistance, fanfare, victory, paddleEffect, scoreEffect and concedeEffect
+func (this *Profiles) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method == "POST" {
+		profile := PlayerProfile{
+			Name:          r.FormValue("name"),
+			Color:         r.FormValue("color"),
+			Fanfare:       r.FormValue("fanfare"),
+			Victory:       r.FormValue("victory"),
+			PaddleEffect:  r.FormValue("paddleEffect"),
+			ScoreEffect:   r.FormValue("scoreEffect"),
+			ConcedeEffect: r.FormValue("concedeEffect"),
+		}
+		numbers := []struct {
+			name  string
+			value *float64
+		}{
+			{"handicap", &profile.Handicap},
+			{"hitZoneWidth", &profile.HitZoneWidth},
+			{"hitZoneDistance", &profile.HitZoneDistance},
+		}
+		for _, number := range numbers {
+			text := r.FormValue(number.name)
+			if text == "" {
+				continue
+			}
+			var err error
+			if *number.value, err = strconv.ParseFloat(text, 64); err != nil {
+				http.Error(w, fmt.Sprintf("%v isn't a number", number.name), http.StatusBadRequest)
+				return
+			}
+		}
+
+		if err := this.Add(profile); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := this.Save(); err != nil {
+			log.Print(err)
+		}
+		log.Print("Created profile ", profile.Name)
+	}
+
+	this.lock.Lock()
+	profiles := []PlayerProfile{GuestProfile}
+	for _, profile := range this.Profiles {
+		profiles = append(profiles, *profile)
+	}
+	this.lock.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profiles)
+}
@@ -0,0 +1,119 @@
+package pong
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// Profiles created from the web should be found by name and listed after the guest in the menu
+func Test_Profiles_Create(t *testing.T) {
+	profiles := &Profiles{}
+
+	form := url.Values{"name": {"Ann"}, "color": {"#ff8000"}, "handicap": {"5"}, "hitZoneWidth": {"3"}, "hitZoneDistance": {"1"}}
+	request := httptest.NewRequest("POST", "/api/profiles", strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	profiles.ServeHTTP(recorder, request)
+	Assert(recorder.Code, 200, "Status", t)
+
+	ann, ok := profiles.Find("Ann")
+	if !ok {
+		t.Fatal("Created profile not found")
+	}
+	if color, ok := ann.RGBA(); !ok || color != (RGBA{255, 128, 0, 255}) {
+		t.Fatal("Color was", color)
+	}
+	Assert(int(ann.Life(10)), 15, "Life with handicap", t)
+	if width, distance := ann.HitZone(); width != 3 || distance != 1 {
+		t.Fatal("Hit zone is", width, "wide", distance, "from the end")
+	}
+	if width, distance := GuestProfile.HitZone(); width != 1 || distance != 0 {
+		t.Fatal("Guest hit zone is", width, "wide", distance, "from the end")
+	}
+	Assert(len(profiles.MenuOptions()), 2, "Menu options", t)
+
+	if err := profiles.Add(PlayerProfile{Name: "Ann"}); err == nil {
+		t.Fatal("Created a second profile with the same name")
+	}
+	if err := profiles.Add(PlayerProfile{Name: "Bob", Color: "orange"}); err == nil {
+		t.Fatal("Created a profile with an unreadable color")
+	}
+	if err := profiles.Add(PlayerProfile{Name: "Cy", HitZoneWidth: -1}); err == nil {
+		t.Fatal("Created a profile with a negative hit zone")
+	}
+	for _, profile := range []PlayerProfile{{Name: "Di", Fanfare: "trumpets"}, {Name: "Ed", Victory: "confetti"}, {Name: "Flo", PaddleEffect: "glitter"}, {Name: "Hal", ScoreEffect: "glitter"}, {Name: "Ida", ConcedeEffect: "scriptfile(path=/etc/passwd)"}} {
+		if err := profiles.Add(profile); err == nil {
+			t.Fatal("Created a profile with a look that isn't built in", profile)
+		}
+	}
+	if err := profiles.Add(PlayerProfile{Name: "Gus", Fanfare: "chime", Victory: VictoryFireworks, PaddleEffect: PaddleRainbow, ConcedeEffect: "test", ScoreEffect: "scriptfile(path=glow.fx)"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Hit zones and handicaps that aren't numbers should be refused, and hit zones kept inside a player's half
+func Test_Profiles_Numbers(t *testing.T) {
+	profiles := &Profiles{}
+
+	for _, value := range []string{"NaN", "Inf", "-Inf"} {
+		for _, name := range []string{"handicap", "hitZoneWidth", "hitZoneDistance"} {
+			form := url.Values{"name": {"Ann"}, name: {value}}
+			request := httptest.NewRequest("POST", "/api/profiles", strings.NewReader(form.Encode()))
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			recorder := httptest.NewRecorder()
+			profiles.ServeHTTP(recorder, request)
+			Assert(recorder.Code, 400, "Status of "+name+" "+value, t)
+		}
+	}
+
+	defer func(ledCount int) { Settings.LedCount = ledCount }(Settings.LedCount)
+	Settings.LedCount = 60
+	if err := profiles.Add(PlayerProfile{Name: "Bob", HitZoneWidth: 1000, HitZoneDistance: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	bob, _ := profiles.Find("Bob")
+	if width, distance := bob.HitZone(); width != 30 || distance != 29 {
+		t.Fatal("Hit zone is", width, "wide", distance, "from the end")
+	}
+}
+
+// Guests should be recorded under the name of the side they play on
+func Test_Profiles_Guest(t *testing.T) {
+	profiles := &Profiles{}
+
+	guest, ok := profiles.Find(GuestProfileName)
+	if !ok || !guest.IsGuest() {
+		t.Fatal("Guest profile missing")
+	}
+	if guest.PlayerName("Left") != "Left" || (PlayerProfile{}).PlayerName("Right") != "Right" {
+		t.Fatal("Guest recorded as", guest.PlayerName("Left"))
+	}
+	if _, ok := profiles.Find("Nobody"); ok {
+		t.Fatal("Found a profile that was never created")
+	}
+
+	// nobody is the same player as a guest, even another guest
+	if guest.SamePlayer(guest) || !(PlayerProfile{Name: "Ann"}).SamePlayer(PlayerProfile{Name: "Ann"}) {
+		t.Fatal("Guests and players told apart wrongly")
+	}
+}
+
+// Names the game records results under for players without a profile can't be taken by one
+func Test_Profiles_ReservedNames(t *testing.T) {
+	oldSettings := Settings
+	defer func() { Settings = oldSettings }()
+	Settings.LeftPlayerName, Settings.RightPlayerName = "Left", "Right"
+
+	ReserveProfileName("steady")
+	profiles := &Profiles{}
+	for _, name := range []string{"guest", "Left", " right", "Steady"} {
+		if err := profiles.Add(PlayerProfile{Name: name}); err == nil {
+			t.Fatal("Created a profile called", name)
+		}
+	}
+	if err := profiles.Add(PlayerProfile{Name: "Lefty"}); err != nil {
+		t.Fatal(err)
+	}
+}
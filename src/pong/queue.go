@@ -0,0 +1,57 @@
+package pong
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Most players that can wait in a queue, and the longest name a player can join with
+var maxQueueLength int = 32
+var maxQueueNameLength int = 24
+
+// Names of players waiting their turn in the order they play, shared by the signup and king of the hill queues which
+// hold their own lock around it
+type nameQueue []string
+
+// Add name to the back of the queue, unless they are already waiting or are one of playing
+func (this *nameQueue) join(name string, playing ...string) error {
+
+	if name == "" {
+		return errors.New("A player needs a name to join")
+	}
+	if utf8.RuneCountInString(name) > maxQueueNameLength {
+		return fmt.Errorf("Names can be at most %v letters", maxQueueNameLength)
+	}
+	for _, other := range append(playing, *this...) {
+		if strings.EqualFold(other, name) {
+			return errors.New(name + " is already in the queue")
+		}
+	}
+	if len(*this) >= maxQueueLength {
+		return errors.New("The queue is full")
+	}
+	*this = append(*this, name)
+	return nil
+}
+
+// Take name out of the queue
+func (this *nameQueue) leave(name string) {
+	for index, waiting := range *this {
+		if strings.EqualFold(waiting, name) {
+			*this = append((*this)[:index], (*this)[index+1:]...)
+			return
+		}
+	}
+}
+
+// Take up to count players off the front of the queue
+func (this *nameQueue) callUp(count int) []string {
+	if count > len(*this) {
+		count = len(*this)
+	}
+	called := append([]string{}, (*this)[:count]...)
+	*this = (*this)[count:]
+	return called
+}
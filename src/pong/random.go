@@ -0,0 +1,31 @@
+package pong
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Source of the seeds of every game and drawable, so a run can be repeated from a single seed
+var seeds = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// Drawables can be created from the web handlers as well as the game loop
+var seedsLock sync.Mutex
+
+// Seed games and drawables from source, a source seeded the same way plays out the same game given the same inputs.
+// Returns the source used until now, so it can be put back
+func UseRandSource(source rand.Source) (previous rand.Source) {
+	seedsLock.Lock()
+	defer seedsLock.Unlock()
+	previous = seeds
+	seeds = rand.New(source)
+	return previous
+}
+
+// Construct random numbers of its own for a game or drawable, seeded from the source given to UseRandSource. It isn't
+// safe to share so only draw from it while stepping the owner forward, never while rendering
+func NewRand() *rand.Rand {
+	seedsLock.Lock()
+	defer seedsLock.Unlock()
+	return rand.New(rand.NewSource(seeds.Int63()))
+}
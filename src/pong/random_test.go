@@ -0,0 +1,38 @@
+package pong
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Random numbers from the same seed should repeat
+func Test_UseRandSource(t *testing.T) {
+
+	draw := func() (numbers []float64) {
+		UseRandSource(rand.NewSource(42))
+		first, second := NewRand(), NewRand()
+		for index := 0; index < 10; index++ {
+			numbers = append(numbers, first.Float64(), float64(second.Intn(100)))
+		}
+		return
+	}
+
+	first, second := draw(), draw()
+	for index := range first {
+		if first[index] != second[index] {
+			t.Fatal("Seeded random numbers differ at", index, first[index], second[index])
+		}
+	}
+}
+
+// Simulated time should only move when advanced
+func Test_SimulatedClock(t *testing.T) {
+
+	start := SystemClock{}.Now()
+	clock := NewSimulatedClock(start)
+	Assert(int(clock.Now().Sub(start)), 0, "Time before advancing", t)
+
+	clock.Advance(1500)
+	clock.Advance(500)
+	Assert(int(clock.Now().Sub(start)), 2000, "Time after advancing", t)
+}
@@ -0,0 +1,152 @@
+package pong
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/brandonagr/pongpi/src/pong/atomicfile"
+)
+
+// Rating given to a player the first time they play
+const InitialRating float64 = 1200
+
+// Maximum change in rating from a single match
+const ratingK float64 = 32
+
+// Elo rating of a single player
+type PlayerRating struct {
+	Name   string  `xml:"name,attr"`
+	Rating float64 `xml:"rating,attr"`
+	Games  int     `xml:"games,attr"`
+
+	// fixed ratings, such as AI opponents, never change and aren't saved
+	fixed bool
+}
+
+// Elo ratings of every player, persisted to a file
+type Ratings struct {
+	XMLName xml.Name        `xml:"Ratings"`
+	Players []*PlayerRating `xml:"Player"`
+
+	// file the ratings are saved to
+	path string
+
+	// ratings are read by the web server while the game updates them
+	lock sync.Mutex
+}
+
+// Load ratings from path, starting empty if the file doesn't exist yet
+func LoadRatings(path string) *Ratings {
+
+	ratings := &Ratings{path: path}
+
+	fileData, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Print(err)
+		}
+		return ratings
+	}
+	if err := xml.Unmarshal(fileData, ratings); err != nil {
+		log.Print(err)
+	}
+
+	return ratings
+}
+
+// Write the ratings to the file they were loaded from
+func (this *Ratings) Save() error {
+
+	this.lock.Lock()
+	saved := &Ratings{}
+	for _, player := range this.Players {
+		if !player.fixed {
+			saved.Players = append(saved.Players, player)
+		}
+	}
+	fileData, err := xml.MarshalIndent(saved, "", "\t")
+	this.lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(this.path, fileData, 0666)
+}
+
+// Find the rating for name, adding it if it doesn't exist, lock must be held
+func (this *Ratings) find(name string) *PlayerRating {
+
+	for _, player := range this.Players {
+		if player.Name == name {
+			return player
+		}
+	}
+
+	player := &PlayerRating{Name: name, Rating: InitialRating}
+	this.Players = append(this.Players, player)
+	return player
+}
+
+// Set a rating that never changes, used for AI opponents
+func (this *Ratings) SetFixed(name string, rating float64) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	player := this.find(name)
+	player.Rating = rating
+	player.fixed = true
+}
+
+// Current rating of name
+func (this *Ratings) Rating(name string) float64 {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	return this.find(name).Rating
+}
+
+// Update the ratings of both players after a match
+func (this *Ratings) RecordMatch(winnerName, loserName string) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	winner, loser := this.find(winnerName), this.find(loserName)
+
+	expectedWin := 1.0 / (1.0 + math.Pow(10, (loser.Rating-winner.Rating)/400.0))
+	change := ratingK * (1.0 - expectedWin)
+
+	if !winner.fixed {
+		winner.Rating += change
+	}
+	if !loser.fixed {
+		loser.Rating -= change
+	}
+	winner.Games++
+	loser.Games++
+}
+
+// Serve the ratings as json, highest rating first
+func (this *Ratings) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	this.lock.Lock()
+	players := make([]PlayerRating, 0, len(this.Players))
+	for _, player := range this.Players {
+		players = append(players, *player)
+	}
+	this.lock.Unlock()
+
+	sort.Slice(players, func(i, j int) bool { return players[i].Rating > players[j].Rating })
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(players)
+}
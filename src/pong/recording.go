@@ -0,0 +1,316 @@
+package pong
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"math"
+
+	"github.com/brandonagr/pongpi/src/pong/atomicfile"
+)
+
+// A single change in the state of a button during a game
+type RecordedPress struct {
+
+	// game time in seconds when the button changed
+	Time float64 `xml:"time,attr"`
+
+	// which button changed
+	Left bool `xml:"left,attr"`
+
+	// true when the button was pushed, false when released
+	Down bool `xml:"down,attr"`
+}
+
+// State of the ball each time it was served or hit back, it moves in a straight line until the next one
+type RecordedBall struct {
+
+	// game time in seconds the ball was at Position
+	Time float64 `xml:"time,attr"`
+
+	Position float64 `xml:"position,attr"`
+	Velocity float64 `xml:"velocity,attr"`
+
+	// side that missed the ball before it was served again, left or right, empty for serves and hits
+	Missed string `xml:"missed,attr,omitempty"`
+}
+
+// Timing of every button press during a single game, and where the ball went
+type GameRecording struct {
+	XMLName xml.Name `xml:"GameRecording"`
+
+	// if the ball was served from the left end
+	ServedFromLeft bool
+
+	// if the left player won the game
+	LeftWon bool
+
+	// if the game was left unfinished, LeftWon is meaningless
+	Abandoned bool `xml:",omitempty"`
+
+	// game time in seconds when the recording ended
+	Duration float64 `xml:",omitempty"`
+
+	// seconds of life each player started with, 0 for recordings made before handicaps
+	LeftLife  float64 `xml:",omitempty"`
+	RightLife float64 `xml:",omitempty"`
+
+	// every button change, in increasing Time order
+	Presses []RecordedPress `xml:"Press"`
+
+	// every serve and hit, in increasing Time order
+	Balls []RecordedBall `xml:"Ball"`
+
+	// state while recording
+	time                        float64
+	leftPrevious, rightPrevious bool
+}
+
+// Construct an empty GameRecording
+func NewGameRecording(servedFromLeft bool) *GameRecording {
+	return &GameRecording{
+		ServedFromLeft: servedFromLeft,
+	}
+}
+
+// Game time in seconds recorded so far
+func (this *GameRecording) Time() float64 {
+	return this.time
+}
+
+// Move the recording forward by dt and record any change in button state
+func (this *GameRecording) Record(dt float64, left, right bool) {
+
+	this.time += dt
+
+	if left != this.leftPrevious {
+		this.Presses = append(this.Presses, RecordedPress{this.time, true, left})
+		this.leftPrevious = left
+	}
+	if right != this.rightPrevious {
+		this.Presses = append(this.Presses, RecordedPress{this.time, false, right})
+		this.rightPrevious = right
+	}
+}
+
+// Record the ball serving or bouncing from position with velocity at the current time, missed is the side that missed it if any
+func (this *GameRecording) RecordBall(position, velocity float64, missed string) {
+	this.Balls = append(this.Balls, RecordedBall{this.time, position, velocity, missed})
+}
+
+// Last seconds of the final rally of the recording as a recording of its own starting at 0, with the lives the players
+// had left once each miss before it took lifePenalty, nil if the ball was never recorded. The serve after the final miss
+// is left out so playback ends with the ball going past the player who missed it
+func (this *GameRecording) FinalRally(seconds, lifePenalty float64) *GameRecording {
+
+	balls := this.Balls
+	if len(balls) > 0 && balls[len(balls)-1].Missed != "" {
+		balls = balls[:len(balls)-1]
+	}
+	if len(balls) == 0 {
+		return nil
+	}
+
+	// the rally starts with the last serve, which follows a miss or is the first ball of the game
+	first := 0
+	for index := range balls {
+		if balls[index].Missed != "" {
+			first = index
+		}
+	}
+	start := math.Max(balls[first].Time, this.Duration-seconds)
+	for first+1 < len(balls) && balls[first+1].Time <= start {
+		first++
+	}
+
+	rally := &GameRecording{
+		LeftLife:  this.LeftLife,
+		RightLife: this.RightLife,
+		Duration:  this.Duration - start,
+		LeftWon:   this.LeftWon,
+	}
+	if rally.LeftLife <= 0 || rally.RightLife <= 0 {
+		// recordings made before handicaps all started the same
+		rally.LeftLife, rally.RightLife = Settings.LifeInSeconds, Settings.LifeInSeconds
+	}
+	for _, ball := range this.Balls[:first+1] {
+		switch ball.Missed {
+		case "left":
+			rally.LeftLife -= lifePenalty
+		case "right":
+			rally.RightLife -= lifePenalty
+		}
+	}
+
+	// the ball has kept moving since it was served or hit before the start
+	served := balls[first]
+	rally.Balls = append(rally.Balls, RecordedBall{0, served.Position + served.Velocity*(start-served.Time), served.Velocity, ""})
+	rally.ServedFromLeft = served.Velocity > 0
+	for _, ball := range balls[first+1:] {
+		rally.Balls = append(rally.Balls, RecordedBall{ball.Time - start, ball.Position, ball.Velocity, ball.Missed})
+	}
+
+	// buttons held at the start are pushed as playback begins
+	var left, right bool
+	for _, press := range this.Presses {
+		if press.Time > start {
+			rally.Presses = append(rally.Presses, RecordedPress{press.Time - start, press.Left, press.Down})
+		} else if press.Left {
+			left = press.Down
+		} else {
+			right = press.Down
+		}
+	}
+	held := []RecordedPress{}
+	if left {
+		held = append(held, RecordedPress{0, true, true})
+	}
+	if right {
+		held = append(held, RecordedPress{0, false, true})
+	}
+	rally.Presses = append(held, rally.Presses...)
+	return rally
+}
+
+// Load a recording written by Save
+func LoadGameRecording(path string) (*GameRecording, error) {
+
+	fileData, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	recording := &GameRecording{}
+	if err := xml.Unmarshal(fileData, recording); err != nil {
+		return nil, err
+	}
+
+	return recording, nil
+}
+
+// Write the recording to path
+func (this *GameRecording) Save(path string) error {
+
+	fileData, err := xml.MarshalIndent(this, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(path, fileData, 0666)
+}
+
+// ButtonInput where one side is played back from a recording and the other is a real player
+type GhostInput struct {
+
+	// buttons used by the real player
+	buttons ButtonInput
+
+	// recording being played back
+	recording *GameRecording
+
+	// side the ghost plays on
+	ghostLeft bool
+
+	// playback state
+	time      float64
+	nextPress int
+	ghostDown bool
+}
+
+var _ TimedInput = &GhostInput{}
+
+// Construct a GhostInput that replays the presses of the winner of recording
+func NewGhostInput(buttons ButtonInput, recording *GameRecording) *GhostInput {
+	return &GhostInput{
+		buttons:   buttons,
+		recording: recording,
+		ghostLeft: recording.LeftWon,
+	}
+}
+
+// Side the ghost is playing on
+func (this *GhostInput) GhostIsLeft() bool {
+	return this.ghostLeft
+}
+
+// Move playback forward by dt
+func (this *GhostInput) Advance(dt float64) {
+
+	this.time += dt
+
+	for ; this.nextPress < len(this.recording.Presses); this.nextPress++ {
+
+		press := this.recording.Presses[this.nextPress]
+		if press.Time > this.time {
+			break
+		}
+
+		if press.Left == this.ghostLeft {
+			this.ghostDown = press.Down
+		}
+	}
+}
+
+// State of the left button
+func (this *GhostInput) LeftButton() bool {
+	if this.ghostLeft {
+		return this.ghostDown
+	}
+	return this.buttons.LeftButton()
+}
+
+// State of the right button
+func (this *GhostInput) RightButton() bool {
+	if !this.ghostLeft {
+		return this.ghostDown
+	}
+	return this.buttons.RightButton()
+}
+
+// ButtonInput where both sides are played back from a recording
+type ReplayInput struct {
+
+	// recording being played back
+	recording *GameRecording
+
+	// playback state
+	time        float64
+	nextPress   int
+	left, right bool
+}
+
+var _ TimedInput = &ReplayInput{}
+
+// Construct a ReplayInput that replays every press in recording
+func NewReplayInput(recording *GameRecording) *ReplayInput {
+	return &ReplayInput{recording: recording}
+}
+
+// Move playback forward by dt
+func (this *ReplayInput) Advance(dt float64) {
+
+	this.time += dt
+
+	for ; this.nextPress < len(this.recording.Presses); this.nextPress++ {
+
+		press := this.recording.Presses[this.nextPress]
+		if press.Time > this.time {
+			break
+		}
+
+		if press.Left {
+			this.left = press.Down
+		} else {
+			this.right = press.Down
+		}
+	}
+}
+
+// State of the left button
+func (this *ReplayInput) LeftButton() bool {
+	return this.left
+}
+
+// State of the right button
+func (this *ReplayInput) RightButton() bool {
+	return this.right
+}
@@ -0,0 +1,77 @@
+package pong
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+// A saved recording should play back both buttons and keep every serve and hit of the ball
+func Test_GameRecording_Replay(t *testing.T) {
+
+	recording := NewGameRecording(true)
+	recording.RecordBall(0, 150, "")
+	recording.Record(0.5, true, false)
+	recording.Record(0.5, false, true)
+	recording.RecordBall(0.5, -160, "")
+	recording.Record(0.5, false, false)
+	recording.RecordBall(225, -160, "left")
+
+	fileData, err := xml.Marshal(recording)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := &GameRecording{}
+	if err := xml.Unmarshal(fileData, loaded); err != nil {
+		t.Fatal(err)
+	}
+	Assert(len(loaded.Balls), 3, "Recorded balls", t)
+	if loaded.Balls[2].Missed != "left" || loaded.Balls[1].Time != 1 {
+		t.Fatal("Ball recorded as", loaded.Balls)
+	}
+
+	input := NewReplayInput(loaded)
+	input.Advance(0.6)
+	if !input.LeftButton() || input.RightButton() {
+		t.Fatal("Buttons after the first press", input.LeftButton(), input.RightButton())
+	}
+	input.Advance(0.5)
+	if input.LeftButton() || !input.RightButton() {
+		t.Fatal("Buttons after the second press", input.LeftButton(), input.RightButton())
+	}
+}
+
+// The final rally should be cut to its last seconds with the ball and buttons as they were when it starts
+func Test_GameRecording_FinalRally(t *testing.T) {
+
+	recording := &GameRecording{
+		Duration:  10,
+		LeftLife:  10,
+		RightLife: 10,
+		Presses:   []RecordedPress{{2.5, false, true}, {3.2, false, false}, {5.5, true, true}, {7, true, false}},
+		Balls:     []RecordedBall{{0, 0, 100, ""}, {2, 30, -10, "left"}, {3, 20, 10, ""}, {10, 30, 10, "right"}},
+	}
+
+	rally := recording.FinalRally(4, 0.75)
+	if rally.Duration != 4 || rally.LeftLife != 9.25 || rally.RightLife != 10 || !rally.ServedFromLeft {
+		t.Fatal("Final rally is", rally)
+	}
+	Assert(len(rally.Balls), 1, "Balls in the final rally", t)
+	if rally.Balls[0] != (RecordedBall{0, 50, 10, ""}) {
+		t.Fatal("Ball starts the final rally as", rally.Balls[0])
+	}
+	Assert(len(rally.Presses), 2, "Presses in the final rally", t)
+	if rally.Presses[0] != (RecordedPress{0, true, true}) || rally.Presses[1] != (RecordedPress{1, true, false}) {
+		t.Fatal("Final rally pushes", rally.Presses)
+	}
+
+	// a short rally is replayed from its serve
+	rally = recording.FinalRally(60, 0.75)
+	if rally.Duration != 8 || rally.Balls[0] != (RecordedBall{0, 30, -10, ""}) || rally.ServedFromLeft {
+		t.Fatal("Whole final rally is", rally, rally.Balls)
+	}
+	Assert(len(rally.Balls), 2, "Balls in the whole final rally", t)
+
+	if (&GameRecording{}).FinalRally(4, 0.75) != nil {
+		t.Fatal("Replayed a recording without the ball")
+	}
+}
@@ -0,0 +1,109 @@
+// Package render gathers what is needed for drawing on a strip or matrix of leds, for projects embedding the renderer
+// without the rest of the game. The names are part of the stable API described in the pong package
+package render
+
+import (
+	"github.com/brandonagr/pongpi/src/pong"
+)
+
+// Colors, the z order drawables are blended in and what a drawable has to implement
+type (
+	RGBA                 = pong.RGBA
+	ZIndex               = pong.ZIndex
+	Drawable             = pong.Drawable
+	Drawable2D           = pong.Drawable2D
+	BoundedDrawable      = pong.BoundedDrawable
+	TrackedDrawable      = pong.TrackedDrawable
+	InterpolatedDrawable = pong.InterpolatedDrawable
+	ContextDrawable      = pong.ContextDrawable
+	LifecycleDrawable    = pong.LifecycleDrawable
+	AnimateContext       = pong.AnimateContext
+)
+
+// Fields drawables are added to and scenes crossfaded between
+type (
+	Field        = pong.Field
+	Topology     = pong.Topology
+	GameField    = pong.GameField
+	Scene        = pong.Scene
+	SceneManager = pong.SceneManager
+)
+
+// How the ends of a field are joined
+const (
+	TopologyLine = pong.TopologyLine
+	TopologyLoop = pong.TopologyLoop
+)
+
+// Places, spans and speeds along a field
+type (
+	Position = pong.Position
+	Extent   = pong.Extent
+	Velocity = pong.Velocity
+)
+
+// Effects chosen by name, see RegisterEffect
+type (
+	EffectParams  = pong.EffectParams
+	EffectFactory = pong.EffectFactory
+	EffectParam   = pong.EffectParam
+)
+
+// Kinds of value an effect parameter takes
+const (
+	EffectParamNumber = pong.EffectParamNumber
+	EffectParamColor  = pong.EffectParamColor
+	EffectParamText   = pong.EffectParamText
+	EffectParamFile   = pong.EffectParamFile
+)
+
+// Where something at position moving at velocity ends up after bouncing off whichever end of extent it went past
+func Reflect(position Position, velocity Velocity, extent Extent) (Position, Velocity) {
+	return pong.Reflect(position, velocity, extent)
+}
+
+// Construct a field of width leds in a strip
+func NewGameField(width int) *GameField {
+	return pong.NewGameField(width)
+}
+
+// Construct a field of height rows of width leds each, rendered a row at a time from the top
+func NewMatrixField(width, height int) *GameField {
+	return pong.NewMatrixField(width, height)
+}
+
+// Construct an empty scene for a strip of width leds
+func NewScene(name string, width int) *Scene {
+	return pong.NewScene(name, width)
+}
+
+// Construct an empty scene for a matrix of height rows of width leds
+func NewMatrixScene(name string, width, height int) *Scene {
+	return pong.NewMatrixScene(name, width, height)
+}
+
+// Construct a SceneManager for a display with width leds
+func NewSceneManager(width int) *SceneManager {
+	return pong.NewSceneManager(width)
+}
+
+// Make an effect available by name, described by the params it reads, call from init of the package implementing the
+// effect
+func RegisterEffect(name string, factory EffectFactory, params ...EffectParam) {
+	pong.RegisterEffect(name, factory, params...)
+}
+
+// Describe a number parameter between min and max
+func NumberParam(name string, min, max, fallback float64) EffectParam {
+	return pong.NumberParam(name, min, max, fallback)
+}
+
+// Describe a #rrggbb color parameter
+func ColorParam(name string, fallback RGBA) EffectParam {
+	return pong.ColorParam(name, fallback)
+}
+
+// Create the effects of spec, such as fire+comet(color=#00ffff), on field at zindex
+func NewEffect(spec string, field Field, zindex ZIndex) (Drawable, error) {
+	return pong.NewEffect(spec, field, zindex)
+}
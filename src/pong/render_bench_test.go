@@ -0,0 +1,89 @@
+package pong
+
+import (
+	"fmt"
+	"testing"
+)
+
+var benchmarkColor RGBA
+
+func BenchmarkBlendWith(b *testing.B) {
+	foreground := RGBA{255, 128, 0, 100}
+	for iteration := 0; iteration < b.N; iteration++ {
+		benchmarkColor = foreground.BlendWith(RGBA{uint8(iteration), 64, 32, 255})
+	}
+}
+
+// Render a field of every width the strip comes in, covered by a full width and a small drawable that moves every frame
+func benchmarkRender(b *testing.B, pool *RenderPool) {
+	for _, width := range []int{60, 150, 300} {
+		b.Run(fmt.Sprint(width), func(b *testing.B) {
+			UseRenderPool(pool)
+			defer UseRenderPool(nil)
+
+			field := NewGameField(width)
+			field.Add(&GradientDrawable{})
+			dot := &DotDrawable{}
+			field.Add(dot)
+
+			b.ResetTimer()
+			for iteration := 0; iteration < b.N; iteration++ {
+				dot.position = float64(iteration % width)
+				dot.version++
+				field.Render()
+			}
+		})
+	}
+}
+
+func BenchmarkFieldRender(b *testing.B) {
+	benchmarkRender(b, nil)
+}
+
+func BenchmarkFieldRenderPool(b *testing.B) {
+	pool := NewRenderPool(0)
+	defer pool.Close()
+	benchmarkRender(b, pool)
+}
+
+func BenchmarkSceneCrossfade(b *testing.B) {
+	red := NewScene("red", 300)
+	red.Add(&SolidDrawable{RGBA{255, 0, 0, 255}})
+	gradient := NewScene("gradient", 300)
+	gradient.Add(&GradientDrawable{})
+
+	scenes := NewSceneManager(300)
+	scenes.Show(red, 0)
+	scenes.Show(gradient, 1e9)
+	capture := &CaptureDisplay{}
+
+	b.ResetTimer()
+	for iteration := 0; iteration < b.N; iteration++ {
+		scenes.Animate(1)
+		scenes.RenderTo(capture)
+	}
+}
+
+// Serialize a frame where every led changes, as happens with a moving background
+func BenchmarkLedDisplayEncode(b *testing.B) {
+	leds := &LedDisplay{expectedColors: 300, byteData: make([]byte, ledFrameSize(300))}
+	frames := [2][]RGBA{make([]RGBA, 300), make([]RGBA, 300)}
+	for index := range frames[1] {
+		frames[1][index] = RGBA{255, 255, 255, 255}
+	}
+
+	b.ResetTimer()
+	for iteration := 0; iteration < b.N; iteration++ {
+		leds.encode(frames[iteration%2])
+	}
+}
+
+func BenchmarkDimmedDisplay(b *testing.B) {
+	dimmed := NewDimmedDisplay(&CaptureDisplay{}, 0.3)
+	frame := make([]RGBA, 300)
+
+	b.ResetTimer()
+	for iteration := 0; iteration < b.N; iteration++ {
+		dimmed.Render(frame)
+	}
+}
@@ -0,0 +1,137 @@
+package pong
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+// Fewest leds given to a worker, smaller fields aren't worth splitting up
+var minRenderChunk int = 32
+
+// Goroutines that each render a range of leds of a field
+type RenderPool struct {
+	workers int
+	jobs    chan renderJob
+
+	// held while a frame is rendered, done is reused by every frame so rendering doesn't allocate
+	lock sync.Mutex
+	done sync.WaitGroup
+
+	// first panic of a worker while rendering the frame, raised again on the goroutine that asked for the frame
+	panicLock sync.Mutex
+	panicked  *RenderPanic
+}
+
+// Panic of a render worker, raised again by Render so the crash handler of the game loop sees it
+type RenderPanic struct {
+	Value interface{}
+
+	// stack of the worker that panicked
+	Stack []byte
+}
+
+// The value the worker panicked with
+func (this *RenderPanic) String() string {
+	return fmt.Sprint("rendering: ", this.Value)
+}
+
+// A range of leds to render into buffer
+type renderJob struct {
+	field      *GameField
+	buffer     []RGBA
+	start, end int
+	done       *sync.WaitGroup
+}
+
+// The pool fields render with, nil renders on the calling goroutine
+var renderPool *RenderPool
+
+// Construct a RenderPool with workers goroutines, 0 starts one per core
+func NewRenderPool(workers int) *RenderPool {
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	this := &RenderPool{
+		workers: workers,
+		jobs:    make(chan renderJob, workers),
+	}
+
+	for worker := 0; worker < workers; worker++ {
+		go this.work()
+	}
+
+	return this
+}
+
+// Render every field with pool, nil to render on the calling goroutine
+func UseRenderPool(pool *RenderPool) {
+	renderPool = pool
+}
+
+// Render jobs until the pool is closed
+func (this *RenderPool) work() {
+	for job := range this.jobs {
+		this.render(job)
+	}
+}
+
+// Render job, keeping a panic for Render to raise instead of letting it end the process
+func (this *RenderPool) render(job renderJob) {
+
+	defer job.done.Done()
+	defer func() {
+		if cause := recover(); cause != nil {
+			this.panicLock.Lock()
+			if this.panicked == nil {
+				this.panicked = &RenderPanic{cause, debug.Stack()}
+			}
+			this.panicLock.Unlock()
+		}
+	}()
+	job.field.renderRange(job.buffer, job.start, job.end)
+}
+
+// Split the positions from start to end exclusive into one chunk per worker and wait for all of them to be rendered into buffer
+func (this *RenderPool) Render(field *GameField, buffer []RGBA, start, end int) {
+
+	chunks := this.workers
+	if maxChunks := (end - start) / minRenderChunk; chunks > maxChunks {
+		chunks = maxChunks
+	}
+	if chunks <= 1 {
+		field.renderRange(buffer, start, end)
+		return
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.done.Add(chunks)
+	for chunk := 0; chunk < chunks; chunk++ {
+		this.jobs <- renderJob{
+			field:  field,
+			buffer: buffer,
+			start:  start + chunk*(end-start)/chunks,
+			end:    start + (chunk+1)*(end-start)/chunks,
+			done:   &this.done,
+		}
+	}
+	this.done.Wait()
+
+	this.panicLock.Lock()
+	panicked := this.panicked
+	this.panicked = nil
+	this.panicLock.Unlock()
+	if panicked != nil {
+		panic(panicked)
+	}
+}
+
+// Stop the workers
+func (this *RenderPool) Close() {
+	close(this.jobs)
+}
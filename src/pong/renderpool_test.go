@@ -0,0 +1,98 @@
+package pong
+
+import (
+	"testing"
+)
+
+// Drawable whose color depends on the position
+type GradientDrawable struct{}
+
+func (gradient *GradientDrawable) ColorAt(position float64, baseColor RGBA) RGBA {
+	return RGBA{uint8(position), 255 - uint8(position), 0, 255}
+}
+
+func (gradient *GradientDrawable) ZIndex() ZIndex {
+	return 0
+}
+
+func (gradient *GradientDrawable) Animate(dt float64) (keepAlive bool) {
+	return true
+}
+
+// Rendering with a pool should give the same frame as rendering on one goroutine
+func Test_RenderPool_MatchesSerial(t *testing.T) {
+	field := NewGameField(250)
+	field.Add(&GradientDrawable{})
+
+	expected := append([]RGBA{}, field.Render()...)
+
+	pool := NewRenderPool(4)
+	defer pool.Close()
+	UseRenderPool(pool)
+	defer UseRenderPool(nil)
+
+	actual := field.Render()
+	Assert(len(actual), len(expected), "Rendered width", t)
+	for index := range expected {
+		if actual[index] != expected[index] {
+			t.Fatal("Led", index, "was", actual[index], "vs expected", expected[index])
+		}
+	}
+}
+
+// Drawable that panics on every led
+type panickingDrawable struct{ GradientDrawable }
+
+func (this *panickingDrawable) ColorAt(position float64, baseColor RGBA) RGBA {
+	panic("broken drawable")
+}
+
+// A panic on a worker should be raised again on the goroutine rendering the frame, leaving the pool usable
+func Test_RenderPool_Panic(t *testing.T) {
+	field := NewGameField(250)
+	field.Add(&panickingDrawable{})
+
+	pool := NewRenderPool(4)
+	defer pool.Close()
+	UseRenderPool(pool)
+	defer UseRenderPool(nil)
+
+	func() {
+		defer func() {
+			cause := recover()
+			if rendering, ok := cause.(*RenderPanic); !ok || rendering.Value != "broken drawable" || len(rendering.Stack) == 0 {
+				t.Fatal("Render panicked with", cause)
+			}
+		}()
+		field.Render()
+	}()
+
+	field = NewGameField(250)
+	field.Add(&GradientDrawable{})
+	if color := field.Render()[10]; color != (RGBA{10, 245, 0, 255}) {
+		t.Fatal("Pool rendered", color, "after a panic")
+	}
+}
+
+// A matrix field should be rendered by the pool too, every row of the columns that changed
+func Test_RenderPool_Matrix(t *testing.T) {
+	field := NewMatrixField(250, 4)
+	field.Add(gradient2D{})
+
+	expected := append([]RGBA{}, field.Render()...)
+
+	pool := NewRenderPool(4)
+	defer pool.Close()
+	UseRenderPool(pool)
+	defer UseRenderPool(nil)
+
+	actual := NewMatrixField(250, 4)
+	actual.Add(gradient2D{})
+	frame := actual.Render()
+	Assert(len(frame), len(expected), "Rendered leds", t)
+	for index := range expected {
+		if frame[index] != expected[index] {
+			t.Fatal("Led", index, "was", frame[index], "vs expected", expected[index])
+		}
+	}
+}
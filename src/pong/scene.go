@@ -0,0 +1,151 @@
+package pong
+
+// A set of Drawables shown together, such as everything needed for one phase of the game
+type Scene struct {
+
+	// name used in logs
+	Name string
+
+	// field holding the drawables of this scene
+	field *GameField
+}
+
+// Construct an empty Scene
+func NewScene(name string, width int) *Scene {
+	return &Scene{
+		Name:  name,
+		field: NewGameField(width),
+	}
+}
+
+// Construct an empty Scene for a matrix of height rows of width leds
+func NewMatrixScene(name string, width, height int) *Scene {
+	return &Scene{
+		Name:  name,
+		field: NewMatrixField(width, height),
+	}
+}
+
+// Adds a drawable to the scene
+func (this *Scene) Add(drawable Drawable) {
+	this.field.Add(drawable)
+}
+
+// Adds every drawable of other to this scene, so scenes can be built out of smaller scenes
+func (this *Scene) Include(other *Scene) {
+	for _, drawable := range other.field.Drawables() {
+		this.field.Add(drawable)
+	}
+}
+
+// Field holding the drawables of this scene
+func (this *Scene) Field() *GameField {
+	return this.field
+}
+
+// Owns the scene being shown, crossfading from the previous scene when it changes
+type SceneManager struct {
+
+	// scene being shown and the scene fading out
+	current, previous *Scene
+
+	// progress of the crossfade
+	fadeTime, fadeDuration float64
+
+	// buffer the crossfade is blended into
+	renderBuffer []RGBA
+}
+
+// Construct a SceneManager for a display with width leds
+func NewSceneManager(width int) *SceneManager {
+	return &SceneManager{
+		renderBuffer: make([]RGBA, width),
+	}
+}
+
+// Show scene, crossfading from the current scene over fadeDuration seconds
+func (this *SceneManager) Show(scene *Scene, fadeDuration float64) {
+
+	dropped := this.previous
+	this.previous = this.current
+	this.current = scene
+	this.fadeTime = 0
+	this.fadeDuration = fadeDuration
+
+	if fadeDuration <= 0 {
+		this.drop(this.previous)
+		this.previous = nil
+	}
+	this.drop(dropped)
+}
+
+// Give the frame of a scene that is no longer shown back to be reused
+func (this *SceneManager) drop(scene *Scene) {
+	if scene != nil && scene != this.current && scene != this.previous {
+		scene.field.Release()
+	}
+}
+
+// Scene currently being shown
+func (this *SceneManager) Current() *Scene {
+	return this.current
+}
+
+// Animate the current scene and any scene fading out outside of a game, dropping it once the fade finishes
+func (this *SceneManager) Animate(dt float64) {
+	this.AnimateInContext(dt, AnimateContext{})
+}
+
+// Animate the current scene and any scene fading out while the game stands as context says
+func (this *SceneManager) AnimateInContext(dt float64, context AnimateContext) {
+
+	if this.previous != nil {
+		this.fadeTime += dt
+		if this.fadeTime >= this.fadeDuration {
+			previous := this.previous
+			this.previous = nil
+			this.drop(previous)
+		} else {
+			this.previous.field.AnimateInContext(dt, context)
+		}
+	}
+
+	this.current.field.AnimateInContext(dt, context)
+}
+
+// Draw the current scene and any scene fading out at alpha of the way between their last two steps
+func (this *SceneManager) Interpolate(alpha float64) {
+
+	if this.previous != nil {
+		this.previous.field.Interpolate(alpha)
+	}
+	this.current.field.Interpolate(alpha)
+}
+
+// Render the current scene, blended with the scene fading out, to display
+func (this *SceneManager) RenderTo(display Display) {
+
+	if this.previous == nil {
+		this.current.field.RenderTo(display)
+		return
+	}
+
+	currentData := this.current.field.Render()
+	previousData := this.previous.field.Render()
+
+	fade := uint(this.fadeTime / this.fadeDuration * 255)
+	for index := range this.renderBuffer {
+		if index >= len(currentData) || index >= len(previousData) {
+			break
+		}
+		current, previous := currentData[index], previousData[index]
+		this.renderBuffer[index] = RGBA{
+			uint8((uint(current.R)*fade + uint(previous.R)*(255-fade)) / 255),
+			uint8((uint(current.G)*fade + uint(previous.G)*(255-fade)) / 255),
+			uint8((uint(current.B)*fade + uint(previous.B)*(255-fade)) / 255),
+			255,
+		}
+	}
+
+	display.Render(this.renderBuffer)
+}
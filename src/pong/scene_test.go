@@ -0,0 +1,73 @@
+package pong
+
+import (
+	"testing"
+)
+
+// Drawable that covers the whole field in a single color
+type SolidDrawable struct {
+	color RGBA
+}
+
+func (solid *SolidDrawable) ColorAt(position float64, baseColor RGBA) RGBA {
+	return solid.color
+}
+
+func (solid *SolidDrawable) ZIndex() ZIndex {
+	return 0
+}
+
+func (solid *SolidDrawable) Animate(dt float64) (keepAlive bool) {
+	return true
+}
+
+// Display that keeps a copy of the last frame
+type CaptureDisplay struct {
+	frame []RGBA
+}
+
+func (capture *CaptureDisplay) Render(data []RGBA) {
+	capture.frame = append(capture.frame[:0], data...)
+}
+
+// Showing a new scene should crossfade from the old one and then drop it
+func Test_SceneManager_Crossfade(t *testing.T) {
+	red := NewScene("red", 4)
+	red.Add(&SolidDrawable{RGBA{255, 0, 0, 255}})
+	blue := NewScene("blue", 4)
+	blue.Add(&SolidDrawable{RGBA{0, 0, 255, 255}})
+
+	scenes := NewSceneManager(4)
+	display := &CaptureDisplay{}
+
+	scenes.Show(red, 0)
+	scenes.RenderTo(display)
+	Assert(int(display.frame[0].R), 255, "Red before fade", t)
+
+	scenes.Show(blue, 1.0)
+	scenes.Animate(0.5)
+	scenes.RenderTo(display)
+	Assert(int(display.frame[0].R), 128, "Red halfway through fade", t)
+	Assert(int(display.frame[0].B), 127, "Blue halfway through fade", t)
+
+	scenes.Animate(0.6)
+	scenes.RenderTo(display)
+	Assert(int(display.frame[0].R), 0, "Red after fade", t)
+	Assert(int(display.frame[0].B), 255, "Blue after fade", t)
+}
+
+// Including a scene should add all of its drawables
+func Test_Scene_Include(t *testing.T) {
+	background := NewScene("background", 4)
+	background.Add(&SolidDrawable{})
+	background.Add(&CountdownDrawable{maxLife: 1})
+
+	scene := NewScene("combined", 4)
+	scene.Add(&CountdownDrawable{maxLife: 2})
+	scene.Include(background)
+
+	Assert(scene.Field().DrawableLen(), 3, "Drawables after Include", t)
+	if !scene.Field().IsValid() {
+		t.Fatal("Invalid field")
+	}
+}
@@ -0,0 +1,66 @@
+package pong
+
+import (
+	"fmt"
+	"time"
+)
+
+// Part of each day when the installation should be quiet, may wrap past midnight
+type QuietHours struct {
+
+	// time since midnight that quiet hours start and end
+	start, end time.Duration
+
+	// false when no quiet hours are configured
+	enabled bool
+}
+
+// Parse quiet hours from times such as "23:00" and "08:00", empty times disable quiet hours
+func ParseQuietHours(start, end string) (QuietHours, error) {
+
+	if start == "" || end == "" {
+		return QuietHours{}, nil
+	}
+
+	startTime, err := parseTimeOfDay(start)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	endTime, err := parseTimeOfDay(end)
+	if err != nil {
+		return QuietHours{}, err
+	}
+
+	return QuietHours{start: startTime, end: endTime, enabled: true}, nil
+}
+
+// Parse a time such as "23:00" into the time since midnight
+func parseTimeOfDay(value string) (time.Duration, error) {
+
+	var hours, minutes int
+	if _, err := fmt.Sscanf(value, "%d:%d", &hours, &minutes); err != nil {
+		return 0, fmt.Errorf("invalid time of day %q: %v", value, err)
+	}
+	if hours < 0 || hours > 23 || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("invalid time of day %q", value)
+	}
+
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
+
+// Returns true if now is within the quiet hours
+func (this QuietHours) Contains(now time.Time) bool {
+
+	if !this.enabled {
+		return false
+	}
+
+	timeOfDay := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute + time.Duration(now.Second())*time.Second
+
+	if this.start <= this.end {
+		return this.start <= timeOfDay && timeOfDay < this.end
+	}
+
+	// wraps past midnight
+	return timeOfDay >= this.start || timeOfDay < this.end
+}
@@ -0,0 +1,34 @@
+package pong
+
+import (
+	"testing"
+	"time"
+)
+
+// Quiet hours that wrap past midnight should include both late evening and early morning
+func Test_QuietHours_Contains(t *testing.T) {
+	quietHours, err := ParseQuietHours("23:00", "08:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2014, 1, 1, hour, minute, 0, 0, time.Local)
+	}
+
+	if !quietHours.Contains(at(23, 30)) || !quietHours.Contains(at(3, 0)) {
+		t.Fatal("Expected night to be quiet")
+	}
+	if quietHours.Contains(at(8, 0)) || quietHours.Contains(at(12, 0)) || quietHours.Contains(at(22, 59)) {
+		t.Fatal("Expected day to not be quiet")
+	}
+
+	disabled, _ := ParseQuietHours("", "")
+	if disabled.Contains(at(3, 0)) {
+		t.Fatal("Expected disabled quiet hours to never be quiet")
+	}
+
+	if _, err := ParseQuietHours("25:00", "08:00"); err == nil {
+		t.Fatal("Expected error for invalid hour")
+	}
+}
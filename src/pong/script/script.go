@@ -0,0 +1,386 @@
+// Package script runs the small language effects can be written in, lines of name = expression worked out for every
+// led of every frame. Scripts can only do arithmetic on the values they are given, so they can't touch anything else
+package script
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A compiled script, run with the values of its inputs in a frame from NewFrame
+type Program struct {
+
+	// assignments in the order they are made
+	statements []statement
+
+	// slot in a frame of every input and assigned name
+	slots map[string]int
+}
+
+// Assignment of an expression to a slot of the frame
+type statement struct {
+	slot  int
+	value expression
+}
+
+// Part of a script worked out to a number from the values in a frame
+type expression func(frame []float64) float64
+
+// Constants every script can use
+var constants = map[string]float64{
+	"pi": math.Pi,
+}
+
+// Functions every script can call, by name
+var functions = map[string]struct {
+	arguments int
+	call      func(arguments []float64) float64
+}{
+	"sin":   {1, func(a []float64) float64 { return math.Sin(a[0]) }},
+	"cos":   {1, func(a []float64) float64 { return math.Cos(a[0]) }},
+	"abs":   {1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"floor": {1, func(a []float64) float64 { return math.Floor(a[0]) }},
+	"fract": {1, func(a []float64) float64 { return a[0] - math.Floor(a[0]) }},
+	"sqrt":  {1, func(a []float64) float64 { return math.Sqrt(math.Max(a[0], 0)) }},
+	"min":   {2, func(a []float64) float64 { return math.Min(a[0], a[1]) }},
+	"max":   {2, func(a []float64) float64 { return math.Max(a[0], a[1]) }},
+	"pow":   {2, func(a []float64) float64 { return math.Pow(a[0], a[1]) }},
+	"step":  {2, func(a []float64) float64 { return boolean(a[1] >= a[0]) }},
+	"clamp": {3, func(a []float64) float64 { return math.Max(a[1], math.Min(a[2], a[0])) }},
+	"mix":   {3, func(a []float64) float64 { return a[0] + (a[1]-a[0])*a[2] }},
+	"if": {3, func(a []float64) float64 {
+		if a[0] != 0 {
+			return a[1]
+		}
+		return a[2]
+	}},
+}
+
+// 1 for true and 0 for false
+func boolean(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// Compile source, which can read inputs and assign any other name. Lines are name = expression, blank lines and
+// anything after # are skipped
+func Compile(source string, inputs []string) (*Program, error) {
+
+	program := &Program{slots: map[string]int{}}
+	for _, input := range inputs {
+		program.slot(input)
+	}
+
+	for number, line := range strings.Split(source, "\n") {
+		if comment := strings.IndexByte(line, '#'); comment >= 0 {
+			line = line[:comment]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		assignment := strings.SplitN(line, "=", 2)
+		name := strings.TrimSpace(assignment[0])
+		if len(assignment) != 2 || !isName(name) {
+			return nil, fmt.Errorf("Line %v isn't name = expression", number+1)
+		}
+		if _, ok := constants[name]; ok {
+			return nil, fmt.Errorf("Line %v assigns the constant %v", number+1, name)
+		}
+
+		parser := &parser{program: program, tokens: tokenize(assignment[1])}
+		value, err := parser.parse()
+		if err != nil {
+			return nil, fmt.Errorf("Line %v: %v", number+1, err)
+		}
+		program.statements = append(program.statements, statement{program.slot(name), value})
+	}
+	return program, nil
+}
+
+// Slot of name in a frame, adding one if it doesn't have one yet
+func (this *Program) slot(name string) int {
+	if slot, ok := this.slots[name]; ok {
+		return slot
+	}
+	this.slots[name] = len(this.slots)
+	return this.slots[name]
+}
+
+// Frame to run the program in, its inputs are at the start in the order they were given to Compile
+func (this *Program) NewFrame() []float64 {
+	return make([]float64, len(this.slots))
+}
+
+// Values in a frame of the program
+func (this *Program) FrameLength() int {
+	return len(this.slots)
+}
+
+// Slot of name in a frame, false if the script never assigns it and it isn't an input
+func (this *Program) Slot(name string) (int, bool) {
+	slot, ok := this.slots[name]
+	return slot, ok
+}
+
+// Make every assignment of the script in frame, in order
+func (this *Program) Run(frame []float64) {
+	for _, statement := range this.statements {
+		frame[statement.slot] = statement.value(frame)
+	}
+}
+
+// If text can be assigned to
+func isName(text string) bool {
+	for index, character := range text {
+		if !(unicode.IsLetter(character) || character == '_' || index > 0 && unicode.IsDigit(character)) {
+			return false
+		}
+	}
+	return text != ""
+}
+
+// Split an expression into numbers, names and operators
+func tokenize(text string) []string {
+
+	tokens := []string{}
+	for index := 0; index < len(text); {
+		character := rune(text[index])
+		start := index
+		switch {
+		case unicode.IsSpace(character):
+			index++
+			continue
+		case unicode.IsDigit(character) || character == '.':
+			for index < len(text) && (unicode.IsDigit(rune(text[index])) || text[index] == '.') {
+				index++
+			}
+		case unicode.IsLetter(character) || character == '_':
+			for index < len(text) && isName(text[start:index+1]) {
+				index++
+			}
+		case strings.Contains("<>=!&|", string(character)) && index+1 < len(text) && strings.Contains("=&|", string(text[index+1])):
+			index += 2
+		default:
+			index++
+		}
+		tokens = append(tokens, text[start:index])
+	}
+	return tokens
+}
+
+// Recursive descent parser of an expression, lowest precedence first
+type parser struct {
+	program *Program
+	tokens  []string
+}
+
+// Operators of each level of precedence, lowest first
+var binaryOperators = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+// Parse every token as a single expression
+func (this *parser) parse() (expression, error) {
+	value, err := this.binary(0)
+	if err != nil {
+		return nil, err
+	}
+	if len(this.tokens) > 0 {
+		return nil, fmt.Errorf("Unexpected %q", this.tokens[0])
+	}
+	return value, nil
+}
+
+// Next token, empty at the end
+func (this *parser) peek() string {
+	if len(this.tokens) == 0 {
+		return ""
+	}
+	return this.tokens[0]
+}
+
+// Take the next token, which has to be expected
+func (this *parser) expect(expected string) error {
+	if this.peek() != expected {
+		return fmt.Errorf("Expected %q but found %q", expected, this.peek())
+	}
+	this.tokens = this.tokens[1:]
+	return nil
+}
+
+// Parse operators of precedence level and above
+func (this *parser) binary(level int) (expression, error) {
+
+	if level == len(binaryOperators) {
+		return this.unary()
+	}
+
+	left, err := this.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		operator := this.peek()
+		found := false
+		for _, candidate := range binaryOperators[level] {
+			found = found || candidate == operator
+		}
+		if !found {
+			return left, nil
+		}
+		this.tokens = this.tokens[1:]
+
+		right, err := this.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = combine(operator, left, right)
+	}
+}
+
+// Expression applying operator to left and right
+func combine(operator string, left, right expression) expression {
+	switch operator {
+	case "||":
+		return func(frame []float64) float64 { return boolean(left(frame) != 0 || right(frame) != 0) }
+	case "&&":
+		return func(frame []float64) float64 { return boolean(left(frame) != 0 && right(frame) != 0) }
+	case "==":
+		return func(frame []float64) float64 { return boolean(left(frame) == right(frame)) }
+	case "!=":
+		return func(frame []float64) float64 { return boolean(left(frame) != right(frame)) }
+	case "<":
+		return func(frame []float64) float64 { return boolean(left(frame) < right(frame)) }
+	case "<=":
+		return func(frame []float64) float64 { return boolean(left(frame) <= right(frame)) }
+	case ">":
+		return func(frame []float64) float64 { return boolean(left(frame) > right(frame)) }
+	case ">=":
+		return func(frame []float64) float64 { return boolean(left(frame) >= right(frame)) }
+	case "+":
+		return func(frame []float64) float64 { return left(frame) + right(frame) }
+	case "-":
+		return func(frame []float64) float64 { return left(frame) - right(frame) }
+	case "*":
+		return func(frame []float64) float64 { return left(frame) * right(frame) }
+	case "/":
+		return func(frame []float64) float64 {
+			if divisor := right(frame); divisor != 0 {
+				return left(frame) / divisor
+			}
+			return 0
+		}
+	}
+	return func(frame []float64) float64 {
+		if divisor := right(frame); divisor != 0 {
+			return math.Mod(left(frame), divisor)
+		}
+		return 0
+	}
+}
+
+// Parse a negated or inverted value
+func (this *parser) unary() (expression, error) {
+
+	switch this.peek() {
+	case "-":
+		this.tokens = this.tokens[1:]
+		value, err := this.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(frame []float64) float64 { return -value(frame) }, nil
+	case "!":
+		this.tokens = this.tokens[1:]
+		value, err := this.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(frame []float64) float64 { return boolean(value(frame) == 0) }, nil
+	}
+	return this.primary()
+}
+
+// Parse a number, name, call or expression in parentheses
+func (this *parser) primary() (expression, error) {
+
+	token := this.peek()
+	if token == "" {
+		return nil, fmt.Errorf("Expression ends early")
+	}
+	this.tokens = this.tokens[1:]
+
+	if token == "(" {
+		value, err := this.binary(0)
+		if err != nil {
+			return nil, err
+		}
+		return value, this.expect(")")
+	}
+
+	if number, err := strconv.ParseFloat(token, 64); err == nil {
+		return func(frame []float64) float64 { return number }, nil
+	}
+
+	if !isName(token) {
+		return nil, fmt.Errorf("Unexpected %q", token)
+	}
+
+	if this.peek() == "(" {
+		return this.call(token)
+	}
+	if constant, ok := constants[token]; ok {
+		return func(frame []float64) float64 { return constant }, nil
+	}
+	slot, ok := this.program.slots[token]
+	if !ok {
+		return nil, fmt.Errorf("%v is used before it is assigned", token)
+	}
+	return func(frame []float64) float64 { return frame[slot] }, nil
+}
+
+// Parse the arguments of a call to the function name
+func (this *parser) call(name string) (expression, error) {
+
+	function, ok := functions[name]
+	if !ok {
+		return nil, fmt.Errorf("There's no function called %v", name)
+	}
+
+	arguments := []expression{}
+	this.tokens = this.tokens[1:]
+	for this.peek() != ")" {
+		if len(arguments) > 0 {
+			if err := this.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		argument, err := this.binary(0)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, argument)
+	}
+	this.tokens = this.tokens[1:]
+
+	if len(arguments) != function.arguments {
+		return nil, fmt.Errorf("%v takes %v arguments, not %v", name, function.arguments, len(arguments))
+	}
+	return func(frame []float64) float64 {
+		values := make([]float64, len(arguments))
+		for index, argument := range arguments {
+			values[index] = argument(frame)
+		}
+		return function.call(values)
+	}, nil
+}
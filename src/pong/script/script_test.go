@@ -0,0 +1,42 @@
+package script
+
+import (
+	"testing"
+)
+
+// Assignments should be worked out in order with operator precedence, functions and earlier names
+func Test_Script_Run(t *testing.T) {
+
+	program, err := Compile(`
+		# comments and blank lines are skipped
+		half = x / 2
+		r = 1 + half * 3 - -1   # 1 + 6 + 1
+		g = clamp(half - 5, 0, 1) + if(x > 3 && !(x == 5), 10, 20)
+		b = (x % 3) * min(2, pow(2, 3)) + x / 0
+	`, []string{"x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame := program.NewFrame()
+	frame[0] = 4
+	program.Run(frame)
+
+	for name, expected := range map[string]float64{"half": 2, "r": 8, "g": 10, "b": 2} {
+		slot, ok := program.Slot(name)
+		if !ok || frame[slot] != expected {
+			t.Fatal(name, "is", frame[slot], "not", expected)
+		}
+	}
+}
+
+// Mistakes should be reported with their line rather than run
+func Test_Script_Mistakes(t *testing.T) {
+	for _, source := range []string{"r = y", "r = 1 +", "r = (1", "r = 1 2", "r = nope(1)", "r = sin(1, 2)", "1 = 2", "pi = 3", "r"} {
+		if _, err := Compile("\n"+source, []string{"x"}); err == nil {
+			t.Fatal(source, "compiled")
+		} else if err.Error()[:6] != "Line 2" {
+			t.Fatal(source, "reported", err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package pong
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// Color the strip blinks to count the problems found by SelfTest, on every other led so it can't be mistaken for a
+// scene of the game
+var startupProblemColor = RGBA{255, 0, 0, 255}
+
+// Time each blink is lit and dark for, the most blinks in a round, the pause after each round and the rounds shown
+var (
+	problemBlinkTime  = 300 * time.Millisecond
+	problemBlinkCount = 10
+	problemPauseTime  = 1500 * time.Millisecond
+	problemRounds     = 3
+)
+
+// Sleep between blinks, replaced by the tests
+var problemSleep = time.Sleep
+
+// Check the settings with CheckStartup and, if they are right, send a dark frame down the SPI bus if leds and claim the
+// gpio pins of the buttons and encoder if buttons, returns StartupProblems listing everything wrong or nil
+func SelfTest(settings SettingsData, leds, buttons bool) error {
+
+	if err := CheckStartup(settings, leds, buttons); err != nil {
+		return err
+	}
+
+	var problems StartupProblems
+	if leds {
+		if err := testSpiBus(settings); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	if buttons {
+		if reader, err := NewGpioReader(settings); err != nil {
+			problems = append(problems, err)
+		} else {
+			reader.Close()
+		}
+		if encoder, err := NewGpioEncoder(settings); err != nil {
+			problems = append(problems, err)
+		} else if encoder != nil {
+			encoder.Close()
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return problems
+}
+
+// Open the SPI bus and write a whole dark frame to every led, error if the bus can't be opened or takes less than all
+// of it
+func testSpiBus(settings SettingsData) error {
+
+	bus, err := NewSpiBus(settings.SpiFilePath, settings.SpiBusSpeedHz)
+	if err != nil {
+		return err
+	}
+	defer bus.Close()
+
+	leds := &LedDisplay{byteData: make([]byte, ledFrameSize(settings.Leds()))}
+	leds.encode(make([]RGBA, settings.Leds()))
+	if n, err := bus.Write(leds.byteData); err != nil || n != len(leds.byteData) {
+		return fmt.Errorf("SPI bus %v took %v of the %v bytes of a dark frame: %v", settings.SpiFilePath, n, len(leds.byteData), err)
+	}
+	return nil
+}
+
+// Blink every other one of ledCount leds on display once for each problem in err, up to problemBlinkCount, with a pause
+// after each round so they can be counted
+func BlinkProblems(display Display, ledCount int, err error) {
+
+	count := 1
+	if problems, ok := err.(StartupProblems); ok {
+		count = len(problems)
+	}
+	if count > problemBlinkCount {
+		count = problemBlinkCount
+	}
+
+	lit, dark := make([]RGBA, ledCount), make([]RGBA, ledCount)
+	for led := 0; led < ledCount; led += 2 {
+		lit[led] = startupProblemColor
+	}
+
+	for round := 0; round < problemRounds; round++ {
+		for blink := 0; blink < count; blink++ {
+			display.Render(lit)
+			problemSleep(problemBlinkTime)
+			display.Render(dark)
+			problemSleep(problemBlinkTime)
+		}
+		problemSleep(problemPauseTime)
+	}
+}
+
+// Blink the problems in err on the strip in settings, if the SPI bus can still be opened
+func BlinkStartupProblems(settings SettingsData, err error) {
+
+	leds, openErr := NewLedDisplay(settings, settings.Leds())
+	if openErr != nil {
+		log.Print("Problems can't be shown on the strip: ", openErr)
+		return
+	}
+	defer leds.Close()
+	BlinkProblems(leds, settings.Leds(), err)
+}
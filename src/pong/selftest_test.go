@@ -0,0 +1,45 @@
+package pong
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// Every other led should blink once for each problem, with a pause after each round
+func Test_BlinkProblems(t *testing.T) {
+
+	var sleeps []time.Duration
+	oldSleep := problemSleep
+	defer func() { problemSleep = oldSleep }()
+	problemSleep = func(duration time.Duration) { sleeps = append(sleeps, duration) }
+
+	display := &blinkingDisplay{}
+	BlinkProblems(display, 5, StartupProblems{errors.New("one"), errors.New("two")})
+
+	Assert(display.lit, 2*problemRounds, "Blinks", t)
+	Assert(len(sleeps), problemRounds*5, "Sleeps", t)
+	if sleeps[4] != problemPauseTime {
+		t.Fatal("No pause after the first round, slept", sleeps)
+	}
+	if display.frame[0] != (RGBA{}) {
+		t.Fatal("Strip left lit after blinking")
+	}
+
+	display.lit = 0
+	BlinkProblems(display, 5, errors.New("one"))
+	Assert(display.lit, problemRounds, "Blinks of a single error", t)
+}
+
+// Counts frames with every other led lit, keeping the last frame
+type blinkingDisplay struct {
+	lastFrameDisplay
+	lit int
+}
+
+func (this *blinkingDisplay) Render(colorData []RGBA) {
+	if colorData[0] == startupProblemColor && colorData[1] == (RGBA{}) && colorData[2] == startupProblemColor {
+		this.lit++
+	}
+	this.lastFrameDisplay.Render(colorData)
+}
@@ -4,15 +4,57 @@ import (
 	"encoding/xml"
 	"io/ioutil"
 	"log"
+	"os"
+
+	"github.com/brandonagr/pongpi/src/pong/atomicfile"
 )
 
 type SettingsData struct {
 	// Max frames per second, app uses thread.sleep to limit FPS
 	MaxFPS float64
 
+	// Lowest frame rate the game drops to when frames take too long to render, equal to MaxFPS to always run at MaxFPS
+	MinFPS float64
+
 	// Number of Leds in board
 	LedCount int
 
+	// Leds on the whole strip when the game is only played on LedCount of them from GameWindowOffset, 0 when the game
+	// has the whole strip, and the background shown on the rest of the strip
+	StripLedCount     int
+	GameWindowOffset  int
+	AmbientBackground string
+
+	// How the ends of a strip are joined, line when they are apart, loop when the strip runs all the way around a table
+	// so the ball can travel around it
+	Topology string
+
+	// Rows of LedCount leds wired one after the other for a matrix, 1 for a strip
+	MatrixRows int
+
+	// How the rows of a matrix are wired, progressive when every row starts at the same side, serpentine when every
+	// other row runs back the other way, or the rows in the order they are wired with an r after each that runs right
+	// to left, such as "1 0r" for two parallel strips used as lanes
+	MatrixLayout string
+
+	// How the strip or panel is mounted, mirror for a strip running the other way, mirrorx, mirrory or rotate180 for a
+	// flipped panel, empty when it is the right way round
+	DisplayTransform string
+
+	// mA the power supply can give the leds, frames that would draw more are dimmed to fit, 0 disables the limit
+	PowerBudgetMilliamps float64
+
+	// mA drawn by each color channel of a led at full brightness, and by each led while dark
+	LedChannelMilliamps float64
+	LedIdleMilliamps    float64
+
+	// Brightness of parts of the strip lit differently, such as "0-19:0.4 40-63:1.5" for the first 20 leds at 0.4 and
+	// leds 40 to 63 at 1.5, counted along the strip as it is wired, empty for none
+	BrightnessZones string
+
+	// JSON or CSV file giving the column and row of every physical led, replaces MatrixLayout, empty for none
+	PixelMapPath string
+
 	// path to the SPI device
 	SpiFilePath string
 
@@ -31,12 +73,298 @@ type SettingsData struct {
 	// GPIO port for right
 	RightButtonGpioPort string
 
+	// Path and GPIO port of the second button of each side, moving the paddle down on a matrix or pressed by the far
+	// player of a team in the fourplayer mode or the second teammate in the relay mode, empty for none
+	LeftDownButtonPath, LeftDownButtonGpioPort   string
+	RightDownButtonPath, RightDownButtonGpioPort string
+
+	// Value files and gpio ports of the two pins of a rotary encoder and its push button, which turns the brightness
+	// and chooses from the menus, empty if there's no knob or button
+	EncoderAPath, EncoderAGpioPort           string
+	EncoderBPath, EncoderBGpioPort           string
+	EncoderButtonPath, EncoderButtonGpioPort string
+
+	// Steps of the encoder pins between clicks of the knob, and the change in brightness from 0 to 1 of each click
+	EncoderStepsPerDetent int
+	EncoderBrightnessStep float64
+
 	// Amount of speedup
 	BounceVelocityIncrease float64
 
 	// Amount of life each player starts with
 	LifeInSeconds float64
 
+	// Backgrounds cycled through while idle, separated by spaces, each an effect such as comet(color=#00ffff) or several
+	// drawn on top of each other such as fire+twinkle
+	AttractBackgrounds string
+
+	// Seconds each idle background is shown and seconds spent crossfading to the next
+	AttractDwellSeconds float64
+	AttractFadeSeconds  float64
+
+	// Times such as 23:00 and 08:00 between which the strip is dimmed, empty disables quiet hours
+	QuietHoursStart string
+	QuietHoursEnd   string
+
+	// Brightness during quiet hours from 0 to 1, 0 blanks the strip and suspends rendering
+	QuietBrightness float64
+
+	// If a button press during quiet hours wakes the game, and for how many minutes
+	QuietWakeOnPress bool
+	QuietWakeMinutes float64
+
+	// Minutes without a button press before an AI vs AI demo game starts, 0 disables demo
+	DemoIdleMinutes float64
+
+	// Brightness the demo game is rendered at, from 0 to 1
+	DemoBrightness float64
+
+	// Temperature files of the board and of a sensor on the strip, such as the w1_slave file of a DS18B20, empty if
+	// there's no sensor on the strip
+	SocTemperaturePath   string
+	StripTemperaturePath string
+
+	// °C at which the board and the strip start being dimmed and slowed down, and at which they are all the way
+	SocWarmCelsius   float64
+	SocHotCelsius    float64
+	StripWarmCelsius float64
+	StripHotCelsius  float64
+
+	// °C a temperature has to fall before the dimming eases off
+	ThermalHysteresisCelsius float64
+
+	// Brightness from 0 to 1 when it is hot enough to be dimmed all the way
+	ThermalMinBrightness float64
+
+	// Seconds between temperature readings, negative disables watching the temperature
+	ThermalPollSeconds float64
+
+	// Battery gauge of a UPS HAT on the I2C bus, max17040 or ina219, empty if there isn't one
+	BatteryGauge string
+
+	// I2C bus the battery gauge is on and its address, 0 for the usual address of the gauge
+	BatteryGaugeBus     string
+	BatteryGaugeAddress int
+
+	// Volts of the battery when empty and when full, for a gauge that only measures the voltage
+	BatteryEmptyVolts float64
+	BatteryFullVolts  float64
+
+	// Value file and gpio port of the pin of a UPS HAT that goes high when the power is lost, empty if there isn't one
+	UpsPowerLossPath, UpsPowerLossGpioPort string
+
+	// Brightness from 0 to 1 and most frames a second while running on the battery
+	BatteryBrightness float64
+	BatteryFPS        float64
+
+	// Seconds between readings of the battery
+	UpsPollSeconds float64
+
+	// Web address of the other Pi of a redundant pair sharing the strip, such as http://10.0.0.2:8080, empty without one
+	FailoverPeer string
+
+	// If this Pi starts on standby, waiting for the peer to stop answering before it drives the strip
+	FailoverStandby bool
+
+	// Seconds the peer can go without answering before the standby takes over, and seconds between checks of it
+	FailoverSeconds      float64
+	FailoverCheckSeconds float64
+
+	// Value file and gpio port of the relay switching the data line of the strip to this Pi, empty if each Pi has its
+	// own output
+	FailoverRelayPath, FailoverRelayGpioPort string
+
+	// Small panel on the I2C bus showing the address of the web page, the score and the frame rate, ssd1306 or
+	// hd44780, empty if there isn't one
+	StatusPanel string
+
+	// I2C bus the status panel is on and its address, 0 for the usual address of the panel
+	StatusPanelBus     string
+	StatusPanelAddress int
+
+	// Characters in a line and lines of an hd44780 panel
+	StatusPanelColumns int
+	StatusPanelRows    int
+
+	// Seconds between updates of the status panel
+	StatusPanelSeconds float64
+
+	// Ambient light sensor on the I2C bus the brightness follows, bh1750 or tsl2561, empty keeps full brightness
+	LightSensor string
+
+	// I2C bus the light sensor is on and its address, 0 for the usual address of the sensor
+	LightSensorBus     string
+	LightSensorAddress int
+
+	// Seconds between readings of the light sensor
+	LightSensorSeconds float64
+
+	// Lux at or below which the strip is at NightBrightness and at or above which it is at full brightness
+	DarkLux   float64
+	BrightLux float64
+
+	// Brightness in the dark from 0 to 1
+	NightBrightness float64
+
+	// Change in brightness from 0 to 1 the light has to call for before the strip follows it, so it doesn't flicker
+	// while the light hovers around a level
+	BrightnessHysteresis float64
+
+	// Name of the theme every scene is drawn in, classic, halloween, christmas or team
+	Theme string
+
+	// Script showing the winner of a game for players who haven't chosen how their wins are shown, empty uses the
+	// theme's victory. Backgrounds can be scripts too with the effect script(path=...), naming a file in EffectScriptsDir
+	VictoryScript string
+
+	// Minutes without a button press before the time of day is shown instead of the attract backgrounds, 0 disables
+	// the clock
+	ClockIdleMinutes float64
+
+	// Seconds before the ball arrives that a human has to press before their AI teammate takes the return
+	DoublesGraceSeconds float64
+
+	// Seconds a button has to be held to open the menu or choose an option
+	LongPressSeconds float64
+
+	// Seconds the leaderboards of the day and the week are shown for after each game, 0 to only show them from the menu
+	LeaderboardSeconds float64
+
+	// Seconds the heatmap of where the ball was returned and missed is shown for after each game, 0 to not show it
+	HeatmapSeconds float64
+
+	// Speed the end of the final rally of each game is replayed at before the winner is shown, 0 to not replay it, and
+	// the most seconds of the rally replayed
+	MatchPointReplaySpeed   float64
+	MatchPointReplaySeconds float64
+
+	// Seconds of countdown before a paused game resumes
+	ResumeCountdownSeconds float64
+
+	// Seconds spent fading the strip to black when shutting down
+	ShutdownFadeSeconds float64
+
+	// Seconds spent crossfading from one scene to the next
+	SceneFadeSeconds float64
+
+	// Seconds the coach overlay is shown after a miss, 0 disables it
+	CoachSeconds float64
+
+	// Seconds the effects chosen by the players or the theme play at each end when a point is scored, 0 disables
+	// them, and how many leds from the end they reach
+	GoalEffectSeconds float64
+	GoalEffectLeds    float64
+
+	// Leds the hit zone of the player in the lead shrinks by for every point they lead by, growing back as the score
+	// evens out, 0 disables it for competitive play
+	RubberBandLeds float64
+
+	// Seconds a tap from a phone in the crowd mode counts toward its side's button, the fraction of a side's phones
+	// that have to tap within it to return the ball, and the fewest seconds allowed between taps of one phone
+	CrowdWindowSeconds float64
+	CrowdQuorum        float64
+	CrowdTapSeconds    float64
+
+	// File the most recent game is recorded to
+	RecordingPath string
+
+	// File the Elo rating of each player is stored in
+	RatingsPath string
+
+	// File the totals and records of every game are stored in
+	StatsPath string
+
+	// File every finished match is appended to
+	HistoryPath string
+
+	// File the achievements each player has unlocked are stored in
+	AchievementsPath string
+
+	// File the bracket of the running tournament is saved to
+	TournamentPath string
+
+	// File the best scores of modes played for a high score are saved to
+	HighScoresPath string
+
+	// Endpoint the stats and match history are uploaded to, empty to keep them on this installation
+	UploadURL string
+
+	// Bearer token sent with each upload, and the name the installation uploads as, the host name if empty
+	UploadToken        string
+	UploadInstallation string
+
+	// Seconds between uploads, at least MinUploadIntervalSeconds
+	UploadIntervalSeconds float64
+
+	// File remembering how much of the match history has been uploaded
+	UploadStatePath string
+
+	// Signed manifest of the theme packs, sounds and effect scripts to install, empty to never update them
+	UpdateURL string
+
+	// Base64 ed25519 public key the manifest is signed with
+	UpdatePublicKey string
+
+	// Minutes between checks for updates, 0 to only check when asked on /api/updates
+	UpdateIntervalMinutes float64
+
+	// File remembering the version of the last manifest installed
+	UpdateStatePath string
+
+	// Directories theme packs and effect scripts are installed into, sounds replace the files in sounds
+	ThemesDir        string
+	EffectScriptsDir string
+
+	// Directory reports of crashes are written to
+	CrashReportDir string
+
+	// Frames kept to look at after a glitch or crash, served from /api/frames
+	FrameCaptureCount int
+
+	// File the player profiles are stored in
+	ProfilesPath string
+
+	// File the values chosen for effect parameters on the effects page are stored in
+	EffectTweaksPath string
+
+	// Names the ratings of the left and right players are tracked under
+	LeftPlayerName  string
+	RightPlayerName string
+
+	// Address the web server listens on
+	WebAddress string
+
+	// Token needed to profile the game and read its runtime stats under /debug/, empty turns them off
+	AdminToken string
+
+	// Seconds without a button press before a game in progress is abandoned, 0 disables
+	AbandonSeconds float64
+
+	// Where the recording of the last abandoned game is saved
+	AbandonedRecordingPath string
+
+	// Goroutines used to render each frame, 0 or 1 renders on the game loop. More calls ColorAt of every drawable from
+	// several goroutines at once, so it is only for scenes whose drawables are all safe to call that way
+	RenderWorkers int
+
+	// Entries in the shared sine and falloff lookup tables
+	TableResolution int
+
+	// Seconds the game loop can stall before the display is reset and the game restarted, 0 disables the watchdog
+	WatchdogSeconds float64
+
+	// Chance of each hardware fault simulated when run with -chaos, see FaultRates
+	ChaosSpiErrorChance    float64
+	ChaosBounceStormChance float64
+	ChaosSlowFrameChance   float64
+	ChaosSlowFrameSeconds  float64
+
+	// Steps per second the game is played at, whatever the frame rate, 0 steps once per frame
+	PhysicsHz float64
+
+	// Seconds of game time per second of real time, from 0.1 for slow motion up to 10 to fast forward
+	TimeScale float64
+
 	// Min time for a single frame
 	MinFrameTime float64 `xml:"-"`
 }
@@ -47,6 +375,22 @@ var Settings SettingsData
 // location of the settings file
 var settingsFile string = "../settings.xml"
 
+// Leds in every row of the board
+func (settings *SettingsData) Leds() int {
+	if settings.MatrixRows > 1 {
+		return settings.LedCount * settings.MatrixRows
+	}
+	return settings.LedCount
+}
+
+// How the ends of the field of a game are joined, only a strip can be a loop
+func (settings *SettingsData) FieldTopology() Topology {
+	if topology, ok := FindTopology(settings.Topology); ok && settings.MatrixRows == 1 {
+		return topology
+	}
+	return TopologyLine
+}
+
 // Read settings from file, setting the global variable
 func (settings *SettingsData) Read() {
 
@@ -58,10 +402,302 @@ func (settings *SettingsData) Read() {
 		log.Fatal(err)
 	}
 
+	if settings.MatrixRows < 1 {
+		settings.MatrixRows = 1
+	}
+
+	if settings.AmbientBackground == "" {
+		settings.AmbientBackground = "sinusoid"
+	}
+
+	if settings.Topology == "" {
+		settings.Topology = "line"
+	}
+
+	if settings.MatrixLayout == "" {
+		settings.MatrixLayout = MatrixSerpentine
+	}
+
 	if settings.MaxFPS == 0 {
 		settings.MaxFPS = 60
 	}
 
+	if settings.MinFPS == 0 {
+		settings.MinFPS = 30
+	}
+
+	if settings.Theme == "" {
+		settings.Theme = "classic"
+	}
+
+	if settings.AttractBackgrounds == "" {
+		settings.AttractBackgrounds = "sinusoid hsl fire noise comet"
+	}
+
+	if settings.AttractDwellSeconds == 0 {
+		settings.AttractDwellSeconds = 30
+	}
+
+	if settings.AttractFadeSeconds == 0 {
+		settings.AttractFadeSeconds = 3
+	}
+
+	if settings.QuietWakeMinutes == 0 {
+		settings.QuietWakeMinutes = 10
+	}
+
+	if settings.DemoBrightness == 0 {
+		settings.DemoBrightness = 0.3
+	}
+
+	if settings.LedChannelMilliamps == 0 {
+		settings.LedChannelMilliamps = 20
+	}
+
+	if settings.LedIdleMilliamps == 0 {
+		settings.LedIdleMilliamps = 1
+	}
+
+	if settings.SocTemperaturePath == "" {
+		settings.SocTemperaturePath = "/sys/class/thermal/thermal_zone0/temp"
+	}
+
+	if settings.SocWarmCelsius == 0 {
+		settings.SocWarmCelsius = 70
+	}
+
+	if settings.SocHotCelsius == 0 {
+		settings.SocHotCelsius = 80
+	}
+
+	if settings.StripWarmCelsius == 0 {
+		settings.StripWarmCelsius = 50
+	}
+
+	if settings.StripHotCelsius == 0 {
+		settings.StripHotCelsius = 65
+	}
+
+	if settings.ThermalHysteresisCelsius == 0 {
+		settings.ThermalHysteresisCelsius = 3
+	}
+
+	if settings.ThermalMinBrightness == 0 {
+		settings.ThermalMinBrightness = 0.3
+	}
+
+	if settings.ThermalPollSeconds == 0 {
+		settings.ThermalPollSeconds = 5
+	}
+
+	if settings.EncoderStepsPerDetent == 0 {
+		settings.EncoderStepsPerDetent = 4
+	}
+
+	if settings.EncoderBrightnessStep == 0 {
+		settings.EncoderBrightnessStep = 0.05
+	}
+
+	if settings.StatusPanelBus == "" {
+		settings.StatusPanelBus = "/dev/i2c-1"
+	}
+
+	if settings.StatusPanelColumns == 0 {
+		settings.StatusPanelColumns = 16
+	}
+
+	if settings.StatusPanelRows == 0 {
+		settings.StatusPanelRows = 2
+	}
+
+	if settings.BatteryGaugeBus == "" {
+		settings.BatteryGaugeBus = "/dev/i2c-1"
+	}
+
+	if settings.BatteryEmptyVolts == 0 {
+		settings.BatteryEmptyVolts = 3
+	}
+
+	if settings.BatteryFullVolts == 0 {
+		settings.BatteryFullVolts = 4.2
+	}
+
+	if settings.BatteryBrightness == 0 {
+		settings.BatteryBrightness = 0.3
+	}
+
+	if settings.BatteryFPS == 0 {
+		settings.BatteryFPS = 30
+	}
+
+	if settings.UpsPollSeconds == 0 {
+		settings.UpsPollSeconds = 5
+	}
+
+	if settings.FailoverSeconds == 0 {
+		settings.FailoverSeconds = 5
+	}
+
+	if settings.FailoverCheckSeconds == 0 {
+		settings.FailoverCheckSeconds = 1
+	}
+
+	if settings.StatusPanelSeconds == 0 {
+		settings.StatusPanelSeconds = 1
+	}
+
+	if settings.LightSensorBus == "" {
+		settings.LightSensorBus = "/dev/i2c-1"
+	}
+
+	if settings.LightSensorSeconds == 0 {
+		settings.LightSensorSeconds = 1
+	}
+
+	if settings.DarkLux == 0 {
+		settings.DarkLux = 5
+	}
+
+	if settings.BrightLux == 0 {
+		settings.BrightLux = 500
+	}
+
+	if settings.NightBrightness == 0 {
+		settings.NightBrightness = 0.2
+	}
+
+	if settings.BrightnessHysteresis == 0 {
+		settings.BrightnessHysteresis = 0.05
+	}
+
+	if settings.DoublesGraceSeconds == 0 {
+		settings.DoublesGraceSeconds = 0.15
+	}
+
+	if settings.LongPressSeconds == 0 {
+		settings.LongPressSeconds = 1
+	}
+
+	if settings.CrowdWindowSeconds == 0 {
+		settings.CrowdWindowSeconds = 0.5
+	}
+
+	if settings.CrowdQuorum == 0 {
+		settings.CrowdQuorum = 0.5
+	}
+
+	if settings.CrowdTapSeconds == 0 {
+		settings.CrowdTapSeconds = 0.2
+	}
+
+	if settings.ShutdownFadeSeconds == 0 {
+		settings.ShutdownFadeSeconds = 1
+	}
+
+	if settings.RecordingPath == "" {
+		settings.RecordingPath = "../lastgame.xml"
+	}
+
+	if settings.RatingsPath == "" {
+		settings.RatingsPath = "../ratings.xml"
+	}
+
+	if settings.StatsPath == "" {
+		settings.StatsPath = "../stats.xml"
+	}
+
+	if settings.HistoryPath == "" {
+		settings.HistoryPath = "../matches.log"
+	}
+
+	if settings.AchievementsPath == "" {
+		settings.AchievementsPath = "../achievements.xml"
+	}
+
+	if settings.TournamentPath == "" {
+		settings.TournamentPath = "../tournament.xml"
+	}
+
+	if settings.HighScoresPath == "" {
+		settings.HighScoresPath = "../highscores.xml"
+	}
+
+	if settings.ResumeCountdownSeconds == 0 {
+		settings.ResumeCountdownSeconds = 3
+	}
+
+	if settings.UploadIntervalSeconds == 0 {
+		settings.UploadIntervalSeconds = 300
+	}
+
+	if settings.UploadStatePath == "" {
+		settings.UploadStatePath = "../upload.xml"
+	}
+
+	if settings.UpdateStatePath == "" {
+		settings.UpdateStatePath = "../update.xml"
+	}
+
+	if settings.ThemesDir == "" {
+		settings.ThemesDir = "../themes"
+	}
+
+	if settings.EffectScriptsDir == "" {
+		settings.EffectScriptsDir = "../effects"
+	}
+
+	if settings.UploadInstallation == "" {
+		settings.UploadInstallation, _ = os.Hostname()
+	}
+
+	if settings.CrashReportDir == "" {
+		settings.CrashReportDir = "../crashes"
+	}
+
+	if settings.FrameCaptureCount == 0 {
+		settings.FrameCaptureCount = 120
+	}
+
+	if settings.ProfilesPath == "" {
+		settings.ProfilesPath = "../profiles.xml"
+	}
+
+	if settings.EffectTweaksPath == "" {
+		settings.EffectTweaksPath = "../effecttweaks.xml"
+	}
+
+	if settings.AbandonedRecordingPath == "" {
+		settings.AbandonedRecordingPath = "../abandoned.xml"
+	}
+
+	if settings.LeftPlayerName == "" {
+		settings.LeftPlayerName = "Left"
+	}
+
+	if settings.RightPlayerName == "" {
+		settings.RightPlayerName = "Right"
+	}
+
+	if settings.TableResolution == 0 {
+		settings.TableResolution = 1024
+	}
+
+	if settings.TimeScale == 0 {
+		settings.TimeScale = 1
+	}
+
+	if settings.GoalEffectLeds == 0 {
+		settings.GoalEffectLeds = 10
+	}
+
+	if settings.MatchPointReplaySeconds == 0 {
+		settings.MatchPointReplaySeconds = 4
+	}
+
+	if settings.WebAddress == "" {
+		settings.WebAddress = ":8080"
+	}
+
 	// setup any derived values
 	settings.MinFrameTime = 1.0 / settings.MaxFPS
 }
@@ -72,7 +708,7 @@ func (settings *SettingsData) Write() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	if err := ioutil.WriteFile(settingsFile, fileData, 0777); err != nil {
+	if err := atomicfile.Write(settingsFile, fileData, 0777); err != nil {
 		log.Fatal(err)
 	}
 }
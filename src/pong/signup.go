@@ -0,0 +1,119 @@
+package pong
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Players who signed up from their phones to play next, called up a pair at a time as each match ends
+type SignupQueue struct {
+	waiting nameQueue
+
+	// the web server signs players up while the game calls them
+	lock sync.Mutex
+}
+
+// Add name to the back of the queue, unless they are already in it or it is full
+func (this *SignupQueue) Join(name string) error {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	return this.waiting.join(name)
+}
+
+// Take name out of the queue
+func (this *SignupQueue) Leave(name string) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.waiting.leave(name)
+}
+
+// Players waiting in the order they are called
+func (this *SignupQueue) Waiting() []string {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return append([]string{}, this.waiting...)
+}
+
+// Take up to count players off the front of the queue
+func (this *SignupQueue) CallUp(count int) []string {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	return this.waiting.callUp(count)
+}
+
+// Serve the queue as json, POST a name to sign up or DELETE one to take them out, which is served behind
+// AdminMethodsOnly so only the admin can clear the queue
+func (this *SignupQueue) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	switch r.Method {
+	case "POST":
+		if err := this.Join(name); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Print(name, " signed up to play next")
+	case "DELETE":
+		this.Leave(name)
+		log.Print(name, " left the queue")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct{ Queue []string }{this.Waiting()})
+}
+
+// Serve a page to sign up from a phone and see who is waiting
+func (this *SignupQueue) ServePage(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `
+<html>
+	<head>
+		<meta name="viewport" content="width=device-width, initial-scale=1"/>
+		<script type="text/javascript"><!--
+		function show(response)
+		{
+			response.json().then(function(queue) {
+				var list = document.getElementById("queue");
+				list.innerHTML = "";
+				queue.Queue.forEach(function(name) {
+					var item = document.createElement("li");
+					item.textContent = name;
+					list.appendChild(item);
+				});
+			});
+		}
+		function signUp()
+		{
+			var name = document.getElementById("name").value;
+			fetch("api/queue", {method: "POST", body: new URLSearchParams({name: name})}).then(function(response) {
+				if (response.ok) {
+					show(response);
+				}
+			});
+			return false;
+		}
+		function refresh()
+		{
+			fetch("api/queue").then(show);
+			setTimeout(refresh, 5000);
+		}
+		--></script>
+	</head>
+	<body onload="refresh()">
+		<form onsubmit="return signUp()">
+			<input id="name" placeholder="Your name"/>
+			<button type="submit">Play next</button>
+		</form>
+		<ol id="queue"></ol>
+	</body>
+</html>`)
+}
@@ -0,0 +1,59 @@
+package pong
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// Players signed up from the web should be called up in the order they joined, once each
+func Test_SignupQueue(t *testing.T) {
+	queue := &SignupQueue{}
+
+	signUp := func(method, name string) int {
+		form := url.Values{"name": {name}}
+		request := httptest.NewRequest(method, "/api/queue", strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+		queue.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	for _, name := range []string{"ann", "bob", "cat", "dan"} {
+		Assert(signUp("POST", name), 200, "Sign up status", t)
+	}
+	Assert(signUp("POST", "Ann"), 400, "Signing up twice status", t)
+	Assert(signUp("POST", " "), 400, "Signing up without a name status", t)
+
+	// names to leave are given in the url, only POST bodies are read as forms
+	leave := httptest.NewRequest("DELETE", "/api/queue?name=bob", nil)
+	queue.ServeHTTP(httptest.NewRecorder(), leave)
+
+	if called := queue.CallUp(2); strings.Join(called, ",") != "ann,cat" {
+		t.Fatal("Called up", called)
+	}
+	if called := queue.CallUp(2); strings.Join(called, ",") != "dan" {
+		t.Fatal("Called up", called)
+	}
+	Assert(len(queue.CallUp(2)), 0, "Called up from an empty queue", t)
+}
+
+// Nobody on the network should be able to fill the queue or sign up with a name too long to show or announce
+func Test_SignupQueue_Limits(t *testing.T) {
+	queue := &SignupQueue{}
+
+	if err := queue.Join(strings.Repeat("x", maxQueueNameLength+1)); err == nil {
+		t.Fatal("Signed up with a name past the longest")
+	}
+	for player := 0; player < maxQueueLength; player++ {
+		if err := queue.Join(fmt.Sprint("player", player)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := queue.Join("one too many"); err == nil {
+		t.Fatal("Signed up to a full queue")
+	}
+	Assert(len(queue.Waiting()), maxQueueLength, "Players waiting in a full queue", t)
+}
@@ -0,0 +1,125 @@
+// Renders drawables into png strips and compares them with golden images, so visual changes are caught by tests
+package snapshot
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strings"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Render field for frames frames, moving it forward dt seconds after each, as an image with one row per frame, drawables
+// that use random numbers should be created after seeding them with UseRandSource
+func RenderField(field *GameField, frames int, dt float64) *image.RGBA {
+
+	strip := image.NewRGBA(image.Rect(0, 0, field.Width(), frames))
+	for frame := 0; frame < frames; frame++ {
+		for position, led := range field.Render() {
+			strip.SetRGBA(position, frame, color.RGBA{led.R, led.G, led.B, 255})
+		}
+		field.Animate(dt)
+	}
+	return strip
+}
+
+// Render drawables on a field of width leds, see RenderField
+func Render(width, frames int, dt float64, drawables ...Drawable) *image.RGBA {
+
+	field := NewGameField(width)
+	defer field.Release()
+	for _, drawable := range drawables {
+		field.Add(drawable)
+	}
+	return RenderField(field, frames, dt)
+}
+
+// Compare strip with the golden png at path, each channel of each led may be off by tolerance, update writes strip as
+// the new golden image instead. A strip that doesn't match is written next to the golden image as .actual.png
+func Match(strip *image.RGBA, path string, tolerance uint8, update bool) error {
+
+	if update {
+		return writePng(strip, path)
+	}
+
+	golden, err := readPng(path)
+	if err != nil {
+		return fmt.Errorf("%v, run the test with -update to create it", err)
+	}
+
+	mismatch := compare(strip, golden, tolerance)
+	if mismatch == "" {
+		return nil
+	}
+	actualPath := strings.TrimSuffix(path, ".png") + ".actual.png"
+	if err := writePng(strip, actualPath); err != nil {
+		return err
+	}
+	return fmt.Errorf("%v doesn't match, %v, rendered %v", path, mismatch, actualPath)
+}
+
+// Describe how strip differs from golden by more than tolerance, empty if it doesn't
+func compare(strip *image.RGBA, golden image.Image, tolerance uint8) string {
+
+	if strip.Bounds() != golden.Bounds() {
+		return fmt.Sprint("size is ", strip.Bounds().Size(), " instead of ", golden.Bounds().Size())
+	}
+
+	differences := 0
+	first := ""
+	bounds := strip.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			actual := strip.RGBAAt(x, y)
+			expected := color.RGBAModel.Convert(golden.At(x, y)).(color.RGBA)
+			if channelDifference(actual.R, expected.R) > tolerance || channelDifference(actual.G, expected.G) > tolerance ||
+				channelDifference(actual.B, expected.B) > tolerance {
+				if differences == 0 {
+					first = fmt.Sprint("led ", x, " of frame ", y, " is ", actual, " instead of ", expected)
+				}
+				differences++
+			}
+		}
+	}
+	if differences == 0 {
+		return ""
+	}
+	return fmt.Sprint(differences, " leds differ, ", first)
+}
+
+// Absolute difference between two channels
+func channelDifference(lhs, rhs uint8) uint8 {
+	if lhs > rhs {
+		return lhs - rhs
+	}
+	return rhs - lhs
+}
+
+// Read the png at path
+func readPng(path string) (image.Image, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return png.Decode(file)
+}
+
+// Write strip to path as a png
+func writePng(strip *image.RGBA, path string) error {
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(file, strip); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
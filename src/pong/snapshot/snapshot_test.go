@@ -0,0 +1,44 @@
+package snapshot
+
+import (
+	"image"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Strips within tolerance of the golden image should match, anything further off should be written out
+func Test_Match(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "strip.png")
+
+	strip := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	strip.SetRGBA(1, 1, color.RGBA{100, 0, 0, 255})
+	if err := Match(strip, path, 0, false); err == nil {
+		t.Fatal("Matched a golden image that doesn't exist")
+	}
+	if err := Match(strip, path, 0, true); err != nil {
+		t.Fatal(err)
+	}
+
+	strip.SetRGBA(1, 1, color.RGBA{102, 0, 0, 255})
+	if err := Match(strip, path, 2, false); err != nil {
+		t.Fatal(err)
+	}
+
+	err = Match(strip, path, 1, false)
+	if err == nil || !strings.Contains(err.Error(), "led 1 of frame 1") {
+		t.Fatal("Mismatch not reported", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "strip.actual.png")); err != nil {
+		t.Fatal("Mismatched strip not written", err)
+	}
+}
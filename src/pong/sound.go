@@ -18,6 +18,14 @@ const (
 	GAMEOVER              = "./sounds/gameover.wav"
 )
 
+// Sounds a profile can choose to be played when they win, by name
+var Fanfares = map[string]SoundType{
+	"chime":    GAMESTART,
+	"gameover": GAMEOVER,
+	"ping":     LEFTBOUNCE,
+	"buzzer":   MISS,
+}
+
 var playWavCommand string
 
 func init() {
@@ -40,7 +48,8 @@ func PlaySound(sound SoundType) {
 
 // Read the given text
 func PlayTTS(speak string) {
-	cmd := exec.Command("espeak", speak, "--stdout")
+	// text can come from players' names, -- keeps a name starting with - from being read as an option
+	cmd := exec.Command("espeak", "--stdout", "--", speak)
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		log.Print(err)
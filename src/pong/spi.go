@@ -3,8 +3,12 @@
 package pong
 
 import (
-	"log"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"strconv"
+	"strings"
 	"syscall"
 	"unsafe"
 )
@@ -32,35 +36,44 @@ const (
 	SPI_IOC_RD_MAX_SPEED_HZ = 0x80046B04
 )
 
-func NewSpiBus(busFilePath string, busSpeedHz uint) *SpiBus {
+// Open the SPI bus at busFilePath and configure it to send at busSpeedHz
+func NewSpiBus(busFilePath string, busSpeedHz uint) (*SpiBus, error) {
 
-	if busSpeedHz < 100 || 10000000 < busSpeedHz {
-		log.Fatal("Bus speed is out of range", busSpeedHz)
+	if err := checkSpiBusSpeed(busSpeedHz); err != nil {
+		return nil, fmt.Errorf("SPI bus %v: %v", busFilePath, err)
 	}
 
 	file, err := os.OpenFile(busFilePath, os.O_RDWR, os.ModeExclusive)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("SPI bus %v: %v", busFilePath, err)
 	}
 
-	configBus(file, SPI_IOC_WR_MODE, 0)
-	configBus(file, SPI_IOC_RD_MODE, 0)
-	configBus(file, SPI_IOC_WR_BITS_PER_WORD, 8)
-	configBus(file, SPI_IOC_RD_BITS_PER_WORD, 8)
-	configBus(file, SPI_IOC_WR_MAX_SPEED_HZ, busSpeedHz)
-	configBus(file, SPI_IOC_RD_MAX_SPEED_HZ, busSpeedHz)
+	for _, config := range [][2]uint{
+		{SPI_IOC_WR_MODE, 0},
+		{SPI_IOC_RD_MODE, 0},
+		{SPI_IOC_WR_BITS_PER_WORD, 8},
+		{SPI_IOC_RD_BITS_PER_WORD, 8},
+		{SPI_IOC_WR_MAX_SPEED_HZ, busSpeedHz},
+		{SPI_IOC_RD_MAX_SPEED_HZ, busSpeedHz},
+	} {
+		if err := configBus(file, config[0], config[1]); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("SPI bus %v: configuring with ioctl %#x: %v", busFilePath, config[0], err)
+		}
+	}
 
 	return &SpiBus{
 		fileDescriptor: file,
-	}
+	}, nil
 }
 
 // Set the value
-func configBus(file *os.File, command, value uint) {
+func configBus(file *os.File, command, value uint) error {
 	_, _, err := syscall.Syscall(syscall.SYS_IOCTL, uintptr(file.Fd()), uintptr(command), uintptr(unsafe.Pointer(&value)))
 	if err != 0 {
-		log.Fatal("Error attempting to configure SPI bus ", err)
+		return err
 	}
+	return nil
 }
 
 // Write data to the bus
@@ -68,9 +81,28 @@ func (bus *SpiBus) Write(data []byte) (n int, err error) {
 
 	n, err = bus.fileDescriptor.Write(data)
 	if n != len(data) {
-		log.Fatal("Failed to write all of the bytes, ", n, " instead of ", len(data))
+		// returned rather than fatal so the game can carry on and the watchdog can reset the bus
+		if err == nil {
+			err = io.ErrShortWrite
+		}
+		return
 	}
 	bus.fileDescriptor.Sync() // flush data to be sure it's been written
 
 	return
 }
+
+// Close the connection to the bus
+func (bus *SpiBus) Close() error {
+	return bus.fileDescriptor.Close()
+}
+
+// Largest write the spidev driver accepts, 0 if it can't be read
+func spidevBufferSize() int {
+	data, err := ioutil.ReadFile("/sys/module/spidev/parameters/bufsiz")
+	if err != nil {
+		return 0
+	}
+	size, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return size
+}
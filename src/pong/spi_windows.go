@@ -3,6 +3,7 @@
 package pong
 
 import (
+	"errors"
 	"log"
 )
 
@@ -10,9 +11,8 @@ import (
 type SpiBus struct {
 }
 
-func NewSpiBus(busFilePath string, busSpeedHz uint) *SpiBus {
-	log.Fatal("Spi not implemented on windows!")
-	return nil
+func NewSpiBus(busFilePath string, busSpeedHz uint) (*SpiBus, error) {
+	return nil, errors.New("SPI bus " + busFilePath + ": spi not implemented on windows")
 }
 
 // Write data to the bus
@@ -20,3 +20,13 @@ func (bus *SpiBus) Write(data []byte) (n int, err error) {
 	log.Fatal("Spi not implemented on windows!")
 	return
 }
+
+// Close the connection to the bus
+func (bus *SpiBus) Close() error {
+	return nil
+}
+
+// Largest write the spidev driver accepts, unknown on windows
+func spidevBufferSize() int {
+	return 0
+}
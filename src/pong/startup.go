@@ -0,0 +1,190 @@
+package pong
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Command run to export a gpio pin when its value file doesn't exist yet
+var GpioCommand = "/usr/local/bin/gpio"
+
+// Export port as an input or output, direction in or out, if its value file at path doesn't exist yet
+func exportGpio(path, port, direction string) error {
+	if _, err := os.Stat(path); err != nil && os.IsNotExist(err) {
+		if err := exec.Command(GpioCommand, "export", port, direction).Run(); err != nil {
+			return fmt.Errorf("Exporting gpio %v with %v: %v", port, GpioCommand, err)
+		}
+	}
+	return nil
+}
+
+// Range of speeds the SPI bus can be set to
+const (
+	MinSpiBusSpeedHz = 100
+	MaxSpiBusSpeedHz = 10000000
+)
+
+// Error if busSpeedHz is outside the range the SPI bus can be set to
+func checkSpiBusSpeed(busSpeedHz uint) error {
+	if busSpeedHz < MinSpiBusSpeedHz || MaxSpiBusSpeedHz < busSpeedHz {
+		return fmt.Errorf("Bus speed of %v Hz is outside %v to %v Hz", busSpeedHz, MinSpiBusSpeedHz, MaxSpiBusSpeedHz)
+	}
+	return nil
+}
+
+// Fewest seconds between stats uploads, so a typo can't hammer the endpoint
+const MinUploadIntervalSeconds = 60
+
+// Every problem found by CheckStartup, so they can all be fixed before trying again
+type StartupProblems []error
+
+// One problem a line
+func (this StartupProblems) Error() string {
+	lines := make([]string, len(this))
+	for i, problem := range this {
+		lines[i] = problem.Error()
+	}
+	return fmt.Sprintf("%v problems found before starting:\n%v", len(this), strings.Join(lines, "\n"))
+}
+
+// Check settings for everything the game needs before it starts, the led strip if leds and the gpio buttons if buttons,
+// returns StartupProblems listing all of them or nil if nothing is wrong
+func CheckStartup(settings SettingsData, leds, buttons bool) error {
+
+	var problems StartupProblems
+	problem := func(err error) {
+		if err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	if settings.LedCount <= 0 {
+		problem(fmt.Errorf("LedCount of %v leds can't be drawn on", settings.LedCount))
+	}
+	_, err := ParseBrightnessZones(settings.BrightnessZones)
+	problem(err)
+	_, err = ParseQuietHours(settings.QuietHoursStart, settings.QuietHoursEnd)
+	problem(err)
+	problems = append(problems, checkHitZones(settings.LedCount, LoadProfiles(settings.ProfilesPath))...)
+	if settings.UploadURL != "" && settings.UploadIntervalSeconds < MinUploadIntervalSeconds {
+		problem(fmt.Errorf("UploadIntervalSeconds of %v is less than %v", settings.UploadIntervalSeconds,
+			MinUploadIntervalSeconds))
+	}
+	if settings.UpdateURL != "" {
+		_, err := parseUpdateKey(settings.UpdatePublicKey)
+		problem(err)
+	}
+	if settings.StatusPanel != "" {
+		if _, err := os.Stat(settings.StatusPanelBus); err != nil {
+			problem(fmt.Errorf("Status panel %v: %v", settings.StatusPanel, err))
+		}
+	}
+
+	if leds {
+		problems = append(problems, checkLeds(settings)...)
+	}
+	if buttons {
+		problem(checkButton("Left button", settings.LeftButtonPath, settings.LeftButtonGpioPort, true))
+		problem(checkButton("Right button", settings.RightButtonPath, settings.RightButtonGpioPort, true))
+		problem(checkButton("Left down button", settings.LeftDownButtonPath, settings.LeftDownButtonGpioPort, false))
+		problem(checkButton("Right down button", settings.RightDownButtonPath, settings.RightDownButtonGpioPort, false))
+		if settings.EncoderAPath != "" || settings.EncoderBPath != "" {
+			problem(checkButton("Encoder pin A", settings.EncoderAPath, settings.EncoderAGpioPort, true))
+			problem(checkButton("Encoder pin B", settings.EncoderBPath, settings.EncoderBGpioPort, true))
+		}
+		problem(checkButton("Encoder button", settings.EncoderButtonPath, settings.EncoderButtonGpioPort, false))
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return problems
+}
+
+// Problems with the SPI bus and the way the leds on it are laid out
+func checkLeds(settings SettingsData) (problems []error) {
+
+	if err := checkSpiBusSpeed(settings.SpiBusSpeedHz); err != nil {
+		problems = append(problems, fmt.Errorf("SPI bus %v: %v", settings.SpiFilePath, err))
+	}
+	if _, err := os.Stat(settings.SpiFilePath); err != nil {
+		problems = append(problems, fmt.Errorf("SPI bus: %v", err))
+	}
+
+	if settings.PixelMapPath != "" {
+		pixels, err := LoadPixelMap(settings.PixelMapPath)
+		if err == nil {
+			_, err = NewPixelMapDisplay(nil, settings.LedCount, settings.MatrixRows, pixels)
+		}
+		if err != nil {
+			problems = append(problems, err)
+		}
+	} else if settings.MatrixRows > 1 {
+		if _, err := NewMatrixDisplay(nil, settings.LedCount, settings.MatrixRows, settings.MatrixLayout); err != nil {
+			problems = append(problems, err)
+		}
+	} else if settings.StripLedCount > 0 && settings.GameWindowOffset < 0 {
+		problems = append(problems, fmt.Errorf("Game window offset %v is before the start of the strip", settings.GameWindowOffset))
+	} else if settings.StripLedCount > 0 && settings.GameWindowOffset+settings.LedCount > settings.StripLedCount {
+		problems = append(problems, fmt.Errorf("Game window of %v leds from %v doesn't fit on the strip of %v",
+			settings.LedCount, settings.GameWindowOffset, settings.StripLedCount))
+	}
+	if idle := settings.LedIdleMilliamps * float64(settings.Leds()); settings.PowerBudgetMilliamps > 0 && settings.PowerBudgetMilliamps <= idle {
+		problems = append(problems, fmt.Errorf("Power budget of %v mA can't light leds that draw %v mA while dark", settings.PowerBudgetMilliamps, idle))
+	}
+	if settings.LightSensor != "" {
+		if settings.LightSensor != LightSensorBH1750 && settings.LightSensor != LightSensorTSL2561 {
+			problems = append(problems, fmt.Errorf("Light sensor %q isn't %v or %v", settings.LightSensor, LightSensorBH1750, LightSensorTSL2561))
+		} else if _, err := os.Stat(settings.LightSensorBus); err != nil {
+			problems = append(problems, fmt.Errorf("Light sensor %v: %v", settings.LightSensor, err))
+		}
+	}
+	if settings.BatteryGauge != "" {
+		if settings.BatteryGauge != BatteryGaugeMAX17040 && settings.BatteryGauge != BatteryGaugeINA219 {
+			problems = append(problems, fmt.Errorf("Battery gauge %q isn't %v or %v", settings.BatteryGauge, BatteryGaugeMAX17040, BatteryGaugeINA219))
+		} else if _, err := os.Stat(settings.BatteryGaugeBus); err != nil {
+			problems = append(problems, fmt.Errorf("Battery gauge %v: %v", settings.BatteryGauge, err))
+		}
+	}
+	if settings.DisplayTransform != "" {
+		if _, err := NewTransformDisplay(nil, settings.LedCount, settings.MatrixRows, settings.DisplayTransform); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return problems
+}
+
+// Profiles whose hit zone doesn't fit in their half of a field ledCount leds long
+func checkHitZones(ledCount int, profiles *Profiles) (problems []error) {
+	half := float64(ledCount) / 2
+	for _, profile := range profiles.Profiles {
+		if width, distance := profile.HitZone(); width+distance > half {
+			problems = append(problems, fmt.Errorf("Hit zone of %v, %v leds wide %v from the end, doesn't fit in half the field of %v leds",
+				profile.Name, width, distance, ledCount))
+		}
+	}
+	return problems
+}
+
+// Problem with the button called name, whose value file at path is made by exporting port, nil if it can be opened
+// or isn't wired up and not required
+func checkButton(name, path, port string, required bool) error {
+
+	if path == "" {
+		if required {
+			return fmt.Errorf("%v on gpio %v has no value file path set", name, port)
+		}
+		return nil
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("%v on gpio %v: %v", name, port, err)
+	}
+	if _, err := os.Stat(GpioCommand); err != nil {
+		return fmt.Errorf("%v on gpio %v: %v doesn't exist and can't be exported: %v", name, port, path, err)
+	}
+	return nil
+}
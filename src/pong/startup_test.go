@@ -0,0 +1,85 @@
+package pong
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Every problem with the settings and hardware should be reported at once, and none when everything is there
+func Test_CheckStartup(t *testing.T) {
+
+	directory, err := ioutil.TempDir("", "startup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	path := func(name string) string {
+		path := filepath.Join(directory, name)
+		ioutil.WriteFile(path, []byte("1\n"), 0644)
+		return path
+	}
+	settings := SettingsData{
+		LedCount:        10,
+		MatrixRows:      1,
+		SpiFilePath:     path("spidev"),
+		SpiBusSpeedHz:   4000000,
+		LeftButtonPath:  path("left"),
+		RightButtonPath: path("right"),
+	}
+	if err := CheckStartup(settings, true, true); err != nil {
+		t.Fatal("Problems found with working settings:", err)
+	}
+
+	settings.StripLedCount, settings.GameWindowOffset = 20, -1
+	if err := CheckStartup(settings, true, true); err == nil || !strings.Contains(err.Error(), "before the start") {
+		t.Fatal("Game window before the strip found", err)
+	}
+	settings.StripLedCount, settings.GameWindowOffset = 0, 0
+
+	settings.UploadURL, settings.UploadIntervalSeconds = "https://stats", 1
+	if err := CheckStartup(settings, true, true); err == nil || !strings.Contains(err.Error(), "UploadIntervalSeconds") {
+		t.Fatal("Upload interval below the minimum found", err)
+	}
+	settings.UploadIntervalSeconds = MinUploadIntervalSeconds
+
+	oldCommand := GpioCommand
+	defer func() { GpioCommand = oldCommand }()
+	GpioCommand = filepath.Join(directory, "gpio")
+
+	settings.SpiFilePath = filepath.Join(directory, "missing")
+	settings.SpiBusSpeedHz = 5
+	settings.StripLedCount = 5
+	settings.RightButtonPath = ""
+	settings.LeftDownButtonPath, settings.LeftDownButtonGpioPort = filepath.Join(directory, "leftdown"), "23"
+	err = CheckStartup(settings, true, true)
+	problems, ok := err.(StartupProblems)
+	if !ok {
+		t.Fatal("Expected every problem, got", err)
+	}
+	Assert(len(problems), 5, "Problems found", t)
+	if !strings.Contains(err.Error(), "Left down button on gpio 23") {
+		t.Fatal("Problem doesn't name the button:", err)
+	}
+
+	if err := CheckStartup(settings, false, false); err != nil {
+		t.Fatal("Hardware checked when it isn't used:", err)
+	}
+
+	if _, err := NewSpiBus(settings.SpiFilePath, 5); err == nil || !strings.Contains(err.Error(), settings.SpiFilePath) {
+		t.Fatal("Opening the SPI bus failed with", err)
+	}
+}
+
+// A hit zone reaching past the middle of the field should be reported
+func Test_CheckHitZones(t *testing.T) {
+	profiles := &Profiles{Profiles: []*PlayerProfile{
+		{Name: "near", HitZoneWidth: 2, HitZoneDistance: 3},
+		{Name: "far", HitZoneWidth: 2, HitZoneDistance: 4},
+	}}
+	Assert(len(checkHitZones(10, profiles)), 1, "Hit zones that don't fit", t)
+	Assert(len(checkHitZones(12, profiles)), 0, "Hit zones that don't fit a longer field", t)
+}
@@ -0,0 +1,159 @@
+package pong
+
+import (
+	"fmt"
+	"log"
+)
+
+// Phase the game is in
+type Phase int
+
+// Every phase of the game
+const (
+	PhaseIdle Phase = iota
+	PhaseWaitingForPlayers
+	PhaseCountdown
+	PhaseRally
+	PhasePointScored
+	PhaseGameOver
+	PhaseLeaderboard
+)
+
+var phaseNames = []string{"Idle", "WaitingForPlayers", "Countdown", "Rally", "PointScored", "GameOver", "Leaderboard"}
+
+// Name of the phase
+func (phase Phase) String() string {
+	if phase < 0 || int(phase) >= len(phaseNames) {
+		return fmt.Sprint("Phase(", int(phase), ")")
+	}
+	return phaseNames[phase]
+}
+
+// Phases that can be moved to from each phase
+var phaseTransitions = map[Phase][]Phase{
+	PhaseIdle:              {PhaseWaitingForPlayers, PhaseRally},
+	PhaseWaitingForPlayers: {PhaseCountdown, PhaseIdle, PhaseLeaderboard},
+	PhaseCountdown:         {PhaseRally},
+	PhaseRally:             {PhasePointScored, PhaseGameOver, PhaseIdle},
+	PhasePointScored:       {PhaseRally, PhaseGameOver},
+	PhaseGameOver:          {PhaseIdle, PhaseLeaderboard},
+	PhaseLeaderboard:       {PhaseIdle},
+}
+
+// Function called when a phase is entered or exited
+type PhaseHook func(phase Phase)
+
+// Function called every frame while in a phase
+type PhaseUpdate func(dt float64)
+
+// Tracks the current phase and runs hooks on transitions
+type StateMachine struct {
+
+	// current phase
+	phase Phase
+
+	// seconds spent in the current phase
+	time float64
+
+	enterHooks map[Phase][]PhaseHook
+	exitHooks  map[Phase][]PhaseHook
+	updates    map[Phase]PhaseUpdate
+}
+
+// Construct a StateMachine starting in PhaseIdle, its enter hooks run on Start
+func NewStateMachine() *StateMachine {
+	return &StateMachine{
+		phase:      PhaseIdle,
+		enterHooks: make(map[Phase][]PhaseHook),
+		exitHooks:  make(map[Phase][]PhaseHook),
+		updates:    make(map[Phase]PhaseUpdate),
+	}
+}
+
+// Add a hook called when phase is entered
+func (this *StateMachine) OnEnter(phase Phase, hook PhaseHook) {
+	this.enterHooks[phase] = append(this.enterHooks[phase], hook)
+}
+
+// Add a hook called when phase is exited
+func (this *StateMachine) OnExit(phase Phase, hook PhaseHook) {
+	this.exitHooks[phase] = append(this.exitHooks[phase], hook)
+}
+
+// Set the function called every frame while in phase
+func (this *StateMachine) OnUpdate(phase Phase, update PhaseUpdate) {
+	this.updates[phase] = update
+}
+
+// Run the enter hooks of the starting phase
+func (this *StateMachine) Start() {
+	for _, hook := range this.enterHooks[this.phase] {
+		hook(this.phase)
+	}
+}
+
+// Current phase
+func (this *StateMachine) Phase() Phase {
+	return this.phase
+}
+
+// Seconds spent in the current phase
+func (this *StateMachine) TimeInPhase() float64 {
+	return this.time
+}
+
+// Returns true if the current phase is allowed to move to phase
+func (this *StateMachine) CanTransition(phase Phase) bool {
+	for _, allowed := range phaseTransitions[this.phase] {
+		if allowed == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// Move to phase, running the exit hooks of the current phase then the enter hooks of the new phase
+func (this *StateMachine) Transition(phase Phase) {
+
+	if !this.CanTransition(phase) {
+		log.Panic("Invalid transition from ", this.phase, " to ", phase)
+	}
+
+	previous := this.phase
+	for _, hook := range this.exitHooks[previous] {
+		hook(previous)
+	}
+
+	this.phase = phase
+	this.time = 0
+
+	for _, hook := range this.enterHooks[phase] {
+		hook(phase)
+	}
+}
+
+// Force a move to phase without checking it is allowed, used to recover from a stalled game
+func (this *StateMachine) Reset(phase Phase) {
+
+	previous := this.phase
+	for _, hook := range this.exitHooks[previous] {
+		hook(previous)
+	}
+
+	this.phase = phase
+	this.time = 0
+
+	for _, hook := range this.enterHooks[phase] {
+		hook(phase)
+	}
+}
+
+// Advance time in the current phase and run its update
+func (this *StateMachine) Update(dt float64) {
+
+	this.time += dt
+
+	if update, ok := this.updates[this.phase]; ok {
+		update(dt)
+	}
+}
@@ -0,0 +1,68 @@
+package pong
+
+import (
+	"testing"
+)
+
+// Enter and exit hooks should run in order as phases change
+func Test_StateMachine_Hooks(t *testing.T) {
+	states := NewStateMachine()
+
+	var order []string
+	states.OnEnter(PhaseIdle, func(phase Phase) { order = append(order, "enter "+phase.String()) })
+	states.OnExit(PhaseIdle, func(phase Phase) { order = append(order, "exit "+phase.String()) })
+	states.OnEnter(PhaseWaitingForPlayers, func(phase Phase) { order = append(order, "enter "+phase.String()) })
+
+	states.Start()
+	states.Update(1.5)
+	if states.TimeInPhase() != 1.5 {
+		t.Fatal("TimeInPhase was", states.TimeInPhase())
+	}
+
+	states.Transition(PhaseWaitingForPlayers)
+	if states.TimeInPhase() != 0 {
+		t.Fatal("TimeInPhase not reset on transition")
+	}
+
+	expected := []string{"enter Idle", "exit Idle", "enter WaitingForPlayers"}
+	Assert(len(order), len(expected), "Number of hooks run", t)
+	for index := range expected {
+		if order[index] != expected[index] {
+			t.Fatal("Hook", index, "was", order[index], "vs expected", expected[index])
+		}
+	}
+}
+
+// Moving to a phase that isn't allowed should panic
+func Test_StateMachine_InvalidTransition(t *testing.T) {
+	states := NewStateMachine()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected panic moving from Idle to GameOver")
+		}
+	}()
+
+	states.Transition(PhaseGameOver)
+}
+
+// Reset should move to any phase, running hooks, even when a transition wouldn't be allowed
+func Test_StateMachine_Reset(t *testing.T) {
+	states := NewStateMachine()
+
+	entered := 0
+	states.OnEnter(PhaseIdle, func(phase Phase) { entered++ })
+
+	states.Transition(PhaseRally)
+	states.Update(3)
+	states.Reset(PhaseIdle)
+	states.Reset(PhaseIdle)
+
+	if states.Phase() != PhaseIdle {
+		t.Fatal("Phase was", states.Phase())
+	}
+	Assert(entered, 2, "Idle enter hooks run", t)
+	if states.TimeInPhase() != 0 {
+		t.Fatal("TimeInPhase not reset")
+	}
+}
@@ -0,0 +1,170 @@
+package stats
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/brandonagr/pongpi/src/pong/atomicfile"
+)
+
+// Something a player can do once to earn a badge
+type Achievement struct {
+	Name, Description string
+
+	// if the player on the left side, or the right side, earned it in match
+	earned func(match Match, left bool) bool
+}
+
+// Every achievement that can be earned
+var Achievements = []Achievement{
+	{"first win", "Win a game", func(match Match, left bool) bool {
+		return match.LeftWon == left
+	}},
+	{"long rally", "Keep a rally going for 20 hits", func(match Match, left bool) bool {
+		for _, rally := range match.Rallies {
+			if rally >= 20 {
+				return true
+			}
+		}
+		return false
+	}},
+	{"comeback", "Win a game after trailing 0-5", func(match Match, left bool) bool {
+		return match.LeftWon == left && trailedByFive(match, left)
+	}},
+	{"night owl", "Play a game after midnight", func(match Match, left bool) bool {
+		return match.Time.Hour() < 5
+	}},
+}
+
+// If the player on the left, or the right, was ever behind 0-5, the score is worked out from the misses
+func trailedByFive(match Match, left bool) bool {
+
+	score, opponentScore := 0, 0
+	for _, hit := range match.Hits {
+		if hit.Returned {
+			continue
+		}
+		if hit.Left == left {
+			opponentScore++
+		} else {
+			score++
+		}
+		if score == 0 && opponentScore >= 5 {
+			return true
+		}
+	}
+	return false
+}
+
+// An achievement earned by a player
+type UnlockedAchievement struct {
+	Name string    `xml:"name,attr"`
+	Time time.Time `xml:"time,attr"`
+}
+
+// Achievements earned by a single player
+type PlayerAchievements struct {
+	Name     string                `xml:"name,attr"`
+	Unlocked []UnlockedAchievement `xml:"Achievement"`
+}
+
+// Achievements earned by every player, persisted to a file
+type AchievementStore struct {
+	XMLName xml.Name              `xml:"Achievements"`
+	Players []*PlayerAchievements `xml:"Player"`
+
+	// file the achievements are saved to
+	path string
+
+	// achievements are read by the web server while the game unlocks them
+	lock sync.Mutex
+}
+
+// Load achievements from path, starting empty if the file doesn't exist yet
+func LoadAchievements(path string) *AchievementStore {
+
+	store := &AchievementStore{path: path}
+
+	fileData, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Print(err)
+		}
+		return store
+	}
+	if err := xml.Unmarshal(fileData, store); err != nil {
+		log.Print(err)
+	}
+
+	return store
+}
+
+// Write the achievements to the file they were loaded from
+func (this *AchievementStore) Save() error {
+
+	this.lock.Lock()
+	fileData, err := xml.MarshalIndent(this, "", "\t")
+	this.lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(this.path, fileData, 0666)
+}
+
+// Unlock every achievement name earned in match playing on the left side, or the right, returns the ones earned for the first time
+func (this *AchievementStore) Evaluate(name string, match Match, left bool) (unlocked []Achievement) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	var player *PlayerAchievements
+	for _, existing := range this.Players {
+		if existing.Name == name {
+			player = existing
+		}
+	}
+	if player == nil {
+		player = &PlayerAchievements{Name: name}
+		this.Players = append(this.Players, player)
+	}
+
+	for _, achievement := range Achievements {
+		if player.has(achievement.Name) || !achievement.earned(match, left) {
+			continue
+		}
+		player.Unlocked = append(player.Unlocked, UnlockedAchievement{achievement.Name, match.Time})
+		unlocked = append(unlocked, achievement)
+	}
+	return unlocked
+}
+
+// If the player has already earned the achievement called name
+func (this *PlayerAchievements) has(name string) bool {
+	for _, unlocked := range this.Unlocked {
+		if unlocked.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Serve the achievements of every player as json
+func (this *AchievementStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	this.lock.Lock()
+	players := make([]PlayerAchievements, 0, len(this.Players))
+	for _, player := range this.Players {
+		players = append(players, PlayerAchievements{player.Name, append([]UnlockedAchievement{}, player.Unlocked...)})
+	}
+	this.lock.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(players)
+}
@@ -0,0 +1,36 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+// Achievements should be unlocked once, the first time they are earned
+func Test_AchievementStore_Evaluate(t *testing.T) {
+	store := &AchievementStore{}
+
+	// left falls behind 0-5 and wins after midnight
+	comeback := Match{Time: time.Date(2020, 6, 1, 1, 30, 0, 0, time.Local), LeftWon: true, Rallies: []int{3}}
+	for miss := 0; miss < 5; miss++ {
+		comeback.Hits = append(comeback.Hits, Hit{Left: true})
+	}
+	for miss := 0; miss < 6; miss++ {
+		comeback.Hits = append(comeback.Hits, Hit{Left: false})
+	}
+
+	unlocked := store.Evaluate("Ann", comeback, true)
+	if len(unlocked) != 3 || unlocked[0].Name != "first win" || unlocked[1].Name != "comeback" || unlocked[2].Name != "night owl" {
+		t.Fatal("Unlocked", unlocked)
+	}
+	if unlocked := store.Evaluate("Ann", comeback, true); len(unlocked) != 0 {
+		t.Fatal("Unlocked again", unlocked)
+	}
+	if unlocked := store.Evaluate("Bob", comeback, false); len(unlocked) != 1 || unlocked[0].Name != "night owl" {
+		t.Fatal("Loser unlocked", unlocked)
+	}
+
+	longRally := Match{Time: time.Date(2020, 6, 1, 12, 0, 0, 0, time.Local), Rallies: []int{4, 21}}
+	if unlocked := store.Evaluate("Bob", longRally, false); len(unlocked) != 2 {
+		t.Fatal("Unlocked", unlocked)
+	}
+}
@@ -0,0 +1,134 @@
+package stats
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/brandonagr/pongpi/src/pong/atomicfile"
+)
+
+// Scores kept for each mode
+const highScoreCount = 10
+
+// A single score set in a mode played for a high score
+type HighScore struct {
+	Name  string    `xml:"name,attr"`
+	Score int       `xml:"score,attr"`
+	Time  time.Time `xml:"time,attr"`
+}
+
+// Best scores set in a single mode, highest first
+type ModeHighScores struct {
+	Mode   string      `xml:"mode,attr"`
+	Scores []HighScore `xml:"Score"`
+}
+
+// Best scores of every mode played for a high score, persisted to a file
+type HighScoreStore struct {
+	XMLName xml.Name          `xml:"HighScores"`
+	Modes   []*ModeHighScores `xml:"Mode"`
+
+	// file the scores are saved to
+	path string
+
+	// scores are read by the web server while the game records them
+	lock sync.Mutex
+}
+
+// Load high scores from path, starting empty if the file doesn't exist yet
+func LoadHighScores(path string) *HighScoreStore {
+
+	store := &HighScoreStore{path: path}
+
+	fileData, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Print(err)
+		}
+		return store
+	}
+	if err := xml.Unmarshal(fileData, store); err != nil {
+		log.Print(err)
+	}
+
+	return store
+}
+
+// Write the high scores to the file they were loaded from
+func (this *HighScoreStore) Save() error {
+
+	this.lock.Lock()
+	fileData, err := xml.MarshalIndent(this, "", "\t")
+	this.lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(this.path, fileData, 0666)
+}
+
+// Record score set in mode, returns its rank from 0 for the best score, false if it didn't make the table
+func (this *HighScoreStore) Record(mode string, score HighScore) (rank int, ok bool) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	var scores *ModeHighScores
+	for _, existing := range this.Modes {
+		if existing.Mode == mode {
+			scores = existing
+		}
+	}
+	if scores == nil {
+		scores = &ModeHighScores{Mode: mode}
+		this.Modes = append(this.Modes, scores)
+	}
+
+	// ties go below the scores already set
+	rank = sort.Search(len(scores.Scores), func(i int) bool { return scores.Scores[i].Score < score.Score })
+	if rank >= highScoreCount {
+		return 0, false
+	}
+	scores.Scores = append(scores.Scores, HighScore{})
+	copy(scores.Scores[rank+1:], scores.Scores[rank:])
+	scores.Scores[rank] = score
+	if len(scores.Scores) > highScoreCount {
+		scores.Scores = scores.Scores[:highScoreCount]
+	}
+	return rank, true
+}
+
+// Best score set in mode, false if it hasn't been played
+func (this *HighScoreStore) Best(mode string) (HighScore, bool) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	for _, scores := range this.Modes {
+		if scores.Mode == mode && len(scores.Scores) > 0 {
+			return scores.Scores[0], true
+		}
+	}
+	return HighScore{}, false
+}
+
+// Serve the high scores of every mode as json
+func (this *HighScoreStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	this.lock.Lock()
+	modes := make([]ModeHighScores, 0, len(this.Modes))
+	for _, scores := range this.Modes {
+		modes = append(modes, ModeHighScores{scores.Mode, append([]HighScore{}, scores.Scores...)})
+	}
+	this.lock.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(modes)
+}
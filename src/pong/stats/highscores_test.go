@@ -0,0 +1,52 @@
+package stats
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Scores should be ranked within their mode, only the best few kept, and be there after loading the file again
+func Test_HighScoreStore(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "highscores")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "highscores.xml")
+
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	store := LoadHighScores(path)
+	for score := 1; score <= highScoreCount; score++ {
+		if rank, ok := store.Record("simon", HighScore{"Ann", score, now}); !ok || rank != 0 {
+			t.Fatal("Score", score, "ranked", rank, ok)
+		}
+	}
+	if _, ok := store.Record("simon", HighScore{"Bob", 1, now}); ok {
+		t.Fatal("Score below a full table was kept")
+	}
+	if rank, ok := store.Record("simon", HighScore{"Bob", 5, now}); !ok || rank != 6 {
+		t.Fatal("Tied score ranked", rank, ok)
+	}
+	store.Record("snake", HighScore{"Cat", 3, now})
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := LoadHighScores(path)
+	if best, ok := loaded.Best("simon"); !ok || best.Name != "Ann" || best.Score != highScoreCount {
+		t.Fatal("Best simon score was", best, ok)
+	}
+	if best, ok := loaded.Best("snake"); !ok || best.Name != "Cat" || !best.Time.Equal(now) {
+		t.Fatal("Best snake score was", best, ok)
+	}
+	if _, ok := loaded.Best("breakout"); ok {
+		t.Fatal("Found a score for a mode that was never played")
+	}
+	if len(loaded.Modes[0].Scores) != highScoreCount {
+		t.Fatal("Kept", len(loaded.Modes[0].Scores), "scores")
+	}
+}
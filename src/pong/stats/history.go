@@ -0,0 +1,284 @@
+package stats
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The ball reaching one of the players
+type Hit struct {
+
+	// index of the rally in the match
+	Rally int
+
+	// if the ball reached the left player
+	Left bool
+
+	// speed of the ball in leds / second
+	Speed float64
+
+	// led the ball was on when it was hit back, or when it went past the player
+	Position float64 `json:",omitempty"`
+
+	// false if the player missed the ball
+	Returned bool
+
+	// if the player pressed as the ball approached, and how many seconds before it reached the hit window, negative if after
+	Pressed bool
+	Timing  float64
+}
+
+// Everything remembered about a single finished match
+type Match struct {
+	Time time.Time
+
+	// name of the game mode played
+	Mode string
+
+	// names of the players, empty for modes without named players
+	Left, Right string
+
+	// points won by each side, and if the left side won the match
+	LeftScore, RightScore int
+	LeftWon               bool
+
+	// bounces in each rally, in the order they were played
+	Rallies []int
+
+	// speed of the fastest ball a player hit back, in leds / second
+	FastestReturn float64
+
+	// every time the ball reached a player, in the order they happened
+	Hits []Hit `json:",omitempty"`
+
+	// seconds of game time the match took
+	Duration float64
+
+	// if the match was walked away from before it finished, neither side won and LeftWon is meaningless
+	Abandoned bool `json:",omitempty"`
+}
+
+// The totals the match adds to a Store
+func (this Match) Game() Game {
+
+	game := Game{Winner: this.Left, Loser: this.Right, FastestReturn: this.FastestReturn, Time: this.Time}
+	if this.Abandoned {
+		game.Winner, game.Loser, game.Abandoned = "", "", true
+	} else if !this.LeftWon {
+		game.Winner, game.Loser = this.Right, this.Left
+	}
+	for _, rally := range this.Rallies {
+		if rally > game.LongestRally {
+			game.LongestRally = rally
+		}
+	}
+	return game
+}
+
+// Log of every match, one json object per line, only ever appended to
+type History struct {
+
+	// file the matches are appended to
+	path string
+
+	// matches are appended by the game loop while the uploader and the web server read them
+	lock sync.Mutex
+}
+
+// The log is shorter than an offset read from, such as after it was cleared
+var ErrHistoryShrunk = errors.New("The match history is shorter than it was")
+
+// Construct a History appending to path, the file is created by the first match
+func NewHistory(path string) *History {
+	return &History{path: path}
+}
+
+// Add match to the end of the log
+func (this *History) Append(match Match) error {
+
+	line, err := json.Marshal(match)
+	if err != nil {
+		return err
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	file, err := os.OpenFile(this.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Every match in the log, oldest first, empty if nothing has been logged yet
+func (this *History) Matches() ([]Match, error) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	matches, _, err := this.read(0, 0, true)
+	return matches, err
+}
+
+// Up to limit matches from the whole lines of the log starting at byte offset, and the offset after the last line
+// read, so a reader can carry on from there next time without reading the log again. Fails with ErrHistoryShrunk if
+// the log is now shorter than offset
+func (this *History) MatchesAfter(offset int64, limit int) ([]Match, int64, error) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	return this.read(offset, limit, false)
+}
+
+// Read the matches from offset, all of them if limit is 0, and the last line even without its newline if partial
+func (this *History) read(offset int64, limit int, partial bool) ([]Match, int64, error) {
+
+	file, err := os.Open(this.path)
+	if os.IsNotExist(err) && offset == 0 {
+		return nil, 0, nil
+	} else if os.IsNotExist(err) {
+		return nil, offset, ErrHistoryShrunk
+	} else if err != nil {
+		return nil, offset, err
+	}
+	defer file.Close()
+
+	if offset > 0 {
+		info, err := file.Stat()
+		if err != nil {
+			return nil, offset, err
+		}
+		if info.Size() < offset {
+			return nil, offset, ErrHistoryShrunk
+		}
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			return nil, offset, err
+		}
+	}
+
+	// lines are read whole however long a match made them, and one that can't be read, such as the end of a match
+	// that was being written when the power went, is skipped so it doesn't hide every other match
+	matches := []Match{}
+	end := offset
+	reader := bufio.NewReader(file)
+	for limit <= 0 || len(matches) < limit {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, offset, err
+		}
+		whole := err == nil
+		if !whole && !partial {
+			// still being written, or torn, it is read once the next match finishes it
+			break
+		}
+
+		start := end
+		end += int64(len(line))
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var match Match
+			if jsonErr := json.Unmarshal(line, &match); jsonErr != nil {
+				log.Printf("Skipping the line of %s at byte %d: %v", this.path, start, jsonErr)
+			} else {
+				matches = append(matches, match)
+			}
+		}
+		if !whole {
+			break
+		}
+	}
+	return matches, end, nil
+}
+
+// Write every match in the log to w as format, either csv or json
+func (this *History) Export(w io.Writer, format string) error {
+
+	matches, err := this.Matches()
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "json":
+		return json.NewEncoder(w).Encode(matches)
+	case "csv":
+		return writeCSV(w, matches)
+	}
+	return fmt.Errorf("Can't export matches as %q, use csv or json", format)
+}
+
+// Write a header row then a row for each match, rallies are separated by spaces
+func writeCSV(w io.Writer, matches []Match) error {
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"time", "mode", "left", "right", "left score", "right score", "winner", "rallies", "fastest return", "duration"})
+
+	for _, match := range matches {
+		winner := match.Left
+		if match.Abandoned {
+			winner = ""
+		} else if !match.LeftWon {
+			winner = match.Right
+		}
+		rallies := make([]string, len(match.Rallies))
+		for index, rally := range match.Rallies {
+			rallies[index] = strconv.Itoa(rally)
+		}
+
+		writer.Write([]string{
+			match.Time.Format(time.RFC3339),
+			match.Mode,
+			match.Left,
+			match.Right,
+			strconv.Itoa(match.LeftScore),
+			strconv.Itoa(match.RightScore),
+			winner,
+			strings.Join(rallies, " "),
+			strconv.FormatFloat(match.FastestReturn, 'f', 1, 64),
+			strconv.FormatFloat(match.Duration, 'f', 1, 64),
+		})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// Serve the log as csv, or as json with ?format=json
+func (this *History) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	format := r.FormValue("format")
+	switch format {
+	case "":
+		format = "csv"
+		fallthrough
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="matches.csv"`)
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+	default:
+		http.Error(w, "Format has to be csv or json", http.StatusBadRequest)
+		return
+	}
+
+	if err := this.Export(w, format); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
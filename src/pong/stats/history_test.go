@@ -0,0 +1,117 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Appended matches should be read back in order and exported with a row for each
+func Test_History(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	history := NewHistory(filepath.Join(dir, "matches.log"))
+
+	if matches, err := history.Matches(); err != nil || len(matches) != 0 {
+		t.Fatal("Empty history had", matches, err)
+	}
+
+	played := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	history.Append(Match{Time: played, Mode: "classic", Left: "Ann", Right: "Bob", LeftScore: 3, RightScore: 1, LeftWon: true, Rallies: []int{4, 9, 2, 5}, Duration: 61})
+	history.Append(Match{Time: played.Add(time.Hour), Mode: "ai", Left: "Ann", Right: "Easy", RightScore: 2, Rallies: []int{1, 3}})
+
+	matches, err := history.Matches()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 2 || matches[0].Right != "Bob" || matches[1].Mode != "ai" {
+		t.Fatal("Read back", matches)
+	}
+	if game := matches[0].Game(); game.Winner != "Ann" || game.Loser != "Bob" || game.LongestRally != 9 {
+		t.Fatal("Match counted as", game)
+	}
+
+	var exported bytes.Buffer
+	if err := history.Export(&exported, "csv"); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&exported).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[1][7] != "4 9 2 5" || rows[2][6] != "Easy" {
+		t.Fatal("Exported", rows)
+	}
+
+	if err := history.Export(&exported, "xml"); err == nil {
+		t.Fatal("Exported an unknown format")
+	}
+}
+
+// A match too long for a line buffer should be read back, and a match torn by a crash skipped without losing the rest
+func Test_History_LongAndTornLines(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "matches.log")
+	history := NewHistory(path)
+
+	long := Match{Mode: "classic", Hits: make([]Hit, 2000)}
+	if err := history.Append(long); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"Mode":"cla`)
+	file.Close()
+	if matches, err := history.Matches(); err != nil || len(matches) != 1 || len(matches[0].Hits) != 2000 {
+		t.Fatal("Read back", len(matches), "matches", err)
+	}
+
+	// the next match is appended to the torn line
+	history.Append(Match{Mode: "ai"})
+	history.Append(Match{Mode: "simon"})
+	matches, err := history.Matches()
+	if err != nil || len(matches) != 2 || matches[1].Mode != "simon" {
+		t.Fatal("Read back", len(matches), "matches after the torn one", err)
+	}
+}
+
+// Hits should be counted in the histograms of returns or misses
+func Test_Telemetry(t *testing.T) {
+
+	telemetry := NewTelemetry([]Match{{
+		Rallies: []int{1, 0},
+		Hits: []Hit{
+			{Rally: 0, Left: true, Speed: 150, Returned: true, Pressed: true, Timing: 0.05},
+			{Rally: 0, Left: false, Speed: 160, Pressed: true, Timing: -0.1},
+			{Rally: 1, Left: true, Speed: 2000},
+		},
+	}})
+
+	if telemetry.LeftMisses != 1 || telemetry.RightMisses != 1 || telemetry.UnpressedMisses != 1 {
+		t.Fatal("Misses were", telemetry.LeftMisses, telemetry.RightMisses, telemetry.UnpressedMisses)
+	}
+	if telemetry.ReturnSpeed.Counts[6] != 1 || telemetry.MissSpeed.Counts[len(telemetry.MissSpeed.Counts)-1] != 1 {
+		t.Fatal("Speeds counted as", telemetry.ReturnSpeed.Counts, telemetry.MissSpeed.Counts)
+	}
+	if telemetry.ReturnTiming.Counts[12] != 1 || telemetry.MissTiming.Counts[6] != 1 {
+		t.Fatal("Timings counted as", telemetry.ReturnTiming.Counts, telemetry.MissTiming.Counts)
+	}
+	if telemetry.RallyLength.Counts[0] != 1 || telemetry.RallyLength.Counts[1] != 1 {
+		t.Fatal("Rallies counted as", telemetry.RallyLength.Counts)
+	}
+}
@@ -0,0 +1,237 @@
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// A scheduled league match, the winner and the day it was played are empty until it has been played
+type LeagueFixture struct {
+	Left   string `xml:"left,attr"`
+	Right  string `xml:"right,attr"`
+	Winner string `xml:"winner,attr,omitempty"`
+	Date   string `xml:"date,attr,omitempty"`
+}
+
+// How a player stands in the league
+type LeagueStanding struct {
+	Name   string `xml:"name,attr"`
+	Played int    `xml:"played,attr"`
+	Won    int    `xml:"won,attr"`
+
+	// points won and lost over every league match
+	PointsFor     int `xml:"pointsFor,attr"`
+	PointsAgainst int `xml:"pointsAgainst,attr"`
+}
+
+// Round robin where every player plays every other once, over as many days as it takes
+type League struct {
+	Players   []string          `xml:"Player"`
+	Fixtures  []*LeagueFixture  `xml:"Fixture"`
+	Standings []*LeagueStanding `xml:"Standing"`
+}
+
+// Schedule of a round robin between players in rounds where nobody plays twice, using the circle method
+func roundRobin(players []string) []*LeagueFixture {
+
+	circle := append([]string{}, players...)
+	if len(circle)%2 == 1 {
+		// whoever is drawn against the empty seat sits the round out
+		circle = append(circle, "")
+	}
+
+	fixtures := []*LeagueFixture{}
+	for round := 0; round < len(circle)-1; round++ {
+		for index := 0; index < len(circle)/2; index++ {
+			left, right := circle[index], circle[len(circle)-1-index]
+			if left == "" || right == "" {
+				continue
+			}
+			if round%2 == 1 {
+				left, right = right, left
+			}
+			fixtures = append(fixtures, &LeagueFixture{Left: left, Right: right})
+		}
+
+		// everyone but the first player moves one seat around the circle
+		last := circle[len(circle)-1]
+		copy(circle[2:], circle[1:len(circle)-1])
+		circle[1] = last
+	}
+	return fixtures
+}
+
+// Start a new league between players, replacing any league already running
+func (this *Store) StartLeague(players []string) error {
+
+	if len(players) < 2 {
+		return errors.New("A league needs at least two players")
+	}
+	seen := map[string]bool{}
+	for _, player := range players {
+		if player == "" || seen[player] {
+			return errors.New("Every player in a league needs a different name")
+		}
+		seen[player] = true
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.League = &League{Players: append([]string{}, players...), Fixtures: roundRobin(players)}
+	for _, player := range players {
+		this.League.Standings = append(this.League.Standings, &LeagueStanding{Name: player})
+	}
+	return nil
+}
+
+// End the league
+func (this *Store) EndLeague() {
+	this.lock.Lock()
+	this.League = nil
+	this.lock.Unlock()
+}
+
+// Unplayed fixture between a and b, lock must be held
+func (this *Store) fixture(a, b string) *LeagueFixture {
+	if this.League == nil {
+		return nil
+	}
+	for _, fixture := range this.League.Fixtures {
+		if fixture.Winner == "" && (fixture.Left == a && fixture.Right == b || fixture.Left == b && fixture.Right == a) {
+			return fixture
+		}
+	}
+	return nil
+}
+
+// Unplayed league match between a and b, false if they don't have one left
+func (this *Store) LeagueFixture(a, b string) (LeagueFixture, bool) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if fixture := this.fixture(a, b); fixture != nil {
+		return *fixture, true
+	}
+	return LeagueFixture{}, false
+}
+
+// First unplayed league match of player, or of anyone if player is empty, false if there isn't one
+func (this *Store) NextLeagueFixture(player string) (LeagueFixture, bool) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if this.League == nil {
+		return LeagueFixture{}, false
+	}
+	for _, fixture := range this.League.Fixtures {
+		if fixture.Winner == "" && (player == "" || fixture.Left == player || fixture.Right == player) {
+			return *fixture, true
+		}
+	}
+	return LeagueFixture{}, false
+}
+
+// Count match in the league if it was the scheduled fixture between its players, returns false if it wasn't
+func (this *Store) RecordLeague(match Match) bool {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	fixture := this.fixture(match.Left, match.Right)
+	if fixture == nil {
+		return false
+	}
+
+	winner, loser := match.Left, match.Right
+	winnerScore, loserScore := match.LeftScore, match.RightScore
+	if !match.LeftWon {
+		winner, loser = loser, winner
+		winnerScore, loserScore = loserScore, winnerScore
+	}
+	fixture.Winner = winner
+	fixture.Date = match.Time.Format(dayLayout)
+
+	for _, standing := range this.League.Standings {
+		switch standing.Name {
+		case winner:
+			standing.Played++
+			standing.Won++
+			standing.PointsFor += winnerScore
+			standing.PointsAgainst += loserScore
+		case loser:
+			standing.Played++
+			standing.PointsFor += loserScore
+			standing.PointsAgainst += winnerScore
+		}
+	}
+	return true
+}
+
+// Copy of the standings, most wins first and ties going to the better point difference
+func (this *Store) LeagueStandings() []LeagueStanding {
+
+	this.lock.Lock()
+	standings := []LeagueStanding{}
+	if this.League != nil {
+		for _, standing := range this.League.Standings {
+			standings = append(standings, *standing)
+		}
+	}
+	this.lock.Unlock()
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Won != standings[j].Won {
+			return standings[i].Won > standings[j].Won
+		}
+		return standings[i].PointsFor-standings[i].PointsAgainst > standings[j].PointsFor-standings[j].PointsAgainst
+	})
+	return standings
+}
+
+// Serve the schedule and standings of the league as json, POST players separated by commas to start a league, or
+// DELETE to end it
+func (this *Store) ServeLeague(w http.ResponseWriter, r *http.Request) {
+
+	switch r.Method {
+	case "POST":
+		players := []string{}
+		for _, player := range strings.Split(r.FormValue("players"), ",") {
+			players = append(players, strings.TrimSpace(player))
+		}
+		if err := this.StartLeague(players); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Print("Started a league between ", strings.Join(players, ", "))
+	case "DELETE":
+		this.EndLeague()
+		log.Print("League ended")
+	}
+	if r.Method != "GET" {
+		if err := this.Save(); err != nil {
+			log.Print(err)
+		}
+	}
+
+	served := struct {
+		Fixtures  []LeagueFixture
+		Standings []LeagueStanding
+	}{Standings: this.LeagueStandings()}
+	this.lock.Lock()
+	if this.League != nil {
+		for _, fixture := range this.League.Fixtures {
+			served.Fixtures = append(served.Fixtures, *fixture)
+		}
+	}
+	this.lock.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(served)
+}
@@ -0,0 +1,81 @@
+package stats
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Every player should be scheduled against every other once, nobody twice in a round
+func Test_League_Schedule(t *testing.T) {
+
+	for players := 2; players <= 7; players++ {
+		names := []string{}
+		for index := 0; index < players; index++ {
+			names = append(names, string(rune('a'+index)))
+		}
+
+		fixtures := roundRobin(names)
+		if len(fixtures) != players*(players-1)/2 {
+			t.Fatal(players, "players have", len(fixtures), "fixtures")
+		}
+		met := map[string]bool{}
+		for _, fixture := range fixtures {
+			pair := fixture.Left + fixture.Right
+			if fixture.Right < fixture.Left {
+				pair = fixture.Right + fixture.Left
+			}
+			if met[pair] || fixture.Left == fixture.Right {
+				t.Fatal("Scheduled", fixture.Left, "against", fixture.Right, "twice")
+			}
+			met[pair] = true
+		}
+	}
+}
+
+// Scheduled matches should update the standings and be there after loading the stats again, other matches shouldn't
+func Test_League_Standings(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "stats.xml")
+
+	store := Load(path)
+	if err := store.StartLeague([]string{"ann", "bob", "ann"}); err == nil {
+		t.Fatal("Started a league with the same player twice")
+	}
+	if err := store.StartLeague([]string{"ann", "bob", "cat"}); err != nil {
+		t.Fatal(err)
+	}
+
+	today := time.Date(2020, 6, 1, 12, 0, 0, 0, time.Local)
+	if !store.RecordLeague(Match{Time: today, Left: "bob", Right: "ann", LeftScore: 2, RightScore: 4, LeftWon: false}) {
+		t.Fatal("Scheduled match wasn't counted")
+	}
+	if store.RecordLeague(Match{Time: today, Left: "ann", Right: "bob", LeftWon: true}) {
+		t.Fatal("Match counted twice")
+	}
+	if store.RecordLeague(Match{Time: today, Left: "ann", Right: "dan", LeftWon: true}) {
+		t.Fatal("Match against someone outside the league counted")
+	}
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := Load(path)
+	standings := loaded.LeagueStandings()
+	if standings[0].Name != "ann" || standings[0].Won != 1 || standings[0].PointsFor != 4 || standings[2].Name != "bob" {
+		t.Fatal("Standings were", standings)
+	}
+	if next, ok := loaded.NextLeagueFixture("ann"); !ok || next.Left+next.Right == "annbob" || next.Left+next.Right == "bobann" {
+		t.Fatal("Ann's next fixture is", next)
+	}
+	if _, ok := loaded.LeagueFixture("cat", "bob"); !ok {
+		t.Fatal("Bob and cat have no fixture left")
+	}
+}
@@ -0,0 +1,276 @@
+// Package stats keeps totals and records across every game played, saved to a file so they survive restarts
+package stats
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/brandonagr/pongpi/src/pong/atomicfile"
+)
+
+// layout of the date each day is stored under
+const dayLayout = "2006-01-02"
+
+// How a single finished game went
+type Game struct {
+
+	// names of the players, empty for modes without named players
+	Winner, Loser string
+
+	// most bounces in a single rally
+	LongestRally int
+
+	// speed of the fastest ball a player hit back, in leds / second
+	FastestReturn float64
+
+	// when the game ended
+	Time time.Time
+
+	// if the game was walked away from, it has no winner or loser and only counts toward the records
+	Abandoned bool
+}
+
+// Games played and won by a single player
+type PlayerStats struct {
+	Name  string `xml:"name,attr"`
+	Games int    `xml:"games,attr"`
+	Wins  int    `xml:"wins,attr"`
+}
+
+// Games played on a single day, and the games played and won that day by each player
+type DayStats struct {
+	Date    string         `xml:"date,attr"`
+	Games   int            `xml:"games,attr"`
+	Players []*PlayerStats `xml:"Player"`
+}
+
+// Totals and records of every game, persisted to a file
+type Store struct {
+	XMLName xml.Name `xml:"Stats"`
+
+	GamesPlayed    int
+	GamesAbandoned int `xml:",omitempty"`
+	LongestRally   int
+	FastestReturn  float64
+
+	Players []*PlayerStats `xml:"Player"`
+	Days    []*DayStats    `xml:"Day"`
+
+	// round robin being played, nil if there isn't one
+	League *League `xml:"League,omitempty"`
+
+	// file the stats are saved to
+	path string
+
+	// stats are read by the web server while the game updates them
+	lock sync.Mutex
+}
+
+// Load stats from path, starting empty if the file doesn't exist yet
+func Load(path string) *Store {
+
+	store := &Store{path: path}
+
+	fileData, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Print(err)
+		}
+		return store
+	}
+	if err := xml.Unmarshal(fileData, store); err != nil {
+		log.Print(err)
+	}
+
+	return store
+}
+
+// Write the stats to the file they were loaded from
+func (this *Store) Save() error {
+
+	this.lock.Lock()
+	fileData, err := xml.MarshalIndent(this, "", "\t")
+	this.lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(this.path, fileData, 0666)
+}
+
+// Add a finished game to the totals
+func (this *Store) Record(game Game) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if game.LongestRally > this.LongestRally {
+		this.LongestRally = game.LongestRally
+	}
+	if game.FastestReturn > this.FastestReturn {
+		this.FastestReturn = game.FastestReturn
+	}
+	if game.Abandoned {
+		this.GamesAbandoned++
+		return
+	}
+	this.GamesPlayed++
+
+	day := this.day(game.Time.Format(dayLayout))
+	day.Games++
+
+	if game.Winner != "" {
+		for _, winner := range []*PlayerStats{findPlayer(&this.Players, game.Winner), findPlayer(&day.Players, game.Winner)} {
+			winner.Games++
+			winner.Wins++
+		}
+	}
+	if game.Loser != "" {
+		findPlayer(&this.Players, game.Loser).Games++
+		findPlayer(&day.Players, game.Loser).Games++
+	}
+}
+
+// Find the stats for name in players, adding them if they don't exist, lock must be held
+func findPlayer(players *[]*PlayerStats, name string) *PlayerStats {
+
+	for _, player := range *players {
+		if player.Name == name {
+			return player
+		}
+	}
+
+	player := &PlayerStats{Name: name}
+	*players = append(*players, player)
+	return player
+}
+
+// Find the stats for date, adding them if they don't exist, lock must be held
+func (this *Store) day(date string) *DayStats {
+
+	// games are recorded in order, so today is almost always the last day
+	for index := len(this.Days) - 1; index >= 0; index-- {
+		if this.Days[index].Date == date {
+			return this.Days[index]
+		}
+	}
+
+	day := &DayStats{Date: date}
+	this.Days = append(this.Days, day)
+	return day
+}
+
+// Stats of name, false if they have never played
+func (this *Store) Player(name string) (PlayerStats, bool) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	for _, player := range this.Players {
+		if player.Name == name {
+			return *player, true
+		}
+	}
+	return PlayerStats{}, false
+}
+
+// Games played on the day of date
+func (this *Store) GamesOn(date time.Time) int {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	for _, day := range this.Days {
+		if day.Date == date.Format(dayLayout) {
+			return day.Games
+		}
+	}
+	return 0
+}
+
+// Up to count players with the most wins over the days from first to last, ties go to whoever played fewer games
+func (this *Store) Leaderboard(first, last time.Time, count int) []PlayerStats {
+
+	this.lock.Lock()
+	totals := []*PlayerStats{}
+	for _, day := range this.Days {
+		if day.Date < first.Format(dayLayout) || day.Date > last.Format(dayLayout) {
+			continue
+		}
+		for _, player := range day.Players {
+			total := findPlayer(&totals, player.Name)
+			total.Games += player.Games
+			total.Wins += player.Wins
+		}
+	}
+	this.lock.Unlock()
+
+	sort.SliceStable(totals, func(i, j int) bool {
+		if totals[i].Wins != totals[j].Wins {
+			return totals[i].Wins > totals[j].Wins
+		}
+		return totals[i].Games < totals[j].Games
+	})
+
+	leaders := []PlayerStats{}
+	for _, player := range totals {
+		if len(leaders) == count || player.Wins == 0 {
+			break
+		}
+		leaders = append(leaders, *player)
+	}
+	return leaders
+}
+
+// Copy of the totals that is safe to use while games are recorded
+type Totals struct {
+	GamesPlayed    int
+	GamesAbandoned int `xml:",omitempty"`
+	LongestRally   int
+	FastestReturn  float64
+	Players        []PlayerStats
+	Days           []DayStats
+}
+
+// Copy the current totals, players with the most wins first
+func (this *Store) Totals() Totals {
+
+	this.lock.Lock()
+	totals := Totals{
+		GamesPlayed:   this.GamesPlayed,
+		LongestRally:  this.LongestRally,
+		FastestReturn: this.FastestReturn,
+		Players:       make([]PlayerStats, 0, len(this.Players)),
+		Days:          make([]DayStats, 0, len(this.Days)),
+	}
+	for _, player := range this.Players {
+		totals.Players = append(totals.Players, *player)
+	}
+	// days are copied along with their players, which Record keeps counting while the copy is encoded
+	for _, day := range this.Days {
+		copied := *day
+		copied.Players = make([]*PlayerStats, len(day.Players))
+		for index, player := range day.Players {
+			player := *player
+			copied.Players[index] = &player
+		}
+		totals.Days = append(totals.Days, copied)
+	}
+	this.lock.Unlock()
+
+	sort.Slice(totals.Players, func(i, j int) bool { return totals.Players[i].Wins > totals.Players[j].Wins })
+	return totals
+}
+
+// Serve the stats as json, players with the most wins first
+func (this *Store) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(this.Totals())
+}
@@ -0,0 +1,126 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Recorded games should update the totals and records, and be there after loading the file again
+func Test_Store(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "stats.xml")
+
+	today := time.Date(2020, 6, 1, 12, 0, 0, 0, time.Local)
+	store := Load(path)
+	store.Record(Game{Winner: "Ann", Loser: "Bob", LongestRally: 12, FastestReturn: 300, Time: today})
+	store.Record(Game{Winner: "Bob", Loser: "Ann", LongestRally: 8, FastestReturn: 400, Time: today})
+	store.Record(Game{LongestRally: 3, Time: today.AddDate(0, 0, 1)})
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := Load(path)
+	if loaded.GamesPlayed != 3 || loaded.LongestRally != 12 || loaded.FastestReturn != 400 {
+		t.Fatal("Totals were", loaded.GamesPlayed, loaded.LongestRally, loaded.FastestReturn)
+	}
+	if ann, ok := loaded.Player("Ann"); !ok || ann.Games != 2 || ann.Wins != 1 {
+		t.Fatal("Ann's stats were", ann)
+	}
+	if _, ok := loaded.Player("Cat"); ok {
+		t.Fatal("Found stats for a player who never played")
+	}
+	if loaded.GamesOn(today) != 2 || loaded.GamesOn(today.AddDate(0, 0, 1)) != 1 {
+		t.Fatal("Daily counts were", loaded.GamesOn(today), loaded.GamesOn(today.AddDate(0, 0, 1)))
+	}
+}
+
+// Totals should be a copy that games recorded afterwards don't change
+func Test_Store_Totals(t *testing.T) {
+
+	today := time.Date(2020, 6, 1, 12, 0, 0, 0, time.Local)
+	store := &Store{}
+	store.Record(Game{Winner: "Ann", Loser: "Bob", Time: today})
+
+	totals := store.Totals()
+	store.Record(Game{Winner: "Ann", Loser: "Bob", Time: today})
+
+	Assert(totals.GamesPlayed, 1, "Games in the copy", t)
+	Assert(totals.Players[0].Wins, 1, "Wins in the copy", t)
+	Assert(totals.Days[0].Games, 1, "Games on the day in the copy", t)
+	Assert(totals.Days[0].Players[0].Wins, 1, "Wins on the day in the copy", t)
+}
+
+// Leaders should be ranked by wins over only the days asked for
+func Test_Store_Leaderboard(t *testing.T) {
+
+	today := time.Date(2020, 6, 10, 12, 0, 0, 0, time.Local)
+	store := &Store{}
+	store.Record(Game{Winner: "Ann", Loser: "Bob", Time: today})
+	store.Record(Game{Winner: "Bob", Loser: "Cat", Time: today})
+	store.Record(Game{Winner: "Bob", Loser: "Ann", Time: today.AddDate(0, 0, -3)})
+	store.Record(Game{Winner: "Bob", Loser: "Ann", Time: today.AddDate(0, 0, -3)})
+	store.Record(Game{Winner: "Cat", Loser: "Ann", Time: today.AddDate(0, 0, -20)})
+
+	daily := store.Leaderboard(today, today, 5)
+	if len(daily) != 2 || daily[0].Name != "Ann" || daily[1].Name != "Bob" {
+		t.Fatal("Today's leaders were", daily)
+	}
+
+	weekly := store.Leaderboard(today.AddDate(0, 0, -6), today, 1)
+	if len(weekly) != 1 || weekly[0].Name != "Bob" || weekly[0].Wins != 3 {
+		t.Fatal("This week's leaders were", weekly)
+	}
+}
+
+// An abandoned match should be kept in the history and count toward the records, but not as a game won or lost
+func Test_Store_Abandoned(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	history := NewHistory(filepath.Join(dir, "matches.log"))
+
+	played := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	history.Append(Match{Time: played, Mode: "classic", Left: "Ann", Right: "Bob", LeftScore: 1, Rallies: []int{14, 2}, FastestReturn: 500, Abandoned: true})
+
+	matches, err := history.Matches()
+	if err != nil || len(matches) != 1 || !matches[0].Abandoned {
+		t.Fatal("Read back", matches, err)
+	}
+
+	store := &Store{}
+	store.Record(Game{Winner: "Ann", Loser: "Bob", LongestRally: 3, Time: played})
+	store.Record(matches[0].Game())
+
+	if store.GamesPlayed != 1 || store.GamesAbandoned != 1 || store.LongestRally != 14 || store.FastestReturn != 500 {
+		t.Fatal("Totals were", store.GamesPlayed, store.GamesAbandoned, store.LongestRally, store.FastestReturn)
+	}
+	if ann, _ := store.Player("Ann"); ann.Games != 1 || ann.Wins != 1 {
+		t.Fatal("Ann's stats were", ann)
+	}
+	if bob, _ := store.Player("Bob"); bob.Games != 1 || bob.Wins != 0 {
+		t.Fatal("Bob's stats were", bob)
+	}
+	Assert(store.GamesOn(played), 1, "Games counted on the day", t)
+
+	var exported bytes.Buffer
+	if err := history.Export(&exported, "csv"); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&exported).ReadAll()
+	if err != nil || len(rows) != 2 || rows[1][6] != "" {
+		t.Fatal("Exported", rows, err)
+	}
+}
@@ -0,0 +1,192 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/brandonagr/pongpi/src/pong/atomicfile"
+)
+
+// Most matches sent in a single upload, the rest follow straight after
+var uploadBatchSize int = 100
+
+// Seconds to wait before retrying the first failed upload, doubled after each failure up to the upload interval
+var uploadRetrySeconds float64 = 10
+
+// What is sent in each upload
+type Upload struct {
+
+	// name of the installation the matches were played on
+	Installation string
+
+	// totals of every game played on the installation
+	Stats Totals
+
+	// index in the installation's history of the first match in Matches, so a repeated upload can be recognized
+	FirstMatch int
+	Matches    []Match
+}
+
+// How far the history has been uploaded, saved so restarts don't send matches twice
+type uploadState struct {
+	XMLName  xml.Name `xml:"UploadState"`
+	Uploaded int
+
+	// byte offset in the history after the last match uploaded, so only newer matches are read
+	Offset int64
+}
+
+// Sends the stats and any matches not sent yet to an endpoint, keeping matches queued in the history while offline
+type Uploader struct {
+	history *History
+	store   *Store
+
+	// endpoint the uploads are POSTed to, token sent as a bearer token if not empty, and the name of the installation
+	url, token, installation string
+
+	// file the number of matches uploaded so far is saved to
+	statePath string
+	state     uploadState
+
+	// totals in the last successful upload, nothing is sent while they and the matches are unchanged
+	sent *Totals
+
+	client *http.Client
+}
+
+// Construct an Uploader for the matches in history and the totals in store
+func NewUploader(history *History, store *Store, url, token, installation, statePath string) *Uploader {
+
+	if !strings.HasPrefix(url, "https://") {
+		log.Print("Uploading stats to ", url, " without https")
+	}
+
+	this := &Uploader{
+		history:      history,
+		store:        store,
+		url:          url,
+		token:        token,
+		installation: installation,
+		statePath:    statePath,
+		client:       &http.Client{Timeout: 30 * time.Second},
+	}
+
+	fileData, err := ioutil.ReadFile(statePath)
+	if err == nil {
+		err = xml.Unmarshal(fileData, &this.state)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		log.Print(err)
+	}
+
+	return this
+}
+
+// Upload every interval until the process exits, retrying sooner after failures
+func (this *Uploader) Run(interval time.Duration) {
+
+	retry := time.Duration(uploadRetrySeconds * float64(time.Second))
+	wait := time.Duration(0)
+	for {
+		time.Sleep(wait)
+
+		more, err := this.Upload()
+		switch {
+		case err != nil:
+			log.Print("Stats upload failed, retrying in ", retry, ": ", err)
+			wait = retry
+			if retry *= 2; retry > interval {
+				retry = interval
+			}
+		case more:
+			wait = 0
+		default:
+			wait = interval
+			retry = time.Duration(uploadRetrySeconds * float64(time.Second))
+		}
+	}
+}
+
+// Send the totals and the next batch of matches if anything changed since the last upload, more is true if matches are
+// still waiting to be sent
+func (this *Uploader) Upload() (more bool, err error) {
+
+	matches, end, err := this.history.MatchesAfter(this.state.Offset, uploadBatchSize)
+	if err == ErrHistoryShrunk {
+		// the history was cleared, start over
+		this.state = uploadState{}
+		matches, end, err = this.history.MatchesAfter(0, uploadBatchSize)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	totals := this.store.Totals()
+	if len(matches) == 0 && this.sent != nil && reflect.DeepEqual(totals, *this.sent) {
+		if end != this.state.Offset {
+			// only unreadable lines were passed over
+			this.state.Offset = end
+			if err := this.saveState(); err != nil {
+				log.Print(err)
+			}
+		}
+		return false, nil
+	}
+
+	upload := Upload{
+		Installation: this.installation,
+		Stats:        totals,
+		FirstMatch:   this.state.Uploaded,
+		Matches:      matches,
+	}
+
+	body, err := json.Marshal(upload)
+	if err != nil {
+		return false, err
+	}
+	request, err := http.NewRequest("POST", this.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if this.token != "" {
+		request.Header.Set("Authorization", "Bearer "+this.token)
+	}
+
+	response, err := this.client.Do(request)
+	if err != nil {
+		return false, err
+	}
+	response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return false, errors.New(fmt.Sprint("Endpoint answered ", response.Status))
+	}
+
+	this.sent = &totals
+	this.state.Uploaded += len(matches)
+	this.state.Offset = end
+	if err := this.saveState(); err != nil {
+		log.Print(err)
+	}
+	return len(matches) == uploadBatchSize, nil
+}
+
+// Write how far the history has been uploaded
+func (this *Uploader) saveState() error {
+
+	fileData, err := xml.MarshalIndent(this.state, "", "\t")
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(this.statePath, fileData, 0666)
+}
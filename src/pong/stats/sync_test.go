@@ -0,0 +1,91 @@
+package stats
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Matches should stay queued while the endpoint fails, then go up in batches without being sent twice
+func Test_Uploader(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	history := NewHistory(filepath.Join(dir, "matches.log"))
+	for match := 0; match < 3; match++ {
+		history.Append(Match{Time: time.Now(), Mode: "classic"})
+	}
+
+	failing := true
+	received := []Upload{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Error("Token missing")
+		}
+		var upload Upload
+		json.NewDecoder(r.Body).Decode(&upload)
+		received = append(received, upload)
+	}))
+	defer server.Close()
+
+	defer func(size int) { uploadBatchSize = size }(uploadBatchSize)
+	uploadBatchSize = 2
+	statePath := filepath.Join(dir, "upload.xml")
+	uploader := NewUploader(history, &Store{}, server.URL, "secret", "garage", statePath)
+
+	if _, err := uploader.Upload(); err == nil {
+		t.Fatal("Upload to a failing endpoint succeeded")
+	}
+
+	failing = false
+	if more, err := uploader.Upload(); err != nil || !more {
+		t.Fatal("First batch", more, err)
+	}
+	if more, err := uploader.Upload(); err != nil || more {
+		t.Fatal("Second batch", more, err)
+	}
+	Assert(len(received), 2, "Uploads", t)
+	Assert(len(received[0].Matches)+len(received[1].Matches), 3, "Matches uploaded", t)
+	Assert(received[1].FirstMatch, 2, "First match of the second batch", t)
+
+	// nothing new, nothing sent
+	if more, err := uploader.Upload(); err != nil || more {
+		t.Fatal("Upload with nothing new", more, err)
+	}
+	Assert(len(received), 2, "Uploads with nothing new", t)
+
+	// a restart picks up where the last upload left off
+	history.Append(Match{Time: time.Now(), Mode: "ai"})
+	restarted := NewUploader(history, &Store{}, server.URL, "secret", "garage", statePath)
+	restarted.Upload()
+	if last := received[len(received)-1]; len(last.Matches) != 1 || last.Matches[0].Mode != "ai" {
+		t.Fatal("Uploaded after restart", last)
+	}
+	Assert(received[len(received)-1].FirstMatch, 3, "First match after restart", t)
+
+	// clearing the history starts the uploads over
+	os.Remove(history.path)
+	history.Append(Match{Time: time.Now(), Mode: "lanes"})
+	restarted.Upload()
+	Assert(received[len(received)-1].FirstMatch, 0, "First match after clearing", t)
+}
+
+// Fail the test if actual isn't expected
+func Assert(actual, expected int, msg string, t *testing.T) {
+	if actual != expected {
+		t.Fatal(msg, "was", actual, "vs expected", expected)
+	}
+}
@@ -0,0 +1,128 @@
+package stats
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Counts of values falling in equal sized buckets, values outside the range count towards the first or last bucket
+type Histogram struct {
+
+	// lowest value of the first bucket, and the range of values in each
+	Min, BucketWidth float64
+
+	Counts []int
+}
+
+// Construct an empty Histogram covering min to max
+func NewHistogram(min, max, bucketWidth float64) *Histogram {
+	return &Histogram{
+		Min:         min,
+		BucketWidth: bucketWidth,
+		Counts:      make([]int, int((max-min)/bucketWidth+0.5)),
+	}
+}
+
+// Count value in its bucket
+func (this *Histogram) Add(value float64) {
+
+	// values on the edge of a bucket belong to the bucket above, even after rounding errors
+	bucket := int(math.Floor((value-this.Min)/this.BucketWidth + 1e-9))
+	if bucket < 0 {
+		bucket = 0
+	} else if bucket >= len(this.Counts) {
+		bucket = len(this.Counts) - 1
+	}
+	this.Counts[bucket]++
+}
+
+// How the ball moved and how players pressed over many matches, for tuning the rules
+type Telemetry struct {
+	Matches int
+
+	// speed in leds / second of each ball that was returned and each that was missed
+	ReturnSpeed, MissSpeed *Histogram
+
+	// seconds before the ball reached the hit window each press was made, for returns and misses
+	ReturnTiming, MissTiming *Histogram
+
+	// bounces in each rally
+	RallyLength *Histogram
+
+	// misses by each side, and misses where the player never pressed
+	LeftMisses, RightMisses, UnpressedMisses int
+}
+
+// Add up the hits of every match
+func NewTelemetry(matches []Match) *Telemetry {
+
+	this := &Telemetry{
+		ReturnSpeed:  NewHistogram(0, 1000, 25),
+		MissSpeed:    NewHistogram(0, 1000, 25),
+		ReturnTiming: NewHistogram(-0.25, 0.5, 0.025),
+		MissTiming:   NewHistogram(-0.25, 0.5, 0.025),
+		RallyLength:  NewHistogram(0, 50, 1),
+	}
+
+	for _, match := range matches {
+		this.Matches++
+
+		for _, rally := range match.Rallies {
+			this.RallyLength.Add(float64(rally))
+		}
+
+		for _, hit := range match.Hits {
+			speed, timing := this.ReturnSpeed, this.ReturnTiming
+			if !hit.Returned {
+				speed, timing = this.MissSpeed, this.MissTiming
+				if hit.Left {
+					this.LeftMisses++
+				} else {
+					this.RightMisses++
+				}
+				if !hit.Pressed {
+					this.UnpressedMisses++
+				}
+			}
+
+			speed.Add(hit.Speed)
+			if hit.Pressed {
+				timing.Add(hit.Timing)
+			}
+		}
+	}
+
+	return this
+}
+
+// Serve the telemetry of every match as json, or of the last few days with ?days=
+func (this *History) ServeTelemetry(w http.ResponseWriter, r *http.Request) {
+
+	matches, err := this.Matches()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if days := r.FormValue("days"); days != "" {
+		count, err := strconv.Atoi(days)
+		if err != nil {
+			http.Error(w, "Days isn't a number", http.StatusBadRequest)
+			return
+		}
+		since := time.Now().AddDate(0, 0, -count)
+		recent := []Match{}
+		for _, match := range matches {
+			if match.Time.After(since) {
+				recent = append(recent, match)
+			}
+		}
+		matches = recent
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(NewTelemetry(matches))
+}
@@ -0,0 +1,283 @@
+// Package status drives a small OLED or LCD panel showing a few lines of text about the game, such as the address of
+// its web page on an installation without a screen
+package status
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/brandonagr/pongpi/src/pong"
+	"github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+// Panels that can be driven over the I2C bus
+const (
+	PanelSSD1306 = "ssd1306"
+	PanelHD44780 = "hd44780"
+)
+
+// A text panel
+type Panel interface {
+	io.Closer
+
+	// Characters in a line and lines on the panel
+	Size() (columns, rows int)
+
+	// Show lines from the top, cut to fit
+	Show(lines []string) error
+}
+
+// Open the panel of kind at address on the I2C bus at busFilePath, 0 uses the usual address of the panel. columns and
+// rows are the size of an HD44780, an SSD1306 is 128x64 pixels
+func Open(kind, busFilePath string, address, columns, rows int) (Panel, error) {
+
+	var open func(device io.ReadWriteCloser) (Panel, error)
+	switch kind {
+	case PanelSSD1306:
+		open = NewSSD1306
+		if address == 0 {
+			address = 0x3c
+		}
+	case PanelHD44780:
+		open = func(device io.ReadWriteCloser) (Panel, error) { return NewHD44780(device, columns, rows) }
+		if address == 0 {
+			address = 0x27
+		}
+	default:
+		return nil, fmt.Errorf("Status panel %q isn't %v or %v", kind, PanelSSD1306, PanelHD44780)
+	}
+
+	device, err := pong.OpenI2cDevice(busFilePath, address)
+	if err != nil {
+		return nil, fmt.Errorf("Status panel %v: %v", kind, err)
+	}
+	panel, err := open(device)
+	if err != nil {
+		device.Close()
+		return nil, fmt.Errorf("Status panel %v at %#x on %v: %v", kind, address, busFilePath, err)
+	}
+	return panel, nil
+}
+
+// lines padded or cut to rows lines of columns characters
+func fit(lines []string, columns, rows int) []string {
+	fitted := make([]string, rows)
+	for row := range fitted {
+		line := ""
+		if row < len(lines) {
+			line = lines[row]
+		}
+		characters := []rune(line)
+		if len(characters) > columns {
+			characters = characters[:columns]
+		}
+		fitted[row] = string(characters) + strings.Repeat(" ", columns-len(characters))
+	}
+	return fitted
+}
+
+// Address of this machine on the network, the first IPv4 address that isn't loopback, empty if there isn't one
+func LocalAddress() string {
+	addresses, err := net.InterfaceAddrs()
+	if err != nil {
+		return ""
+	}
+	for _, address := range addresses {
+		if network, ok := address.(*net.IPNet); ok && !network.IP.IsLoopback() && network.IP.To4() != nil {
+			return network.IP.String()
+		}
+	}
+	return ""
+}
+
+// 128x64 pixel SSD1306 OLED, 21 characters of the font across and 8 lines down
+type SSD1306 struct {
+	device io.ReadWriteCloser
+
+	// a byte for every column of each 8 pixel page, bit 0 the top, after the data control byte
+	page  []byte
+	shown []string
+}
+
+// Width and height of an SSD1306 in pixels
+const (
+	ssd1306Width  = 128
+	ssd1306Height = 64
+)
+
+// Turn on the SSD1306 on device
+func NewSSD1306(device io.ReadWriteCloser) (Panel, error) {
+
+	panel := &SSD1306{device: device, page: make([]byte, 1+ssd1306Width)}
+	panel.page[0] = 0x40
+
+	err := panel.command(
+		0xae,       // display off
+		0xd5, 0x80, // clock
+		0xa8, ssd1306Height-1, // multiplex
+		0xd3, 0x00, // no offset
+		0x40,       // start line 0
+		0x8d, 0x14, // charge pump on
+		0x20, 0x00, // horizontal addressing
+		0xa1,       // columns left to right
+		0xc8,       // rows top to bottom
+		0xda, 0x12, // com pins
+		0x81, 0x8f, // contrast
+		0xd9, 0xf1, // precharge
+		0xdb, 0x40, // vcom detect
+		0xa4, // show the ram
+		0xa6, // not inverted
+		0xaf, // display on
+	)
+	if err != nil {
+		return nil, err
+	}
+	return panel, nil
+}
+
+// Send commands to the panel
+func (this *SSD1306) command(commands ...byte) error {
+	_, err := this.device.Write(append([]byte{0x00}, commands...))
+	return err
+}
+
+// Characters in a line and lines on the panel
+func (this *SSD1306) Size() (columns, rows int) {
+	return ssd1306Width / (draw.FontWidth + draw.FontSpacing), ssd1306Height / 8
+}
+
+// Draw lines in the font, a line to every page of 8 pixel rows, only sending the ones that changed
+func (this *SSD1306) Show(lines []string) error {
+
+	columns, rows := this.Size()
+	lines = fit(lines, columns, rows)
+	if this.shown == nil {
+		this.shown = make([]string, rows)
+	}
+
+	for row, line := range lines {
+		if line == this.shown[row] {
+			continue
+		}
+
+		for column := range this.page[1:] {
+			this.page[1+column] = 0
+		}
+		for index, character := range []rune(line) {
+			glyph := draw.FontGlyph(character)
+			copy(this.page[1+index*(draw.FontWidth+draw.FontSpacing):], glyph[:])
+		}
+
+		if err := this.command(0x21, 0, ssd1306Width-1, 0x22, byte(row), byte(row)); err != nil {
+			return err
+		}
+		if _, err := this.device.Write(this.page); err != nil {
+			return err
+		}
+		this.shown[row] = line
+	}
+	return nil
+}
+
+// Turn the panel off and close the connection to it
+func (this *SSD1306) Close() error {
+	this.command(0xae)
+	return this.device.Close()
+}
+
+// Bits of the PCF8574 expander on the back of an HD44780 panel
+const (
+	hd44780RegisterSelect = 0x01
+	hd44780Enable         = 0x04
+	hd44780Backlight      = 0x08
+)
+
+// Character LCD driven through a PCF8574 I2C expander in 4 bit mode, usually 16x2 or 20x4
+type HD44780 struct {
+	device        io.ReadWriteCloser
+	columns, rows int
+	shown         []string
+}
+
+// Start the HD44780 of columns by rows characters on device in 4 bit mode and clear it
+func NewHD44780(device io.ReadWriteCloser, columns, rows int) (Panel, error) {
+
+	if columns <= 0 || rows <= 0 || rows > 4 {
+		return nil, fmt.Errorf("HD44780 can't be %vx%v characters", columns, rows)
+	}
+	panel := &HD44780{device: device, columns: columns, rows: rows}
+
+	// from 8 bit mode or part way through a 4 bit byte, into 4 bit mode
+	for _, nibble := range []byte{0x03, 0x03, 0x03, 0x02} {
+		if err := panel.writeNibble(nibble, 0); err != nil {
+			return nil, err
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	// 2 lines, display on without a cursor, move right after each character, clear
+	for _, command := range []byte{0x28, 0x0c, 0x06, 0x01} {
+		if err := panel.write(command, 0); err != nil {
+			return nil, err
+		}
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	// the clear left every line blank
+	panel.shown = fit(nil, columns, rows)
+	return panel, nil
+}
+
+// Clock the low 4 bits of nibble into the panel, with the register select bit in mode
+func (this *HD44780) writeNibble(nibble, mode byte) error {
+	data := nibble<<4 | mode | hd44780Backlight
+	_, err := this.device.Write([]byte{data | hd44780Enable, data})
+	return err
+}
+
+// Send value to the panel a nibble at a time, a command or with hd44780RegisterSelect in mode a character
+func (this *HD44780) write(value, mode byte) error {
+	if err := this.writeNibble(value>>4, mode); err != nil {
+		return err
+	}
+	return this.writeNibble(value&0x0f, mode)
+}
+
+// Characters in a line and lines on the panel
+func (this *HD44780) Size() (columns, rows int) {
+	return this.columns, this.rows
+}
+
+// Write lines to the panel, only sending the ones that changed
+func (this *HD44780) Show(lines []string) error {
+
+	lines = fit(lines, this.columns, this.rows)
+
+	// where each line starts in the display ram
+	starts := []byte{0x00, 0x40, byte(this.columns), 0x40 + byte(this.columns)}
+	for row, line := range lines {
+		if line == this.shown[row] {
+			continue
+		}
+		if err := this.write(0x80|starts[row], 0); err != nil {
+			return err
+		}
+		for _, character := range []rune(line) {
+			if character < ' ' || character > '~' {
+				character = '?'
+			}
+			if err := this.write(byte(character), hd44780RegisterSelect); err != nil {
+				return err
+			}
+		}
+		this.shown[row] = line
+	}
+	return nil
+}
+
+// Close the connection to the panel
+func (this *HD44780) Close() error {
+	return this.device.Close()
+}
@@ -0,0 +1,90 @@
+package status
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+// Device on the I2C bus keeping every write to it
+type recordingDevice struct {
+	writes [][]byte
+}
+
+func (this *recordingDevice) Read(data []byte) (int, error) {
+	return 0, nil
+}
+
+func (this *recordingDevice) Write(data []byte) (int, error) {
+	this.writes = append(this.writes, append([]byte(nil), data...))
+	return len(data), nil
+}
+
+func (this *recordingDevice) Close() error {
+	return nil
+}
+
+// Lines should be cut or padded to fit
+func Test_Fit(t *testing.T) {
+	lines := fit([]string{"192.168.1.20:8080", "idle"}, 8, 3)
+	if len(lines) != 3 || lines[0] != "192.168." || lines[1] != "idle    " || lines[2] != "        " {
+		t.Fatalf("Fitted lines are %q", lines)
+	}
+}
+
+// An SSD1306 should be sent a page of font columns for every line that changed
+func Test_SSD1306(t *testing.T) {
+
+	device := &recordingDevice{}
+	panel, err := NewSSD1306(device)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if columns, rows := panel.Size(); columns != 21 || rows != 8 {
+		t.Fatal("SSD1306 holds", columns, "by", rows, "characters")
+	}
+
+	device.writes = nil
+	panel.Show([]string{"", "Hi"})
+	// every line is new, so each is addressed and sent
+	if len(device.writes) != 16 {
+		t.Fatal("Showing 8 new lines took", len(device.writes), "writes")
+	}
+	page := device.writes[3]
+	glyph := draw.FontGlyph('H')
+	if page[0] != 0x40 || !bytes.Equal(page[1:1+draw.FontWidth], glyph[:]) || len(page) != 129 {
+		t.Fatal("Second line was sent as", page)
+	}
+
+	device.writes = nil
+	panel.Show([]string{"", "Ho"})
+	if len(device.writes) != 2 {
+		t.Fatal("Changing one line took", len(device.writes), "writes")
+	}
+}
+
+// An HD44780 should be sent each character of a line as two nibbles clocked in by the enable bit
+func Test_HD44780(t *testing.T) {
+
+	device := &recordingDevice{}
+	panel, err := NewHD44780(device, 16, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewHD44780(device, 16, 5); err == nil {
+		t.Fatal("Made an HD44780 of 5 lines")
+	}
+
+	device.writes = nil
+	panel.Show([]string{"", "A"})
+	// address of the second line then 16 characters, each a write of two nibbles
+	if len(device.writes) != 17*2 {
+		t.Fatal("Showing a line took", len(device.writes), "writes")
+	}
+	address := device.writes[0][1]&0xf0 | device.writes[1][1]>>4
+	character := device.writes[2][1]&0xf0 | device.writes[3][1]>>4
+	if address != 0xc0 || character != 'A' || device.writes[2][0]&hd44780Enable == 0 || device.writes[2][1]&hd44780RegisterSelect == 0 {
+		t.Fatal("Second line was sent as", device.writes[:4])
+	}
+}
@@ -0,0 +1,95 @@
+// Precomputed lookup tables shared by the drawables, so per-led math doesn't call math.Sin or math.Exp
+package tables
+
+import (
+	"fmt"
+	"math"
+)
+
+// Entries in the sine and falloff tables, set with SetResolution
+var resolution int
+
+// sin over one turn, and exponential falloff from 1 to 0 over a fraction from 0 to 1
+var sineTable, falloffTable []float64
+
+// (sin + 1) / 2 over one turn scaled to 0 to 255
+var sineByteTable []uint8
+
+// Brightness correction for the 7 bit channels of the LPD8806 strip
+var gammaTable [256]uint8
+
+// How quickly the falloff curve drops, larger is a tighter glow
+var falloffSharpness float64 = 3
+
+// Gamma of the strip's brightness curve
+var gamma float64 = 2.5
+
+func init() {
+	SetResolution(1024)
+
+	for index := range gammaTable {
+		gammaTable[index] = uint8(math.Pow(float64(index)/255, gamma)*127 + 0.5)
+	}
+}
+
+// Rebuild the sine and falloff tables with entries values each, more entries are smoother but use more memory
+func SetResolution(entries int) error {
+
+	if entries <= 0 {
+		return fmt.Errorf("Lookup tables need at least one entry, not %v", entries)
+	}
+	resolution = entries
+	sineTable = make([]float64, entries)
+	sineByteTable = make([]uint8, entries)
+	falloffTable = make([]float64, entries+1)
+
+	for index := 0; index < entries; index++ {
+		turns := float64(index) / float64(entries)
+		sineTable[index] = math.Sin(turns * 2 * math.Pi)
+		sineByteTable[index] = uint8((sineTable[index] + 1) / 2 * 255)
+	}
+
+	floor := math.Exp(-falloffSharpness)
+	for index := 0; index <= entries; index++ {
+		fraction := float64(index) / float64(entries)
+		falloffTable[index] = (math.Exp(-falloffSharpness*fraction) - floor) / (1 - floor)
+	}
+	return nil
+}
+
+// Entries in the sine and falloff tables
+func Resolution() int {
+	return resolution
+}
+
+// Index into a table of one turn for turns, wrapping around
+func turnIndex(turns float64) int {
+	turns -= math.Floor(turns)
+	return int(turns*float64(resolution)) % resolution
+}
+
+// sin(turns * 2 pi)
+func Sine(turns float64) float64 {
+	return sineTable[turnIndex(turns)]
+}
+
+// (sin(turns * 2 pi) + 1) / 2 scaled from 0 to 255
+func SineByte(turns float64) uint8 {
+	return sineByteTable[turnIndex(turns)]
+}
+
+// Exponential falloff from 1 at fraction 0 to 0 at fraction 1, clamped outside that range
+func Falloff(fraction float64) float64 {
+	if fraction <= 0 {
+		return 1
+	}
+	if fraction >= 1 {
+		return 0
+	}
+	return falloffTable[int(fraction*float64(resolution))]
+}
+
+// Gamma corrected 7 bit brightness of an 8 bit channel
+func Gamma(value uint8) uint8 {
+	return gammaTable[value]
+}
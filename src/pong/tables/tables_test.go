@@ -0,0 +1,88 @@
+package tables
+
+import (
+	"math"
+	"testing"
+)
+
+// Lookups should stay close to the functions they replace
+func Test_Tables(t *testing.T) {
+	for _, turns := range []float64{0, 0.1, 0.25, 0.5, 0.8, 1.3, -0.25} {
+		if math.Abs(Sine(turns)-math.Sin(turns*2*math.Pi)) > 0.01 {
+			t.Fatal("Sine of", turns, "was", Sine(turns))
+		}
+	}
+
+	if SineByte(0.25) != 255 || SineByte(0.75) != 0 {
+		t.Fatal("SineByte peaks were", SineByte(0.25), SineByte(0.75))
+	}
+
+	if Falloff(0) != 1 || Falloff(1) != 0 || Falloff(0.5) <= 0 || Falloff(0.5) >= 0.5 {
+		t.Fatal("Falloff curve was", Falloff(0), Falloff(0.5), Falloff(1))
+	}
+
+	// matches the table the strip was originally tuned with
+	for value, expected := range map[uint8]uint8{0: 0, 64: 4, 128: 23, 200: 69, 255: 127} {
+		if Gamma(value) != expected {
+			t.Fatal("Gamma of", value, "was", Gamma(value), "vs expected", expected)
+		}
+	}
+}
+
+// Changing the resolution should rebuild the tables
+func Test_SetResolution(t *testing.T) {
+	defer SetResolution(Resolution())
+
+	if err := SetResolution(64); err != nil {
+		t.Fatal(err)
+	}
+	if Resolution() != 64 || len(sineTable) != 64 || len(falloffTable) != 65 {
+		t.Fatal("Tables weren't rebuilt")
+	}
+	if math.Abs(Sine(0.25)-1) > 0.01 {
+		t.Fatal("Sine at low resolution was", Sine(0.25))
+	}
+
+	for _, entries := range []int{0, -8} {
+		if err := SetResolution(entries); err == nil {
+			t.Fatal("Tables built with", entries, "entries")
+		}
+	}
+	if Resolution() != 64 {
+		t.Fatal("Tables changed by a resolution that was refused")
+	}
+}
+
+// Indexes into a table should wrap whole turns away, as the 256 entry lookups they replaced did
+func Test_TurnIndex(t *testing.T) {
+	defer SetResolution(Resolution())
+
+	SetResolution(256)
+	for turns, expected := range map[float64]int{0: 0, 0.5: 128, 1.25: 64, 0.999: 255, -0.25: 192, 3: 0} {
+		if index := turnIndex(turns); index != expected {
+			t.Fatal("Index of", turns, "was", index, "vs expected", expected)
+		}
+	}
+}
+
+var benchmarkValue float64
+
+func BenchmarkSine(b *testing.B) {
+	for iteration := 0; iteration < b.N; iteration++ {
+		benchmarkValue += Sine(float64(iteration) * 0.001)
+	}
+}
+
+func BenchmarkTurnIndex(b *testing.B) {
+	var index int
+	for iteration := 0; iteration < b.N; iteration++ {
+		index += turnIndex(float64(iteration%200) * 0.01)
+	}
+	benchmarkValue += float64(index)
+}
+
+func BenchmarkMathSin(b *testing.B) {
+	for iteration := 0; iteration < b.N; iteration++ {
+		benchmarkValue += math.Sin(float64(iteration) * 0.001 * 2 * math.Pi)
+	}
+}
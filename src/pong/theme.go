@@ -0,0 +1,225 @@
+package pong
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+)
+
+// Ways the winner of a game can be shown
+const (
+	VictoryFlash     = "flash"
+	VictoryFireworks = "fireworks"
+	VictoryWipe      = "wipe"
+)
+
+// How the paddle of a player is drawn while they hold their button
+const (
+	PaddleSolid   = "solid"
+	PaddlePulse   = "pulse"
+	PaddleRainbow = "rainbow"
+)
+
+// A look shared by every scene, the colors of the two sides, the backgrounds shown while idle and how a win is shown
+type Theme struct {
+	Name string
+
+	// colors of the left and right players when their profiles don't set one
+	LeftColor, RightColor RGBA
+
+	// backgrounds rotated through while idle, the attract backgrounds setting when empty
+	Backgrounds []string
+
+	// color washed over every scene, transparent for none
+	Tint RGBA
+
+	// VictoryFlash, VictoryFireworks or VictoryWipe
+	Victory string
+
+	// effects played at the end of a player who scores a point and of the one who concedes it when their profile
+	// doesn't choose one, empty for none
+	ScoreEffect, ConcedeEffect string
+}
+
+// Themes that can be chosen from the settings or the menu
+var Themes = []Theme{
+	{"classic", RGBA{0, 0, 255, 255}, RGBA{0, 255, 0, 255}, nil, RGBA{}, VictoryFlash, "", ""},
+	{"halloween", RGBA{255, 100, 0, 255}, RGBA{140, 0, 255, 255}, []string{"fire", "noise"}, RGBA{255, 60, 0, 24}, VictoryFireworks, "", "fire"},
+	{"christmas", RGBA{255, 0, 0, 255}, RGBA{0, 200, 0, 255}, []string{"comet", "sinusoid"}, RGBA{255, 255, 255, 12}, VictoryFireworks, "twinkle", ""},
+	{"team", RGBA{0, 60, 160, 255}, RGBA{255, 180, 0, 255}, []string{"hsl", "comet"}, RGBA{}, VictoryFlash, "", ""},
+}
+
+// theme every scene is drawn in, scenes are built on the game loop while the web server reads it
+var theme = Themes[0]
+var themeLock sync.Mutex
+
+// Draw every scene built from now on in the theme called name, returns false if there isn't one
+func UseTheme(name string) bool {
+
+	themeLock.Lock()
+	defer themeLock.Unlock()
+
+	for _, existing := range Themes {
+		if existing.Name == name {
+			theme = existing
+			return true
+		}
+	}
+	return false
+}
+
+// Theme scenes are being drawn in
+func CurrentTheme() Theme {
+	themeLock.Lock()
+	defer themeLock.Unlock()
+	return theme
+}
+
+// Color of the player with profile on the left or right side, the theme's color for that side if the profile doesn't
+// set one
+func (this Theme) PlayerColor(profile PlayerProfile, left bool) RGBA {
+	if color, ok := profile.RGBA(); ok {
+		return color
+	}
+	if left {
+		return this.LeftColor
+	}
+	return this.RightColor
+}
+
+// Way the player with profile is shown winning, the theme's way if the profile doesn't choose one
+func (this Theme) PlayerVictory(profile PlayerProfile) string {
+	switch profile.Victory {
+	case VictoryFlash, VictoryFireworks, VictoryWipe:
+		return profile.Victory
+	}
+	return this.Victory
+}
+
+// Effect played at the end of the player with profile when they score a point, or when they concede one if not scored,
+// the theme's if the profile doesn't choose one
+func (this Theme) GoalEffect(profile PlayerProfile, scored bool) string {
+	if scored && profile.ScoreEffect != "" {
+		return profile.ScoreEffect
+	}
+	if !scored && profile.ConcedeEffect != "" {
+		return profile.ConcedeEffect
+	}
+	if scored {
+		return this.ScoreEffect
+	}
+	return this.ConcedeEffect
+}
+
+// Names of every theme, shown in the menu in their left color
+func ThemeOptions() (options []MenuOption) {
+	themeLock.Lock()
+	defer themeLock.Unlock()
+	for _, theme := range Themes {
+		options = append(options, MenuOption{theme.Name, theme.LeftColor})
+	}
+	return
+}
+
+// Theme pack file, colors written as #rrggbb
+type themePack struct {
+	XMLName     xml.Name `xml:"Theme"`
+	Name        string   `xml:"name,attr"`
+	LeftColor   string   `xml:"left,attr"`
+	RightColor  string   `xml:"right,attr"`
+	Tint        string   `xml:"tint,attr,omitempty"`
+	TintAlpha   uint8    `xml:"tintAlpha,attr,omitempty"`
+	Victory     string   `xml:"victory,attr"`
+	Score       string   `xml:"scoreEffect,attr,omitempty"`
+	Concede     string   `xml:"concedeEffect,attr,omitempty"`
+	Backgrounds []string `xml:"Background"`
+}
+
+// Read the theme in the theme pack file at path
+func LoadThemePack(path string) (Theme, error) {
+	fileData, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+	return parseThemePack(fileData, path)
+}
+
+// Theme in the theme pack fileData, read from the file called path
+func parseThemePack(fileData []byte, path string) (Theme, error) {
+
+	var pack themePack
+	if err := xml.Unmarshal(fileData, &pack); err != nil {
+		return Theme{}, fmt.Errorf("Theme pack %v: %v", path, err)
+	}
+
+	theme := Theme{Name: pack.Name, Backgrounds: pack.Backgrounds, Victory: pack.Victory, ScoreEffect: pack.Score, ConcedeEffect: pack.Concede}
+	if theme.Name == "" {
+		return Theme{}, fmt.Errorf("Theme pack %v has no name", path)
+	}
+	switch theme.Victory {
+	case VictoryFlash, VictoryFireworks, VictoryWipe:
+	default:
+		return Theme{}, fmt.Errorf("Theme %v can't show wins with %q", theme.Name, theme.Victory)
+	}
+	for _, effect := range []string{theme.ScoreEffect, theme.ConcedeEffect} {
+		if effect == "" {
+			continue
+		}
+		if err := CheckEffect(effect); err != nil {
+			return Theme{}, fmt.Errorf("Theme %v: %v", theme.Name, err)
+		}
+	}
+	var err error
+	if theme.LeftColor, err = parseHexColor(pack.LeftColor); err != nil {
+		return Theme{}, fmt.Errorf("Theme %v: %v", theme.Name, err)
+	}
+	if theme.RightColor, err = parseHexColor(pack.RightColor); err != nil {
+		return Theme{}, fmt.Errorf("Theme %v: %v", theme.Name, err)
+	}
+	if pack.Tint != "" {
+		if theme.Tint, err = parseHexColor(pack.Tint); err != nil {
+			return Theme{}, fmt.Errorf("Theme %v: %v", theme.Name, err)
+		}
+		theme.Tint.A = pack.TintAlpha
+	}
+	return theme, nil
+}
+
+// Add every theme pack ending in .theme in dir to the themes, returns the first problem with any of them
+func LoadThemePacks(dir string) error {
+
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.theme"))
+	var firstErr error
+	for _, path := range paths {
+		theme, err := LoadThemePack(path)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		AddTheme(theme)
+	}
+	return firstErr
+}
+
+// Add theme to the themes that can be chosen, replacing the one with the same name, which is redrawn in it from the
+// next scene if it is the current theme
+func AddTheme(added Theme) {
+
+	themeLock.Lock()
+	defer themeLock.Unlock()
+
+	if theme.Name == added.Name {
+		theme = added
+	}
+	for index, existing := range Themes {
+		if existing.Name == added.Name {
+			Themes[index] = added
+			return
+		}
+	}
+	Themes = append(Themes, added)
+}
@@ -0,0 +1,46 @@
+package pong
+
+import (
+	"testing"
+)
+
+// Players without a color should be drawn in the colors of the theme in use
+func Test_Theme(t *testing.T) {
+
+	defer UseTheme("classic")
+	if UseTheme("nonexistent") {
+		t.Fatal("Used a theme that doesn't exist")
+	}
+	if !UseTheme("halloween") || CurrentTheme().Name != "halloween" {
+		t.Fatal("Theme in use is", CurrentTheme().Name)
+	}
+
+	guest := PlayerProfile{Name: "Guest"}
+	colored := PlayerProfile{Name: "Ann", Color: "#ff00ff"}
+	if CurrentTheme().PlayerColor(guest, false) != Themes[1].RightColor {
+		t.Fatal("Guest on the right is", CurrentTheme().PlayerColor(guest, false))
+	}
+	if CurrentTheme().PlayerColor(colored, true) != (RGBA{255, 0, 255, 255}) {
+		t.Fatal("Ann is", CurrentTheme().PlayerColor(colored, true))
+	}
+
+	if CurrentTheme().PlayerVictory(guest) != VictoryFireworks {
+		t.Fatal("Guest wins are shown with", CurrentTheme().PlayerVictory(guest))
+	}
+	if CurrentTheme().PlayerVictory(PlayerProfile{Name: "Ann", Victory: VictoryFlash}) != VictoryFlash {
+		t.Fatal("Profile's choice of victory wasn't used")
+	}
+
+	if CurrentTheme().GoalEffect(guest, false) != "fire" || CurrentTheme().GoalEffect(guest, true) != "" {
+		t.Fatal("Guest goals are shown with", CurrentTheme().GoalEffect(guest, true), CurrentTheme().GoalEffect(guest, false))
+	}
+	if CurrentTheme().GoalEffect(PlayerProfile{Name: "Ann", ScoreEffect: "comet"}, true) != "comet" {
+		t.Fatal("Profile's choice of score effect wasn't used")
+	}
+
+	for _, theme := range Themes {
+		if theme.Victory != VictoryFlash && theme.Victory != VictoryFireworks {
+			t.Fatal(theme.Name, "shows the winner with", theme.Victory)
+		}
+	}
+}
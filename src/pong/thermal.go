@@ -0,0 +1,185 @@
+package pong
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Read the temperature in °C from a sysfs file holding millidegrees, such as the Pi's thermal zone, or the w1_slave
+// file of a DS18B20 one wire sensor ending in t=millidegrees
+func ReadTemperature(path string) (float64, error) {
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	text := strings.TrimSpace(string(data))
+	if strings.Contains(text, "t=") {
+		if strings.Contains(text, "NO") {
+			return 0, fmt.Errorf("Temperature in %v failed its CRC check", path)
+		}
+		text = text[strings.LastIndex(text, "t=")+2:]
+	}
+	millidegrees, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("Temperature in %v: %v", path, err)
+	}
+	return float64(millidegrees) / 1000, nil
+}
+
+// Display that dims the leds and tells the game loop to lower the frame rate as the board or the strip gets hot,
+// a little at first and more the hotter it gets, so an enclosed installation doesn't cook in summer
+type ThermalMonitor struct {
+	dimmed *DimmedDisplay
+	wrappedDisplay
+
+	// temperature files of the board and the strip, empty if there's no sensor on the strip
+	socPath, stripPath string
+
+	// °C at which each starts being throttled and at which it is throttled all the way
+	socWarm, socHot     float64
+	stripWarm, stripHot float64
+
+	// °C a temperature has to fall before the throttling eases off, so it doesn't hunt around a threshold
+	hysteresis float64
+
+	// brightness when throttled all the way
+	minBrightness float64
+
+	lock      sync.Mutex
+	throttle  float64
+	lastError string
+}
+
+var _ ResettableDisplay = &ThermalMonitor{}
+
+// Construct a ThermalMonitor wrapping display, reading the temperatures and thresholds in settings
+func NewThermalMonitor(display Display, settings SettingsData) *ThermalMonitor {
+	return &ThermalMonitor{
+		dimmed:         NewDimmedDisplay(display, 1),
+		wrappedDisplay: wrappedDisplay{display},
+		socPath:        settings.SocTemperaturePath,
+		stripPath:      settings.StripTemperaturePath,
+		socWarm:        settings.SocWarmCelsius,
+		socHot:         settings.SocHotCelsius,
+		stripWarm:      settings.StripWarmCelsius,
+		stripHot:       settings.StripHotCelsius,
+		hysteresis:     settings.ThermalHysteresisCelsius,
+		minBrightness:  settings.ThermalMinBrightness,
+	}
+}
+
+// Fraction of the way from warm to hot celsius is
+func heat(celsius, warm, hot float64) float64 {
+	return math.Max(0, math.Min(1, (celsius-warm)/(hot-warm)))
+}
+
+// Throttle as hard as the hotter of the board at socCelsius and the strip at stripCelsius calls for, NaN if there's
+// no reading of the strip, easing off once they have cooled by the hysteresis
+func (this *ThermalMonitor) Update(socCelsius, stripCelsius float64) {
+
+	target, cooled := heat(socCelsius, this.socWarm, this.socHot), heat(socCelsius+this.hysteresis, this.socWarm, this.socHot)
+	if !math.IsNaN(stripCelsius) {
+		target = math.Max(target, heat(stripCelsius, this.stripWarm, this.stripHot))
+		cooled = math.Max(cooled, heat(stripCelsius+this.hysteresis, this.stripWarm, this.stripHot))
+	}
+
+	this.lock.Lock()
+	previous := this.throttle
+	if target > this.throttle {
+		this.throttle = target
+	} else if cooled < this.throttle {
+		this.throttle = cooled
+	}
+	throttle := this.throttle
+	this.lock.Unlock()
+
+	GameMetrics.Observe("thermal_throttle", throttle)
+	if previous == 0 && throttle > 0 {
+		log.Printf("Board at %.1f°C, strip at %.1f°C, dimming and lowering the frame rate", socCelsius, stripCelsius)
+	} else if previous > 0 && throttle == 0 {
+		log.Printf("Board at %.1f°C, strip at %.1f°C, no longer throttled", socCelsius, stripCelsius)
+	}
+}
+
+// Read the temperatures every interval forever, run as a goroutine
+func (this *ThermalMonitor) Watch(interval time.Duration) {
+	for _ = range time.Tick(interval) {
+		this.read()
+	}
+}
+
+// Read the temperatures and update the throttling, skipping the update if the board can't be read
+func (this *ThermalMonitor) read() {
+
+	soc, err := ReadTemperature(this.socPath)
+	if err != nil {
+		this.reportError(err)
+		return
+	}
+	GameMetrics.Observe("soc_celsius", soc)
+
+	strip := math.NaN()
+	if this.stripPath != "" {
+		if strip, err = ReadTemperature(this.stripPath); err != nil {
+			this.reportError(err)
+			strip = math.NaN()
+		} else {
+			GameMetrics.Observe("strip_celsius", strip)
+		}
+	}
+	if err == nil {
+		this.reportError(nil)
+	}
+
+	this.Update(soc, strip)
+}
+
+// Log err if it is different to the last one, so a missing sensor doesn't fill the log
+func (this *ThermalMonitor) reportError(err error) {
+
+	message := ""
+	if err != nil {
+		message = err.Error()
+	}
+
+	this.lock.Lock()
+	changed := message != this.lastError
+	this.lastError = message
+	this.lock.Unlock()
+
+	if changed && err != nil {
+		log.Print("Reading the temperature: ", err)
+	}
+}
+
+// How hard the game is throttled, from 0 when it is cool enough to 1 when throttled all the way
+func (this *ThermalMonitor) Throttle() float64 {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.throttle
+}
+
+// Highest frame rate allowed at the current temperature, from maxFPS when cool down to minFPS
+func (this *ThermalMonitor) FPSLimit(minFPS, maxFPS float64) float64 {
+	return maxFPS - this.Throttle()*(maxFPS-minFPS)
+}
+
+// Scale the colorData by the brightness allowed at the current temperature and render it to the wrapped display
+func (this *ThermalMonitor) Render(colorData []RGBA) {
+
+	throttle := this.Throttle()
+	if throttle == 0 {
+		this.display.Render(colorData)
+		return
+	}
+	this.dimmed.SetBrightness(1 - throttle*(1-this.minBrightness))
+	this.dimmed.Render(colorData)
+}
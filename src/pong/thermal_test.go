@@ -0,0 +1,65 @@
+package pong
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Temperatures should be read from sysfs and one wire files, and the game throttled harder the hotter it gets
+func Test_ThermalMonitor(t *testing.T) {
+
+	directory, err := ioutil.TempDir("", "thermal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	socPath := filepath.Join(directory, "temp")
+	ioutil.WriteFile(socPath, []byte("48312\n"), 0644)
+	if celsius, err := ReadTemperature(socPath); err != nil || celsius != 48.312 {
+		t.Fatal("Board temperature read as", celsius, err)
+	}
+	stripPath := filepath.Join(directory, "w1_slave")
+	ioutil.WriteFile(stripPath, []byte("72 01 4b 46 7f ff 0e 10 57 : crc=57 YES\n72 01 4b 46 7f ff 0e 10 57 t=23125\n"), 0644)
+	if celsius, err := ReadTemperature(stripPath); err != nil || celsius != 23.125 {
+		t.Fatal("Strip temperature read as", celsius, err)
+	}
+	ioutil.WriteFile(stripPath, []byte("72 01 4b 46 7f ff 0e 10 57 : crc=00 NO\n72 01 4b 46 7f ff 0e 10 57 t=23125\n"), 0644)
+	if _, err := ReadTemperature(stripPath); err == nil {
+		t.Fatal("Temperature that failed its CRC check was read")
+	}
+
+	display := &lastFrameDisplay{}
+	thermal := NewThermalMonitor(display, SettingsData{SocWarmCelsius: 70, SocHotCelsius: 80, StripWarmCelsius: 50,
+		StripHotCelsius: 60, ThermalHysteresisCelsius: 2, ThermalMinBrightness: 0.5})
+
+	thermal.Update(60, math.NaN())
+	if thermal.Throttle() != 0 || thermal.FPSLimit(30, 60) != 60 {
+		t.Fatal("Throttled while cool")
+	}
+	thermal.Update(75, math.NaN())
+	if thermal.Throttle() != 0.5 || thermal.FPSLimit(30, 60) != 45 {
+		t.Fatal("Throttled", thermal.Throttle(), "halfway to hot")
+	}
+	thermal.Update(74, math.NaN())
+	if thermal.Throttle() != 0.5 {
+		t.Fatal("Throttle eased off to", thermal.Throttle(), "before cooling by the hysteresis")
+	}
+	thermal.Update(72, 60)
+	if thermal.Throttle() != 1 {
+		t.Fatal("Hot strip throttled", thermal.Throttle())
+	}
+
+	thermal.Render([]RGBA{{200, 100, 0, 255}})
+	if display.frame[0] != (RGBA{100, 50, 0, 255}) {
+		t.Fatal("Hot display rendered", display.frame[0])
+	}
+
+	thermal.Update(40, 40)
+	if thermal.Throttle() != 0 {
+		t.Fatal("Still throttled", thermal.Throttle(), "once cool")
+	}
+}
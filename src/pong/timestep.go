@@ -0,0 +1,80 @@
+package pong
+
+import (
+	"math"
+)
+
+// Most steps run in one frame, time beyond them is dropped so a slow frame can't snowball into slower ones
+var maxStepsPerFrame int = 8
+
+// Splits the time between frames into fixed steps, so the game plays the same at any frame rate
+type FixedTimestep struct {
+
+	// seconds in each step, 0 to take a single step of whatever time passed
+	step float64
+
+	// seconds passed that haven't been stepped through yet
+	accumulated float64
+
+	// size of the steps returned by the last Advance
+	lastStep float64
+
+	// most steps returned by a single Advance
+	maxSteps int
+}
+
+// Construct a FixedTimestep taking hz steps every second, hz of 0 steps once per frame
+func NewFixedTimestep(hz float64) *FixedTimestep {
+
+	this := &FixedTimestep{maxSteps: maxStepsPerFrame}
+	if hz > 0 {
+		this.step = 1 / hz
+	}
+	return this
+}
+
+// Move time forward by dt, returns the number of steps to run, each Step seconds long
+func (this *FixedTimestep) Advance(dt float64) (steps int) {
+
+	if this.step <= 0 {
+		this.lastStep = dt
+		if dt <= 0 {
+			return 0
+		}
+		return 1
+	}
+
+	this.lastStep = this.step
+	this.accumulated += dt
+	steps = int(this.accumulated / this.step)
+	this.accumulated -= float64(steps) * this.step
+
+	if steps > this.maxSteps {
+		steps = this.maxSteps
+	}
+	return steps
+}
+
+// Allow scale times as many steps in a frame, for when game time runs faster than wall clock time
+func (this *FixedTimestep) ScaleMaxSteps(scale float64) {
+	this.maxSteps = int(math.Ceil(float64(maxStepsPerFrame) * math.Max(scale, 1)))
+}
+
+// Seconds in each step returned by the last Advance
+func (this *FixedTimestep) Step() float64 {
+	return this.lastStep
+}
+
+// How far time has moved past the last step, from 0 to 1 of the way to the next one
+func (this *FixedTimestep) Alpha() float64 {
+
+	if this.step <= 0 {
+		return 1
+	}
+	return this.accumulated / this.step
+}
+
+// Forget any time left over, for when the game starts over
+func (this *FixedTimestep) Reset() {
+	this.accumulated = 0
+}
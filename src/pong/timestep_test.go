@@ -0,0 +1,63 @@
+package pong
+
+import (
+	"testing"
+)
+
+// The same game time should take the same number of steps at any frame rate
+func Test_FixedTimestep(t *testing.T) {
+
+	for _, fps := range []float64{30, 60, 144, 200} {
+		timestep := NewFixedTimestep(120)
+
+		totalSteps := 0
+		for frame := 0; frame < int(fps); frame++ {
+			totalSteps += timestep.Advance(1 / fps)
+			if alpha := timestep.Alpha(); alpha < 0 || alpha >= 1 {
+				t.Fatal("Alpha out of range at", fps, "FPS:", alpha)
+			}
+		}
+
+		if totalSteps < 119 || totalSteps > 120 {
+			t.Fatal("Took", totalSteps, "steps in one second at", fps, "FPS")
+		}
+	}
+}
+
+// Without a fixed rate a single step of the whole frame should be taken
+func Test_FixedTimestep_Variable(t *testing.T) {
+	timestep := NewFixedTimestep(0)
+
+	Assert(timestep.Advance(0.02), 1, "Steps", t)
+	if timestep.Step() != 0.02 || timestep.Alpha() != 1 {
+		t.Fatal("Step", timestep.Step(), "alpha", timestep.Alpha())
+	}
+	Assert(timestep.Advance(0), 0, "Steps while paused", t)
+}
+
+// Scaling game time should change how many steps run each frame, but never how long each step is
+func Test_FixedTimestep_TimeScale(t *testing.T) {
+
+	for _, scale := range []float64{0.1, 1, 10} {
+		clock := NewGameClock()
+		clock.SetScale(scale)
+		timestep := NewFixedTimestep(120)
+		timestep.ScaleMaxSteps(clock.Scale())
+
+		// a second of game time at 60 frames a second of wall time
+		steps := 0
+		for frame := 0; frame < int(60/scale); frame++ {
+			steps += timestep.Advance(clock.Advance(1.0 / 60))
+			if timestep.Step() != 1.0/120 {
+				t.Fatal("Step of", timestep.Step(), "at scale", scale)
+			}
+		}
+		AssertClose(steps, 120, "Steps in a second of game time", t)
+	}
+
+	clock := NewGameClock()
+	clock.SetScale(100)
+	if clock.Scale() != maxTimeScale {
+		t.Fatal("Scale not limited, was", clock.Scale())
+	}
+}
@@ -0,0 +1,266 @@
+package pong
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/brandonagr/pongpi/src/pong/atomicfile"
+)
+
+// A single match of a tournament, players are empty until the matches before decide them
+type TournamentMatch struct {
+	Left   string `xml:"left,attr"`
+	Right  string `xml:"right,attr"`
+	Winner string `xml:"winner,attr,omitempty"`
+}
+
+// Matches of a tournament played at the same stage, two matches feed each match of the next round
+type TournamentRound struct {
+	Matches []*TournamentMatch `xml:"Match"`
+}
+
+// Single elimination bracket played match by match, persisted to a file, empty when no tournament is running
+type Tournament struct {
+	XMLName xml.Name          `xml:"Tournament"`
+	Players []string          `xml:"Player"`
+	Rounds  []TournamentRound `xml:"Round"`
+
+	// file the tournament is saved to
+	path string
+
+	// the web server starts tournaments while the game plays them
+	lock sync.Mutex
+}
+
+// Load the tournament saved at path, empty if the file doesn't exist yet
+func LoadTournament(path string) *Tournament {
+
+	tournament := &Tournament{path: path}
+
+	fileData, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Print(err)
+		}
+		return tournament
+	}
+	if err := xml.Unmarshal(fileData, tournament); err != nil {
+		log.Print(err)
+	}
+
+	return tournament
+}
+
+// Write the tournament to the file it was loaded from
+func (this *Tournament) Save() error {
+
+	this.lock.Lock()
+	fileData, err := xml.MarshalIndent(this, "", "\t")
+	this.lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(this.path, fileData, 0666)
+}
+
+// Order seeds are placed in a bracket of size, so the best seeds meet as late as possible
+func seedOrder(size int) []int {
+
+	order := []int{1}
+	for len(order) < size {
+		next := make([]int, 0, len(order)*2)
+		for _, seed := range order {
+			next = append(next, seed, len(order)*2+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// Start a new tournament between players, best seed first, replacing any tournament already running
+func (this *Tournament) Start(players []string) error {
+
+	if len(players) < 2 {
+		return errors.New("A tournament needs at least two players")
+	}
+	seen := map[string]bool{}
+	for _, player := range players {
+		if player == "" || seen[player] {
+			return errors.New("Every player in a tournament needs a different name")
+		}
+		seen[player] = true
+	}
+
+	size := 1
+	for size < len(players) {
+		size *= 2
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.Players = append([]string{}, players...)
+	this.Rounds = nil
+	for matches := size / 2; matches >= 1; matches /= 2 {
+		round := TournamentRound{}
+		for index := 0; index < matches; index++ {
+			round.Matches = append(round.Matches, &TournamentMatch{})
+		}
+		this.Rounds = append(this.Rounds, round)
+	}
+
+	// seeds past the number of players are byes, which the player they are drawn against gets through
+	seeds := seedOrder(size)
+	for index, match := range this.Rounds[0].Matches {
+		if left := seeds[index*2]; left <= len(players) {
+			match.Left = players[left-1]
+		}
+		if right := seeds[index*2+1]; right <= len(players) {
+			match.Right = players[right-1]
+		}
+		if match.Left == "" || match.Right == "" {
+			this.advance(0, index, match.Left+match.Right)
+		}
+	}
+
+	return nil
+}
+
+// End the tournament
+func (this *Tournament) Cancel() {
+	this.lock.Lock()
+	this.Players = nil
+	this.Rounds = nil
+	this.lock.Unlock()
+}
+
+// Set the winner of a match and move them into the next round, lock must be held
+func (this *Tournament) advance(round, index int, winner string) {
+
+	this.Rounds[round].Matches[index].Winner = winner
+	if round+1 == len(this.Rounds) {
+		return
+	}
+
+	next := this.Rounds[round+1].Matches[index/2]
+	if index%2 == 0 {
+		next.Left = winner
+	} else {
+		next.Right = winner
+	}
+}
+
+// Round and index of the next match to play, false if there isn't one, lock must be held
+func (this *Tournament) next() (round, index int, ok bool) {
+
+	for round := range this.Rounds {
+		for index, match := range this.Rounds[round].Matches {
+			if match.Winner == "" && match.Left != "" && match.Right != "" {
+				return round, index, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// Players of the next match to play, false if no tournament is running or it is over
+func (this *Tournament) Next() (left, right string, ok bool) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	round, index, ok := this.next()
+	if !ok {
+		return "", "", false
+	}
+	match := this.Rounds[round].Matches[index]
+	return match.Left, match.Right, true
+}
+
+// Record winner of the next match, returns true if they won the tournament
+func (this *Tournament) RecordWinner(winner string) (champion bool, err error) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	round, index, ok := this.next()
+	if !ok {
+		return false, errors.New("No tournament match is being played")
+	}
+	match := this.Rounds[round].Matches[index]
+	if winner != match.Left && winner != match.Right {
+		return false, errors.New(winner + " isn't playing in the next tournament match")
+	}
+
+	this.advance(round, index, winner)
+	return round+1 == len(this.Rounds), nil
+}
+
+// Winner of the tournament, false if it isn't over
+func (this *Tournament) Champion() (string, bool) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if len(this.Rounds) == 0 {
+		return "", false
+	}
+	final := this.Rounds[len(this.Rounds)-1].Matches[0]
+	return final.Winner, final.Winner != ""
+}
+
+// Serve the bracket and the next match as json, POST players separated by commas to start a tournament, or DELETE to
+// end it, which are served behind AdminMethodsOnly so only the admin can start or end one
+func (this *Tournament) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	switch r.Method {
+	case "POST":
+		players := []string{}
+		for _, player := range strings.Split(r.FormValue("players"), ",") {
+			players = append(players, strings.TrimSpace(player))
+		}
+		if err := this.Start(players); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Print("Started a tournament between ", strings.Join(players, ", "))
+	case "DELETE":
+		this.Cancel()
+		log.Print("Tournament cancelled")
+	}
+	if r.Method != "GET" {
+		if err := this.Save(); err != nil {
+			log.Print(err)
+		}
+	}
+
+	this.lock.Lock()
+	served := struct {
+		Players []string
+		Rounds  [][]TournamentMatch
+		Next    *TournamentMatch
+	}{Players: this.Players}
+	if round, index, ok := this.next(); ok {
+		next := *this.Rounds[round].Matches[index]
+		served.Next = &next
+	}
+	for _, round := range this.Rounds {
+		matches := []TournamentMatch{}
+		for _, match := range round.Matches {
+			matches = append(matches, *match)
+		}
+		served.Rounds = append(served.Rounds, matches)
+	}
+	this.lock.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(served)
+}
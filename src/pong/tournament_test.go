@@ -0,0 +1,50 @@
+package pong
+
+import (
+	"testing"
+)
+
+// Top seeds should get byes, and winners should move through the bracket until there is a champion
+func Test_Tournament(t *testing.T) {
+	tournament := &Tournament{}
+
+	if err := tournament.Start([]string{"Ann"}); err == nil {
+		t.Fatal("Started a tournament with one player")
+	}
+	if err := tournament.Start([]string{"Ann", "Bob", "Cat", "Dan", "Eve"}); err != nil {
+		t.Fatal(err)
+	}
+	Assert(len(tournament.Rounds), 3, "Rounds", t)
+
+	played := []string{}
+	for {
+		left, right, ok := tournament.Next()
+		if !ok {
+			break
+		}
+		played = append(played, left+" v "+right)
+
+		// the better seed always wins
+		champion, err := tournament.RecordWinner(left)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if champion != (len(played) == 4) {
+			t.Fatal("Champion after", played)
+		}
+	}
+
+	expected := []string{"Dan v Eve", "Ann v Dan", "Bob v Cat", "Ann v Bob"}
+	Assert(len(played), len(expected), "Matches played", t)
+	for index := range expected {
+		if played[index] != expected[index] {
+			t.Fatal("Played", played)
+		}
+	}
+	if champion, ok := tournament.Champion(); !ok || champion != "Ann" {
+		t.Fatal("Champion was", champion)
+	}
+	if _, err := tournament.RecordWinner("Ann"); err == nil {
+		t.Fatal("Recorded a match after the tournament was over")
+	}
+}
@@ -0,0 +1,84 @@
+package pong
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Transforms that can be listed in a display transform, each flipping the frame. Mirror flips a strip end to end and
+// is the same as mirrorx, rotate180 is both mirrors at once
+const (
+	TransformMirror    = "mirror"
+	TransformMirrorX   = "mirrorx"
+	TransformMirrorY   = "mirrory"
+	TransformRotate180 = "rotate180"
+)
+
+// Display of a panel or strip mounted flipped, flipping frames before they are wired so the game doesn't have to know
+type TransformDisplay struct {
+	wrappedDisplay
+
+	// leds in each row, rows in the frame, and which ways the frame is flipped
+	width, height    int
+	mirrorX, mirrorY bool
+
+	flippedData []RGBA
+}
+
+var _ ResettableDisplay = &TransformDisplay{}
+
+// Construct a TransformDisplay wrapping the display of height rows of width leds, flipping frames by the transforms
+// separated by spaces in transform, such as "rotate180" or "mirrorx"
+func NewTransformDisplay(display Display, width, height int, transform string) (*TransformDisplay, error) {
+
+	transformed := &TransformDisplay{
+		wrappedDisplay: wrappedDisplay{display},
+		width:          width,
+		height:         height,
+		flippedData:    make([]RGBA, width*height),
+	}
+	for _, name := range strings.Fields(strings.ToLower(transform)) {
+		switch name {
+		case TransformMirror, TransformMirrorX:
+			transformed.mirrorX = !transformed.mirrorX
+		case TransformMirrorY:
+			transformed.mirrorY = !transformed.mirrorY
+		case TransformRotate180:
+			transformed.mirrorX = !transformed.mirrorX
+			transformed.mirrorY = !transformed.mirrorY
+		default:
+			return nil, fmt.Errorf("Display transform %q isn't %v, %v, %v or %v", name, TransformMirror, TransformMirrorX, TransformMirrorY, TransformRotate180)
+		}
+	}
+	return transformed, nil
+}
+
+// If the frame is flipped at all
+func (this *TransformDisplay) Flipped() bool {
+	return this.mirrorX || this.mirrorY
+}
+
+// Flip colorData and render it to the wrapped display
+func (this *TransformDisplay) Render(colorData []RGBA) {
+
+	if !this.Flipped() || len(colorData) != len(this.flippedData) {
+		this.display.Render(colorData)
+		return
+	}
+
+	for y := 0; y < this.height; y++ {
+		fromY := y
+		if this.mirrorY {
+			fromY = this.height - 1 - y
+		}
+		for x := 0; x < this.width; x++ {
+			fromX := x
+			if this.mirrorX {
+				fromX = this.width - 1 - x
+			}
+			this.flippedData[y*this.width+x] = colorData[fromY*this.width+fromX]
+		}
+	}
+
+	this.display.Render(this.flippedData)
+}
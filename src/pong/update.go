@@ -0,0 +1,330 @@
+package pong
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brandonagr/pongpi/src/pong/atomicfile"
+)
+
+// Kinds of asset an update can install
+const (
+	AssetTheme  = "theme"
+	AssetSound  = "sound"
+	AssetEffect = "effect"
+)
+
+// Largest manifest or asset downloaded, so a broken server can't fill the card
+var maxAssetBytes int64 = 16 << 20
+
+// List of the assets an installation should have, signed as a whole so every file can be checked against its hash
+type AssetManifest struct {
+
+	// raised with every release of the assets, older manifests are refused so an old release can't be replayed
+	Version int
+
+	Assets []Asset
+}
+
+// A file to install, name being its file name in the directory of its kind
+type Asset struct {
+	Kind   string
+	Name   string
+	URL    string
+	SHA256 string
+}
+
+// Version of the last manifest installed, saved so a restart doesn't accept an older one
+type updateState struct {
+	XMLName xml.Name `xml:"UpdateState"`
+	Version int
+}
+
+// Fetches theme packs, sounds and effect scripts listed in a signed manifest and installs the ones that changed, so
+// installations get new visuals without anyone logging in to them
+type AssetUpdater struct {
+
+	// manifest, with its ed25519 signature in base64 at the same url ending in .sig
+	url       string
+	publicKey ed25519.PublicKey
+
+	// directories each kind of asset is installed into
+	dirs map[string]string
+
+	// file the version of the last manifest installed is saved to
+	statePath string
+
+	client *http.Client
+
+	// updates run from the timer and from the web page
+	lock      sync.Mutex
+	state     updateState
+	lastCheck time.Time
+	installed []string
+	lastError string
+}
+
+// Construct an AssetUpdater for the manifest at the url in settings signed by the key in settings
+func NewAssetUpdater(settings SettingsData) (*AssetUpdater, error) {
+
+	key, err := parseUpdateKey(settings.UpdatePublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(settings.UpdateURL, "https://") {
+		log.Print("Fetching updates from ", settings.UpdateURL, " without https")
+	}
+
+	this := &AssetUpdater{
+		url:       settings.UpdateURL,
+		publicKey: key,
+		dirs: map[string]string{
+			AssetTheme:  settings.ThemesDir,
+			AssetSound:  filepath.Dir(string(GAMESTART)),
+			AssetEffect: settings.EffectScriptsDir,
+		},
+		statePath: settings.UpdateStatePath,
+		client:    &http.Client{Timeout: 60 * time.Second},
+	}
+
+	fileData, err := ioutil.ReadFile(this.statePath)
+	if err == nil {
+		err = xml.Unmarshal(fileData, &this.state)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		log.Print(err)
+	}
+	return this, nil
+}
+
+// Public key written in base64 that manifests are signed with
+func parseUpdateKey(text string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(text)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("UpdatePublicKey isn't a base64 ed25519 public key of %v bytes", ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// Download url, up to maxAssetBytes
+func (this *AssetUpdater) fetch(url string) ([]byte, error) {
+
+	response, err := this.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%v answered %v", url, response.Status)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(response.Body, maxAssetBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxAssetBytes {
+		return nil, fmt.Errorf("%v is larger than %v bytes", url, maxAssetBytes)
+	}
+	return data, nil
+}
+
+// Fetch the manifest and check its signature and version
+func (this *AssetUpdater) manifest() (AssetManifest, error) {
+
+	var manifest AssetManifest
+	data, err := this.fetch(this.url)
+	if err != nil {
+		return manifest, err
+	}
+	encoded, err := this.fetch(this.url + ".sig")
+	if err != nil {
+		return manifest, err
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil || !ed25519.Verify(this.publicKey, data, signature) {
+		return manifest, fmt.Errorf("Signature of %v doesn't match the update key", this.url)
+	}
+
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("Manifest %v: %v", this.url, err)
+	}
+	if manifest.Version < this.state.Version {
+		return manifest, fmt.Errorf("Manifest %v is version %v, older than the installed %v", this.url, manifest.Version, this.state.Version)
+	}
+	return manifest, nil
+}
+
+// Path asset is installed to, error if its kind isn't known or its name could reach outside the directory
+func (this *AssetUpdater) path(asset Asset) (string, error) {
+
+	extensions := map[string]string{AssetTheme: ".theme", AssetSound: ".wav", AssetEffect: ".fx"}
+	extension, ok := extensions[asset.Kind]
+	if !ok {
+		return "", fmt.Errorf("Asset %v is a %q, not a %v, %v or %v", asset.Name, asset.Kind, AssetTheme, AssetSound, AssetEffect)
+	}
+	if asset.Name != filepath.Base(asset.Name) || strings.HasPrefix(asset.Name, ".") || filepath.Ext(asset.Name) != extension {
+		return "", fmt.Errorf("Asset %q has to be a file name ending in %v", asset.Name, extension)
+	}
+	return filepath.Join(this.dirs[asset.Kind], asset.Name), nil
+}
+
+// Download asset and replace the file at path with it, unless it already has the same hash
+func (this *AssetUpdater) install(asset Asset, path string) (bool, error) {
+
+	if existing, err := ioutil.ReadFile(path); err == nil && sha256Hex(existing) == strings.ToLower(asset.SHA256) {
+		return false, nil
+	}
+
+	data, err := this.fetch(asset.URL)
+	if err != nil {
+		return false, err
+	}
+	if sha256Hex(data) != strings.ToLower(asset.SHA256) {
+		return false, fmt.Errorf("Asset %v doesn't match the hash in the manifest", asset.Name)
+	}
+	if asset.Kind == AssetTheme {
+		if _, err := parseThemePack(data, asset.Name); err != nil {
+			return false, err
+		}
+	}
+
+	// written next to the old file and renamed over it, so a sound or script is never read half written
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	if err := atomicfile.Write(path, data, 0644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Hash of data written as hex
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Fetch the manifest and install every asset that changed, loading new theme packs so they can be chosen straight
+// away, returns the names of the assets installed. Effect scripts are read again by the effects showing them and
+// sounds the next time they are played
+func (this *AssetUpdater) Update() ([]string, error) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	installed, err := this.update()
+	this.lastCheck = time.Now()
+	if len(installed) > 0 {
+		this.installed = installed
+	}
+	this.lastError = ""
+	if err != nil {
+		this.lastError = err.Error()
+	}
+	return installed, err
+}
+
+// Update with the lock held
+func (this *AssetUpdater) update() (installed []string, err error) {
+
+	manifest, err := this.manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	// every name is checked before anything is downloaded, so a bad manifest doesn't install part of a release
+	paths := make([]string, len(manifest.Assets))
+	for index, asset := range manifest.Assets {
+		if paths[index], err = this.path(asset); err != nil {
+			return nil, err
+		}
+	}
+
+	for index, asset := range manifest.Assets {
+		changed, err := this.install(asset, paths[index])
+		if err != nil {
+			return installed, err
+		}
+		if !changed {
+			continue
+		}
+		installed = append(installed, asset.Name)
+		if asset.Kind == AssetTheme {
+			theme, err := LoadThemePack(paths[index])
+			if err != nil {
+				return installed, err
+			}
+			AddTheme(theme)
+		}
+	}
+
+	if manifest.Version != this.state.Version {
+		this.state.Version = manifest.Version
+		fileData, err := xml.MarshalIndent(this.state, "", "\t")
+		if err == nil {
+			err = atomicfile.Write(this.statePath, fileData, 0666)
+		}
+		if err != nil {
+			return installed, err
+		}
+	}
+	return installed, nil
+}
+
+// Check for updates every interval until the process exits, run as a goroutine
+func (this *AssetUpdater) Run(interval time.Duration) {
+	for {
+		installed, err := this.Update()
+		if err != nil {
+			log.Print("Updating assets: ", err)
+		}
+		if len(installed) > 0 {
+			log.Print("Installed ", strings.Join(installed, ", "))
+		}
+		time.Sleep(interval)
+	}
+}
+
+type updateDescription struct {
+	URL       string
+	Version   int
+	LastCheck time.Time
+	Installed []string
+	Error     string `json:",omitempty"`
+}
+
+// Serve the version installed and what the last check found as json, POST to check for updates now, which is served
+// behind AdminMethodsOnly so only the admin can force an install
+func (this *AssetUpdater) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method == "POST" {
+		installed, err := this.Update()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(installed) > 0 {
+			log.Print("Installed ", strings.Join(installed, ", "))
+		}
+	}
+
+	this.lock.Lock()
+	description := updateDescription{this.url, this.state.Version, this.lastCheck, this.installed, this.lastError}
+	this.lock.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(description)
+}
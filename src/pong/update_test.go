@@ -0,0 +1,120 @@
+package pong
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Assets in a signed manifest should be installed once, and manifests that are unsigned, old or reach outside their
+// directories refused
+func Test_AssetUpdater(t *testing.T) {
+
+	directory, err := ioutil.TempDir("", "update")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	oldThemes := append([]Theme(nil), Themes...)
+	defer func() { Themes = oldThemes }()
+
+	publicKey, privateKey, _ := ed25519.GenerateKey(nil)
+	files := map[string]string{
+		"/spring.theme": `<Theme name="spring" left="#ff88cc" right="#88ff00" victory="wipe"><Background>twinkle</Background></Theme>`,
+		"/glow.fx":      "r = 1",
+	}
+	downloads := 0
+	var manifest, signature []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/manifest.json":
+			w.Write(manifest)
+		case "/manifest.json.sig":
+			w.Write(signature)
+		default:
+			downloads++
+			w.Write([]byte(files[r.URL.Path]))
+		}
+	}))
+	defer server.Close()
+
+	release := func(version int, assets ...Asset) {
+		manifest, _ = json.Marshal(AssetManifest{version, assets})
+		signature = []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, manifest)))
+	}
+	asset := func(kind, name string) Asset {
+		return Asset{kind, name, server.URL + "/" + name, sha256Hex([]byte(files["/"+name]))}
+	}
+
+	updater, err := NewAssetUpdater(SettingsData{
+		UpdateURL:        server.URL + "/manifest.json",
+		UpdatePublicKey:  base64.StdEncoding.EncodeToString(publicKey),
+		UpdateStatePath:  filepath.Join(directory, "update.xml"),
+		ThemesDir:        filepath.Join(directory, "themes"),
+		EffectScriptsDir: filepath.Join(directory, "effects"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release(2, asset(AssetTheme, "spring.theme"), asset(AssetEffect, "glow.fx"))
+	if installed, err := updater.Update(); err != nil || len(installed) != 2 {
+		t.Fatal("Installed", installed, err)
+	}
+	if script, _ := ioutil.ReadFile(filepath.Join(directory, "effects", "glow.fx")); string(script) != "r = 1" {
+		t.Fatalf("Effect installed as %q", script)
+	}
+	if _, err := os.Stat(filepath.Join(directory, "themes", "glow.fx")); !os.IsNotExist(err) {
+		t.Fatal("Effect installed with the themes", err)
+	}
+	if !UseTheme("spring") || CurrentTheme().LeftColor != (RGBA{255, 136, 204, 255}) {
+		t.Fatal("Installed theme can't be used", CurrentTheme())
+	}
+	UseTheme("classic")
+
+	if installed, err := updater.Update(); err != nil || len(installed) != 0 || downloads != 2 {
+		t.Fatal("Unchanged assets installed again", installed, downloads, err)
+	}
+
+	release(1)
+	if _, err := updater.Update(); err == nil || !strings.Contains(err.Error(), "older") {
+		t.Fatal("Older manifest accepted", err)
+	}
+
+	release(3, Asset{AssetSound, "../../start.wav", server.URL, ""})
+	if _, err := updater.Update(); err == nil {
+		t.Fatal("Asset outside its directory accepted")
+	}
+
+	release(3, asset(AssetEffect, "glow.fx"))
+	manifest = []byte(strings.Replace(string(manifest), `"Version":3`, `"Version":4`, 1))
+	if _, err := updater.Update(); err == nil || !strings.Contains(err.Error(), "Signature") {
+		t.Fatal("Manifest that was changed after signing accepted", err)
+	}
+}
+
+// Theme packs should need a name, colors and a way of showing the winner
+func Test_LoadThemePack(t *testing.T) {
+
+	theme, err := parseThemePack([]byte(`<Theme name="dusk" left="#ff0000" right="#0000ff" tint="#ffffff" tintAlpha="12" victory="flash"/>`), "dusk.theme")
+	if err != nil || theme.Tint != (RGBA{255, 255, 255, 12}) || theme.Victory != VictoryFlash {
+		t.Fatal("Theme pack read as", theme, err)
+	}
+	for _, pack := range []string{
+		`<Theme left="#ff0000" right="#0000ff" victory="flash"/>`,
+		`<Theme name="dusk" left="red" right="#0000ff" victory="flash"/>`,
+		`<Theme name="dusk" left="#ff0000" right="#0000ff" victory="confetti"/>`,
+	} {
+		if _, err := parseThemePack([]byte(pack), "dusk.theme"); err == nil {
+			t.Fatal("Theme pack accepted:", pack)
+		}
+	}
+}
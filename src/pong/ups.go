@@ -0,0 +1,258 @@
+package pong
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Battery gauges of UPS HATs that can be read from the I2C bus
+const (
+	BatteryGaugeMAX17040 = "max17040"
+	BatteryGaugeINA219   = "ina219"
+)
+
+// Measures the battery of a UPS HAT
+type BatteryGauge interface {
+	io.Closer
+
+	// Charge of the battery from 0 to 1, and if it is running the Pi, false if the gauge can't tell
+	Battery() (charge float64, discharging bool, err error)
+}
+
+// Open the battery gauge of kind at address on the I2C bus at busFilePath, 0 uses the usual address of the gauge. An
+// INA219 works out the charge from the voltage of a battery between emptyVolts and fullVolts
+func NewBatteryGauge(kind, busFilePath string, address int, emptyVolts, fullVolts float64) (BatteryGauge, error) {
+
+	switch kind {
+	case BatteryGaugeMAX17040:
+		address = defaultAddress(address, 0x36)
+	case BatteryGaugeINA219:
+		address = defaultAddress(address, 0x42)
+	default:
+		return nil, fmt.Errorf("Battery gauge %q isn't %v or %v", kind, BatteryGaugeMAX17040, BatteryGaugeINA219)
+	}
+
+	device, err := OpenI2cDevice(busFilePath, address)
+	if err != nil {
+		return nil, fmt.Errorf("Battery gauge %v: %v", kind, err)
+	}
+	if kind == BatteryGaugeINA219 {
+		return NewINA219(device, emptyVolts, fullVolts), nil
+	}
+	return NewMAX17040(device), nil
+}
+
+// Read the big endian word in register of device
+func readRegister(device io.ReadWriter, register byte, data []byte) (uint16, error) {
+	if _, err := device.Write([]byte{register}); err != nil {
+		return 0, err
+	}
+	if _, err := io.ReadFull(device, data); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(data), nil
+}
+
+// MAX17040 fuel gauge, as on the Geekworm X728, which knows the charge but not where the power is coming from, that is
+// read from the power loss pin
+type MAX17040 struct {
+	device io.ReadWriteCloser
+	data   []byte
+}
+
+// Construct a MAX17040 on device
+func NewMAX17040(device io.ReadWriteCloser) BatteryGauge {
+	return &MAX17040{device: device, data: make([]byte, 2)}
+}
+
+// State of charge register, whole percent in the high byte and 1/256 of a percent in the low byte
+func (this *MAX17040) Battery() (float64, bool, error) {
+	soc, err := readRegister(this.device, 0x04, this.data)
+	if err != nil {
+		return 0, false, err
+	}
+	return math.Min(float64(soc)/256/100, 1), false, nil
+}
+
+// Close the connection to the gauge
+func (this *MAX17040) Close() error {
+	return this.device.Close()
+}
+
+// INA219 current sensor on the battery, as on the Waveshare UPS HAT, which is discharging while the current through
+// its shunt is negative
+type INA219 struct {
+	device io.ReadWriteCloser
+	data   []byte
+
+	// volts of the battery when empty and when full
+	emptyVolts, fullVolts float64
+}
+
+// Construct an INA219 on device with a battery between emptyVolts and fullVolts
+func NewINA219(device io.ReadWriteCloser, emptyVolts, fullVolts float64) BatteryGauge {
+	return &INA219{device: device, data: make([]byte, 2), emptyVolts: emptyVolts, fullVolts: fullVolts}
+}
+
+// Charge from the bus voltage, 4mV a step from bit 3, and the sign of the shunt voltage
+func (this *INA219) Battery() (float64, bool, error) {
+	shunt, err := readRegister(this.device, 0x01, this.data)
+	if err != nil {
+		return 0, false, err
+	}
+	bus, err := readRegister(this.device, 0x02, this.data)
+	if err != nil {
+		return 0, false, err
+	}
+	volts := float64(bus>>3) * 0.004
+	charge := math.Max(0, math.Min(1, (volts-this.emptyVolts)/(this.fullVolts-this.emptyVolts)))
+	return charge, int16(shunt) < 0, nil
+}
+
+// Close the connection to the gauge
+func (this *INA219) Close() error {
+	return this.device.Close()
+}
+
+// Display that dims the leds while the Pi is running on the battery of a UPS HAT, and tells the game loop to lower the
+// frame rate so the battery lasts until the power comes back
+type UpsMonitor struct {
+	wrappedDisplay
+	dimmed *DimmedDisplay
+
+	// gauge of the battery, nil if there's only the power loss pin
+	gauge BatteryGauge
+
+	// value file of the gpio pin that goes high when the power is lost, empty if there isn't one
+	powerLossPath, powerLossPort string
+
+	// most frames a second while on the battery, which also dims the display
+	batteryFPS float64
+
+	lock      sync.Mutex
+	onBattery bool
+	charge    float64
+	lastError string
+}
+
+var _ ResettableDisplay = &UpsMonitor{}
+
+// Construct an UpsMonitor wrapping display, reading gauge, which can be nil, and the power loss pin in settings
+func NewUpsMonitor(display Display, gauge BatteryGauge, settings SettingsData) *UpsMonitor {
+	return &UpsMonitor{
+		wrappedDisplay: wrappedDisplay{display},
+		dimmed:         NewDimmedDisplay(display, settings.BatteryBrightness),
+		gauge:          gauge,
+		powerLossPath:  settings.UpsPowerLossPath,
+		powerLossPort:  settings.UpsPowerLossGpioPort,
+		batteryFPS:     settings.BatteryFPS,
+		charge:         math.NaN(),
+	}
+}
+
+// Record the charge of the battery, NaN if it isn't known, and if the Pi is running on it
+func (this *UpsMonitor) Update(charge float64, onBattery bool) {
+
+	this.lock.Lock()
+	changed := onBattery != this.onBattery
+	this.charge, this.onBattery = charge, onBattery
+	this.lock.Unlock()
+
+	if !math.IsNaN(charge) {
+		GameMetrics.Observe("battery_charge", charge)
+	}
+	if changed && onBattery {
+		log.Print("Power lost, running on the battery, dimming and lowering the frame rate")
+	} else if changed {
+		log.Print("Power back, no longer on the battery")
+	}
+}
+
+// Read the battery every interval forever, run as a goroutine
+func (this *UpsMonitor) Watch(interval time.Duration) {
+	if this.powerLossPath != "" {
+		if err := exportGpio(this.powerLossPath, this.powerLossPort, "in"); err != nil {
+			log.Print("Power loss pin: ", err)
+		}
+	}
+	for _ = range time.Tick(interval) {
+		this.read()
+	}
+}
+
+// Read the gauge and the power loss pin and update the power saving, keeping the last state if either can't be read
+func (this *UpsMonitor) read() {
+
+	charge, onBattery, err := math.NaN(), false, error(nil)
+	if this.gauge != nil {
+		charge, onBattery, err = this.gauge.Battery()
+	}
+	if err == nil && this.powerLossPath != "" {
+		var data []byte
+		if data, err = ioutil.ReadFile(this.powerLossPath); err == nil {
+			onBattery = onBattery || strings.TrimSpace(string(data)) == "1"
+		}
+	}
+
+	this.reportError(err)
+	if err == nil {
+		this.Update(charge, onBattery)
+	}
+}
+
+// Log err if it is different to the last one, so a missing gauge doesn't fill the log
+func (this *UpsMonitor) reportError(err error) {
+
+	message := ""
+	if err != nil {
+		message = err.Error()
+	}
+
+	this.lock.Lock()
+	changed := message != this.lastError
+	this.lastError = message
+	this.lock.Unlock()
+
+	if changed && err != nil {
+		log.Print("Reading the UPS: ", err)
+	}
+}
+
+// If the Pi is running on the battery, and the charge of the battery from 0 to 1, NaN if it isn't known
+func (this *UpsMonitor) Battery() (onBattery bool, charge float64) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.onBattery, this.charge
+}
+
+// Highest frame rate allowed, maxFPS unless running on the battery
+func (this *UpsMonitor) FPSLimit(maxFPS float64) float64 {
+	if onBattery, _ := this.Battery(); onBattery {
+		return math.Min(maxFPS, this.batteryFPS)
+	}
+	return maxFPS
+}
+
+// Render colorData to the wrapped display, dimmed while running on the battery
+func (this *UpsMonitor) Render(colorData []RGBA) {
+	if onBattery, _ := this.Battery(); onBattery {
+		this.dimmed.Render(colorData)
+		return
+	}
+	this.display.Render(colorData)
+}
+
+// Close the gauge and the wrapped display if it can be
+func (this *UpsMonitor) Close() error {
+	if this.gauge != nil {
+		this.gauge.Close()
+	}
+	return this.wrappedDisplay.Close()
+}
@@ -0,0 +1,66 @@
+package pong
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Gauges should turn their registers into the charge of the battery and if it is discharging
+func Test_BatteryGauges(t *testing.T) {
+
+	// 57.5%
+	device := &fakeI2cDevice{replies: bytes.NewReader([]byte{57, 128})}
+	charge, discharging, err := NewMAX17040(device).Battery()
+	if err != nil || math.Abs(charge-0.575) > 1e-9 || discharging {
+		t.Fatal("MAX17040 read", charge, discharging, err)
+	}
+	if !bytes.Equal(device.written, []byte{0x04}) {
+		t.Fatal("MAX17040 was asked for", device.written)
+	}
+
+	// negative shunt voltage and 3.6V, half way from 3V to 4.2V
+	device = &fakeI2cDevice{replies: bytes.NewReader([]byte{0xff, 0x00, 0x1c, 0x20})}
+	charge, discharging, err = NewINA219(device, 3, 4.2).Battery()
+	if err != nil || math.Abs(charge-0.5) > 1e-9 || !discharging {
+		t.Fatal("INA219 read", charge, discharging, err)
+	}
+}
+
+// Losing the power should dim the display and lower the frame rate until it comes back
+func Test_UpsMonitor(t *testing.T) {
+
+	directory, err := ioutil.TempDir("", "ups")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	powerLoss := filepath.Join(directory, "value")
+	ioutil.WriteFile(powerLoss, []byte("0\n"), 0644)
+
+	output := &lastFrameDisplay{}
+	ups := NewUpsMonitor(output, nil, SettingsData{UpsPowerLossPath: powerLoss, BatteryBrightness: 0.5, BatteryFPS: 30})
+	frame := []RGBA{{200, 100, 0, 255}}
+
+	ups.read()
+	ups.Render(frame)
+	if onBattery, charge := ups.Battery(); onBattery || !math.IsNaN(charge) || output.frame[0] != frame[0] || ups.FPSLimit(60) != 60 {
+		t.Fatal("Dimmed with the power on", output.frame)
+	}
+
+	ioutil.WriteFile(powerLoss, []byte("1\n"), 0644)
+	ups.read()
+	ups.Render(frame)
+	if onBattery, _ := ups.Battery(); !onBattery || output.frame[0].R != 100 || ups.FPSLimit(60) != 30 {
+		t.Fatal("Not saving power on the battery", output.frame, ups.FPSLimit(60))
+	}
+
+	os.Remove(powerLoss)
+	ups.read()
+	if onBattery, _ := ups.Battery(); !onBattery {
+		t.Fatal("Pin that can't be read was taken as the power coming back")
+	}
+}
@@ -0,0 +1,77 @@
+package pong
+
+import (
+	"log"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Watches for the game loop to stop making progress and runs a recovery when it does
+type Watchdog struct {
+
+	// time without a beat before the loop is considered stalled
+	timeout time.Duration
+
+	// time of the last beat and what the loop was doing
+	lock     sync.Mutex
+	lastBeat time.Time
+	activity string
+
+	// if the current stall has already been handled
+	handled bool
+}
+
+// Construct a Watchdog
+func NewWatchdog(timeout time.Duration) *Watchdog {
+	return &Watchdog{
+		timeout:  timeout,
+		lastBeat: time.Now(),
+	}
+}
+
+// Called by the game loop every frame with a description of what it is doing
+func (this *Watchdog) Beat(activity string) {
+	this.lock.Lock()
+	this.lastBeat = time.Now()
+	this.activity = activity
+	this.handled = false
+	this.lock.Unlock()
+}
+
+// Time of the last beat and what the loop was doing then
+func (this *Watchdog) LastBeat() (time.Time, string) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.lastBeat, this.activity
+}
+
+// Check for a stall every interval forever, calling onStall once per stall, run as a goroutine
+func (this *Watchdog) Watch(interval time.Duration, onStall func()) {
+
+	for _ = range time.Tick(interval) {
+
+		this.lock.Lock()
+		stalledFor := time.Since(this.lastBeat)
+		activity := this.activity
+		stalled := stalledFor > this.timeout && !this.handled
+		if stalled {
+			this.handled = true
+		}
+		this.lock.Unlock()
+
+		if stalled {
+			logStall(stalledFor, activity)
+			onStall()
+		}
+	}
+}
+
+// Log what every goroutine is doing to help find what stalled
+func logStall(stalledFor time.Duration, activity string) {
+
+	stack := make([]byte, 64*1024)
+	stack = stack[:runtime.Stack(stack, true)]
+
+	log.Print("Game loop stalled for ", stalledFor, " while ", activity, ", goroutines:\n", string(stack))
+}
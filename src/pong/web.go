@@ -0,0 +1,69 @@
+package pong
+
+import (
+	"crypto/subtle"
+	"expvar"
+	"log"
+	"net/http"
+	_ "net/http/pprof"
+	"runtime"
+	"strings"
+)
+
+// Paths only served with the admin token, net/http/pprof and expvar register their handlers under it
+const adminPrefix = "/debug/"
+
+func init() {
+	expvar.Publish("goroutines", expvar.Func(func() interface{} { return runtime.NumGoroutine() }))
+	expvar.Publish("metrics", expvar.Func(func() interface{} { return GameMetrics.Snapshot() }))
+}
+
+// Run the web server hosting the web display and the stats api, handlers register themselves with http.Handle
+func StartWebServer(address string) {
+
+	log.Print("Server listening on ", address)
+	log.Print(http.ListenAndServe(address, AdminOnly(http.DefaultServeMux, Settings.AdminToken)))
+}
+
+// Wrap handler so paths under /debug/ are only served to requests with token as a bearer token or token parameter,
+// and not at all if token is empty
+func AdminOnly(handler http.Handler, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, adminPrefix) && !authorizeAdmin(w, r, token, adminPrefix) {
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// Wrap handler so requests with any of methods need token like the paths under /debug/, for api endpoints anyone on
+// the network can read but only the admin should change
+func AdminMethodsOnly(handler http.Handler, token string, methods ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, method := range methods {
+			if r.Method == method && !authorizeAdmin(w, r, token, method+" "+r.URL.Path) {
+				return
+			}
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// True if r has token as a bearer token or token parameter, otherwise answers that it is needed for what, or that
+// there's nothing there if token is empty
+func authorizeAdmin(w http.ResponseWriter, r *http.Request, token, what string) bool {
+
+	if token == "" {
+		http.NotFound(w, r)
+		return false
+	}
+	given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if given == "" {
+		given = r.URL.Query().Get("token")
+	}
+	if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		http.Error(w, "The admin token is needed for "+what, http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
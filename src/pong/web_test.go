@@ -0,0 +1,48 @@
+package pong
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Debug endpoints should need the admin token, and be hidden when there isn't one
+func Test_AdminOnly(t *testing.T) {
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	status := func(token, path, authorization string) int {
+		request := httptest.NewRequest("GET", path, nil)
+		if authorization != "" {
+			request.Header.Set("Authorization", "Bearer "+authorization)
+		}
+		response := httptest.NewRecorder()
+		AdminOnly(handler, token).ServeHTTP(response, request)
+		return response.Code
+	}
+
+	Assert(status("", "/api/stats", ""), http.StatusOK, "Api without a token", t)
+	Assert(status("", "/debug/vars", ""), http.StatusNotFound, "Debug without a token", t)
+	Assert(status("secret", "/debug/vars", ""), http.StatusUnauthorized, "Debug without authorization", t)
+	Assert(status("secret", "/debug/vars", "wrong"), http.StatusUnauthorized, "Debug with the wrong token", t)
+	Assert(status("secret", "/debug/vars", "secret"), http.StatusOK, "Debug with the token", t)
+	Assert(status("secret", "/debug/pprof/?token=secret", ""), http.StatusOK, "Debug with the token parameter", t)
+}
+
+// Changes through an endpoint anyone can read should need the admin token, and be refused when there isn't one
+func Test_AdminMethodsOnly(t *testing.T) {
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	status := func(token, method, path string) int {
+		request := httptest.NewRequest(method, path, nil)
+		response := httptest.NewRecorder()
+		AdminMethodsOnly(handler, token, "POST", "DELETE").ServeHTTP(response, request)
+		return response.Code
+	}
+
+	Assert(status("", "GET", "/api/effects"), http.StatusOK, "Reading without a token", t)
+	Assert(status("", "POST", "/api/effects"), http.StatusNotFound, "Changing without a token", t)
+	Assert(status("secret", "DELETE", "/api/effects"), http.StatusUnauthorized, "Changing without authorization", t)
+	Assert(status("secret", "POST", "/api/effects?token=secret"), http.StatusOK, "Changing with the token", t)
+}
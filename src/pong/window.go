@@ -0,0 +1,53 @@
+package pong
+
+import (
+	"fmt"
+	"time"
+)
+
+// Display of a strip the game is only played on part of, showing frames of the game in a window of the strip and an
+// ambient field of its own everywhere else
+type WindowDisplay struct {
+	wrappedDisplay
+
+	// first led of the window, and the field drawn on the whole strip under it
+	offset  int
+	ambient *GameField
+
+	// source of the time the ambient field is animated by, and the time between the frames rendered
+	now   func() time.Time
+	timer FrameTimer
+
+	stripData []RGBA
+}
+
+var _ ResettableDisplay = &WindowDisplay{}
+
+// Construct a WindowDisplay wrapping the display of the whole strip, with the game starting at led offset and ambient
+// drawn on the strip around it
+func NewWindowDisplay(display Display, offset int, ambient *GameField, now func() time.Time) (*WindowDisplay, error) {
+
+	if offset < 0 || offset >= ambient.Width() {
+		return nil, fmt.Errorf("Game window offset %v isn't on the strip of %v leds", offset, ambient.Width())
+	}
+	return &WindowDisplay{
+		wrappedDisplay: wrappedDisplay{display},
+		offset:         offset,
+		ambient:        ambient,
+		now:            now,
+		stripData:      make([]RGBA, ambient.Width()),
+	}, nil
+}
+
+// Animate the ambient field to now, put colorData in the window and render the whole strip to the wrapped display
+func (this *WindowDisplay) Render(colorData []RGBA) {
+
+	if dt := this.timer.Elapsed(this.now()); dt > 0 {
+		this.ambient.Animate(dt)
+	}
+
+	copy(this.stripData, this.ambient.Render())
+	copy(this.stripData[this.offset:], colorData)
+
+	this.display.Render(this.stripData)
+}
@@ -0,0 +1,59 @@
+package pong
+
+import (
+	"testing"
+	"time"
+)
+
+// Lights every led in the amount of time it has been animated for
+type elapsedDrawable struct {
+	elapsed float64
+}
+
+func (this *elapsedDrawable) ColorAt(position float64, baseColor RGBA) RGBA {
+	return RGBA{uint8(this.elapsed), 0, 0, 255}
+}
+
+func (this *elapsedDrawable) ZIndex() ZIndex {
+	return 0
+}
+
+func (this *elapsedDrawable) Animate(dt float64) bool {
+	this.elapsed += dt
+	return true
+}
+
+// The game should be shown in its window and the ambient field, animated by wall clock time, on the rest of the strip
+func Test_WindowDisplay(t *testing.T) {
+
+	now := time.Unix(0, 0)
+	ambient := NewGameField(6)
+	ambient.Add(&elapsedDrawable{})
+	output := &lastFrameDisplay{}
+	window, err := NewWindowDisplay(output, 2, ambient, func() time.Time { return now })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	game := RGBA{0, 0, 255, 255}
+	window.Render([]RGBA{game, game})
+	for frame := 0; frame < 3; frame++ {
+		now = now.Add(time.Second)
+		window.Render([]RGBA{game, game})
+	}
+
+	Assert(len(output.frame), 6, "Leds on the strip", t)
+	ambientColor := RGBA{3, 0, 0, 255}
+	expected := []RGBA{ambientColor, ambientColor, game, game, ambientColor, ambientColor}
+	for led, color := range expected {
+		if output.frame[led] != color {
+			t.Fatal("Strip was", output.frame)
+		}
+	}
+
+	for _, offset := range []int{-1, 6} {
+		if _, err := NewWindowDisplay(output, offset, ambient, time.Now); err == nil {
+			t.Fatal("Made a window at offset", offset)
+		}
+	}
+}
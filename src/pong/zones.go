@@ -0,0 +1,112 @@
+package pong
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Leds from Start to End inclusive, counted along the strip as it is wired, whose colors are scaled by Brightness
+type BrightnessZone struct {
+	Start, End int
+	Brightness float64
+}
+
+// Read zones written as start-end:brightness separated by spaces, such as "0-19:0.4 40-63:1.5"
+func ParseBrightnessZones(zones string) ([]BrightnessZone, error) {
+
+	var parsed []BrightnessZone
+	for _, zone := range strings.Fields(zones) {
+
+		bad := fmt.Errorf("Brightness zone %q isn't start-end:brightness", zone)
+		span := strings.SplitN(zone, ":", 2)
+		if len(span) != 2 {
+			return nil, bad
+		}
+		ends := strings.SplitN(span[0], "-", 2)
+		if len(ends) != 2 {
+			return nil, bad
+		}
+
+		start, startErr := strconv.Atoi(ends[0])
+		end, endErr := strconv.Atoi(ends[1])
+		brightness, brightnessErr := strconv.ParseFloat(span[1], 64)
+		if startErr != nil || endErr != nil || brightnessErr != nil || start < 0 || end < start || brightness < 0 {
+			return nil, bad
+		}
+		parsed = append(parsed, BrightnessZone{start, end, brightness})
+	}
+	return parsed, nil
+}
+
+// Display of a strip running through places lit differently, scaling the brightness of each led by the zone it is in
+type ZonedDisplay struct {
+	wrappedDisplay
+
+	// amount the color of every led is scaled by
+	brightness []float64
+
+	zonedData []RGBA
+}
+
+var _ ResettableDisplay = &ZonedDisplay{}
+
+// Construct a ZonedDisplay wrapping the display of ledCount leds, scaled by brightness for every led, 1 for any that
+// aren't given, then by each of zones in turn
+func NewZonedDisplay(display Display, ledCount int, brightness []float64, zones []BrightnessZone) *ZonedDisplay {
+
+	zoned := &ZonedDisplay{
+		wrappedDisplay: wrappedDisplay{display},
+		brightness:     make([]float64, ledCount),
+		zonedData:      make([]RGBA, ledCount),
+	}
+	for led := range zoned.brightness {
+		zoned.brightness[led] = 1
+		if led < len(brightness) {
+			zoned.brightness[led] = brightness[led]
+		}
+	}
+	for _, zone := range zones {
+		for led := zone.Start; led <= zone.End && led < ledCount; led++ {
+			zoned.brightness[led] *= zone.Brightness
+		}
+	}
+	return zoned
+}
+
+// If any led is shown brighter or dimmer than it is rendered
+func (this *ZonedDisplay) Zoned() bool {
+	for _, brightness := range this.brightness {
+		if brightness != 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// Scale the colorData of each led and render it to the wrapped display
+func (this *ZonedDisplay) Render(colorData []RGBA) {
+
+	if len(colorData) != len(this.zonedData) {
+		this.display.Render(colorData)
+		return
+	}
+
+	for led, color := range colorData {
+		brightness := this.brightness[led]
+		this.zonedData[led] = RGBA{
+			scaleZoneChannel(color.R, brightness),
+			scaleZoneChannel(color.G, brightness),
+			scaleZoneChannel(color.B, brightness),
+			color.A,
+		}
+	}
+
+	this.display.Render(this.zonedData)
+}
+
+// Scale value by amount, which can brighten it as far as full brightness
+func scaleZoneChannel(value uint8, amount float64) uint8 {
+	return uint8(math.Min(255, float64(value)*amount))
+}
@@ -0,0 +1,40 @@
+package pong
+
+import (
+	"testing"
+)
+
+// Zones should be read from the settings, and scale the leds in them by their brightness on top of any from a pixel map
+func Test_ZonedDisplay(t *testing.T) {
+
+	zones, err := ParseBrightnessZones("0-1:0.5  3-9:2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zones) != 2 || zones[1] != (BrightnessZone{3, 9, 2}) {
+		t.Fatal("Zones were read as", zones)
+	}
+	for _, bad := range []string{"0-1", "1:0.5", "3-1:0.5", "0-1:dim", "0-1:-1"} {
+		if _, err := ParseBrightnessZones(bad); err == nil {
+			t.Fatal("Read the zone", bad)
+		}
+	}
+
+	output := &lastFrameDisplay{}
+	zoned := NewZonedDisplay(output, 5, []float64{1, 0.5}, zones)
+	if !zoned.Zoned() {
+		t.Fatal("Zoned display doesn't change any leds")
+	}
+	color := RGBA{200, 100, 0, 255}
+	zoned.Render([]RGBA{color, color, color, color, color})
+	expected := []RGBA{{100, 50, 0, 255}, {50, 25, 0, 255}, color, {255, 200, 0, 255}, {255, 200, 0, 255}}
+	for led, color := range expected {
+		if output.frame[led] != color {
+			t.Fatal("Zoned leds were", output.frame)
+		}
+	}
+
+	if NewZonedDisplay(output, 5, nil, nil).Zoned() {
+		t.Fatal("Display without zones changes leds")
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+// Shade the countdown by how the spectators on the web page think the match will go, if any of them predicted it
+func (this *game) showPredictions(scene *Scene) {
+
+	left, right := this.predictions.Count()
+	if left+right == 0 {
+		return
+	}
+	options, ok := this.tournamentOptions()
+	if !ok {
+		options = this.options
+	}
+	theme := CurrentTheme()
+	leftColor := theme.PlayerColor(options.config.LeftProfile, true)
+	rightColor := theme.PlayerColor(options.config.RightProfile, false)
+	scene.Add(NewCrowdPrediction(scene.Field(), left, right, leftColor, rightColor))
+}
+
+// Score the spectators' predictions against the outcome of the match, returns how many were right to announce, or
+// nothing if nobody predicted it
+func (this *game) resolvePredictions() string {
+
+	if this.outcome != GameLeftWon && this.outcome != GameRightWon {
+		this.predictions.Discard()
+		return ""
+	}
+
+	correct, total := this.predictions.Resolve(this.outcome == GameLeftWon)
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%v of %v spectators called it.", correct, total)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Time between button checks while the render loop is suspended
+var quietPollTime = 100 * time.Millisecond
+
+// Returns true if quiet hours apply right now, a button press wakes the game if allowed
+func (this *game) isQuiet(now time.Time) bool {
+
+	if this.states.Phase() != PhaseIdle || !this.quietHours.Contains(now) || now.Before(this.awakeUntil) {
+		return false
+	}
+
+	if Settings.QuietWakeOnPress && (this.buttons.LeftButton() || this.buttons.RightButton()) {
+		log.Print("Woken up during quiet hours")
+		this.awakeUntil = now.Add(time.Duration(Settings.QuietWakeMinutes * float64(time.Minute)))
+		return false
+	}
+
+	return true
+}
+
+// Blank the strip once and wait before the next button check, instead of rendering
+func (this *game) suspend() {
+
+	if !this.blanked {
+		this.display.Render(make([]RGBA, Settings.Leds()))
+		this.blanked = true
+	}
+
+	time.Sleep(quietPollTime)
+}
+
+// Display the field is rendered to during quiet hours
+func (this *game) quietOutput() Display {
+
+	if this.quietDisplay == nil {
+		this.quietDisplay = NewDimmedDisplay(this.display, Settings.QuietBrightness)
+	}
+
+	return this.quietDisplay
+}
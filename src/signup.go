@@ -0,0 +1,42 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Put the next players who signed up from their phones on the buttons for the following game, a lone player takes on
+// the winner. Returns the announcement of who is up, nothing if nobody is waiting or a tournament is running
+func (this *game) callUpNext() string {
+
+	if _, _, ok := this.tournament.Next(); ok {
+		return ""
+	}
+
+	called := this.signups.CallUp(2)
+	if len(called) == 0 {
+		return ""
+	}
+
+	left, right := this.current.config.LeftProfile, this.current.config.RightProfile
+	switch {
+	case len(called) == 2:
+		left, right = this.tournamentProfile(called[0]), this.tournamentProfile(called[1])
+	case this.leftPlayerWon:
+		right = this.tournamentProfile(called[0])
+		called = []string{left.PlayerName(Settings.LeftPlayerName), called[0]}
+	default:
+		left = this.tournamentProfile(called[0])
+		called = append(called, right.PlayerName(Settings.RightPlayerName))
+	}
+
+	this.options.config.LeftProfile, this.options.config.RightProfile = left, right
+	this.menus[leftPlayerMenu].SelectName(left.Name)
+	this.menus[rightPlayerMenu].SelectName(right.Name)
+
+	announcement := strings.Join(called, " and ") + ", you're up next."
+	log.Print(announcement)
+	return announcement
+}
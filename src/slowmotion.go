@@ -0,0 +1,81 @@
+package main
+
+import (
+	"log"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+// Final rally of the game just won being played back in slow motion before the winner is shown
+type matchPointReplay struct {
+	mode GameMode
+
+	// scene of the winner shown once the replay is over, and the time scale to go back to
+	winner *Scene
+	scale  float64
+
+	// state of the buttons last frame, so only a new push skips the replay and not one held from the last point
+	left, right bool
+}
+
+// Replay the end of the final rally of the game just won at MatchPointReplaySpeed then show winner, or show winner
+// straight away if there's no recording of the game to replay
+func (this *game) replayMatchPoint(winner *Scene) {
+
+	recorded, ok := this.mode.(RecordedGameMode)
+	if !ok || Settings.MatchPointReplaySpeed <= 0 {
+		this.show(winner, 0)
+		return
+	}
+	rally := recorded.Recording().FinalRally(Settings.MatchPointReplaySeconds, MissLifePenalty)
+	mode, ok := NewGameMode("replay")
+	if rally == nil || !ok {
+		this.show(winner, 0)
+		return
+	}
+
+	scene := NewMatrixScene("matchpoint", Settings.LedCount, Settings.MatrixRows)
+	mode.Setup(scene.Field(), GameConfig{Replay: rally})
+	this.show(scene, 0)
+
+	this.matchPoint = &matchPointReplay{
+		mode:   mode,
+		winner: winner,
+		scale:  this.clock.Scale(),
+		left:   this.buttons.LeftButton(),
+		right:  this.buttons.RightButton(),
+	}
+	this.clock.SetScale(this.matchPoint.scale * Settings.MatchPointReplaySpeed)
+	log.Printf("Replaying the last %.1f seconds of match point", rally.Duration)
+}
+
+// Play the replay forward by dt, showing the winner once it ends or either button is pushed, returns false once it is
+// over
+func (this *game) updateMatchPointReplay(dt float64) bool {
+
+	replay := this.matchPoint
+	if replay == nil {
+		return false
+	}
+
+	left, right := this.buttons.LeftButton(), this.buttons.RightButton()
+	skipped := (left && !replay.left) || (right && !replay.right)
+	replay.left, replay.right = left, right
+
+	if !skipped && replay.mode.Tick(dt) == GameInProgress {
+		return true
+	}
+
+	this.stopMatchPointReplay()
+	this.show(replay.winner, Settings.SceneFadeSeconds)
+	return false
+}
+
+// Stop the replay if one is playing and bring time back to its usual speed, also when the game leaves the phase early
+func (this *game) stopMatchPointReplay() {
+	if this.matchPoint != nil {
+		this.clock.SetScale(this.matchPoint.scale)
+		this.matchPoint = nil
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	"github.com/brandonagr/pongpi/src/pong/status"
+)
+
+// Lines shown on the status panel for state, most important first as small panels only have room for a couple,
+// address being where the web page can be reached
+func statusLines(state gameState, address string) []string {
+
+	lines := []string{address}
+
+	switch {
+	case !state.Responding:
+		lines = append(lines, "Not responding")
+	case state.Mode != "":
+		lines = append(lines, fmt.Sprintf("%v %v-%v", state.Mode, state.LeftScore, state.RightScore))
+	default:
+		lines = append(lines, state.Phase)
+	}
+	if state.Paused {
+		lines[len(lines)-1] += " paused"
+	}
+
+	lines = append(lines, fmt.Sprintf("%.0f/%.0f fps", state.FPS, state.TargetFPS))
+	if state.Activity != "" {
+		lines = append(lines, state.Activity)
+	}
+	return lines
+}
+
+// Address the web page is reached at from another machine, the port of the web server on this machine's address
+func webPageAddress() string {
+	address := Settings.WebAddress
+	if strings.HasPrefix(address, ":") {
+		address = status.LocalAddress() + address
+	}
+	return address
+}
+
+// Show the state of the game on panel every interval forever, run as a goroutine
+func (this *game) showStatus(panel status.Panel, interval time.Duration) {
+
+	lastError := ""
+	for _ = range time.Tick(interval) {
+		err := panel.Show(statusLines(this.requestState(), webPageAddress()))
+
+		// a panel that stops answering is only logged once
+		message := ""
+		if err != nil {
+			message = err.Error()
+		}
+		if message != lastError && err != nil {
+			log.Print("Showing the status: ", err)
+		}
+		lastError = message
+	}
+}
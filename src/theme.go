@@ -0,0 +1,40 @@
+package main
+
+import (
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+	"github.com/brandonagr/pongpi/src/pong/effects/scripted"
+)
+
+// Seconds the winner of a game is shown for
+var victorySeconds float64 = 4
+
+// Shows the winner of a game in the way the theme chooses
+type victory interface {
+	Drawable
+
+	// Seconds left until the winner has been shown
+	TimeRemaining() float64
+}
+
+// Show the left or right player winning on field, flashing, with fireworks or a wipe depending on the profile of the
+// winner, or else the victory script or the theme, and play the winner's fanfare if they chose one
+func newVictory(field Field, leftWon bool, winner PlayerProfile) victory {
+
+	if fanfare, ok := Fanfares[winner.Fanfare]; ok {
+		go PlaySound(fanfare)
+	}
+
+	if winner.Victory == "" && Settings.VictoryScript != "" {
+		return scripted.NewScriptedVictory(field, Settings.VictoryScript, leftWon, victorySeconds)
+	}
+
+	theme := CurrentTheme()
+	switch theme.PlayerVictory(winner) {
+	case VictoryFireworks:
+		return NewCelebration(field, leftWon, theme.PlayerColor(winner, leftWon), victorySeconds)
+	case VictoryWipe:
+		return NewWipe(field, leftWon, theme.PlayerColor(winner, leftWon), victorySeconds)
+	}
+	return NewWinner(field, leftWon, victorySeconds)
+}
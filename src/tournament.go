@@ -0,0 +1,74 @@
+package main
+
+import (
+	"log"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+	. "github.com/brandonagr/pongpi/src/pong/draw"
+)
+
+// Profile of a tournament or king of the hill player, players without a profile play as a profile with just their name
+func (this *game) tournamentProfile(name string) PlayerProfile {
+	if profile, ok := this.profiles.Find(name); ok && !profile.IsGuest() {
+		return profile
+	}
+	return PlayerProfile{Name: name}
+}
+
+// Options of the next tournament match, false if no tournament is running
+func (this *game) tournamentOptions() (gameOptions, bool) {
+
+	left, right, ok := this.tournament.Next()
+	if !ok {
+		return gameOptions{}, false
+	}
+
+	// tournament matches are always two people playing classic, on the background chosen from the menu
+	options := gameOptions{mode: "classic", background: this.options.background, tournament: true}
+	options.config.LeftProfile = this.tournamentProfile(left)
+	options.config.RightProfile = this.tournamentProfile(right)
+	return options, true
+}
+
+// Pulse the ends of scene in the colors of the players of the next tournament match, if there is one
+func (this *game) showUpNext(scene *Scene) {
+
+	options, ok := this.tournamentOptions()
+	if !ok {
+		return
+	}
+	theme := CurrentTheme()
+	leftColor := theme.PlayerColor(options.config.LeftProfile, true)
+	rightColor := theme.PlayerColor(options.config.RightProfile, false)
+	scene.Add(NewUpNext(scene.Field(), leftColor, rightColor))
+}
+
+// Move the winner of a finished tournament match through the bracket, and announce who plays next
+func (this *game) updateTournament() {
+
+	if !this.current.tournament {
+		return
+	}
+
+	winner := this.current.config.RightProfile.Name
+	if this.leftPlayerWon {
+		winner = this.current.config.LeftProfile.Name
+	}
+	champion, err := this.tournament.RecordWinner(winner)
+	if err != nil {
+		// the tournament was restarted from the web during the match
+		log.Print(err)
+		return
+	}
+	if err := this.tournament.Save(); err != nil {
+		log.Print(err)
+	}
+
+	if champion {
+		log.Print(winner, " won the tournament")
+		go PlayTTS(winner + " wins the tournament")
+	} else if left, right, ok := this.tournament.Next(); ok {
+		log.Print(winner, " advances, ", left, " plays ", right, " next")
+		go PlayTTS(winner + " advances. " + left + " plays " + right + " next")
+	}
+}
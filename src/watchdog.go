@@ -0,0 +1,60 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	. "github.com/brandonagr/pongpi/src/pong"
+)
+
+// Time between checks for a stalled game loop
+var watchdogPollTime = 250 * time.Millisecond
+
+// Start a watchdog that resets the display and restarts the game if the loop stops for timeout
+func (this *game) watch(timeout time.Duration) {
+
+	this.watchdog = NewWatchdog(timeout)
+	go this.watchdog.Watch(watchdogPollTime, this.reopen)
+}
+
+// Feed the watchdog, if there is one, with what the loop is doing
+func (this *game) beat(activity string) {
+	if this.watchdog != nil {
+		this.watchdog.Beat(activity)
+	}
+}
+
+// Called from the watchdog goroutine, reopen the display to unblock a stuck render then ask the loop to restart
+func (this *game) reopen() {
+
+	if resettable, ok := this.display.(ResettableDisplay); ok {
+		log.Print("Resetting display")
+		if err := resettable.Reset(); err != nil {
+			log.Print(err)
+		}
+	}
+
+	select {
+	case this.stalls <- true:
+	default:
+	}
+}
+
+// Abandon whatever was happening and start over from the intro
+func (this *game) restart() {
+
+	if this.boot != nil {
+		// the boot animation runs on wall time so will finish by itself
+		return
+	}
+
+	log.Print("Restarting from ", this.states.Phase())
+
+	if this.clock.Paused() {
+		this.clock.Resume()
+	}
+	this.resumeTime = 0
+	this.menuOpen = false
+
+	this.states.Reset(PhaseIdle)
+}